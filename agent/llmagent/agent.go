@@ -17,6 +17,11 @@ package llmagent
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"time"
+	"weak"
 
 	"github.com/volcengine/veadk-go/auth/veauth"
 	"github.com/volcengine/veadk-go/common"
@@ -32,6 +37,58 @@ import (
 	"google.golang.org/adk/tool"
 )
 
+// models remembers the model of every agent built by New. The agents are held weakly and
+// their entries dropped once they are collected, e.g. after a reload of the agent tree.
+var (
+	modelsMu sync.Mutex
+	models   = map[weak.Pointer[byte]]adkmodel.LLM{}
+)
+
+// Model returns the model of an agent built by New, seeing through the agents wrapping
+// it with an Unwrap method.
+func Model(ag agent.Agent) (adkmodel.LLM, bool) {
+	for {
+		wrapper, ok := ag.(interface{ Unwrap() agent.Agent })
+		if !ok {
+			break
+		}
+		ag = wrapper.Unwrap()
+	}
+	ptr := agentPointer(ag)
+	if ptr == nil {
+		return nil, false
+	}
+	modelsMu.Lock()
+	defer modelsMu.Unlock()
+	llm, ok := models[weak.Make(ptr)]
+	return llm, ok
+}
+
+func storeModel(ag agent.Agent, llm adkmodel.LLM) {
+	ptr := agentPointer(ag)
+	if ptr == nil {
+		return
+	}
+	key := weak.Make(ptr)
+	modelsMu.Lock()
+	models[key] = llm
+	modelsMu.Unlock()
+	runtime.AddCleanup(ptr, func(key weak.Pointer[byte]) {
+		modelsMu.Lock()
+		delete(models, key)
+		modelsMu.Unlock()
+	}, key)
+}
+
+// agentPointer returns the address of the agent behind ag, nil when it is not a pointer.
+func agentPointer(ag agent.Agent) *byte {
+	v := reflect.ValueOf(ag)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil
+	}
+	return (*byte)(v.UnsafePointer())
+}
+
 type Config struct {
 	llmagent.Config
 	ModelName        string
@@ -41,7 +98,10 @@ type Config struct {
 	ModelExtraConfig map[string]any
 	KnowledgeBase    *knowledgebase.KnowledgeBase
 	PromptManager    prompts.BasePromptManager
-	DisableThought   bool
+	// PromptRefreshInterval, when set, fetches the instruction from PromptManager again once
+	// it elapsed, instead of once when the agent is created.
+	PromptRefreshInterval time.Duration
+	DisableThought        bool
 }
 
 func New(cfg *Config) (agent.Agent, error) {
@@ -49,8 +109,10 @@ func New(cfg *Config) (agent.Agent, error) {
 		cfg.Name = common.DEFAULT_LLMAGENT_NAME
 	}
 
-	if cfg.Instruction == "" {
-		if cfg.PromptManager != nil {
+	if cfg.Instruction == "" && cfg.InstructionProvider == nil {
+		if cfg.PromptManager != nil && cfg.PromptRefreshInterval > 0 {
+			cfg.InstructionProvider = prompts.NewInstructionProvider(cfg.PromptManager, cfg.PromptRefreshInterval).Instruction
+		} else if cfg.PromptManager != nil {
 			cfg.Instruction = cfg.PromptManager.GetPrompt()
 		} else {
 			cfg.Instruction = prompts.DEFAULT_INSTRUCTION
@@ -123,7 +185,12 @@ func New(cfg *Config) (agent.Agent, error) {
 		cfg.Tools = append(cfg.Tools, knowledgeTool)
 	}
 
-	return llmagent.New(cfg.Config)
+	ag, err := llmagent.New(cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+	storeModel(ag, cfg.Model)
+	return ag, nil
 }
 
 func addDisableThoughtConfig(extConfig map[string]any) (map[string]any, error) {
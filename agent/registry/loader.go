@@ -0,0 +1,234 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/volcengine/veadk-go/agent/remoteagent"
+	"github.com/volcengine/veadk-go/log"
+	"google.golang.org/adk/agent"
+)
+
+const (
+	DefaultRefreshInterval = 30 * time.Second
+	listTimeout            = 10 * time.Second
+)
+
+// LoaderConfig configures NewLoader.
+type LoaderConfig struct {
+	Registry Registry
+	// Tags keeps the agents with a skill tagged with one of them, every agent when empty.
+	Tags []string
+	// RefreshInterval is how long the list of agents is cached, DefaultRefreshInterval when zero.
+	RefreshInterval time.Duration
+	// RemoteConfig returns the base config of the remote agent of entry, e.g. with its
+	// credentials. Its name, description, agent card and base URLs are set by the loader.
+	RemoteConfig func(entry Entry) *remoteagent.Config
+	// Root builds the root agent, such as a coordinator, from the remote agents. It is rebuilt
+	// when they change. Without Root, the first remote agent is the root agent.
+	Root func(remotes []agent.Agent) (agent.Agent, error)
+}
+
+// Loader is an agent.Loader serving the remote agents of a registry. The replicas of an
+// agent are called through one remote agent, falling back from one URL to the next. Stale
+// lists are refreshed in the background while the agents listed last are served.
+type Loader struct {
+	config LoaderConfig
+	// refreshMu serializes the refreshes, which list the registry without holding mu
+	refreshMu sync.Mutex
+
+	mu         sync.Mutex
+	refreshed  time.Time
+	refreshing bool
+	// groups holds the entries of each agent, by agent name
+	groups  map[string][]Entry
+	names   []string
+	remotes map[string]agent.Agent
+	root    agent.Agent
+}
+
+var _ agent.Loader = (*Loader)(nil)
+
+// NewLoader lists the agents of the registry once, so that misconfigurations fail early.
+func NewLoader(ctx context.Context, config LoaderConfig) (*Loader, error) {
+	if config.Registry == nil {
+		return nil, fmt.Errorf("registry can't be nil")
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = DefaultRefreshInterval
+	}
+	l := &Loader{config: config}
+	if err := l.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Loader) ListAgents() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refreshIfStale()
+	return slices.Clone(l.names)
+}
+
+func (l *Loader) LoadAgent(name string) (agent.Agent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refreshIfStale()
+	if ag, ok := l.remotes[name]; ok {
+		return ag, nil
+	}
+	if l.root != nil && l.root.Name() == name {
+		return l.root, nil
+	}
+	return nil, fmt.Errorf("agent %s is not registered", name)
+}
+
+// RootAgent returns the agent built by LoaderConfig.Root, nil when no agent is registered.
+func (l *Loader) RootAgent() agent.Agent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refreshIfStale()
+	return l.root
+}
+
+// Refresh lists the agents of the registry now.
+func (l *Loader) Refresh(ctx context.Context) error {
+	l.refreshMu.Lock()
+	defer l.refreshMu.Unlock()
+	return l.refresh(ctx)
+}
+
+// refreshIfStale starts a refresh in the background once the list is stale. It is called
+// with mu held.
+func (l *Loader) refreshIfStale() {
+	if l.refreshing || time.Since(l.refreshed) < l.config.RefreshInterval {
+		return
+	}
+	l.refreshing = true
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+		defer cancel()
+		if err := l.Refresh(ctx); err != nil {
+			// keep serving the agents listed last, the registry may be back on the next refresh
+			log.Warnf("refresh agents from registry failed: %v", err)
+		}
+		l.mu.Lock()
+		l.refreshing = false
+		l.mu.Unlock()
+	}()
+}
+
+// refresh is called with refreshMu held, and only holds mu to publish the agents.
+func (l *Loader) refresh(ctx context.Context) error {
+	entries, err := l.config.Registry.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	groups := make(map[string][]Entry)
+	var names []string
+	for _, entry := range entries {
+		if len(l.config.Tags) > 0 && !entry.HasAnyTag(l.config.Tags...) {
+			continue
+		}
+		if _, ok := groups[entry.Name()]; !ok {
+			names = append(names, entry.Name())
+		}
+		groups[entry.Name()] = append(groups[entry.Name()], entry)
+	}
+	l.mu.Lock()
+	l.refreshed = time.Now()
+	unchanged := l.remotes != nil && sameGroups(l.groups, groups)
+	previous := l.names
+	l.mu.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	// agents can only have one parent, every root gets its own remote agents
+	remotes := make(map[string]agent.Agent, len(names))
+	ordered := make([]agent.Agent, 0, len(names))
+	for _, name := range names {
+		ag, err := l.newRemoteAgent(groups[name])
+		if err != nil {
+			return fmt.Errorf("create remote agent %s: %w", name, err)
+		}
+		remotes[name] = ag
+		ordered = append(ordered, ag)
+	}
+	var root agent.Agent
+	if l.config.Root != nil {
+		if root, err = l.config.Root(ordered); err != nil {
+			return fmt.Errorf("build root agent: %w", err)
+		}
+	} else if len(ordered) > 0 {
+		root = ordered[0]
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.remotes != nil {
+		log.Infof("registry agents changed from %v to %v", previous, names)
+	}
+	l.groups, l.names, l.remotes, l.root = groups, names, remotes, root
+	return nil
+}
+
+func (l *Loader) newRemoteAgent(entries []Entry) (agent.Agent, error) {
+	config := remoteagent.NewDefaultConfig()
+	if l.config.RemoteConfig != nil {
+		config = l.config.RemoteConfig(entries[0])
+	}
+	card := entries[0].Card
+	var fallbacks []string
+	for _, entry := range entries[1:] {
+		fallbacks = append(fallbacks, entry.URL())
+	}
+	config.SetName(card.Name).
+		SetDescription(card.Description).
+		SetAgentCard(&card).
+		SetBaseUrl(card.URL).
+		SetBaseUrls(fallbacks)
+	return remoteagent.NewVeRemoteAgent(config)
+}
+
+// sameGroups reports whether the agents and their cards are unchanged, expiry aside.
+func sameGroups(a, b map[string][]Entry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, entries := range a {
+		if !slices.EqualFunc(entries, b[name], func(x, y Entry) bool {
+			return sameCard(x, y)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameCard(a, b Entry) bool {
+	x, errX := json.Marshal(a.Card)
+	y, errY := json.Marshal(b.Card)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
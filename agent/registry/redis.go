@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/redis/go-redis/v9"
+)
+
+const DefaultRedisKeyPrefix = "veadk:registry"
+
+// RedisRegistry keeps each registration under its own key expiring with it, and the keys
+// in a set pruned when listing.
+type RedisRegistry struct {
+	client *redis.Client
+	prefix string
+}
+
+var _ Registry = (*RedisRegistry)(nil)
+
+// NewRedisRegistry stores the registrations under prefix, DefaultRedisKeyPrefix when empty.
+func NewRedisRegistry(client *redis.Client, prefix string) (*RedisRegistry, error) {
+	if client == nil {
+		return nil, errors.New("redis client can't be nil")
+	}
+	if prefix == "" {
+		prefix = DefaultRedisKeyPrefix
+	}
+	return &RedisRegistry{client: client, prefix: prefix}, nil
+}
+
+func (r *RedisRegistry) indexKey() string {
+	return r.prefix + ":agents"
+}
+
+func (r *RedisRegistry) entryKey(name, url string) string {
+	return r.prefix + ":agent:" + entryKey(name, url)
+}
+
+func (r *RedisRegistry) Register(ctx context.Context, card *a2a.AgentCard, ttl time.Duration) error {
+	entry, err := newEntry(card, ttl)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	key := r.entryKey(entry.Name(), entry.URL())
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, ttl)
+		pipe.SAdd(ctx, r.indexKey(), key)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("register agent %s: %w", entry.Name(), err)
+	}
+	return nil
+}
+
+func (r *RedisRegistry) Deregister(ctx context.Context, name, url string) error {
+	key := r.entryKey(name, url)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.SRem(ctx, r.indexKey(), key)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("deregister agent %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *RedisRegistry) List(ctx context.Context) ([]Entry, error) {
+	keys, err := r.client.SMembers(ctx, r.indexKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("list agents: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("list agents: %w", err)
+	}
+	var entries []Entry
+	var expired []any
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			expired = append(expired, keys[i])
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			return nil, fmt.Errorf("parse agent %s: %w", keys[i], err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(expired) > 0 {
+		_ = r.client.SRem(ctx, r.indexKey(), expired...).Err()
+	}
+	sortEntries(entries)
+	return entries, nil
+}
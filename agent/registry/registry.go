@@ -0,0 +1,177 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry lets A2A servers publish their agent cards, and agents discover the
+// remote agents they delegate to instead of hard-coding their URLs.
+package registry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/volcengine/veadk-go/log"
+)
+
+const (
+	// DefaultTTL is how long a registration lives without heartbeat.
+	DefaultTTL = 30 * time.Second
+	// registerTimeout bounds each registration of the heartbeat
+	registerTimeout = 5 * time.Second
+)
+
+var ErrInvalidCard = errors.New("agent card must have a name and a url")
+
+// Registry keeps the cards of the agents served over A2A. Entries are keyed by the name and
+// the URL of the card, so that the replicas of an agent are registered side by side.
+type Registry interface {
+	// Register adds or renews card, which expires after ttl, never when ttl is zero.
+	Register(ctx context.Context, card *a2a.AgentCard, ttl time.Duration) error
+	// Deregister removes the card of the agent name served at url.
+	Deregister(ctx context.Context, name, url string) error
+	// List returns the entries which haven't expired, sorted by name and url.
+	List(ctx context.Context) ([]Entry, error)
+}
+
+// Entry is a registered agent card.
+type Entry struct {
+	Card a2a.AgentCard `json:"card"`
+	// ExpiresAt is zero for entries which never expire.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+func (e Entry) Name() string {
+	return e.Card.Name
+}
+
+func (e Entry) URL() string {
+	return e.Card.URL
+}
+
+// Tags returns the tags of the skills of the agent.
+func (e Entry) Tags() []string {
+	var tags []string
+	for _, skill := range e.Card.Skills {
+		for _, tag := range skill.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// HasAnyTag reports whether one of the skills of the agent is tagged with one of tags.
+func (e Entry) HasAnyTag(tags ...string) bool {
+	return slices.ContainsFunc(e.Tags(), func(tag string) bool { return slices.Contains(tags, tag) })
+}
+
+func (e Entry) expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+func newEntry(card *a2a.AgentCard, ttl time.Duration) (Entry, error) {
+	if card == nil || card.Name == "" || card.URL == "" {
+		return Entry{}, ErrInvalidCard
+	}
+	entry := Entry{Card: *card}
+	if ttl > 0 {
+		entry.ExpiresAt = time.Now().Add(ttl)
+	}
+	return entry, nil
+}
+
+func entryKey(name, url string) string {
+	return name + "@" + url
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Name() != entries[j].Name() {
+			return entries[i].Name() < entries[j].Name()
+		}
+		return entries[i].URL() < entries[j].URL()
+	})
+}
+
+// Heartbeat keeps agent cards registered while the server is running.
+type Heartbeat struct {
+	registry Registry
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	cards []*a2a.AgentCard
+}
+
+// NewHeartbeat registers cards with ttl, DefaultTTL when zero, renewed every third of ttl.
+func NewHeartbeat(registry Registry, ttl time.Duration) *Heartbeat {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Heartbeat{registry: registry, ttl: ttl}
+}
+
+// Add registers card now and on every heartbeat, replacing the card of the same agent and url.
+func (h *Heartbeat) Add(card *a2a.AgentCard) {
+	h.mu.Lock()
+	h.cards = slices.DeleteFunc(h.cards, func(c *a2a.AgentCard) bool { return c.Name == card.Name && c.URL == card.URL })
+	h.cards = append(h.cards, card)
+	h.mu.Unlock()
+	h.register(context.Background(), card)
+}
+
+// Run renews the registrations until ctx is done, then deregisters the cards.
+func (h *Heartbeat) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			h.deregister(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+			for _, card := range h.snapshot() {
+				h.register(ctx, card)
+			}
+		}
+	}
+}
+
+func (h *Heartbeat) snapshot() []*a2a.AgentCard {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.cards)
+}
+
+func (h *Heartbeat) register(ctx context.Context, card *a2a.AgentCard) {
+	ctx, cancel := context.WithTimeout(ctx, registerTimeout)
+	defer cancel()
+	if err := h.registry.Register(ctx, card, h.ttl); err != nil {
+		log.Warnf("register agent %s at %s failed: %v", card.Name, card.URL, err)
+	}
+}
+
+func (h *Heartbeat) deregister(ctx context.Context) {
+	for _, card := range h.snapshot() {
+		ctx, cancel := context.WithTimeout(ctx, registerTimeout)
+		if err := h.registry.Deregister(ctx, card.Name, card.URL); err != nil {
+			log.Warnf("deregister agent %s at %s failed: %v", card.Name, card.URL, err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+func card(name, url string, tags ...string) *a2a.AgentCard {
+	return &a2a.AgentCard{
+		Name:        name,
+		Description: name + " agent",
+		URL:         url,
+		Skills:      []a2a.AgentSkill{{ID: name, Name: name, Tags: tags}},
+	}
+}
+
+func names(entries []Entry) []string {
+	var result []string
+	for _, entry := range entries {
+		result = append(result, entry.Name()+" "+entry.URL())
+	}
+	return result
+}
+
+func testRegistry(t *testing.T, r Registry) {
+	ctx := context.Background()
+	require.NoError(t, r.Register(ctx, card("writer", "http://b/"), 0))
+	require.NoError(t, r.Register(ctx, card("researcher", "http://b/"), time.Minute))
+	require.NoError(t, r.Register(ctx, card("researcher", "http://a/"), time.Minute))
+	require.NoError(t, r.Register(ctx, card("expired", "http://a/"), time.Millisecond))
+	assert.ErrorIs(t, r.Register(ctx, card("", "http://a/"), 0), ErrInvalidCard)
+	assert.ErrorIs(t, r.Register(ctx, card("nourl", ""), 0), ErrInvalidCard)
+	time.Sleep(10 * time.Millisecond)
+
+	entries, err := r.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"researcher http://a/", "researcher http://b/", "writer http://b/"}, names(entries))
+	assert.True(t, entries[2].ExpiresAt.IsZero())
+	assert.False(t, entries[0].ExpiresAt.IsZero())
+
+	// registering again renews the entry
+	updated := card("writer", "http://b/", "prose")
+	require.NoError(t, r.Register(ctx, updated, 0))
+	require.NoError(t, r.Deregister(ctx, "researcher", "http://b/"))
+	require.NoError(t, r.Deregister(ctx, "unknown", "http://b/"))
+	entries, err = r.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"researcher http://a/", "writer http://b/"}, names(entries))
+	assert.Equal(t, []string{"prose"}, entries[1].Tags())
+}
+
+func TestStaticRegistry(t *testing.T) {
+	r, err := NewStaticRegistry()
+	require.NoError(t, err)
+	testRegistry(t, r)
+
+	_, err = NewStaticRegistry(card("", ""))
+	assert.ErrorIs(t, err, ErrInvalidCard)
+}
+
+func TestFileRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.json")
+	r, err := NewFileRegistry(path)
+	require.NoError(t, err)
+	testRegistry(t, r)
+
+	// the registrations are shared through the file
+	other, err := NewFileRegistry(path)
+	require.NoError(t, err)
+	entries, err := other.List(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"researcher http://a/", "writer http://b/"}, names(entries))
+
+	// files written by hand list agents which never expire
+	manual := filepath.Join(t.TempDir(), "manual.json")
+	require.NoError(t, os.WriteFile(manual, []byte(`{"agents": [{"card": {"name": "translator", "url": "http://c/"}}]}`), 0o600))
+	r, err = NewFileRegistry(manual)
+	require.NoError(t, err)
+	entries, err = r.List(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"translator http://c/"}, names(entries))
+
+	require.NoError(t, os.WriteFile(manual, []byte(`not json`), 0o600))
+	_, err = NewFileRegistry(manual)
+	assert.Error(t, err)
+}
+
+func TestFileRegistry_ConcurrentProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.json")
+	var wg sync.WaitGroup
+	for i := range 10 {
+		// every registry stands for another process, with its own mutex
+		r, err := NewFileRegistry(path)
+		require.NoError(t, err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, r.Register(context.Background(), card(fmt.Sprintf("agent%d", i), "http://a/"), 0))
+		}()
+	}
+	wg.Wait()
+
+	r, err := NewFileRegistry(path)
+	require.NoError(t, err)
+	entries, err := r.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, entries, 10, "no registration is lost")
+}
+
+func TestRedisRegistry(t *testing.T) {
+	host := os.Getenv("DATABASE_REDIS_HOST")
+	if host == "" {
+		t.Skip("DATABASE_REDIS_HOST is not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: host, Password: os.Getenv("DATABASE_REDIS_PASSWORD")})
+	t.Cleanup(func() { _ = client.Close() })
+
+	prefix := "veadk:test:" + time.Now().Format("150405.000000")
+	r, err := NewRedisRegistry(client, prefix)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		keys, _ := client.Keys(context.Background(), prefix+":*").Result()
+		if len(keys) > 0 {
+			client.Del(context.Background(), keys...)
+		}
+	})
+	testRegistry(t, r)
+}
+
+func TestHeartbeat(t *testing.T) {
+	r, err := NewStaticRegistry()
+	require.NoError(t, err)
+	heartbeat := NewHeartbeat(r, 30*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		heartbeat.Run(ctx)
+	}()
+	heartbeat.Add(card("researcher", "http://a/"))
+	heartbeat.Add(card("researcher", "http://a/"))
+
+	// the registration outlives its ttl while the heartbeat runs
+	time.Sleep(100 * time.Millisecond)
+	entries, err := r.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"researcher http://a/"}, names(entries))
+
+	cancel()
+	<-stopped
+	entries, err = r.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func coordinator(remotes []agent.Agent) (agent.Agent, error) {
+	return agent.New(agent.Config{
+		Name:      "coordinator",
+		SubAgents: remotes,
+		Run: func(agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(func(*session.Event, error) bool) {}
+		},
+	})
+}
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewStaticRegistry(
+		card("researcher", "http://a/", "search"),
+		card("researcher", "http://b/", "search"),
+		card("writer", "http://a/", "prose"),
+		card("painter", "http://a/", "image"),
+	)
+	require.NoError(t, err)
+
+	loader, err := NewLoader(ctx, LoaderConfig{
+		Registry:        r,
+		Tags:            []string{"search", "prose"},
+		RefreshInterval: time.Hour,
+		Root:            coordinator,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"researcher", "writer"}, loader.ListAgents())
+
+	root := loader.RootAgent()
+	assert.Equal(t, "coordinator", root.Name())
+	assert.Len(t, root.SubAgents(), 2)
+	researcher, err := loader.LoadAgent("researcher")
+	require.NoError(t, err)
+	assert.Equal(t, "researcher agent", researcher.Description())
+	_, err = loader.LoadAgent("painter")
+	assert.Error(t, err)
+
+	// unchanged registrations keep the agents
+	require.NoError(t, loader.Refresh(ctx))
+	assert.Same(t, root, loader.RootAgent())
+
+	// new specialists are picked up by a new coordinator
+	require.NoError(t, r.Register(ctx, card("translator", "http://c/", "prose"), time.Minute))
+	require.NoError(t, loader.Refresh(ctx))
+	assert.Equal(t, []string{"researcher", "translator", "writer"}, loader.ListAgents())
+	assert.Len(t, loader.RootAgent().SubAgents(), 3)
+
+	// without root, the first remote agent is the root agent
+	loader, err = NewLoader(ctx, LoaderConfig{Registry: r, Tags: []string{"image"}})
+	require.NoError(t, err)
+	assert.Equal(t, "painter", loader.RootAgent().Name())
+
+	_, err = NewLoader(ctx, LoaderConfig{})
+	assert.Error(t, err)
+}
+
+// slowRegistry blocks its listings until release is closed.
+type slowRegistry struct {
+	Registry
+	release chan struct{}
+}
+
+func (r *slowRegistry) List(ctx context.Context) ([]Entry, error) {
+	<-r.release
+	return r.Registry.List(ctx)
+}
+
+func TestLoader_RefreshesInBackground(t *testing.T) {
+	ctx := context.Background()
+	static, err := NewStaticRegistry(card("researcher", "http://a/"))
+	require.NoError(t, err)
+	r := &slowRegistry{Registry: static, release: make(chan struct{})}
+	close(r.release)
+	loader, err := NewLoader(ctx, LoaderConfig{Registry: r, RefreshInterval: time.Millisecond})
+	require.NoError(t, err)
+
+	r.release = make(chan struct{})
+	require.NoError(t, static.Register(ctx, card("writer", "http://a/"), 0))
+	time.Sleep(5 * time.Millisecond)
+	// the stale list is served while the registry is slow
+	assert.Equal(t, []string{"researcher"}, loader.ListAgents())
+	_, err = loader.LoadAgent("researcher")
+	assert.NoError(t, err)
+
+	close(r.release)
+	assert.Eventually(t, func() bool {
+		return slices.Equal([]string{"researcher", "writer"}, loader.ListAgents())
+	}, time.Second, time.Millisecond)
+}
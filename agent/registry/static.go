@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// StaticRegistry keeps the registrations in memory, e.g. for a fixed set of agents or tests.
+type StaticRegistry struct {
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+var _ Registry = (*StaticRegistry)(nil)
+
+// NewStaticRegistry returns a registry holding cards, which never expire.
+func NewStaticRegistry(cards ...*a2a.AgentCard) (*StaticRegistry, error) {
+	r := &StaticRegistry{entries: make(map[string]Entry)}
+	for _, card := range cards {
+		if err := r.Register(context.Background(), card, 0); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *StaticRegistry) Register(_ context.Context, card *a2a.AgentCard, ttl time.Duration) error {
+	entry, err := newEntry(card, ttl)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[entryKey(entry.Name(), entry.URL())] = entry
+	return nil
+}
+
+func (r *StaticRegistry) Deregister(_ context.Context, name, url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, entryKey(name, url))
+	return nil
+}
+
+func (r *StaticRegistry) List(_ context.Context) ([]Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return liveEntries(r.entries), nil
+}
+
+func liveEntries(entries map[string]Entry) []Entry {
+	now := time.Now()
+	live := make([]Entry, 0, len(entries))
+	for key, entry := range entries {
+		if entry.expired(now) {
+			delete(entries, key)
+			continue
+		}
+		live = append(live, entry)
+	}
+	sortEntries(live)
+	return live
+}
+
+// FileRegistry keeps the registrations in a JSON file, which can also be written by hand
+// to list agents that don't register themselves. The file is rewritten atomically, and the
+// registrations of several processes are serialized by a lock of the file, held in a
+// ".lock" file next to it. File locks are only taken on Unix systems, elsewhere the file
+// is only safe to share within one process.
+type FileRegistry struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ Registry = (*FileRegistry)(nil)
+
+type registryFile struct {
+	Agents []Entry `json:"agents"`
+}
+
+// NewFileRegistry uses the file at path, created on the first registration.
+func NewFileRegistry(path string) (*FileRegistry, error) {
+	if path == "" {
+		return nil, errors.New("registry file path can't be empty")
+	}
+	r := &FileRegistry{path: path}
+	if _, err := r.read(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *FileRegistry) Register(_ context.Context, card *a2a.AgentCard, ttl time.Duration) error {
+	entry, err := newEntry(card, ttl)
+	if err != nil {
+		return err
+	}
+	unlock, err := r.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	entries, err := r.read()
+	if err != nil {
+		return err
+	}
+	entries[entryKey(entry.Name(), entry.URL())] = entry
+	return r.write(entries)
+}
+
+func (r *FileRegistry) Deregister(_ context.Context, name, url string) error {
+	unlock, err := r.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	entries, err := r.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[entryKey(name, url)]; !ok {
+		return nil
+	}
+	delete(entries, entryKey(name, url))
+	return r.write(entries)
+}
+
+func (r *FileRegistry) List(_ context.Context) ([]Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	return liveEntries(entries), nil
+}
+
+// lock serializes the read-modify-write cycles of the file, within the process and with
+// the other processes using it.
+func (r *FileRegistry) lock() (func(), error) {
+	r.mu.Lock()
+	unlock, err := lockFile(r.path)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		unlock()
+		r.mu.Unlock()
+	}, nil
+}
+
+func (r *FileRegistry) read() (map[string]Entry, error) {
+	entries := make(map[string]Entry)
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read registry file: %w", err)
+	}
+	var file registryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse registry file %s: %w", r.path, err)
+	}
+	for _, entry := range file.Agents {
+		entries[entryKey(entry.Name(), entry.URL())] = entry
+	}
+	return entries, nil
+}
+
+func (r *FileRegistry) write(entries map[string]Entry) error {
+	file := registryFile{Agents: liveEntries(entries)}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*")
+	if err != nil {
+		return fmt.Errorf("write registry file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write registry file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write registry file: %w", err)
+	}
+	return os.Rename(tmp.Name(), r.path)
+}
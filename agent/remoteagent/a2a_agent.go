@@ -16,14 +16,21 @@ package remoteagent
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2aclient"
 	"github.com/a2aproject/a2a-go/a2aclient/agentcard"
+	"github.com/google/uuid"
+	"github.com/volcengine/veadk-go/log"
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/remoteagent"
+	"google.golang.org/adk/server/adka2a"
+	"google.golang.org/genai"
 )
 
 var (
@@ -35,6 +42,25 @@ type Config struct {
 	remoteagent.A2AConfig
 	BaseUrl string
 	ApiKey  string
+	// BaseUrls are tried in order when BaseUrl doesn't answer.
+	BaseUrls []string
+	// Credentials authenticate every call, ApiKey is sent as a bearer token when unset.
+	Credentials CredentialProvider
+	// TLSConfig is used for HTTPS connections, see NewMTLSConfig for mutual TLS.
+	TLSConfig *tls.Config
+	// Transport sends the calls, a clone of http.DefaultTransport when nil, e.g. to close its
+	// connections once the agent isn't used anymore. TLSConfig replaces its TLS config.
+	Transport *http.Transport
+	// CallTimeout bounds the calls to the remote agent, or only the wait for the response
+	// headers of the streamed calls, no limit when zero.
+	CallTimeout time.Duration
+	// MaxAttempts is the number of attempts of a call over all base URLs, DefaultMaxAttempts when zero.
+	// Only the calls which never reached the remote agent are attempted again.
+	MaxAttempts int
+	// RetryBackoff is the wait before retrying once every base URL failed, doubled on each round.
+	RetryBackoff time.Duration
+	// CardTTL is how long the agent card is cached before it is revalidated.
+	CardTTL time.Duration
 }
 
 func NewDefaultConfig() *Config {
@@ -51,6 +77,46 @@ func (c *Config) SetApiKey(apiKey string) *Config {
 	return c
 }
 
+func (c *Config) SetBaseUrls(urls []string) *Config {
+	c.BaseUrls = urls
+	return c
+}
+
+func (c *Config) SetCredentials(credentials CredentialProvider) *Config {
+	c.Credentials = credentials
+	return c
+}
+
+func (c *Config) SetTLSConfig(tlsConfig *tls.Config) *Config {
+	c.TLSConfig = tlsConfig
+	return c
+}
+
+func (c *Config) SetTransport(transport *http.Transport) *Config {
+	c.Transport = transport
+	return c
+}
+
+func (c *Config) SetCallTimeout(timeout time.Duration) *Config {
+	c.CallTimeout = timeout
+	return c
+}
+
+func (c *Config) SetMaxAttempts(maxAttempts int) *Config {
+	c.MaxAttempts = maxAttempts
+	return c
+}
+
+func (c *Config) SetRetryBackoff(backoff time.Duration) *Config {
+	c.RetryBackoff = backoff
+	return c
+}
+
+func (c *Config) SetCardTTL(ttl time.Duration) *Config {
+	c.CardTTL = ttl
+	return c
+}
+
 func (c *Config) SetName(name string) *Config {
 	c.Name = name
 	return c
@@ -126,36 +192,94 @@ func (a *AuthInterceptor) Before(ctx context.Context, req *a2aclient.Request) (c
 	return ctx, nil
 }
 
+// NewVeRemoteAgent creates an agent calling the remote agent at BaseUrl over A2A JSON-RPC.
+// The agent card is fetched lazily and cached, so that the agent can be created while the
+// remote agent is down. Calls fall back to BaseUrls and are retried when the remote agent
+// couldn't be reached or answered 429, 502, 503 or 504.
 func NewVeRemoteAgent(config *Config) (agent.Agent, error) {
 	if config.BaseUrl == "" {
 		return nil, ErrBaseUrlInvalid
 	}
 
-	config.SetAgentCardSource(config.BaseUrl)
-
 	if config.Name == "" {
 		return nil, ErrNameInvalid
 	}
 
-	ctx := context.Background()
-	if config.ApiKey != "" {
-		resolveOptions := agentcard.WithRequestHeader("Authorization", fmt.Sprintf("Bearer %s", config.ApiKey))
-		// Resolve an AgentCard
-		card, err := agentcard.DefaultResolver.Resolve(ctx, config.BaseUrl, resolveOptions)
-		if err != nil {
-			return nil, fmt.Errorf("veadk: failed to resolve veadk card: %w", err)
-		}
+	credentials := config.Credentials
+	if credentials == nil && config.ApiKey != "" {
+		credentials = BearerToken(config.ApiKey)
+	}
+	baseUrls := append([]string{config.BaseUrl}, config.BaseUrls...)
+	httpClient := &http.Client{
+		Transport: newRetryTransport(baseUrls, credentials, config.Transport, config.TLSConfig, config.CallTimeout, config.MaxAttempts, config.RetryBackoff),
+	}
 
-		card.URL = config.BaseUrl
+	var cards *cardCache
+	if config.AgentCard != nil {
+		cards = newStaticCardCache(config.AgentCard)
+	} else {
+		cards = newCardCache(config.BaseUrl, httpClient, config.CardTTL)
+		// the actual card is served by the transport, this one only routes the calls to BaseUrl
+		config.SetAgentCard(&a2a.AgentCard{
+			Name:                              config.Name,
+			Description:                       config.Description,
+			URL:                               config.BaseUrl,
+			PreferredTransport:                a2a.TransportProtocolJSONRPC,
+			Capabilities:                      a2a.AgentCapabilities{Streaming: true},
+			SupportsAuthenticatedExtendedCard: true,
+		})
+	}
 
-		config.SetAgentCard(card)
+	if config.ClientFactory == nil {
+		config.SetClientFactory(a2aclient.NewFactory(
+			a2aclient.WithTransport(a2a.TransportProtocolJSONRPC, a2aclient.TransportFactoryFn(
+				func(_ context.Context, url string, _ *a2a.AgentCard) (a2aclient.Transport, error) {
+					return &cardTransport{Transport: a2aclient.NewJSONRPCTransport(url, httpClient), cards: cards}, nil
+				})),
+		))
+	}
 
-		clientFactory := a2aclient.NewFactory(
-			a2aclient.WithInterceptors(&AuthInterceptor{Token: config.ApiKey}),
-		)
-		config.SetClientFactory(clientFactory)
+	if config.A2APartConverter == nil {
+		config.A2APartConverter = FileArtifactConverter
 	}
 
 	return remoteagent.NewA2A(config.A2AConfig)
+}
 
+// FileArtifactConverter converts the parts received from the remote agent with
+// adka2a.ToGenAIPart and saves the inline files among them as artifacts of the invocation,
+// so that other agents and tools can load them. Unnamed files are named after the remote agent.
+func FileArtifactConverter(ctx context.Context, _ a2a.Event, part a2a.Part) (*genai.Part, error) {
+	genaiPart, err := adka2a.ToGenAIPart(part)
+	if err != nil {
+		return nil, err
+	}
+	// artifacts hold inline data, files behind URLs are left to the model
+	filePart, ok := part.(a2a.FilePart)
+	if !ok || genaiPart.InlineData == nil {
+		return genaiPart, nil
+	}
+	invocationCtx, ok := ctx.(agent.InvocationContext)
+	if !ok || invocationCtx.Artifacts() == nil {
+		return genaiPart, nil
+	}
+
+	name := fileName(filePart)
+	if name == "" {
+		name = fmt.Sprintf("%s_%s", invocationCtx.Agent().Name(), uuid.NewString())
+	}
+	if _, err := invocationCtx.Artifacts().Save(ctx, name, genaiPart); err != nil {
+		log.WarnContext(invocationCtx, "save file of remote agent as artifact failed", "file", name, "agent", invocationCtx.Agent().Name(), "error", err)
+	}
+	return genaiPart, nil
+}
+
+func fileName(part a2a.FilePart) string {
+	switch file := part.File.(type) {
+	case a2a.FileBytes:
+		return file.Name
+	case a2a.FileURI:
+		return file.Name
+	}
+	return ""
 }
@@ -0,0 +1,321 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/common"
+)
+
+func get(t *testing.T, client *http.Client, url string) *http.Response {
+	t.Helper()
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestRetryTransport_FallsBackToNextBaseUrl(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	var calls atomic.Int32
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		assert.Equal(t, "/a2a/invoke", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+
+	transport := newRetryTransport([]string{down.URL + "/a2a", up.URL + "/a2a"}, nil, nil, nil, 0, 0, time.Millisecond)
+	client := &http.Client{Transport: transport}
+
+	assert.Equal(t, http.StatusOK, get(t, client, down.URL+"/a2a/invoke").StatusCode)
+	// the base URL which answered is tried first from now on
+	assert.Equal(t, http.StatusOK, get(t, client, down.URL+"/a2a/invoke").StatusCode)
+	assert.EqualValues(t, 2, calls.Load())
+	assert.EqualValues(t, 1, transport.preferred.Load())
+}
+
+func TestRetryTransport_RetriesUnavailable(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make([]byte, 4)
+		n, _ := r.Body.Read(body)
+		assert.Equal(t, "ping", string(body[:n]))
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newRetryTransport([]string{server.URL}, nil, nil, nil, 0, 3, time.Millisecond)}
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("ping"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, 3, calls.Load())
+
+	// the last response is returned once the attempts are exhausted
+	calls.Store(0)
+	client = &http.Client{Transport: newRetryTransport([]string{server.URL}, nil, nil, nil, 0, 2, time.Millisecond)}
+	resp, err = client.Post(server.URL, "text/plain", strings.NewReader("ping"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestRetryTransport_CallTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newRetryTransport([]string{server.URL}, nil, nil, nil, 20*time.Millisecond, 1, time.Millisecond)}
+	_, err := client.Get(server.URL)
+	assert.ErrorContains(t, err, "timeout")
+}
+
+func TestRetryTransport_CallTimeoutOfStreams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte("done"))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: newRetryTransport([]string{server.URL}, nil, nil, nil, 20*time.Millisecond, 1, time.Millisecond)}
+
+	// the whole call is bounded
+	resp := get(t, client, server.URL)
+	_, err := io.ReadAll(resp.Body)
+	assert.Error(t, err)
+
+	// streamed events may last longer than the wait for the headers
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "done", string(body))
+}
+
+func TestRetryTransport_DoesNotRetrySentCalls(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		_ = conn.Close()
+	}))
+	defer server.Close()
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer other.Close()
+
+	client := &http.Client{Transport: newRetryTransport([]string{server.URL, other.URL}, nil, nil, nil, 0, 3, time.Millisecond)}
+	_, err := client.Post(server.URL, "text/plain", strings.NewReader("ping"))
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, calls.Load(), "the call may have reached the remote agent")
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+	calls.Store(0)
+	client = &http.Client{Transport: newRetryTransport([]string{server.URL, other.URL}, nil, nil, nil, 20*time.Millisecond, 3, time.Millisecond)}
+	_, err = client.Get(server.URL)
+	assert.ErrorContains(t, err, "timeout")
+	assert.EqualValues(t, 1, calls.Load(), "timed out calls are not retried")
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	var issued atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "a2a", r.PostForm.Get("scope"))
+		id, secret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "client", id)
+		assert.Equal(t, "secret", secret)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", issued.Add(1)),
+			"expires_in":   3600,
+		})
+	}))
+	defer tokenServer.Close()
+
+	// the first token was revoked by the remote agent
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer remote.Close()
+
+	credentials, err := NewOAuth2ClientCredentials(OAuth2Config{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"a2a"},
+	})
+	require.NoError(t, err)
+	client := &http.Client{Transport: newRetryTransport([]string{remote.URL}, credentials, nil, nil, 0, 1, time.Millisecond)}
+
+	assert.Equal(t, http.StatusOK, get(t, client, remote.URL).StatusCode)
+	assert.Equal(t, http.StatusOK, get(t, client, remote.URL).StatusCode)
+	// the token is cached until it is rejected
+	assert.EqualValues(t, 2, issued.Load())
+
+	_, err = NewOAuth2ClientCredentials(OAuth2Config{ClientID: "client"})
+	assert.ErrorIs(t, err, ErrCredentials)
+}
+
+func TestVolcengineSigner(t *testing.T) {
+	t.Setenv(common.VOLCENGINE_ACCESS_KEY, "ak")
+	t.Setenv(common.VOLCENGINE_SECRET_KEY, "sk")
+
+	req := httptest.NewRequest(http.MethodPost, "https://agent.example.com/a2a?x=1", strings.NewReader("{}"))
+	require.NoError(t, NewVolcengineSigner("cn-beijing", "vefaas").Apply(context.Background(), req))
+
+	authorization := req.Header.Get("Authorization")
+	assert.True(t, strings.HasPrefix(authorization, "HMAC-SHA256 Credential=ak/"))
+	assert.Contains(t, authorization, "/cn-beijing/vefaas/request")
+	assert.NotEmpty(t, req.Header.Get("X-Date"))
+	assert.NotEmpty(t, req.Header.Get("X-Content-Sha256"))
+
+	// the body is still readable after signing
+	body := make([]byte, 2)
+	n, _ := req.Body.Read(body)
+	assert.Equal(t, "{}", string(body[:n]))
+}
+
+func TestRetryTransport_SignsTheAttemptedHost(t *testing.T) {
+	t.Setenv(common.VOLCENGINE_ACCESS_KEY, "ak")
+	t.Setenv(common.VOLCENGINE_SECRET_KEY, "sk")
+	signer := NewVolcengineSigner("cn-beijing", "vefaas")
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	var verified atomic.Int32
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// re-sign what was received, with the host the request was sent to
+		check := r.Clone(r.Context())
+		check.URL.Scheme, check.URL.Host = "http", r.Host
+		require.NoError(t, signer.Apply(r.Context(), check))
+		if check.Header.Get("X-Date") != r.Header.Get("X-Date") {
+			// signed in another second, let the transport retry
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if check.Header.Get("Authorization") != r.Header.Get("Authorization") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		verified.Add(1)
+	}))
+	defer up.Close()
+
+	transport := newRetryTransport([]string{down.URL, up.URL}, signer, nil, nil, 0, 3, time.Millisecond)
+	client := &http.Client{Transport: transport}
+	resp, err := client.Post(down.URL+"/a2a", "application/json", strings.NewReader(`{"jsonrpc":"2.0"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, 1, verified.Load())
+}
+
+func TestCardCache(t *testing.T) {
+	var fetched, revalidated atomic.Int32
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/.well-known/agent-card.json", r.URL.Path)
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fetched.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode(a2a.AgentCard{Name: "remote", Capabilities: a2a.AgentCapabilities{Streaming: false}})
+	}))
+	defer server.Close()
+
+	cards := newCardCache(server.URL+"/", server.Client(), 20*time.Millisecond)
+	ctx := context.Background()
+	card, err := cards.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "remote", card.Name)
+	_, err = cards.Get(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, fetched.Load())
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = cards.Get(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, fetched.Load())
+	assert.EqualValues(t, 1, revalidated.Load())
+
+	// the cached card is kept while the remote agent is down
+	down.Store(true)
+	time.Sleep(30 * time.Millisecond)
+	card, err = cards.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "remote", card.Name)
+
+	_, err = newCardCache(server.URL, server.Client(), 0).Get(ctx)
+	assert.Error(t, err)
+}
+
+func TestNewVeRemoteAgent(t *testing.T) {
+	_, err := NewVeRemoteAgent(NewDefaultConfig().SetName("remote"))
+	assert.ErrorIs(t, err, ErrBaseUrlInvalid)
+	_, err = NewVeRemoteAgent(NewDefaultConfig().SetBaseUrl("http://127.0.0.1:1"))
+	assert.ErrorIs(t, err, ErrNameInvalid)
+
+	// the remote agent is not contacted on creation
+	config := NewDefaultConfig().SetName("remote").SetBaseUrl("http://127.0.0.1:1").SetApiKey("key")
+	ag, err := NewVeRemoteAgent(config)
+	require.NoError(t, err)
+	assert.Equal(t, "remote", ag.Name())
+	assert.Equal(t, "http://127.0.0.1:1", config.AgentCard.URL)
+	assert.NotNil(t, config.ClientFactory)
+}
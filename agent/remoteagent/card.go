@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/volcengine/veadk-go/log"
+)
+
+const DefaultCardTTL = 5 * time.Minute
+
+// cardCache keeps the agent card of the remote agent, refreshed once ttl elapsed. The card
+// is revalidated with its ETag, and the last known card is kept while the remote agent is down.
+type cardCache struct {
+	url    string
+	client *http.Client
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	card    *a2a.AgentCard
+	etag    string
+	fetched time.Time
+	// static cards, provided in the config, are never refreshed
+	static bool
+}
+
+func newCardCache(baseUrl string, client *http.Client, ttl time.Duration) *cardCache {
+	if ttl <= 0 {
+		ttl = DefaultCardTTL
+	}
+	return &cardCache{
+		url:    strings.TrimSuffix(baseUrl, "/") + a2asrv.WellKnownAgentCardPath,
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func newStaticCardCache(card *a2a.AgentCard) *cardCache {
+	return &cardCache{card: card, static: true}
+}
+
+func (c *cardCache) Get(ctx context.Context) (*a2a.AgentCard, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.static || (c.card != nil && time.Since(c.fetched) < c.ttl) {
+		return c.card, nil
+	}
+	if err := c.refresh(ctx); err != nil {
+		if c.card != nil {
+			log.Warnf("refresh agent card from %s failed, using the cached card: %v", c.url, err)
+			return c.card, nil
+		}
+		return nil, err
+	}
+	return c.card, nil
+}
+
+func (c *cardCache) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.card != nil && c.etag != "" {
+		req.Header.Set("If-None-Match", c.etag)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch agent card: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		c.fetched = time.Now()
+		return nil
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("fetch agent card: unexpected HTTP status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("fetch agent card: %w", err)
+	}
+	var card a2a.AgentCard
+	if err := json.Unmarshal(body, &card); err != nil {
+		return fmt.Errorf("parse agent card: %w", err)
+	}
+	c.card, c.etag, c.fetched = &card, resp.Header.Get("ETag"), time.Now()
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volcengine/veadk-go/auth/veauth"
+	"github.com/volcengine/veadk-go/integrations/ve_sign"
+)
+
+var ErrCredentials = errors.New("remote agent credentials unavailable")
+
+// CredentialProvider authenticates the calls to a remote agent. It is applied to every
+// attempt, so that rotated credentials are picked up without restarting.
+type CredentialProvider interface {
+	Apply(ctx context.Context, req *http.Request) error
+}
+
+// Invalidator is implemented by providers caching credentials. Invalidate is called when
+// the remote agent rejects them with 401, before the call is retried once.
+type Invalidator interface {
+	Invalidate()
+}
+
+// CredentialProviderFunc adapts a function to CredentialProvider.
+type CredentialProviderFunc func(ctx context.Context, req *http.Request) error
+
+func (f CredentialProviderFunc) Apply(ctx context.Context, req *http.Request) error {
+	return f(ctx, req)
+}
+
+// BearerToken sends a static token, like Config.ApiKey.
+func BearerToken(token string) CredentialProvider {
+	return CredentialProviderFunc(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+const (
+	// tokenExpiryMargin renews OAuth2 tokens before they expire
+	tokenExpiryMargin = 30 * time.Second
+	defaultTokenTTL   = 5 * time.Minute
+)
+
+// OAuth2Config configures the OAuth2 client credentials grant.
+type OAuth2Config struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// Audience is sent as the audience parameter when set, as required by some providers.
+	Audience   string
+	HTTPClient *http.Client
+}
+
+// OAuth2ClientCredentials sends tokens obtained with the client credentials grant, renewed
+// before they expire or when the remote agent rejects them.
+type OAuth2ClientCredentials struct {
+	config OAuth2Config
+	client *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+var _ Invalidator = (*OAuth2ClientCredentials)(nil)
+
+func NewOAuth2ClientCredentials(config OAuth2Config) (*OAuth2ClientCredentials, error) {
+	if config.TokenURL == "" || config.ClientID == "" {
+		return nil, fmt.Errorf("%w: oauth2 token url and client id are required", ErrCredentials)
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &OAuth2ClientCredentials{config: config, client: client}, nil
+}
+
+func (o *OAuth2ClientCredentials) Apply(ctx context.Context, req *http.Request) error {
+	token, err := o.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (o *OAuth2ClientCredentials) Invalidate() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.token = ""
+}
+
+// Token returns the cached access token, or requests a new one.
+func (o *OAuth2ClientCredentials) Token(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.token != "" && time.Now().Before(o.expires) {
+		return o.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(o.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.config.Scopes, " "))
+	}
+	if o.config.Audience != "" {
+		form.Set("audience", o.config.Audience)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(o.config.ClientID), url.QueryEscape(o.config.ClientSecret))
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: oauth2 token request failed: %w", ErrCredentials, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("%w: read oauth2 token response: %w", ErrCredentials, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: oauth2 token endpoint returned %s: %s", ErrCredentials, resp.Status, body)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("%w: invalid oauth2 token response", ErrCredentials)
+	}
+
+	ttl := defaultTokenTTL
+	if token.ExpiresIn > 0 {
+		ttl = time.Duration(token.ExpiresIn) * time.Second
+	}
+	o.token = token.AccessToken
+	o.expires = time.Now().Add(ttl - min(tokenExpiryMargin, ttl/2))
+	return o.token, nil
+}
+
+// VolcengineSigner signs the calls with the Volcengine identity of the process, the access
+// key from the environment or the config, or the credentials of the VeFaaS IAM role, which are
+// read again for every call as they rotate.
+type VolcengineSigner struct {
+	Region  string
+	Service string
+}
+
+func NewVolcengineSigner(region, service string) *VolcengineSigner {
+	return &VolcengineSigner{Region: region, Service: service}
+}
+
+func (s *VolcengineSigner) Apply(_ context.Context, req *http.Request) error {
+	ak, sk, sessionToken := veauth.GetAuthInfo()
+	if err := ve_sign.SignRequest(req, ak, sk, sessionToken, s.Region, s.Service); err != nil {
+		return fmt.Errorf("%w: %w", ErrCredentials, err)
+	}
+	return nil
+}
+
+// NewMTLSConfig loads a client certificate for mutual TLS, and the CA verifying the remote
+// agent when caFile is set, for Config.TLSConfig.
+func NewMTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load client certificate: %w", err)
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2aclient"
+	"github.com/volcengine/veadk-go/log"
+)
+
+const (
+	DefaultMaxAttempts  = 3
+	DefaultRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff     = 10 * time.Second
+)
+
+// errCallTimeout ends the calls which exceeded the call timeout.
+var errCallTimeout = errors.New("remote agent call timeout")
+
+// retryTransport sends the calls to the remote agent, falling back to the next base URL and
+// retrying with backoff once every base URL failed. Requests are addressed to the first base
+// URL and rewritten to the others. Only the calls which never reached the remote agent are
+// retried: failed dials, and the responses of overloaded or unreachable upstreams, so that
+// the remote agent doesn't run twice.
+type retryTransport struct {
+	next        http.RoundTripper
+	baseUrls    []string
+	credentials CredentialProvider
+	callTimeout time.Duration
+	maxAttempts int
+	backoff     time.Duration
+	// preferred is the index of the last base URL which answered
+	preferred atomic.Int32
+}
+
+func newRetryTransport(baseUrls []string, credentials CredentialProvider, next *http.Transport, tlsConfig *tls.Config, callTimeout time.Duration, maxAttempts int, backoff time.Duration) *retryTransport {
+	if next == nil {
+		next = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if tlsConfig != nil {
+		next.TLSClientConfig = tlsConfig
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	return &retryTransport{
+		next:        next,
+		baseUrls:    baseUrls,
+		credentials: credentials,
+		callTimeout: callTimeout,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+	}
+}
+
+// RoundTrip bounds the call by the call timeout: the whole call, or only the wait for the
+// response headers of streamed calls, whose events may last longer.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.callTimeout <= 0 {
+		return t.roundTrip(req)
+	}
+	ctx, cancel := context.WithCancelCause(req.Context())
+	timer := time.AfterFunc(t.callTimeout, func() { cancel(errCallTimeout) })
+	resp, err := t.roundTrip(req.WithContext(ctx))
+	if err != nil {
+		timer.Stop()
+		cancel(nil)
+		if errors.Is(context.Cause(ctx), errCallTimeout) {
+			return nil, fmt.Errorf("%w after %s", errCallTimeout, t.callTimeout)
+		}
+		return nil, err
+	}
+	if req.Header.Get("Accept") == "text/event-stream" {
+		timer.Stop()
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: func() {
+		timer.Stop()
+		cancel(nil)
+	}}
+	return resp, nil
+}
+
+func (t *retryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	target := req.URL.String()
+	start := int(t.preferred.Load())
+	reauthenticated := false
+
+	var lastErr error
+	for attempt := 0; attempt < t.maxAttempts; attempt++ {
+		if attempt > 0 && attempt%len(t.baseUrls) == 0 {
+			// every base URL failed, wait before the next round
+			if err := sleep(ctx, t.retryDelay(attempt/len(t.baseUrls), lastErr)); err != nil {
+				return nil, err
+			}
+		}
+		index := (start + attempt) % len(t.baseUrls)
+		resp, err := t.send(req, t.rewrite(target, index))
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			if invalidator, ok := t.credentials.(Invalidator); ok {
+				// the credentials may have been rotated, renew them and try again
+				reauthenticated = true
+				invalidator.Invalidate()
+				drain(resp)
+				attempt--
+				continue
+			}
+		}
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			t.preferred.Store(int32(index))
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			if err == nil {
+				drain(resp)
+			}
+			return nil, ctx.Err()
+		}
+		if err != nil && !notSent(err) {
+			return nil, err
+		}
+		if err == nil {
+			if attempt == t.maxAttempts-1 {
+				return resp, nil
+			}
+			lastErr = &statusError{status: resp.StatusCode, retryAfter: retryAfter(resp)}
+			drain(resp)
+		} else {
+			lastErr = err
+		}
+		log.WarnContext(ctx, "call to remote agent failed", "url", t.baseUrls[index], "attempt", attempt+1, "max_attempts", t.maxAttempts, "error", lastErr)
+	}
+	return nil, lastErr
+}
+
+func (t *retryTransport) send(req *http.Request, target string) (*http.Response, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	attempt := req.Clone(req.Context())
+	attempt.URL = u
+	// the caller's Host names the first base URL, signers cover the host the attempt goes to
+	attempt.Host = u.Host
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, errors.New("request body can't be replayed")
+		}
+		if attempt.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	if t.credentials != nil {
+		if err := t.credentials.Apply(req.Context(), attempt); err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(attempt)
+}
+
+// rewrite addresses the request to the base URL at index.
+func (t *retryTransport) rewrite(target string, index int) string {
+	if index == 0 || !strings.HasPrefix(target, t.baseUrls[0]) {
+		return target
+	}
+	return t.baseUrls[index] + strings.TrimPrefix(target, t.baseUrls[0])
+}
+
+func (t *retryTransport) retryDelay(round int, lastErr error) time.Duration {
+	var statusErr *statusError
+	if errors.As(lastErr, &statusErr) && statusErr.retryAfter > 0 {
+		return min(statusErr.retryAfter, maxRetryBackoff)
+	}
+	return min(t.backoff<<(round-1), maxRetryBackoff)
+}
+
+type statusError struct {
+	status     int
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d", e.status)
+}
+
+// notSent reports whether the request failed before it could reach the remote agent.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial" || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
+
+// cancelBody ends the call once its response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel func()
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// drain discards the response so that its connection can be reused.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cardTransport serves the agent card from the cache, and sends the messages without
+// streaming when the card of the remote agent doesn't support it.
+type cardTransport struct {
+	a2aclient.Transport
+	cards *cardCache
+}
+
+func (t *cardTransport) GetAgentCard(ctx context.Context) (*a2a.AgentCard, error) {
+	return t.cards.Get(ctx)
+}
+
+func (t *cardTransport) SendStreamingMessage(ctx context.Context, message *a2a.MessageSendParams) iter.Seq2[a2a.Event, error] {
+	if card, err := t.cards.Get(ctx); err == nil && !card.Capabilities.Streaming {
+		return func(yield func(a2a.Event, error) bool) {
+			yield(t.SendMessage(ctx, message))
+		}
+	}
+	return t.Transport.SendStreamingMessage(ctx, message)
+}
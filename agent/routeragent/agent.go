@@ -0,0 +1,214 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routeragent
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/volcengine/veadk-go/common"
+	"github.com/volcengine/veadk-go/log"
+	"github.com/volcengine/veadk-go/observability"
+	"github.com/volcengine/veadk-go/prompts"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// Reasons of a route.
+const (
+	// ReasonClassified routes to the agent picked by the classifier.
+	ReasonClassified = "classified"
+	// ReasonSticky keeps the agent of the previous turn when the classifier is unsure.
+	ReasonSticky = "sticky"
+	// ReasonDefault routes to the default agent when the classifier is unsure.
+	ReasonDefault = "default"
+)
+
+var (
+	ErrNoClassifier   = errors.New("router agent requires a classifier")
+	ErrNoSubAgents    = errors.New("router agent requires sub agents")
+	ErrUnknownDefault = errors.New("default agent is not a sub agent")
+)
+
+// Config defines the configuration for a veRouterAgent.
+type Config struct {
+	// Basic agent setup. The sub agents are the routing targets.
+	AgentConfig agent.Config
+
+	// Classifier picks the sub agent of each user turn.
+	Classifier Classifier
+
+	// DefaultAgent is the sub agent of turns the classifier is unsure about, the first
+	// sub agent when empty.
+	DefaultAgent string
+
+	// MinConfidence is the confidence below which the classifier is unsure.
+	MinConfidence float64
+
+	// Sticky keeps the agent of the previous turn of the session, instead of the
+	// default agent, when the classifier is unsure.
+	Sticky bool
+}
+
+// New creates a RouterAgent.
+//
+// RouterAgent classifies each user turn and runs one of its sub agents with it,
+// without asking the model to call transfer_to_agent.
+//
+// Each route is stored in the session state under StateKey, and its agent,
+// confidence and reason are recorded on the agent span.
+func New(cfg Config) (agent.Agent, error) {
+	if cfg.AgentConfig.Run != nil {
+		return nil, fmt.Errorf("RouterAgent doesn't allow custom Run implementations")
+	}
+	if cfg.Classifier == nil {
+		return nil, ErrNoClassifier
+	}
+	if len(cfg.AgentConfig.SubAgents) == 0 {
+		return nil, ErrNoSubAgents
+	}
+
+	if cfg.AgentConfig.Name == "" {
+		cfg.AgentConfig.Name = common.DEFAULT_ROUTERAGENT_NAME
+	}
+	if cfg.AgentConfig.Description == "" {
+		cfg.AgentConfig.Description = prompts.DEFAULT_DESCRIPTION
+	}
+	if cfg.DefaultAgent == "" {
+		cfg.DefaultAgent = cfg.AgentConfig.SubAgents[0].Name()
+	}
+	if findSubAgent(cfg.AgentConfig.SubAgents, cfg.DefaultAgent) == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDefault, cfg.DefaultAgent)
+	}
+
+	routerAgentImpl := &routerAgent{
+		classifier:    cfg.Classifier,
+		defaultAgent:  cfg.DefaultAgent,
+		minConfidence: cfg.MinConfidence,
+		sticky:        cfg.Sticky,
+		stateKey:      StateKey(cfg.AgentConfig.Name),
+	}
+	cfg.AgentConfig.Run = routerAgentImpl.Run
+
+	return agent.New(cfg.AgentConfig)
+}
+
+// StateKey is the session state key of the last route of the router agent name.
+func StateKey(name string) string {
+	return "routeragent:" + name
+}
+
+type routerAgent struct {
+	classifier    Classifier
+	defaultAgent  string
+	minConfidence float64
+	sticky        bool
+	stateKey      string
+}
+
+func (a *routerAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		subAgents := ctx.Agent().SubAgents()
+		route := a.route(ctx, subAgents)
+		observability.SetAttributes(observability.GetSpanFromContext(ctx),
+			attribute.String(observability.AttrRouterAgent, route.Agent),
+			attribute.Float64(observability.AttrRouterConfidence, route.Confidence),
+			attribute.String(observability.AttrRouterReason, route.Reason))
+
+		event := session.NewEvent(ctx.InvocationID())
+		event.Actions.StateDelta[a.stateKey] = route.state()
+		if !yield(event, nil) {
+			return
+		}
+
+		for event, err := range findSubAgent(subAgents, route.Agent).Run(ctx) {
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
+
+// route classifies the user turn and falls back when the classifier is unsure or fails.
+func (a *routerAgent) route(ctx agent.InvocationContext, subAgents []agent.Agent) Route {
+	candidates := make([]Candidate, 0, len(subAgents))
+	for _, subAgent := range subAgents {
+		candidates = append(candidates, Candidate{Name: subAgent.Name(), Description: subAgent.Description()})
+	}
+
+	var route Route
+	if text := userText(ctx.UserContent()); text != "" {
+		classified, err := a.classifier.Classify(ctx, text, candidates)
+		switch {
+		case err != nil:
+			log.WarnContext(ctx, "router agent failed to classify the user turn", "agent", ctx.Agent().Name(), "error", err)
+		case findSubAgent(subAgents, classified.Agent) == nil:
+			log.WarnContext(ctx, "router agent classified the user turn to an unknown agent", "agent", ctx.Agent().Name(), "target", classified.Agent)
+		default:
+			route = classified
+		}
+	}
+	if route.Agent != "" && route.Confidence >= a.minConfidence {
+		route.Reason = ReasonClassified
+		return route
+	}
+
+	if a.sticky {
+		if previous, ok := a.previous(ctx); ok && findSubAgent(subAgents, previous) != nil {
+			return Route{Agent: previous, Confidence: route.Confidence, Reason: ReasonSticky}
+		}
+	}
+	return Route{Agent: a.defaultAgent, Confidence: route.Confidence, Reason: ReasonDefault}
+}
+
+// previous returns the agent of the last route of the session.
+func (a *routerAgent) previous(ctx agent.InvocationContext) (string, bool) {
+	value, err := ctx.Session().State().Get(a.stateKey)
+	if err != nil {
+		return "", false
+	}
+	state, ok := value.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	name, ok := state["agent"].(string)
+	return name, ok
+}
+
+func findSubAgent(subAgents []agent.Agent, name string) agent.Agent {
+	for _, subAgent := range subAgents {
+		if subAgent.Name() == name {
+			return subAgent
+		}
+	}
+	return nil
+}
+
+func userText(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part != nil && part.Text != "" && !part.Thought {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n"))
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routeragent
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/model"
+	"google.golang.org/adk/agent"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+func newWorker(t *testing.T, name, description string) agent.Agent {
+	t.Helper()
+	worker, err := agent.New(agent.Config{
+		Name:        name,
+		Description: description,
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				event := session.NewEvent(ctx.InvocationID())
+				event.Content = genai.NewContentFromText(name+" here", genai.RoleModel)
+				yield(event, nil)
+			}
+		},
+	})
+	require.NoError(t, err)
+	return worker
+}
+
+type routerRunner struct {
+	t        *testing.T
+	runner   *runner.Runner
+	sessions session.Service
+}
+
+func newRouterRunner(t *testing.T, cfg Config) *routerRunner {
+	t.Helper()
+	cfg.AgentConfig.Name = "router"
+	cfg.AgentConfig.SubAgents = []agent.Agent{
+		newWorker(t, "weather", "Answers questions about the weather."),
+		newWorker(t, "billing", "Answers questions about invoices and payments."),
+	}
+	router, err := New(cfg)
+	require.NoError(t, err)
+
+	sessions := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: router, SessionService: sessions, AutoCreateSession: true})
+	require.NoError(t, err)
+	return &routerRunner{t: t, runner: r, sessions: sessions}
+}
+
+// send runs a turn and returns the author of the reply and the recorded route.
+func (r *routerRunner) send(text string) (string, map[string]any) {
+	author := ""
+	for event, err := range r.runner.Run(context.Background(), "user", "session", genai.NewContentFromText(text, genai.RoleUser), agent.RunConfig{}) {
+		require.NoError(r.t, err)
+		if event.Content != nil {
+			author = event.Author
+		}
+	}
+	resp, err := r.sessions.Get(context.Background(), &session.GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	require.NoError(r.t, err)
+	route, err := resp.Session.State().Get(StateKey("router"))
+	require.NoError(r.t, err)
+	return author, route.(map[string]any)
+}
+
+func TestRouterAgent(t *testing.T) {
+	classifier := NewKeywordClassifier(
+		KeywordRule{Agent: "weather", Keywords: []string{"rain", "sunny"}},
+		KeywordRule{Agent: "billing", Keywords: []string{"invoice", "refund"}},
+	)
+
+	t.Run("classified and default", func(t *testing.T) {
+		r := newRouterRunner(t, Config{Classifier: classifier, DefaultAgent: "billing", MinConfidence: 0.6})
+
+		author, route := r.send("Will it rain tomorrow?")
+		assert.Equal(t, "weather", author)
+		assert.Equal(t, map[string]any{"agent": "weather", "confidence": 1.0, "reason": ReasonClassified}, route)
+
+		author, route = r.send("Hello")
+		assert.Equal(t, "billing", author)
+		assert.Equal(t, ReasonDefault, route["reason"])
+
+		// one keyword of each agent is not confident enough
+		author, route = r.send("Do I get a refund when it rains?")
+		assert.Equal(t, "billing", author)
+		assert.Equal(t, map[string]any{"agent": "billing", "confidence": 0.5, "reason": ReasonDefault}, route)
+	})
+
+	t.Run("sticky", func(t *testing.T) {
+		r := newRouterRunner(t, Config{Classifier: classifier, Sticky: true})
+
+		author, _ := r.send("Send me the invoice")
+		assert.Equal(t, "billing", author)
+
+		author, route := r.send("And the one of last month?")
+		assert.Equal(t, "billing", author)
+		assert.Equal(t, ReasonSticky, route["reason"])
+
+		author, _ = r.send("Is it sunny outside?")
+		assert.Equal(t, "weather", author)
+	})
+
+	t.Run("classifier errors fall back", func(t *testing.T) {
+		failing := ClassifierFunc(func(context.Context, string, []Candidate) (Route, error) {
+			return Route{Agent: "nope", Confidence: 1}, nil
+		})
+		r := newRouterRunner(t, Config{Classifier: failing})
+
+		author, route := r.send("Hello")
+		assert.Equal(t, "weather", author)
+		assert.Equal(t, ReasonDefault, route["reason"])
+	})
+}
+
+func TestNew_Errors(t *testing.T) {
+	worker := newWorker(t, "weather", "")
+	classifier := NewKeywordClassifier()
+
+	_, err := New(Config{AgentConfig: agent.Config{SubAgents: []agent.Agent{worker}}})
+	assert.ErrorIs(t, err, ErrNoClassifier)
+	_, err = New(Config{Classifier: classifier})
+	assert.ErrorIs(t, err, ErrNoSubAgents)
+	_, err = New(Config{Classifier: classifier, DefaultAgent: "billing", AgentConfig: agent.Config{SubAgents: []agent.Agent{worker}}})
+	assert.ErrorIs(t, err, ErrUnknownDefault)
+}
+
+type fakeEmbedder struct {
+	calls int
+}
+
+// EmbedTexts embeds texts by whether they are about the weather or about money.
+func (e *fakeEmbedder) EmbedTexts(_ context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error) {
+	e.calls++
+	resp := &model.EmbeddingResponse{}
+	for _, text := range req.Texts {
+		vector := []float32{0.1, 0.1}
+		if strings.Contains(text, "weather") || strings.Contains(text, "rain") {
+			vector[0] = 1
+		}
+		if strings.Contains(text, "invoice") {
+			vector[1] = 1
+		}
+		resp.Embeddings = append(resp.Embeddings, vector)
+	}
+	return resp, nil
+}
+
+func TestEmbeddingClassifier(t *testing.T) {
+	embedder := &fakeEmbedder{}
+	classifier := NewEmbeddingClassifier(embedder)
+	candidates := []Candidate{
+		{Name: "weather", Description: "Answers questions about the weather."},
+		{Name: "billing", Description: "Answers questions about invoices and payments."},
+	}
+
+	route, err := classifier.Classify(context.Background(), "Will it rain?", candidates)
+	require.NoError(t, err)
+	assert.Equal(t, "weather", route.Agent)
+	assert.InDelta(t, 1, route.Confidence, 0.001)
+
+	route, err = classifier.Classify(context.Background(), "Where is my invoice?", candidates)
+	require.NoError(t, err)
+	assert.Equal(t, "billing", route.Agent)
+	assert.Equal(t, 2, embedder.calls)
+}
+
+type fakeLLM struct {
+	reply string
+	req   *adkmodel.LLMRequest
+}
+
+func (m *fakeLLM) Name() string { return "fake" }
+
+func (m *fakeLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	m.req = req
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText(m.reply, genai.RoleModel)}, nil)
+	}
+}
+
+func TestLLMClassifier(t *testing.T) {
+	llm := &fakeLLM{reply: "```json\n{\"agent\": \"billing\", \"confidence\": 1.2}\n```"}
+	classifier := NewLLMClassifier(llm)
+	candidates := []Candidate{{Name: "weather", Description: "Weather."}, {Name: "billing", Description: "Invoices."}}
+
+	route, err := classifier.Classify(context.Background(), "Where is my invoice?", candidates)
+	require.NoError(t, err)
+	assert.Equal(t, Route{Agent: "billing", Confidence: 1}, route)
+	assert.Equal(t, []string{"", "weather", "billing"}, llm.req.Config.ResponseSchema.Properties["agent"].Enum)
+	assert.Contains(t, llm.req.Config.SystemInstruction.Parts[0].Text, "- billing: Invoices.")
+
+	llm.reply = "billing"
+	_, err = classifier.Classify(context.Background(), "Where is my invoice?", candidates)
+	assert.ErrorIs(t, err, ErrClassification)
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routeragent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/volcengine/veadk-go/model"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+var ErrClassification = errors.New("failed to classify")
+
+// Candidate is a sub agent a user turn can be routed to.
+type Candidate struct {
+	Name        string
+	Description string
+}
+
+// Route is the sub agent picked for a user turn.
+type Route struct {
+	Agent string
+	// Confidence is between 0 and 1.
+	Confidence float64
+	Reason     string
+}
+
+func (r Route) state() map[string]any {
+	return map[string]any{"agent": r.Agent, "confidence": r.Confidence, "reason": r.Reason}
+}
+
+// Classifier picks the candidate of a user turn. It returns an empty Agent when no
+// candidate fits.
+type Classifier interface {
+	Classify(ctx context.Context, text string, candidates []Candidate) (Route, error)
+}
+
+// ClassifierFunc adapts a function to a Classifier.
+type ClassifierFunc func(ctx context.Context, text string, candidates []Candidate) (Route, error)
+
+func (f ClassifierFunc) Classify(ctx context.Context, text string, candidates []Candidate) (Route, error) {
+	return f(ctx, text, candidates)
+}
+
+// KeywordRule routes the user turns containing any of Keywords to Agent.
+type KeywordRule struct {
+	Agent    string
+	Keywords []string
+}
+
+// KeywordClassifier routes to the rule with the most keywords in the user turn,
+// case-insensitively. The confidence is its share of all matched keywords, and ties
+// go to the earlier rule.
+type KeywordClassifier struct {
+	rules []KeywordRule
+}
+
+// NewKeywordClassifier creates a KeywordClassifier.
+func NewKeywordClassifier(rules ...KeywordRule) *KeywordClassifier {
+	return &KeywordClassifier{rules: rules}
+}
+
+func (c *KeywordClassifier) Classify(_ context.Context, text string, _ []Candidate) (Route, error) {
+	text = strings.ToLower(text)
+	var best Route
+	bestMatches, total := 0, 0
+	for _, rule := range c.rules {
+		matches := 0
+		for _, keyword := range rule.Keywords {
+			if keyword != "" && strings.Contains(text, strings.ToLower(keyword)) {
+				matches++
+			}
+		}
+		total += matches
+		if matches > bestMatches {
+			best, bestMatches = Route{Agent: rule.Agent}, matches
+		}
+	}
+	if total > 0 {
+		best.Confidence = float64(bestMatches) / float64(total)
+	}
+	return best, nil
+}
+
+// EmbeddingClassifier routes to the candidate whose description is most similar to
+// the user turn. The confidence is the cosine similarity. Description embeddings are
+// cached.
+type EmbeddingClassifier struct {
+	embedder model.Embedder
+
+	mu      sync.Mutex
+	vectors map[Candidate][]float32
+}
+
+// NewEmbeddingClassifier creates an EmbeddingClassifier.
+func NewEmbeddingClassifier(embedder model.Embedder) *EmbeddingClassifier {
+	return &EmbeddingClassifier{embedder: embedder, vectors: map[Candidate][]float32{}}
+}
+
+func (c *EmbeddingClassifier) Classify(ctx context.Context, text string, candidates []Candidate) (Route, error) {
+	c.mu.Lock()
+	texts := []string{text}
+	var missing []Candidate
+	for _, candidate := range candidates {
+		if _, ok := c.vectors[candidate]; !ok {
+			missing = append(missing, candidate)
+			texts = append(texts, candidate.Name+": "+candidate.Description)
+		}
+	}
+	c.mu.Unlock()
+
+	resp, err := c.embedder.EmbedTexts(ctx, &model.EmbeddingRequest{Texts: texts})
+	if err != nil {
+		return Route{}, fmt.Errorf("%w: %w", ErrClassification, err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return Route{}, fmt.Errorf("%w: got %d embeddings for %d texts", ErrClassification, len(resp.Embeddings), len(texts))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, candidate := range missing {
+		c.vectors[candidate] = resp.Embeddings[i+1]
+	}
+	var best Route
+	for _, candidate := range candidates {
+		if similarity := cosineSimilarity(resp.Embeddings[0], c.vectors[candidate]); similarity > best.Confidence {
+			best = Route{Agent: candidate.Name, Confidence: similarity}
+		}
+	}
+	return best, nil
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+const llmClassifierInstruction = `You route user messages to the agent best suited to handle them.
+
+Agents:
+%s
+Reply with a JSON object with the fields "agent", the name of one of the agents or "" if none fits, and "confidence", a number between 0 and 1.`
+
+// LLMClassifier asks a model to pick the candidate, with a response schema limited
+// to the candidate names.
+type LLMClassifier struct {
+	llm adkmodel.LLM
+}
+
+// NewLLMClassifier creates an LLMClassifier. A small, fast model is usually enough.
+func NewLLMClassifier(llm adkmodel.LLM) *LLMClassifier {
+	return &LLMClassifier{llm: llm}
+}
+
+func (c *LLMClassifier) Classify(ctx context.Context, text string, candidates []Candidate) (Route, error) {
+	var agents strings.Builder
+	names := []string{""}
+	for _, candidate := range candidates {
+		fmt.Fprintf(&agents, "- %s: %s\n", candidate.Name, candidate.Description)
+		names = append(names, candidate.Name)
+	}
+
+	req := &adkmodel.LLMRequest{
+		Model:    c.llm.Name(),
+		Contents: []*genai.Content{genai.NewContentFromText(text, genai.RoleUser)},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(fmt.Sprintf(llmClassifierInstruction, agents.String()), genai.RoleUser),
+			Temperature:       genai.Ptr[float32](0),
+			ResponseMIMEType:  "application/json",
+			ResponseSchema: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"agent":      {Type: genai.TypeString, Enum: names},
+					"confidence": {Type: genai.TypeNumber},
+				},
+				Required: []string{"agent", "confidence"},
+			},
+		},
+	}
+
+	var reply strings.Builder
+	for resp, err := range c.llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return Route{}, fmt.Errorf("%w: %w", ErrClassification, err)
+		}
+		if resp == nil || resp.Partial || resp.Content == nil {
+			continue
+		}
+		for _, part := range resp.Content.Parts {
+			if part != nil && !part.Thought {
+				reply.WriteString(part.Text)
+			}
+		}
+	}
+
+	var decision struct {
+		Agent      string  `json:"agent"`
+		Confidence float64 `json:"confidence"`
+	}
+	raw := strings.TrimSpace(reply.String())
+	raw = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(raw, "```json"), "```"), "```")
+	if err := json.Unmarshal([]byte(raw), &decision); err != nil {
+		return Route{}, fmt.Errorf("%w: invalid model reply %q: %w", ErrClassification, reply.String(), err)
+	}
+	return Route{Agent: decision.Agent, Confidence: math.Max(0, math.Min(1, decision.Confidence))}, nil
+}
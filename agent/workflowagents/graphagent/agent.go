@@ -0,0 +1,370 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/volcengine/veadk-go/common"
+	"github.com/volcengine/veadk-go/log"
+	"github.com/volcengine/veadk-go/observability"
+	"github.com/volcengine/veadk-go/prompts"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+// DefaultMaxSteps is the number of steps after which a graph run stops.
+const DefaultMaxSteps = 25
+
+var ErrMaxSteps = errors.New("graph exceeded its max steps")
+
+// Config defines the configuration for a GraphAgent.
+type Config struct {
+	// Basic agent setup. The node agents are added to the sub-agents.
+	AgentConfig agent.Config
+
+	Graph Graph
+
+	// MaxSteps limits the cycles of the graph, DefaultMaxSteps when zero.
+	MaxSteps int
+}
+
+// New creates a GraphAgent.
+//
+// GraphAgent runs the nodes of its graph step by step, following the edges whose
+// conditions hold, until no edge is left to take or a node escalates. Nodes of the
+// same step run in parallel, each in its own branch like the sub-agents of a
+// ParallelAgent.
+//
+// The nodes of the next step are checkpointed in the session state under StateKey
+// before they run, so that the next run of the agent resumes there after a crash
+// or an error. The checkpoint is cleared when the graph ends.
+//
+// Each node run is traced as a graph_step span with the step index and the node.
+func New(cfg Config) (agent.Agent, error) {
+	if cfg.AgentConfig.Run != nil {
+		return nil, fmt.Errorf("GraphAgent doesn't allow custom Run implementations")
+	}
+	if err := cfg.Graph.Validate(); err != nil {
+		return nil, err
+	}
+
+	if cfg.AgentConfig.Name == "" {
+		cfg.AgentConfig.Name = common.DEFAULT_GRAPHAGENT_NAME
+	}
+	if cfg.AgentConfig.Description == "" {
+		cfg.AgentConfig.Description = prompts.DEFAULT_DESCRIPTION
+	}
+	if cfg.MaxSteps <= 0 {
+		cfg.MaxSteps = DefaultMaxSteps
+	}
+
+	graphAgentImpl := &graphAgent{
+		nodes:    map[string]Node{},
+		edges:    map[string][]Edge{},
+		start:    cfg.Graph.Start,
+		maxSteps: cfg.MaxSteps,
+		stateKey: StateKey(cfg.AgentConfig.Name),
+	}
+	subAgents := slices.Clone(cfg.AgentConfig.SubAgents)
+	for _, node := range cfg.Graph.Nodes {
+		graphAgentImpl.nodes[node.Name] = node
+		if node.Agent != nil && !slices.Contains(subAgents, node.Agent) {
+			subAgents = append(subAgents, node.Agent)
+		}
+	}
+	for _, edge := range cfg.Graph.Edges {
+		graphAgentImpl.edges[edge.From] = append(graphAgentImpl.edges[edge.From], edge)
+	}
+	cfg.AgentConfig.SubAgents = subAgents
+	cfg.AgentConfig.Run = graphAgentImpl.Run
+
+	return agent.New(cfg.AgentConfig)
+}
+
+// StateKey is the session state key of the checkpoint of the graph agent name.
+func StateKey(name string) string {
+	return "graphagent:" + name
+}
+
+type graphAgent struct {
+	nodes    map[string]Node
+	edges    map[string][]Edge
+	start    string
+	maxSteps int
+	stateKey string
+}
+
+func (a *graphAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		nodes, step := []string{a.start}, 0
+		if checkpoint, ok := a.checkpoint(ctx); ok {
+			log.Infof("graph agent %s resumes at step %d with nodes %v", ctx.Agent().Name(), checkpoint.step, checkpoint.nodes)
+			nodes, step = checkpoint.nodes, checkpoint.step
+		}
+
+		for len(nodes) > 0 {
+			if step >= a.maxSteps {
+				observability.RecordMaxIterationsReached(ctx, uint(a.maxSteps))
+				if yield(a.checkpointEvent(ctx, nil, step), nil) {
+					yield(nil, fmt.Errorf("%w: %d", ErrMaxSteps, a.maxSteps))
+				}
+				return
+			}
+			if !yield(a.checkpointEvent(ctx, nodes, step), nil) {
+				return
+			}
+
+			lastEvents, escalated, ok := a.runStep(ctx, nodes, step, yield)
+			if !ok {
+				return
+			}
+			if escalated {
+				break
+			}
+			nodes = a.next(ctx, nodes, lastEvents)
+			step++
+		}
+		yield(a.checkpointEvent(ctx, nil, step), nil)
+	}
+}
+
+// runStep runs the nodes of a step and returns their last events, whether one of them
+// escalated and whether the run goes on.
+func (a *graphAgent) runStep(ctx agent.InvocationContext, nodes []string, step int, yield func(*session.Event, error) bool) (map[string]*session.Event, bool, bool) {
+	if len(nodes) == 1 {
+		last, escalated, ok := a.runNode(ctx, a.nodes[nodes[0]], step, "", yield)
+		return map[string]*session.Event{nodes[0]: last}, escalated, ok
+	}
+
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		lastEvents = map[string]*session.Event{}
+		escalated  bool
+		results    = make(chan result)
+		done       = make(chan struct{})
+	)
+	defer close(done)
+
+	for _, name := range nodes {
+		branch := fmt.Sprintf("%s.%s", ctx.Agent().Name(), name)
+		if ctx.Branch() != "" {
+			branch = fmt.Sprintf("%s.%s", ctx.Branch(), branch)
+		}
+		emit := func(event *session.Event, err error) bool {
+			ack := make(chan struct{})
+			select {
+			case results <- result{event: event, err: err, ackChan: ack}:
+			case <-done:
+				return false
+			}
+			// wait for the runner to append the event before the node goes on
+			select {
+			case <-ack:
+				return true
+			case <-done:
+				return false
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last, nodeEscalated, _ := a.runNode(ctx, a.nodes[name], step, branch, emit)
+			mu.Lock()
+			defer mu.Unlock()
+			lastEvents[name] = last
+			escalated = escalated || nodeEscalated
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for res := range results {
+		ok := yield(res.event, res.err)
+		close(res.ackChan)
+		if !ok || res.err != nil {
+			return nil, false, false
+		}
+	}
+	return lastEvents, escalated, true
+}
+
+type result struct {
+	event   *session.Event
+	err     error
+	ackChan chan struct{}
+}
+
+// runNode runs a node and returns its last event, whether it escalated and whether
+// the run goes on.
+func (a *graphAgent) runNode(ctx agent.InvocationContext, node Node, step int, branch string, yield func(*session.Event, error) bool) (*session.Event, bool, bool) {
+	attrs := []attribute.KeyValue{attribute.String(observability.AttrGenAIWorkflowNode, node.Name)}
+	if branch != "" {
+		attrs = append(attrs, attribute.String(observability.AttrGenAIWorkflowBranch, branch))
+	}
+	if node.Agent != nil {
+		attrs = append(attrs, attribute.StringSlice(observability.AttrGenAIWorkflowSubAgents, []string{node.Agent.Name()}))
+	}
+	stepCtx, span := observability.StartWorkflowSpan(ctx, observability.WorkflowTypeGraph, step, attrs...)
+	defer span.End()
+
+	if node.Func != nil {
+		nodeCtx := stepCtx
+		if branch != "" {
+			nodeCtx = &branchContext{InvocationContext: stepCtx, agent: ctx.Agent(), branch: branch}
+		}
+		delta, err := node.Func(nodeCtx)
+		if err != nil {
+			span.SetError(err)
+			yield(nil, fmt.Errorf("graph node %q failed: %w", node.Name, err))
+			return nil, false, false
+		}
+		event := session.NewEvent(ctx.InvocationID())
+		event.Author = ctx.Agent().Name()
+		event.Branch = nodeCtx.Branch()
+		for key, value := range delta {
+			event.Actions.StateDelta[key] = value
+		}
+		return event, false, yield(event, nil)
+	}
+
+	nodeCtx := stepCtx
+	if branch != "" {
+		nodeCtx = &branchContext{InvocationContext: stepCtx, agent: node.Agent, branch: branch}
+	}
+	var last *session.Event
+	escalated := false
+	for event, err := range node.Agent.Run(nodeCtx) {
+		span.SetError(err)
+		if !yield(event, err) {
+			span.SetExitReason(observability.WorkflowExitCancelled)
+			return last, escalated, false
+		}
+		if err != nil {
+			return last, escalated, false
+		}
+		if event != nil && !event.Partial {
+			last = event
+		}
+		if event != nil && event.Actions.Escalate {
+			escalated = true
+			span.Escalate(event.Author)
+		}
+	}
+	return last, escalated, true
+}
+
+// next returns the targets of the edges taken from nodes, in order and once each.
+func (a *graphAgent) next(ctx agent.InvocationContext, nodes []string, lastEvents map[string]*session.Event) []string {
+	var targets []string
+	for _, name := range nodes {
+		edgeCtx := EdgeContext{State: ctx.Session().State(), LastEvent: lastEvents[name]}
+		for _, edge := range a.edges[name] {
+			if edge.To == End || slices.Contains(targets, edge.To) {
+				continue
+			}
+			if edge.Condition == nil || edge.Condition(edgeCtx) {
+				targets = append(targets, edge.To)
+			}
+		}
+	}
+	return targets
+}
+
+type checkpoint struct {
+	nodes []string
+	step  int
+}
+
+// checkpointEvent stores the nodes of the next step, or clears the checkpoint when nodes is empty.
+func (a *graphAgent) checkpointEvent(ctx agent.InvocationContext, nodes []string, step int) *session.Event {
+	event := session.NewEvent(ctx.InvocationID())
+	event.Author = ctx.Agent().Name()
+	event.Branch = ctx.Branch()
+	if len(nodes) == 0 {
+		event.Actions.StateDelta[a.stateKey] = nil
+	} else {
+		event.Actions.StateDelta[a.stateKey] = map[string]any{"nodes": nodes, "step": step}
+	}
+	return event
+}
+
+// checkpoint returns the checkpoint of an unfinished run, if its nodes still exist.
+func (a *graphAgent) checkpoint(ctx agent.InvocationContext) (checkpoint, bool) {
+	value, err := ctx.Session().State().Get(a.stateKey)
+	if err != nil {
+		return checkpoint{}, false
+	}
+	state, ok := value.(map[string]any)
+	if !ok {
+		return checkpoint{}, false
+	}
+
+	var result checkpoint
+	switch nodes := state["nodes"].(type) {
+	case []string:
+		result.nodes = nodes
+	case []any:
+		for _, node := range nodes {
+			name, _ := node.(string)
+			result.nodes = append(result.nodes, name)
+		}
+	}
+	switch step := state["step"].(type) {
+	case int:
+		result.step = step
+	case float64:
+		result.step = int(step)
+	}
+	for _, name := range result.nodes {
+		if _, ok := a.nodes[name]; !ok {
+			log.Warnf("graph agent %s ignores its checkpoint at unknown node %q", ctx.Agent().Name(), name)
+			return checkpoint{}, false
+		}
+	}
+	return result, len(result.nodes) > 0
+}
+
+// branchContext runs a node in its own branch of the invocation.
+type branchContext struct {
+	agent.InvocationContext
+	agent  agent.Agent
+	branch string
+}
+
+func (c *branchContext) Agent() agent.Agent {
+	return c.agent
+}
+
+func (c *branchContext) Branch() string {
+	return c.branch
+}
+
+func (c *branchContext) WithContext(ctx context.Context) agent.InvocationContext {
+	return &branchContext{
+		InvocationContext: c.InvocationContext.WithContext(ctx),
+		agent:             c.agent,
+		branch:            c.branch,
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphagent
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// newWriter returns an agent that replies with the next of drafts on each run.
+func newWriter(t *testing.T, drafts ...string) (agent.Agent, *int) {
+	t.Helper()
+	runs := 0
+	writer, err := agent.New(agent.Config{
+		Name: "writer",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				event := session.NewEvent(ctx.InvocationID())
+				event.Content = genai.NewContentFromText(drafts[min(runs, len(drafts)-1)], genai.RoleModel)
+				runs++
+				yield(event, nil)
+			}
+		},
+	})
+	require.NoError(t, err)
+	return writer, &runs
+}
+
+type graphRunner struct {
+	t        *testing.T
+	runner   *runner.Runner
+	sessions session.Service
+}
+
+func newGraphRunner(t *testing.T, cfg Config) *graphRunner {
+	t.Helper()
+	cfg.AgentConfig.Name = "graph"
+	graph, err := New(cfg)
+	require.NoError(t, err)
+
+	sessions := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: graph, SessionService: sessions, AutoCreateSession: true})
+	require.NoError(t, err)
+	return &graphRunner{t: t, runner: r, sessions: sessions}
+}
+
+// run runs a turn and returns its error and the session state.
+func (r *graphRunner) run() (error, session.ReadonlyState) {
+	var runErr error
+	for _, err := range r.runner.Run(context.Background(), "user", "session", genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			runErr = err
+		}
+	}
+	resp, err := r.sessions.Get(context.Background(), &session.GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	require.NoError(r.t, err)
+	return runErr, resp.Session.State()
+}
+
+func checkpointOf(t *testing.T, state session.ReadonlyState) any {
+	t.Helper()
+	value, err := state.Get(StateKey("graph"))
+	require.NoError(t, err)
+	return value
+}
+
+func TestGraphAgent_ConditionalCycle(t *testing.T) {
+	writer, writes := newWriter(t, "draft with typos", "clean draft")
+	r := newGraphRunner(t, Config{Graph: Graph{
+		Nodes: []Node{
+			{Agent: writer},
+			{Name: "reviewer", Func: func(ctx agent.InvocationContext) (map[string]any, error) {
+				return map[string]any{"published": false}, nil
+			}},
+			{Name: "publish", Func: func(ctx agent.InvocationContext) (map[string]any, error) {
+				return map[string]any{"published": true}, nil
+			}},
+		},
+		Edges: []Edge{
+			{From: "writer", To: "reviewer", Condition: TextContains("typos"), Label: "typos"},
+			{From: "writer", To: "publish", Condition: Not(TextContains("typos")), Label: "clean"},
+			{From: "reviewer", To: "writer"},
+			{From: "publish", To: End},
+		},
+	}})
+
+	err, state := r.run()
+	require.NoError(t, err)
+	assert.Equal(t, 2, *writes)
+	published, _ := state.Get("published")
+	assert.Equal(t, true, published)
+	assert.Nil(t, checkpointOf(t, state))
+}
+
+func TestGraphAgent_FanOutFanIn(t *testing.T) {
+	joins := 0
+	var seen []any
+	set := func(key string) NodeFunc {
+		return func(agent.InvocationContext) (map[string]any, error) {
+			return map[string]any{key: true}, nil
+		}
+	}
+	r := newGraphRunner(t, Config{Graph: Graph{
+		Nodes: []Node{
+			{Name: "plan", Func: set("planned")},
+			{Name: "flights", Func: set("flights")},
+			{Name: "hotels", Func: set("hotels")},
+			{Name: "join", Func: func(ctx agent.InvocationContext) (map[string]any, error) {
+				joins++
+				flights, _ := ctx.Session().State().Get("flights")
+				hotels, _ := ctx.Session().State().Get("hotels")
+				seen = []any{flights, hotels}
+				return nil, nil
+			}},
+		},
+		Edges: []Edge{
+			{From: "plan", To: "flights"},
+			{From: "plan", To: "hotels"},
+			{From: "flights", To: "join"},
+			{From: "hotels", To: "join"},
+		},
+	}})
+
+	err, _ := r.run()
+	require.NoError(t, err)
+	assert.Equal(t, 1, joins)
+	assert.Equal(t, []any{true, true}, seen)
+}
+
+func TestGraphAgent_MaxSteps(t *testing.T) {
+	writer, writes := newWriter(t, "again")
+	r := newGraphRunner(t, Config{
+		Graph:    Graph{Nodes: []Node{{Agent: writer}}, Edges: []Edge{{From: "writer", To: "writer"}}},
+		MaxSteps: 3,
+	})
+
+	err, state := r.run()
+	assert.ErrorIs(t, err, ErrMaxSteps)
+	assert.Equal(t, 3, *writes)
+	assert.Nil(t, checkpointOf(t, state))
+}
+
+func TestGraphAgent_ResumesFromCheckpoint(t *testing.T) {
+	writer, writes := newWriter(t, "draft")
+	failures := 1
+	r := newGraphRunner(t, Config{Graph: Graph{
+		Nodes: []Node{
+			{Agent: writer},
+			{Name: "publish", Func: func(agent.InvocationContext) (map[string]any, error) {
+				if failures > 0 {
+					failures--
+					return nil, errors.New("publishing is down")
+				}
+				return map[string]any{"published": true}, nil
+			}},
+		},
+		Edges: []Edge{{From: "writer", To: "publish"}},
+	}})
+
+	err, state := r.run()
+	assert.ErrorContains(t, err, "publishing is down")
+	assert.Equal(t, map[string]any{"nodes": []string{"publish"}, "step": 1}, checkpointOf(t, state))
+
+	// the writer is not run again
+	err, state = r.run()
+	require.NoError(t, err)
+	assert.Equal(t, 1, *writes)
+	published, _ := state.Get("published")
+	assert.Equal(t, true, published)
+	assert.Nil(t, checkpointOf(t, state))
+}
+
+func TestGraph_Validate(t *testing.T) {
+	writer, _ := newWriter(t, "draft")
+	noop := func(agent.InvocationContext) (map[string]any, error) { return nil, nil }
+
+	_, err := New(Config{})
+	assert.ErrorIs(t, err, ErrInvalidGraph)
+
+	graph := Graph{
+		Nodes: []Node{{Agent: writer}, {Name: "writer", Func: noop}, {Name: "both", Agent: writer, Func: noop}},
+		Edges: []Edge{{From: "writer", To: "missing"}, {From: "nowhere", To: End}},
+	}
+	err = graph.Validate()
+	assert.ErrorIs(t, err, ErrInvalidGraph)
+	assert.ErrorContains(t, err, "node writer is defined twice")
+	assert.ErrorContains(t, err, "node both must have either an agent or a func")
+	assert.ErrorContains(t, err, `edges[0] leads to undefined node "missing"`)
+	assert.ErrorContains(t, err, `edges[1] leads from undefined node "nowhere"`)
+}
+
+func TestGraph_Export(t *testing.T) {
+	writer, _ := newWriter(t, "draft")
+	graph := Graph{
+		Nodes: []Node{{Agent: writer}, {Name: "review-draft", Func: func(agent.InvocationContext) (map[string]any, error) { return nil, nil }}},
+		Edges: []Edge{
+			{From: "writer", To: "review-draft"},
+			{From: "review-draft", To: "writer", Condition: StateEquals("approved", false), Label: "rejected"},
+			{From: "review-draft", To: End, Condition: StateEquals("approved", true), Label: "approved"},
+		},
+	}
+
+	assert.Equal(t, `digraph {
+	"__start__" [shape=point];
+	"writer" [shape=box];
+	"review-draft" [shape=ellipse];
+	"__end__" [shape=doublecircle, label="end"];
+	"__start__" -> "writer";
+	"writer" -> "review-draft";
+	"review-draft" -> "writer" [label="rejected", style=dashed];
+	"review-draft" -> "__end__" [label="approved", style=dashed];
+}
+`, graph.DOT())
+
+	assert.Equal(t, `flowchart TD
+	__start__((start))
+	writer["writer"]
+	review_draft(["review-draft"])
+	__end__((end))
+	__start__ --> writer
+	writer --> review_draft
+	review_draft -.->|rejected| writer
+	review_draft -.->|approved| __end__
+`, graph.Mermaid())
+}
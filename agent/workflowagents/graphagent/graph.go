@@ -0,0 +1,249 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphagent
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// End is the target of the edges that end the graph.
+const End = "__end__"
+
+const start = "__start__"
+
+var ErrInvalidGraph = errors.New("invalid graph")
+
+// NodeFunc is a node written in Go. The returned values are added to the session state.
+type NodeFunc func(ctx agent.InvocationContext) (map[string]any, error)
+
+// Node runs either Agent or Func.
+type Node struct {
+	// Name defaults to the name of Agent.
+	Name  string
+	Agent agent.Agent
+	Func  NodeFunc
+}
+
+func (n Node) name() string {
+	if n.Name == "" && n.Agent != nil {
+		return n.Agent.Name()
+	}
+	return n.Name
+}
+
+// EdgeContext is what the condition of an edge is evaluated on.
+type EdgeContext struct {
+	State session.ReadonlyState
+	// LastEvent is the last event of the source node, nil if it had none.
+	LastEvent *session.Event
+}
+
+// Text returns the text of the last event.
+func (c EdgeContext) Text() string {
+	if c.LastEvent == nil {
+		return ""
+	}
+	return textOf(c.LastEvent.Content)
+}
+
+// Condition decides whether an edge is taken.
+type Condition func(ctx EdgeContext) bool
+
+// StateEquals is true when the session state value of key prints as value.
+func StateEquals(key string, value any) Condition {
+	return func(ctx EdgeContext) bool {
+		current, err := ctx.State.Get(key)
+		return err == nil && fmt.Sprint(current) == fmt.Sprint(value)
+	}
+}
+
+// TextContains is true when the last event of the source node contains text, case-insensitively.
+func TextContains(text string) Condition {
+	return func(ctx EdgeContext) bool {
+		return strings.Contains(strings.ToLower(ctx.Text()), strings.ToLower(text))
+	}
+}
+
+// Not negates condition.
+func Not(condition Condition) Condition {
+	return func(ctx EdgeContext) bool {
+		return !condition(ctx)
+	}
+}
+
+// Edge leads from the node From to the node To, or to End.
+type Edge struct {
+	From, To string
+	// Condition is evaluated after From ran, the edge is always taken when nil.
+	Condition Condition
+	// Label describes Condition in the exported graph.
+	Label string
+}
+
+// Graph is a set of nodes and the edges between them.
+//
+// It runs in steps. A step runs its nodes in parallel and the next step runs the
+// targets of all the edges taken, once each, so that fan-out branches join on the
+// node they share.
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+	// Start is the first node, the first of Nodes when empty.
+	Start string
+}
+
+// Validate checks the node names and the edges, and fills in the default names.
+func (g *Graph) Validate() error {
+	var problems []string
+	names := map[string]bool{}
+	for i := range g.Nodes {
+		node := &g.Nodes[i]
+		node.Name = node.name()
+		switch {
+		case node.Name == "" || node.Name == End || node.Name == start:
+			problems = append(problems, fmt.Sprintf("nodes[%d] has an invalid name %q", i, node.Name))
+		case names[node.Name]:
+			problems = append(problems, fmt.Sprintf("node %s is defined twice", node.Name))
+		}
+		if (node.Agent == nil) == (node.Func == nil) {
+			problems = append(problems, fmt.Sprintf("node %s must have either an agent or a func", node.Name))
+		}
+		names[node.Name] = true
+	}
+	if len(g.Nodes) == 0 {
+		problems = append(problems, "graph has no nodes")
+	} else if g.Start == "" {
+		g.Start = g.Nodes[0].Name
+	}
+	if g.Start != "" && !names[g.Start] {
+		problems = append(problems, fmt.Sprintf("start node %s is not defined", g.Start))
+	}
+	for i, edge := range g.Edges {
+		if !names[edge.From] {
+			problems = append(problems, fmt.Sprintf("edges[%d] leads from undefined node %q", i, edge.From))
+		}
+		if edge.To != End && !names[edge.To] {
+			problems = append(problems, fmt.Sprintf("edges[%d] leads to undefined node %q", i, edge.To))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidGraph, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// DOT returns the graph in the Graphviz DOT language. Conditional edges are dashed.
+func (g Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph {\n")
+	fmt.Fprintf(&b, "\t%q [shape=point];\n", start)
+	for _, node := range g.Nodes {
+		shape := "box"
+		if node.Func != nil {
+			shape = "ellipse"
+		}
+		fmt.Fprintf(&b, "\t%q [shape=%s];\n", node.name(), shape)
+	}
+	if g.endsAnywhere() {
+		fmt.Fprintf(&b, "\t%q [shape=doublecircle, label=\"end\"];\n", End)
+	}
+	fmt.Fprintf(&b, "\t%q -> %q;\n", start, g.first())
+	for _, edge := range g.Edges {
+		var attrs []string
+		if edge.Label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", edge.Label))
+		}
+		if edge.Condition != nil {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, "\t%q -> %q [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", edge.From, edge.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+var mermaidUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Mermaid returns the graph as a Mermaid flowchart. Conditional edges are dotted.
+func (g Graph) Mermaid() string {
+	id := func(name string) string {
+		return mermaidUnsafe.ReplaceAllString(name, "_")
+	}
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	fmt.Fprintf(&b, "\t%s((start))\n", start)
+	for _, node := range g.Nodes {
+		if node.Func != nil {
+			fmt.Fprintf(&b, "\t%s([%q])\n", id(node.name()), node.name())
+		} else {
+			fmt.Fprintf(&b, "\t%s[%q]\n", id(node.name()), node.name())
+		}
+	}
+	if g.endsAnywhere() {
+		fmt.Fprintf(&b, "\t%s((end))\n", End)
+	}
+	fmt.Fprintf(&b, "\t%s --> %s\n", start, id(g.first()))
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Condition != nil {
+			arrow = "-.->"
+		}
+		if edge.Label != "" {
+			arrow += "|" + strings.ReplaceAll(edge.Label, "|", "/") + "|"
+		}
+		fmt.Fprintf(&b, "\t%s %s %s\n", id(edge.From), arrow, id(edge.To))
+	}
+	return b.String()
+}
+
+func (g Graph) first() string {
+	if g.Start == "" && len(g.Nodes) > 0 {
+		return g.Nodes[0].name()
+	}
+	return g.Start
+}
+
+func (g Graph) endsAnywhere() bool {
+	for _, edge := range g.Edges {
+		if edge.To == End {
+			return true
+		}
+	}
+	return false
+}
+
+func textOf(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part != nil && part.Text != "" && !part.Thought {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "")
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package workflowtest holds the helpers of the workflow agent tests.
+package workflowtest
+
+import (
+	"context"
+	"iter"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// Exporter receives the spans of the tests run by Main.
+var Exporter = tracetest.NewInMemoryExporter()
+
+// Main runs the tests of a package with spans exported to Exporter.
+func Main(m *testing.M) {
+	// ADK binds its tracer to the first global provider, so it is set once for the package
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(Exporter)))
+	os.Exit(m.Run())
+}
+
+// NewAgent returns an agent yielding the single event built by event on each run,
+// numbered from 1.
+func NewAgent(t *testing.T, name string, event func(ctx agent.InvocationContext, run int) *session.Event) agent.Agent {
+	t.Helper()
+	runs := 0
+	ag, err := agent.New(agent.Config{
+		Name: name,
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				runs++
+				yield(event(ctx, runs), nil)
+			}
+		},
+	})
+	require.NoError(t, err)
+	return ag
+}
+
+// TextEvent returns an event of ctx with text as its content.
+func TextEvent(ctx agent.InvocationContext, text string) *session.Event {
+	event := session.NewEvent(ctx.InvocationID())
+	event.Content = genai.NewContentFromText(text, genai.RoleModel)
+	return event
+}
+
+// Run resets Exporter and runs root for one user turn, returning its events.
+func Run(t *testing.T, root agent.Agent) []*session.Event {
+	t.Helper()
+	Exporter.Reset()
+	r, err := runner.New(runner.Config{
+		AppName:           "app",
+		Agent:             root,
+		SessionService:    session.InMemoryService(),
+		AutoCreateSession: true,
+	})
+	require.NoError(t, err)
+
+	var events []*session.Event
+	for event, err := range r.Run(context.Background(), "user", "session", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+	return events
+}
+
+// SpansByName returns the exported spans named name, in the order they ended.
+func SpansByName(name string) []tracetest.SpanStub {
+	var spans []tracetest.SpanStub
+	for _, span := range Exporter.GetSpans() {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// Attr returns the value of the attribute key of span.
+func Attr(span tracetest.SpanStub, key string) attribute.Value {
+	for _, kv := range span.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
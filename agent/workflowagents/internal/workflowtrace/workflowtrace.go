@@ -0,0 +1,168 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package workflowtrace traces the steps of the ADK workflow agents. The workflow agents
+// are built with the ADK constructors, which keep their agent type for the A2A cards and
+// the agent graph, so the steps are traced by the sub-agents instead: each sub-agent is
+// wrapped in an agent that runs it within a workflow step span.
+package workflowtrace
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/volcengine/veadk-go/observability"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+// Sequential wraps the sub-agents of the sequential agent workflowName, tracing each run
+// as a sequential_step span.
+func Sequential(workflowName string, subAgents []agent.Agent) []agent.Agent {
+	return wrap(workflowName, observability.WorkflowTypeSequential, nil, subAgents)
+}
+
+// Parallel wraps the sub-agents of the parallel agent workflowName, tracing each run as a
+// parallel_branch span.
+func Parallel(workflowName string, subAgents []agent.Agent) []agent.Agent {
+	return wrap(workflowName, observability.WorkflowTypeParallel, nil, subAgents)
+}
+
+// Loop wraps the sub-agents of the loop agent workflowName, tracing each run as a
+// loop_iteration span of its iteration. Exhausting maxIterations is recorded as an event
+// of the loop agent span.
+func Loop(workflowName string, maxIterations uint, subAgents []agent.Agent) []agent.Agent {
+	return wrap(workflowName, observability.WorkflowTypeLoop, &loop{
+		maxIterations: maxIterations,
+		last:          len(subAgents) - 1,
+		iterations:    map[loopRun]int{},
+	}, subAgents)
+}
+
+func wrap(workflowName, workflowType string, l *loop, subAgents []agent.Agent) []agent.Agent {
+	wrapped := make([]agent.Agent, 0, len(subAgents))
+	for i, subAgent := range subAgents {
+		wrapped = append(wrapped, &stepAgent{
+			Agent:        subAgent,
+			workflowName: workflowName,
+			workflowType: workflowType,
+			index:        i,
+			loop:         l,
+		})
+	}
+	return wrapped
+}
+
+// stepAgent runs the sub-agent at index of a workflow agent within a step span.
+type stepAgent struct {
+	agent.Agent
+	workflowName string
+	workflowType string
+	index        int
+	loop         *loop
+}
+
+// Unwrap returns the traced sub-agent.
+func (a *stepAgent) Unwrap() agent.Agent {
+	return a.Agent
+}
+
+func (a *stepAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		index := a.index
+		attrs := []attribute.KeyValue{attribute.StringSlice(observability.AttrGenAIWorkflowSubAgents, []string{a.Name()})}
+		switch a.workflowType {
+		case observability.WorkflowTypeParallel:
+			attrs = append(attrs, attribute.String(observability.AttrGenAIWorkflowBranch, ctx.Branch()))
+		case observability.WorkflowTypeLoop:
+			index = a.loop.iteration(ctx)
+			attrs = append(attrs, attribute.Int64(observability.AttrGenAIWorkflowMaxIterations, int64(a.loop.maxIterations)))
+		}
+		stepCtx, span := observability.StartWorkflowStepSpan(ctx, a.workflowName, a.workflowType, index, attrs...)
+		defer span.End()
+
+		escalated := false
+		for event, err := range a.Agent.Run(stepCtx) {
+			span.SetError(err)
+			if !yield(event, err) {
+				span.SetExitReason(observability.WorkflowExitCancelled)
+				a.loop.end(ctx)
+				return
+			}
+			if a.loop != nil && event != nil && event.Actions.Escalate {
+				escalated = true
+				span.Escalate(event.Author)
+			}
+		}
+
+		switch {
+		case a.loop == nil:
+		case escalated:
+			a.loop.end(ctx)
+		case a.index == a.loop.last:
+			if a.loop.next(ctx) {
+				span.SetExitReason(observability.WorkflowExitMaxIterations)
+				observability.RecordMaxIterationsReached(ctx, a.loop.maxIterations)
+			}
+		}
+	}
+}
+
+// loop counts the iterations of the runs of a loop agent. The runs are told apart by
+// invocation and branch, and forgotten when they end.
+type loop struct {
+	maxIterations uint
+	last          int
+
+	mu         sync.Mutex
+	iterations map[loopRun]int
+}
+
+type loopRun struct {
+	invocationID string
+	branch       string
+}
+
+func newLoopRun(ctx agent.InvocationContext) loopRun {
+	return loopRun{invocationID: ctx.InvocationID(), branch: ctx.Branch()}
+}
+
+func (l *loop) iteration(ctx agent.InvocationContext) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.iterations[newLoopRun(ctx)]
+}
+
+// next moves the run to its next iteration and reports whether it exhausted maxIterations.
+func (l *loop) next(ctx agent.InvocationContext) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	run := newLoopRun(ctx)
+	l.iterations[run]++
+	if l.maxIterations > 0 && uint(l.iterations[run]) >= l.maxIterations {
+		delete(l.iterations, run)
+		return true
+	}
+	return false
+}
+
+func (l *loop) end(ctx agent.InvocationContext) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.iterations, newLoopRun(ctx))
+}
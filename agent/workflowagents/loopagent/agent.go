@@ -15,6 +15,7 @@
 package loopagent
 
 import (
+	"github.com/volcengine/veadk-go/agent/workflowagents/internal/workflowtrace"
 	"github.com/volcengine/veadk-go/common"
 	"github.com/volcengine/veadk-go/prompts"
 	"google.golang.org/adk/agent"
@@ -29,8 +30,6 @@ type Config struct {
 	// If MaxIterations == 0, then LoopAgent runs indefinitely or until any
 	// sub-agent escalates.
 	MaxIterations uint
-
-	//TODO: add tracers
 }
 
 // New creates a LoopAgent.
@@ -40,6 +39,9 @@ type Config struct {
 //
 // Use the LoopAgent when your workflow involves repetition or iterative
 // refinement, such as like revising code.
+//
+// Each sub-agent run is traced as a loop_iteration span with its iteration index and
+// exit reason, and exhausting MaxIterations is recorded as an event of the agent span.
 func New(cfg Config) (agent.Agent, error) {
 
 	if cfg.AgentConfig.Name == "" {
@@ -48,6 +50,7 @@ func New(cfg Config) (agent.Agent, error) {
 	if cfg.AgentConfig.Description == "" {
 		cfg.AgentConfig.Description = prompts.DEFAULT_DESCRIPTION
 	}
+	cfg.AgentConfig.SubAgents = workflowtrace.Loop(cfg.AgentConfig.Name, cfg.MaxIterations, cfg.AgentConfig.SubAgents)
 
 	return googleADKLoopAgent.New(googleADKLoopAgent.Config{
 		AgentConfig:   cfg.AgentConfig,
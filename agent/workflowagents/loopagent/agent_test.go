@@ -0,0 +1,119 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loopagent
+
+import (
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/agent/workflowagents/internal/workflowtest"
+	"github.com/volcengine/veadk-go/observability"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/server/adka2a"
+	"google.golang.org/adk/session"
+)
+
+func TestMain(m *testing.M) {
+	workflowtest.Main(m)
+}
+
+// newWorker returns an agent that escalates on its escalateAt-th run, never if 0.
+func newWorker(t *testing.T, name string, escalateAt int) agent.Agent {
+	return workflowtest.NewAgent(t, name, func(ctx agent.InvocationContext, run int) *session.Event {
+		event := workflowtest.TextEvent(ctx, "working")
+		event.Actions.Escalate = run == escalateAt
+		return event
+	})
+}
+
+func TestLoopAgent_TracesIterationsUntilEscalation(t *testing.T) {
+	loop, err := New(Config{
+		AgentConfig:   agent.Config{Name: "refine", SubAgents: []agent.Agent{newWorker(t, "worker", 3)}},
+		MaxIterations: 5,
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, workflowtest.Run(t, loop), 3)
+
+	iterations := workflowtest.SpansByName(observability.SpanPrefixLoopIteration + "refine")
+	require.Len(t, iterations, 3)
+	for i, span := range iterations {
+		assert.Equal(t, int64(i), workflowtest.Attr(span, observability.AttrGenAIWorkflowStepIndex).AsInt64())
+		assert.Equal(t, []string{"worker"}, workflowtest.Attr(span, observability.AttrGenAIWorkflowSubAgents).AsStringSlice())
+		assert.Equal(t, int64(5), workflowtest.Attr(span, observability.AttrGenAIWorkflowMaxIterations).AsInt64())
+	}
+	assert.Equal(t, observability.WorkflowExitCompleted, workflowtest.Attr(iterations[0], observability.AttrGenAIWorkflowExitReason).AsString())
+	assert.Equal(t, observability.WorkflowExitEscalated, workflowtest.Attr(iterations[2], observability.AttrGenAIWorkflowExitReason).AsString())
+	assert.Equal(t, "worker", workflowtest.Attr(iterations[2], observability.AttrGenAIWorkflowEscalatedBy).AsString())
+
+	// each worker run is nested in its iteration
+	workers := workflowtest.SpansByName(observability.SpanPrefixInvokeAgent + "worker")
+	require.Len(t, workers, 3)
+	for i, span := range workers {
+		assert.Equal(t, iterations[i].SpanContext.SpanID(), span.Parent.SpanID())
+	}
+
+	loops := workflowtest.SpansByName(observability.SpanPrefixInvokeAgent + "refine")
+	require.Len(t, loops, 1)
+	assert.Equal(t, loops[0].SpanContext.SpanID(), iterations[0].Parent.SpanID())
+	assert.Empty(t, loops[0].Events)
+}
+
+func TestLoopAgent_RecordsMaxIterations(t *testing.T) {
+	loop, err := New(Config{
+		AgentConfig:   agent.Config{SubAgents: []agent.Agent{newWorker(t, "draft", 0), newWorker(t, "review", 0)}},
+		MaxIterations: 2,
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, workflowtest.Run(t, loop), 4)
+
+	iterations := workflowtest.SpansByName(observability.SpanPrefixLoopIteration + loop.Name())
+	require.Len(t, iterations, 4)
+	var indexes []int64
+	for _, span := range iterations {
+		indexes = append(indexes, workflowtest.Attr(span, observability.AttrGenAIWorkflowStepIndex).AsInt64())
+	}
+	assert.Equal(t, []int64{0, 0, 1, 1}, indexes)
+	assert.Equal(t, observability.WorkflowExitCompleted, workflowtest.Attr(iterations[1], observability.AttrGenAIWorkflowExitReason).AsString())
+	assert.Equal(t, observability.WorkflowExitMaxIterations, workflowtest.Attr(iterations[3], observability.AttrGenAIWorkflowExitReason).AsString())
+
+	loops := workflowtest.SpansByName(observability.SpanPrefixInvokeAgent + loop.Name())
+	require.Len(t, loops, 1)
+	require.Len(t, loops[0].Events, 1)
+	assert.Equal(t, observability.EventWorkflowMaxIterations, loops[0].Events[0].Name)
+
+	// a new run starts over
+	workflowtest.Run(t, loop)
+	iterations = workflowtest.SpansByName(observability.SpanPrefixLoopIteration + loop.Name())
+	require.Len(t, iterations, 4)
+	assert.Equal(t, int64(0), workflowtest.Attr(iterations[0], observability.AttrGenAIWorkflowStepIndex).AsInt64())
+}
+
+func TestNew_KeepsLoopAgentType(t *testing.T) {
+	loop, err := New(Config{AgentConfig: agent.Config{Name: "refine", SubAgents: []agent.Agent{newWorker(t, "worker", 0)}}})
+	require.NoError(t, err)
+	assert.Equal(t, "worker", loop.FindAgent("worker").Name())
+	skills := adka2a.BuildAgentSkills(loop)
+	require.NotEmpty(t, skills)
+	assert.Equal(t, []string{"loop_workflow"}, skills[0].Tags)
+
+	_, err = New(Config{AgentConfig: agent.Config{
+		Run: func(agent.InvocationContext) iter.Seq2[*session.Event, error] { return nil },
+	}})
+	assert.Error(t, err)
+}
@@ -15,6 +15,7 @@
 package parallelagent
 
 import (
+	"github.com/volcengine/veadk-go/agent/workflowagents/internal/workflowtrace"
 	"github.com/volcengine/veadk-go/common"
 	"github.com/volcengine/veadk-go/prompts"
 	"google.golang.org/adk/agent"
@@ -25,9 +26,14 @@ import (
 type Config struct {
 	// Basic agent setup.
 	AgentConfig agent.Config
-	//TODO: add tracers
 }
 
+// New creates a ParallelAgent.
+//
+// ParallelAgent runs its sub-agents in parallel, each in its own branch so that
+// sub-agents do not see the conversation history of their peers.
+//
+// Each sub-agent run is traced as a parallel_branch span with its index and branch.
 func New(cfg Config) (agent.Agent, error) {
 
 	if cfg.AgentConfig.Name == "" {
@@ -36,6 +42,7 @@ func New(cfg Config) (agent.Agent, error) {
 	if cfg.AgentConfig.Description == "" {
 		cfg.AgentConfig.Description = prompts.DEFAULT_DESCRIPTION
 	}
+	cfg.AgentConfig.SubAgents = workflowtrace.Parallel(cfg.AgentConfig.Name, cfg.AgentConfig.SubAgents)
 
 	return googleADKParallelAgent.New(googleADKParallelAgent.Config{
 		AgentConfig: cfg.AgentConfig,
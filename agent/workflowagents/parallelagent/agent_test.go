@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parallelagent
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/agent/workflowagents/internal/workflowtest"
+	"github.com/volcengine/veadk-go/observability"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+func TestMain(m *testing.M) {
+	workflowtest.Main(m)
+}
+
+// newBranch returns an agent that reports the branch it runs in.
+func newBranch(t *testing.T, name string) agent.Agent {
+	return workflowtest.NewAgent(t, name, func(ctx agent.InvocationContext, _ int) *session.Event {
+		event := workflowtest.TextEvent(ctx, name)
+		event.Branch = ctx.Branch()
+		return event
+	})
+}
+
+func TestParallelAgent_TracesBranches(t *testing.T) {
+	fanout, err := New(Config{AgentConfig: agent.Config{
+		Name:      "fanout",
+		SubAgents: []agent.Agent{newBranch(t, "search"), newBranch(t, "summarize")},
+	}})
+	require.NoError(t, err)
+
+	branches := map[string]string{}
+	for _, event := range workflowtest.Run(t, fanout) {
+		branches[event.Author] = event.Branch
+	}
+	assert.Equal(t, map[string]string{"search": "fanout.search", "summarize": "fanout.summarize"}, branches)
+
+	branchSpans := map[string]tracetest.SpanStub{}
+	for _, span := range workflowtest.SpansByName(observability.SpanPrefixParallelBranch + "fanout") {
+		branchSpans[workflowtest.Attr(span, observability.AttrGenAIWorkflowBranch).AsString()] = span
+	}
+	require.Len(t, branchSpans, 2)
+
+	parallel := workflowtest.SpansByName(observability.SpanPrefixInvokeAgent + "fanout")
+	require.Len(t, parallel, 1)
+	var indexes []int64
+	for _, name := range []string{"search", "summarize"} {
+		spans := workflowtest.SpansByName(observability.SpanPrefixInvokeAgent + name)
+		require.Len(t, spans, 1)
+		branchSpan := branchSpans["fanout."+name]
+		assert.Equal(t, branchSpan.SpanContext.SpanID(), spans[0].Parent.SpanID())
+		assert.Equal(t, parallel[0].SpanContext.SpanID(), branchSpan.Parent.SpanID())
+		assert.Equal(t, []string{name}, workflowtest.Attr(branchSpan, observability.AttrGenAIWorkflowSubAgents).AsStringSlice())
+		indexes = append(indexes, workflowtest.Attr(branchSpan, observability.AttrGenAIWorkflowStepIndex).AsInt64())
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	assert.Equal(t, []int64{0, 1}, indexes)
+}
@@ -15,6 +15,7 @@
 package sequentialagent
 
 import (
+	"github.com/volcengine/veadk-go/agent/workflowagents/internal/workflowtrace"
 	"github.com/volcengine/veadk-go/common"
 	"github.com/volcengine/veadk-go/prompts"
 	"google.golang.org/adk/agent"
@@ -25,8 +26,6 @@ import (
 type Config struct {
 	// Basic agent setup.
 	AgentConfig agent.Config
-
-	//TODO: add tracers
 }
 
 // New creates a SequentialAgent.
@@ -35,6 +34,8 @@ type Config struct {
 //
 // Use the SequentialAgent when you want the execution to occur in a fixed,
 // strict order.
+//
+// Each sub-agent run is traced as a sequential_step span with its index.
 func New(cfg Config) (agent.Agent, error) {
 
 	if cfg.AgentConfig.Name == "" {
@@ -43,6 +44,7 @@ func New(cfg Config) (agent.Agent, error) {
 	if cfg.AgentConfig.Description == "" {
 		cfg.AgentConfig.Description = prompts.DEFAULT_DESCRIPTION
 	}
+	cfg.AgentConfig.SubAgents = workflowtrace.Sequential(cfg.AgentConfig.Name, cfg.AgentConfig.SubAgents)
 
 	return googleADKSequentialAgent.New(googleADKSequentialAgent.Config{
 		AgentConfig: cfg.AgentConfig,
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sequentialagent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/agent/workflowagents/internal/workflowtest"
+	"github.com/volcengine/veadk-go/observability"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+func TestMain(m *testing.M) {
+	workflowtest.Main(m)
+}
+
+func newStep(t *testing.T, name string) agent.Agent {
+	return workflowtest.NewAgent(t, name, func(ctx agent.InvocationContext, _ int) *session.Event {
+		return workflowtest.TextEvent(ctx, name)
+	})
+}
+
+func TestSequentialAgent_TracesSteps(t *testing.T) {
+	pipeline, err := New(Config{AgentConfig: agent.Config{
+		Name:      "pipeline",
+		SubAgents: []agent.Agent{newStep(t, "draft"), newStep(t, "review")},
+	}})
+	require.NoError(t, err)
+
+	var authors []string
+	for _, event := range workflowtest.Run(t, pipeline) {
+		authors = append(authors, event.Author)
+	}
+	assert.Equal(t, []string{"draft", "review"}, authors)
+
+	parents := map[string]tracetest.SpanStub{}
+	for _, span := range workflowtest.Exporter.GetSpans() {
+		parents[span.SpanContext.SpanID().String()] = span
+	}
+	steps := workflowtest.SpansByName(observability.SpanPrefixSequentialStep + "pipeline")
+	require.Len(t, steps, 2)
+	for i, step := range steps {
+		assert.Equal(t, int64(i), workflowtest.Attr(step, observability.AttrGenAIWorkflowStepIndex).AsInt64())
+		assert.Equal(t, observability.WorkflowTypeSequential, workflowtest.Attr(step, observability.AttrGenAIWorkflowType).AsString())
+		assert.Equal(t, []string{authors[i]}, workflowtest.Attr(step, observability.AttrGenAIWorkflowSubAgents).AsStringSlice())
+		assert.Equal(t, observability.WorkflowExitCompleted, workflowtest.Attr(step, observability.AttrGenAIWorkflowExitReason).AsString())
+		assert.Equal(t, observability.SpanPrefixInvokeAgent+"pipeline", parents[step.Parent.SpanID().String()].Name)
+	}
+
+	reviews := workflowtest.SpansByName(observability.SpanPrefixInvokeAgent + "review")
+	require.Len(t, reviews, 1)
+	assert.Equal(t, steps[1].SpanContext.SpanID(), reviews[0].Parent.SpanID())
+}
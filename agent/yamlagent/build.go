@@ -0,0 +1,344 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yamlagent
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	veagent "github.com/volcengine/veadk-go/agent/llmagent"
+	"github.com/volcengine/veadk-go/agent/remoteagent"
+	"github.com/volcengine/veadk-go/agent/workflowagents/loopagent"
+	"github.com/volcengine/veadk-go/agent/workflowagents/parallelagent"
+	"github.com/volcengine/veadk-go/agent/workflowagents/sequentialagent"
+	"github.com/volcengine/veadk-go/code_executors"
+	"github.com/volcengine/veadk-go/knowledgebase"
+	"github.com/volcengine/veadk-go/knowledgebase/backend/local_knowledge_backend"
+	"github.com/volcengine/veadk-go/knowledgebase/backend/opensearch_knowledge_backend"
+	"github.com/volcengine/veadk-go/knowledgebase/backend/redis_knowledge_backend"
+	"github.com/volcengine/veadk-go/knowledgebase/backend/viking_knowledge_backend"
+	"github.com/volcengine/veadk-go/knowledgebase/ktypes"
+	"github.com/volcengine/veadk-go/log"
+	veadktool "github.com/volcengine/veadk-go/tool"
+	"github.com/volcengine/veadk-go/tool/builtin_tools"
+	"github.com/volcengine/veadk-go/tool/builtin_tools/web_search"
+	"github.com/volcengine/veadk-go/tool/skilltool"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/tool"
+)
+
+// DefaultSkillScriptTimeout bounds the skill scripts run by agents with skill_scripts.
+const DefaultSkillScriptTimeout = 300 * time.Second
+
+// ToolFactory creates a tool referenced by name in the definitions.
+type ToolFactory func() (tool.Tool, error)
+
+// ToolsetFactory creates a toolset referenced by name in the definitions.
+type ToolsetFactory func() (tool.Toolset, error)
+
+var (
+	toolsMu  sync.RWMutex
+	tools    = map[string]ToolFactory{}
+	toolsets = map[string]ToolsetFactory{}
+	builtins = map[string]ToolFactory{
+		"web_search":                func() (tool.Tool, error) { return web_search.NewWebSearchTool(nil) },
+		"parallel_web_search":       func() (tool.Tool, error) { return web_search.NewParallelWebSearchTool(nil) },
+		"link_reader":               func() (tool.Tool, error) { return builtin_tools.NewLinkReaderTool(nil) },
+		"web_scraper":               func() (tool.Tool, error) { return builtin_tools.NewWebScraperTool(nil) },
+		"vesearch":                  func() (tool.Tool, error) { return builtin_tools.NewVeSearchTool(nil) },
+		"image_generate":            func() (tool.Tool, error) { return builtin_tools.NewImageGenerateTool(nil) },
+		"image_edit":                func() (tool.Tool, error) { return builtin_tools.NewImageEditTool(nil) },
+		"video_generate":            func() (tool.Tool, error) { return builtin_tools.NewVideoGenerateTool(nil) },
+		"text_to_speech":            func() (tool.Tool, error) { return builtin_tools.NewTTSTool(nil) },
+		"run_code":                  builtin_tools.NewRunCodeSandboxTool,
+		"search_past_conversations": builtin_tools.LoadLongMemoryTool,
+		"get_city_weather":          veadktool.GetCityWeatherTool,
+	}
+	builtinToolsets = map[string]ToolsetFactory{
+		"las":        builtin_tools.NewLasToolset,
+		"mcp_router": func() (tool.Toolset, error) { return builtin_tools.NewMcpRouter(), nil },
+	}
+)
+
+var knowledgeBackends = map[string]bool{
+	ktypes.LocalBackend:      true,
+	ktypes.VikingBackend:     true,
+	ktypes.OpensearchBackend: true,
+	ktypes.RedisBackend:      true,
+}
+
+// RegisterTool makes a tool available to the definitions under name, replacing the builtin
+// tool of the same name.
+func RegisterTool(name string, factory ToolFactory) {
+	toolsMu.Lock()
+	defer toolsMu.Unlock()
+	tools[name] = factory
+}
+
+// RegisterToolset makes a toolset available to the definitions under name.
+func RegisterToolset(name string, factory ToolsetFactory) {
+	toolsMu.Lock()
+	defer toolsMu.Unlock()
+	toolsets[name] = factory
+}
+
+// ToolNames lists the tools and toolsets available to the definitions.
+func ToolNames() []string {
+	toolsMu.RLock()
+	defer toolsMu.RUnlock()
+	var names []string
+	for _, factories := range []map[string]ToolFactory{builtins, tools} {
+		for name := range factories {
+			names = append(names, name)
+		}
+	}
+	for _, factories := range []map[string]ToolsetFactory{builtinToolsets, toolsets} {
+		for name := range factories {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func hasTool(name string) bool {
+	_, isTool, isToolset := lookupTool(name)
+	return isTool || isToolset
+}
+
+// lookupTool returns the factory of name, registered tools first.
+func lookupTool(name string) (factory any, isTool, isToolset bool) {
+	toolsMu.RLock()
+	defer toolsMu.RUnlock()
+	if f, ok := tools[name]; ok {
+		return f, true, false
+	}
+	if f, ok := toolsets[name]; ok {
+		return f, false, true
+	}
+	if f, ok := builtins[name]; ok {
+		return f, true, false
+	}
+	if f, ok := builtinToolsets[name]; ok {
+		return f, false, true
+	}
+	return nil, false, false
+}
+
+// Build creates the agent of def and its sub-agents.
+func Build(def *Definition) (agent.Agent, error) {
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	return def.build(&resources{})
+}
+
+// resources collects the tools, knowledge base backends and remote agent transports of a
+// tree of agents which hold connections, to release them when the tree is replaced.
+type resources []io.Closer
+
+func (r *resources) add(v any) {
+	if closer, ok := v.(io.Closer); ok {
+		*r = append(*r, closer)
+	}
+}
+
+func (r resources) close() {
+	for _, closer := range r {
+		if err := closer.Close(); err != nil {
+			log.Warnf("release agent resource failed: %v", err)
+		}
+	}
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+func (d *Definition) build(res *resources) (agent.Agent, error) {
+	subAgents := make([]agent.Agent, 0, len(d.SubAgents))
+	for _, sub := range d.SubAgents {
+		ag, err := sub.build(res)
+		if err != nil {
+			return nil, err
+		}
+		subAgents = append(subAgents, ag)
+	}
+
+	var ag agent.Agent
+	var err error
+	switch d.kind() {
+	case TypeLLM:
+		ag, err = d.buildLLMAgent(subAgents, res)
+	case TypeSequential:
+		ag, err = sequentialagent.New(sequentialagent.Config{AgentConfig: d.agentConfig(subAgents)})
+	case TypeParallel:
+		ag, err = parallelagent.New(parallelagent.Config{AgentConfig: d.agentConfig(subAgents)})
+	case TypeLoop:
+		ag, err = loopagent.New(loopagent.Config{AgentConfig: d.agentConfig(subAgents), MaxIterations: d.MaxIterations})
+	case TypeRemote:
+		ag, err = d.buildRemoteAgent(res)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("build agent %s: %w", d.Name, err)
+	}
+	return ag, nil
+}
+
+func (d *Definition) agentConfig(subAgents []agent.Agent) agent.Config {
+	return agent.Config{Name: d.Name, Description: d.Description, SubAgents: subAgents}
+}
+
+func (d *Definition) buildLLMAgent(subAgents []agent.Agent, res *resources) (agent.Agent, error) {
+	cfg := &veagent.Config{
+		Config: llmagent.Config{
+			Name:        d.Name,
+			Description: d.Description,
+			Instruction: d.Instruction,
+			OutputKey:   d.OutputKey,
+			SubAgents:   subAgents,
+		},
+	}
+	if d.InstructionFile != "" {
+		instruction, err := os.ReadFile(d.path(d.InstructionFile))
+		if err != nil {
+			return nil, fmt.Errorf("read instruction file: %w", err)
+		}
+		cfg.Instruction = string(instruction)
+	}
+	if m := d.Model; m != nil {
+		cfg.ModelName, cfg.ModelProvider, cfg.ModelAPIBase, cfg.ModelAPIKey = m.Name, m.Provider, m.APIBase, m.APIKey
+		cfg.ModelExtraConfig = m.ExtraConfig
+		cfg.DisableThought = m.DisableThought
+	}
+
+	for _, name := range d.Tools {
+		factory, isTool, _ := lookupTool(name)
+		if isTool {
+			t, err := factory.(ToolFactory)()
+			if err != nil {
+				return nil, fmt.Errorf("create tool %s: %w", name, err)
+			}
+			res.add(t)
+			cfg.Tools = append(cfg.Tools, t)
+			continue
+		}
+		toolset, err := factory.(ToolsetFactory)()
+		if err != nil {
+			return nil, fmt.Errorf("create toolset %s: %w", name, err)
+		}
+		res.add(toolset)
+		cfg.Toolsets = append(cfg.Toolsets, toolset)
+	}
+
+	if len(d.Skills) > 0 {
+		toolset, err := d.skillToolset()
+		if err != nil {
+			return nil, err
+		}
+		res.add(toolset)
+		cfg.Toolsets = append(cfg.Toolsets, toolset)
+	}
+
+	if d.KnowledgeBase != nil {
+		kb, err := d.KnowledgeBase.build()
+		if err != nil {
+			return nil, fmt.Errorf("create knowledge base: %w", err)
+		}
+		res.add(kb.Backend)
+		cfg.KnowledgeBase = kb
+	}
+	return veagent.New(cfg)
+}
+
+func (d *Definition) skillToolset() (tool.Toolset, error) {
+	dirs := make([]string, 0, len(d.Skills))
+	for _, dir := range d.Skills {
+		dirs = append(dirs, d.path(dir))
+	}
+	var executor code_executors.CodeExecutor
+	if d.SkillScripts {
+		executor = code_executors.NewUnsafeLocalCodeExecutor(DefaultSkillScriptTimeout)
+	}
+	return skilltool.NewSkillToolsetFromDirs(dirs, executor)
+}
+
+func (kb *KnowledgeBaseDefinition) build() (*knowledgebase.KnowledgeBase, error) {
+	var backendConfig any
+	switch kb.Backend {
+	case ktypes.LocalBackend:
+		backendConfig = &local_knowledge_backend.Config{Index: kb.Index, TopK: kb.TopK}
+	case ktypes.VikingBackend:
+		backendConfig = &viking_knowledge_backend.Config{Index: kb.Index, TopK: int32(kb.TopK)}
+	case ktypes.OpensearchBackend:
+		backendConfig = &opensearch_knowledge_backend.Config{Index: kb.Index, TopK: kb.TopK}
+	case ktypes.RedisBackend:
+		backendConfig = &redis_knowledge_backend.Config{Index: kb.Index, TopK: kb.TopK}
+	}
+	return knowledgebase.NewKnowledgeBase(kb.Backend,
+		knowledgebase.WithName(kb.Name),
+		knowledgebase.WithDescription(kb.Description),
+		knowledgebase.WithBackendConfig(backendConfig))
+}
+
+func (d *Definition) buildRemoteAgent(res *resources) (agent.Agent, error) {
+	r := d.Remote
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	res.add(closerFunc(func() error {
+		transport.CloseIdleConnections()
+		return nil
+	}))
+	return remoteagent.NewVeRemoteAgent(remoteagent.NewDefaultConfig().
+		SetName(d.Name).
+		SetDescription(d.Description).
+		SetBaseUrl(r.BaseUrl).
+		SetBaseUrls(r.BaseUrls).
+		SetApiKey(r.ApiKey).
+		SetTransport(transport).
+		SetCallTimeout(r.CallTimeout).
+		SetMaxAttempts(r.MaxAttempts))
+}
+
+// files lists the files and directories referenced by the definition and its sub-agents.
+func (d *Definition) files() []string {
+	var files []string
+	if d.InstructionFile != "" {
+		files = append(files, d.path(d.InstructionFile))
+	}
+	for _, dir := range d.Skills {
+		files = append(files, d.path(dir))
+	}
+	for _, sub := range d.SubAgents {
+		files = append(files, sub.files()...)
+	}
+	return files
+}
+
+// path resolves p against the directory of the definition.
+func (d *Definition) path(p string) string {
+	if filepath.IsAbs(p) || d.dir == "" {
+		return p
+	}
+	return filepath.Join(d.dir, p)
+}
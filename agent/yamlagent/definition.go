@@ -0,0 +1,208 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package yamlagent builds agents from YAML definitions, so that agents can be authored
+// without writing Go.
+package yamlagent
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Agent types of Definition.Type.
+const (
+	TypeLLM        = "llm"
+	TypeSequential = "sequential"
+	TypeParallel   = "parallel"
+	TypeLoop       = "loop"
+	TypeRemote     = "remote"
+)
+
+var ErrInvalidDefinition = errors.New("invalid agent definition")
+
+// Definition describes an agent and its sub-agents. Relative paths are resolved against the
+// directory of the YAML file, and ${VAR} is replaced by the environment variable VAR.
+type Definition struct {
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"`
+	Description string `yaml:"description"`
+
+	// Instruction, or InstructionFile holding it, of llm agents.
+	Instruction     string                   `yaml:"instruction"`
+	InstructionFile string                   `yaml:"instruction_file"`
+	OutputKey       string                   `yaml:"output_key"`
+	Model           *ModelDefinition         `yaml:"model"`
+	Tools           []string                 `yaml:"tools"`
+	KnowledgeBase   *KnowledgeBaseDefinition `yaml:"knowledge_base"`
+	// Skills are skill directories, offered to the agent with the skill toolset.
+	Skills []string `yaml:"skills"`
+	// SkillScripts lets the agent run the scripts of its skills on the local host.
+	SkillScripts bool `yaml:"skill_scripts"`
+
+	SubAgents []*Definition `yaml:"sub_agents"`
+	// MaxIterations of loop agents, unlimited when zero.
+	MaxIterations uint `yaml:"max_iterations"`
+
+	Remote *RemoteDefinition `yaml:"remote"`
+
+	dir string
+}
+
+// ModelDefinition overrides the model of an llm agent, taken from the config otherwise.
+type ModelDefinition struct {
+	Name           string         `yaml:"name"`
+	Provider       string         `yaml:"provider"`
+	APIBase        string         `yaml:"api_base"`
+	APIKey         string         `yaml:"api_key"`
+	ExtraConfig    map[string]any `yaml:"extra_config"`
+	DisableThought bool           `yaml:"disable_thought"`
+}
+
+// KnowledgeBaseDefinition gives an llm agent a knowledge base, whose backend reads its
+// connection settings from the config.
+type KnowledgeBaseDefinition struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Backend is local, viking, opensearch or redis.
+	Backend string `yaml:"backend"`
+	Index   string `yaml:"index"`
+	TopK    int    `yaml:"top_k"`
+}
+
+// RemoteDefinition calls an agent served over A2A.
+type RemoteDefinition struct {
+	BaseUrl     string        `yaml:"base_url"`
+	BaseUrls    []string      `yaml:"base_urls"`
+	ApiKey      string        `yaml:"api_key"`
+	CallTimeout time.Duration `yaml:"call_timeout"`
+	MaxAttempts int           `yaml:"max_attempts"`
+}
+
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Parse reads a definition, resolving its relative paths against dir. Unknown fields are
+// rejected so that typos don't go unnoticed.
+func Parse(data []byte, dir string) (*Definition, error) {
+	data = envPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+		return []byte(os.Getenv(string(envPattern.FindSubmatch(match)[1])))
+	})
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var def Definition
+	if err := decoder.Decode(&def); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDefinition, err)
+	}
+	def.setDir(dir)
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+func (d *Definition) setDir(dir string) {
+	d.dir = dir
+	for _, sub := range d.SubAgents {
+		if sub != nil {
+			sub.setDir(dir)
+		}
+	}
+}
+
+// Validate checks the definition and its sub-agents, reporting every problem found.
+func (d *Definition) Validate() error {
+	var errs []error
+	path := d.Name
+	if path == "" {
+		path = "agent"
+	}
+	d.validate(path, map[string]bool{}, &errs)
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidDefinition, errors.Join(errs...))
+	}
+	return nil
+}
+
+func (d *Definition) validate(path string, names map[string]bool, errs *[]error) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+	if d.Name == "" {
+		fail("name is required")
+	} else if names[d.Name] {
+		fail("agent name %s is used twice", d.Name)
+	}
+	names[d.Name] = true
+
+	llmOnly := d.Instruction != "" || d.InstructionFile != "" || d.OutputKey != "" || d.Model != nil ||
+		len(d.Tools) > 0 || d.KnowledgeBase != nil || len(d.Skills) > 0
+	switch d.kind() {
+	case TypeLLM:
+		if d.Instruction != "" && d.InstructionFile != "" {
+			fail("instruction and instruction_file are exclusive")
+		}
+		for _, name := range d.Tools {
+			if !hasTool(name) {
+				fail("unknown tool %s", name)
+			}
+		}
+		if kb := d.KnowledgeBase; kb != nil && !knowledgeBackends[kb.Backend] {
+			fail("unknown knowledge base backend %q", kb.Backend)
+		}
+	case TypeSequential, TypeParallel, TypeLoop:
+		if llmOnly {
+			fail("%s agents take sub-agents only, not instructions, models, tools, knowledge bases or skills", d.kind())
+		}
+		if len(d.SubAgents) == 0 {
+			fail("%s agents need sub-agents", d.kind())
+		}
+	case TypeRemote:
+		if llmOnly || len(d.SubAgents) > 0 {
+			fail("remote agents are configured by remote only")
+		}
+		if d.Remote == nil || d.Remote.BaseUrl == "" {
+			fail("remote.base_url is required")
+		}
+	default:
+		fail("unknown agent type %q", d.Type)
+	}
+	if d.MaxIterations > 0 && d.kind() != TypeLoop {
+		fail("max_iterations applies to loop agents only")
+	}
+	if d.Remote != nil && d.kind() != TypeRemote {
+		fail("remote applies to remote agents only")
+	}
+
+	for i, sub := range d.SubAgents {
+		if sub == nil {
+			fail("sub_agents[%d] is empty", i)
+			continue
+		}
+		sub.validate(fmt.Sprintf("%s.sub_agents[%d](%s)", path, i, sub.Name), names, errs)
+	}
+}
+
+// kind returns the type of the agent, llm when unset.
+func (d *Definition) kind() string {
+	if d.Type == "" {
+		return TypeLLM
+	}
+	return d.Type
+}
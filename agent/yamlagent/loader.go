@@ -0,0 +1,296 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yamlagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volcengine/veadk-go/log"
+	"github.com/volcengine/veadk-go/utils"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/genai"
+)
+
+// LoadFile parses the definition in the YAML file at path.
+func LoadFile(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	def, err := Parse(data, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return def, nil
+}
+
+// Loader is an agent.Loader of the agents defined in YAML files, each becoming an app.
+// Reload rebuilds them, while the invocations of the agents loaded before go on unchanged.
+type Loader struct {
+	paths []string
+	// reloadMu serializes the reloads, so that they are applied in the order they started
+	reloadMu sync.Mutex
+
+	mu      sync.RWMutex
+	current *generation
+	// watched are the definition files and the files they reference
+	watched []string
+	hooks   []func(agent.Loader)
+	// tracked is set by Plugin, the replaced agents are then released after their invocations
+	tracked bool
+	retired []*generation
+	// running holds the generation of every invocation, by invocation ID
+	running map[string]*generation
+}
+
+// generation is the tree of agents built by one reload.
+type generation struct {
+	agent.Loader
+	resources   resources
+	invocations int
+}
+
+// owns reports whether ag belongs to the agents of g.
+func (g *generation) owns(ag agent.Agent) bool {
+	for _, name := range g.ListAgents() {
+		if root, err := g.LoadAgent(name); err == nil && root.FindAgent(ag.Name()) == ag {
+			return true
+		}
+	}
+	return false
+}
+
+var _ agent.Loader = (*Loader)(nil)
+
+// NewLoader builds an agent from every definition file at paths. Directories are scanned
+// for .yaml and .yml files. The first agent is the root agent. Every file is validated
+// before any agent is built.
+func NewLoader(paths ...string) (*Loader, error) {
+	l := &Loader{paths: paths, running: make(map[string]*generation)}
+	if err := l.Reload(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Loader) ListAgents() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.current.ListAgents()
+}
+
+func (l *Loader) LoadAgent(name string) (agent.Agent, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.current.LoadAgent(name)
+}
+
+func (l *Loader) RootAgent() agent.Agent {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.current.RootAgent()
+}
+
+// OnReload calls hook with the new agents after every successful reload.
+func (l *Loader) OnReload(hook func(loader agent.Loader)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
+// Plugin counts the invocations of the agents of each reload, to release the tools,
+// knowledge bases and remote agent connections of the replaced agents once their last
+// invocation ended. It is added to the plugins of the runners of the agents; without it,
+// the replaced agents are left to the garbage collector.
+func (l *Loader) Plugin() *plugin.Plugin {
+	l.mu.Lock()
+	l.tracked = true
+	l.mu.Unlock()
+	// no need to check the error as it is always nil.
+	p, _ := plugin.New(plugin.Config{
+		Name:              "yamlagent_reload",
+		BeforeRunCallback: l.beforeRun,
+		AfterRunCallback:  l.afterRun,
+	})
+	return p
+}
+
+func (l *Loader) beforeRun(ctx agent.InvocationContext) (*genai.Content, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, gen := range append([]*generation{l.current}, l.retired...) {
+		if gen.owns(ctx.Agent()) {
+			gen.invocations++
+			l.running[ctx.InvocationID()] = gen
+			break
+		}
+	}
+	return nil, nil
+}
+
+func (l *Loader) afterRun(ctx agent.InvocationContext) {
+	l.mu.Lock()
+	gen, ok := l.running[ctx.InvocationID()]
+	if !ok {
+		l.mu.Unlock()
+		return
+	}
+	delete(l.running, ctx.InvocationID())
+	gen.invocations--
+	release := gen != l.current && gen.invocations == 0
+	if release {
+		l.retired = slices.DeleteFunc(l.retired, func(g *generation) bool { return g == gen })
+	}
+	l.mu.Unlock()
+
+	if release {
+		gen.resources.close()
+	}
+}
+
+// Reload builds the agents from the definition files again. The current agents are kept
+// when a definition is invalid or an agent fails to build. Concurrent reloads are applied
+// one after the other.
+func (l *Loader) Reload() error {
+	l.reloadMu.Lock()
+	defer l.reloadMu.Unlock()
+
+	gen, watched, err := load(l.paths)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	previous := l.current
+	l.current, l.watched = gen, watched
+	hooks := append([]func(agent.Loader){}, l.hooks...)
+	release := previous != nil && l.tracked && previous.invocations == 0
+	if previous != nil && l.tracked && !release {
+		l.retired = append(l.retired, previous)
+	}
+	l.mu.Unlock()
+
+	if release {
+		previous.resources.close()
+	}
+	if previous != nil {
+		for _, hook := range hooks {
+			hook(gen.Loader)
+		}
+	}
+	return nil
+}
+
+// Watch reloads the agents whenever their definition files, instruction files or skill
+// directories change, polling them every interval, until ctx is done.
+func (l *Loader) Watch(ctx context.Context, interval time.Duration) {
+	for ctx.Err() == nil {
+		l.mu.RLock()
+		watched := l.watched
+		l.mu.RUnlock()
+
+		// the watched files change with the definitions, restart watching after a reload
+		watchCtx, cancel := context.WithCancel(ctx)
+		utils.WatchPaths(watchCtx, watched, interval, func() {
+			defer cancel()
+			if err := l.Reload(); err != nil {
+				log.Warnf("reload agents from %v failed, keeping the current agents: %v", l.paths, err)
+				return
+			}
+			log.Infof("reloaded agents from %v", l.paths)
+		})
+		cancel()
+	}
+}
+
+func load(paths []string) (*generation, []string, error) {
+	files, err := definitionFiles(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("%w: no agent definition found in %v", ErrInvalidDefinition, paths)
+	}
+
+	var defs []*Definition
+	var errs []error
+	watched := slices.Clone(paths)
+	for _, file := range files {
+		def, err := LoadFile(file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		defs = append(defs, def)
+		watched = append(watched, def.files()...)
+	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	var res resources
+	agents := make([]agent.Agent, 0, len(defs))
+	for i, def := range defs {
+		ag, err := def.build(&res)
+		if err != nil {
+			res.close()
+			return nil, nil, fmt.Errorf("%s: %w", files[i], err)
+		}
+		agents = append(agents, ag)
+	}
+	loader, err := agent.NewMultiLoader(agents[0], agents[1:]...)
+	if err != nil {
+		res.close()
+		return nil, nil, err
+	}
+	return &generation{Loader: loader, resources: res}, watched, nil
+}
+
+func definitionFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		var found []string
+		for _, entry := range entries {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				found = append(found, filepath.Join(path, entry.Name()))
+			}
+		}
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+	return files, nil
+}
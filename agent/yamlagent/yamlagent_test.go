@@ -0,0 +1,264 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yamlagent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+const assistant = `
+name: assistant
+description: Answers questions
+instruction_file: prompts/assistant.md
+model:
+  name: test-model
+  provider: openai
+  api_base: http://127.0.0.1:1/v1
+  api_key: ${YAMLAGENT_TEST_KEY}
+tools: [get_city_weather, lookup]
+skills: [skills/calc]
+knowledge_base:
+  backend: local
+  top_k: 3
+sub_agents:
+  - name: reviewer
+    type: loop
+    max_iterations: 2
+    sub_agents:
+      - name: critic
+        instruction: Review the answer.
+        model: {name: test-model, provider: openai, api_key: key}
+  - name: researcher
+    type: remote
+    description: Researches topics
+    remote:
+      base_url: http://127.0.0.1:1
+      base_urls: [http://127.0.0.1:2]
+      call_timeout: 30s
+`
+
+func TestLoadFile(t *testing.T) {
+	t.Setenv("YAMLAGENT_TEST_KEY", "secret")
+	RegisterTool("lookup", func() (tool.Tool, error) {
+		return functiontool.New(functiontool.Config{Name: "lookup"}, func(tool.Context, struct{}) (string, error) { return "", nil })
+	})
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "assistant.yaml"), assistant)
+	writeFile(t, filepath.Join(dir, "prompts/assistant.md"), "Answer briefly.")
+	writeFile(t, filepath.Join(dir, "skills/calc/SKILL.md"), "---\nname: calc\ndescription: Calculates\n---\n\n# Calc\n")
+
+	def, err := LoadFile(filepath.Join(dir, "assistant.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", def.Model.APIKey)
+	assert.Equal(t, "30s", def.SubAgents[1].Remote.CallTimeout.String())
+
+	ag, err := Build(def)
+	require.NoError(t, err)
+	assert.Equal(t, "assistant", ag.Name())
+	require.Len(t, ag.SubAgents(), 2)
+	reviewer := ag.SubAgents()[0]
+	assert.Equal(t, "reviewer", reviewer.Name())
+	assert.Equal(t, "critic", reviewer.SubAgents()[0].Name())
+	assert.Equal(t, "Researches topics", ag.SubAgents()[1].Description())
+}
+
+func TestParse_Validation(t *testing.T) {
+	_, err := Parse([]byte(`
+name: root
+tools: [no_such_tool]
+sub_agents:
+  - name: root
+  - name: pipeline
+    type: sequential
+    instruction: not for workflows
+  - name: remote
+    type: remote
+  - name: odd
+    type: graph
+    max_iterations: 3
+`), "")
+	require.ErrorIs(t, err, ErrInvalidDefinition)
+	for _, problem := range []string{
+		"root: unknown tool no_such_tool",
+		"agent name root is used twice",
+		"root.sub_agents[1](pipeline): sequential agents take sub-agents only",
+		"sequential agents need sub-agents",
+		"remote.base_url is required",
+		`unknown agent type "graph"`,
+		"max_iterations applies to loop agents only",
+	} {
+		assert.ErrorContains(t, err, problem)
+	}
+
+	// unknown fields are typos
+	_, err = Parse([]byte("name: root\ninstructions: hi\n"), "")
+	assert.ErrorIs(t, err, ErrInvalidDefinition)
+	assert.ErrorContains(t, err, "field instructions not found")
+
+	_, err = Parse([]byte("description: nameless\n"), "")
+	assert.ErrorContains(t, err, "agent: name is required")
+}
+
+func TestNewLoader(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "b_writer.yaml"), `
+name: writer
+type: sequential
+sub_agents:
+  - {name: drafter, model: {provider: openai, api_key: key}}
+  - {name: editor, model: {provider: openai, api_key: key}}
+`)
+	writeFile(t, filepath.Join(dir, "a_weather.yml"), `
+name: weather
+tools: [get_city_weather]
+model: {provider: openai, api_key: key}
+`)
+	writeFile(t, filepath.Join(dir, "notes.txt"), "not an agent")
+
+	loader, err := NewLoader(dir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"weather", "writer"}, loader.ListAgents())
+	assert.Equal(t, "weather", loader.RootAgent().Name())
+	writer, err := loader.LoadAgent("writer")
+	require.NoError(t, err)
+	assert.Len(t, writer.SubAgents(), 2)
+
+	// every file is validated before reporting
+	writeFile(t, filepath.Join(dir, "c_broken.yaml"), "name: broken\ntools: [nope]\n")
+	writeFile(t, filepath.Join(dir, "d_broken.yaml"), "name: broken2\ntype: loop\n")
+	_, err = NewLoader(dir)
+	assert.ErrorContains(t, err, "c_broken.yaml")
+	assert.ErrorContains(t, err, "d_broken.yaml")
+
+	_, err = NewLoader(t.TempDir())
+	assert.ErrorIs(t, err, ErrInvalidDefinition)
+}
+
+func TestToolNames(t *testing.T) {
+	names := ToolNames()
+	assert.Contains(t, names, "web_search")
+	assert.Contains(t, names, "mcp_router")
+}
+
+func TestLoader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "weather.yaml")
+	writeFile(t, file, "name: weather\ninstruction_file: weather.md\nmodel: {provider: openai, api_key: key}\n")
+	writeFile(t, filepath.Join(dir, "weather.md"), "Report the weather.")
+
+	loader, err := NewLoader(file)
+	require.NoError(t, err)
+	before := loader.RootAgent()
+	reloaded := make(chan string, 4)
+	loader.OnReload(func(l agent.Loader) { reloaded <- l.RootAgent().Name() })
+
+	// invalid definitions keep the current agents
+	writeFile(t, file, "name: weather\ntools: [nope]\n")
+	assert.Error(t, loader.Reload())
+	assert.Same(t, before, loader.RootAgent())
+
+	writeFile(t, file, "name: forecast\ninstruction_file: weather.md\nmodel: {provider: openai, api_key: key}\n")
+	require.NoError(t, loader.Reload())
+	assert.Equal(t, "forecast", <-reloaded)
+	assert.Equal(t, []string{"forecast"}, loader.ListAgents())
+
+	// changes of referenced files are watched too
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go loader.Watch(ctx, 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	writeFile(t, filepath.Join(dir, "weather.md"), "Report the weather in detail.")
+	select {
+	case name := <-reloaded:
+		assert.Equal(t, "forecast", name)
+	case <-time.After(2 * time.Second):
+		t.Fatal("agents were not reloaded")
+	}
+	assert.NotSame(t, before, loader.RootAgent())
+}
+
+// closingTool counts the times it was closed.
+type closingTool struct {
+	tool.Tool
+	closed *atomic.Int32
+}
+
+func (t closingTool) Close() error {
+	t.closed.Add(1)
+	return nil
+}
+
+type invocation struct {
+	agent.InvocationContext
+	id    string
+	agent agent.Agent
+}
+
+func (i invocation) InvocationID() string { return i.id }
+
+func (i invocation) Agent() agent.Agent { return i.agent }
+
+func TestLoader_ReleasesReplacedAgents(t *testing.T) {
+	var closed atomic.Int32
+	RegisterTool("closing", func() (tool.Tool, error) {
+		lookup, err := functiontool.New(functiontool.Config{Name: "closing"}, func(tool.Context, struct{}) (string, error) { return "", nil })
+		return closingTool{Tool: lookup, closed: &closed}, err
+	})
+	file := filepath.Join(t.TempDir(), "weather.yaml")
+	writeFile(t, file, "name: weather\ntools: [closing]\nmodel: {provider: openai, api_key: key}\n")
+	loader, err := NewLoader(file)
+	require.NoError(t, err)
+	p := loader.Plugin()
+
+	// the agents are released once their last invocation ended
+	first := invocation{id: "first", agent: loader.RootAgent()}
+	_, err = p.BeforeRunCallback()(first)
+	require.NoError(t, err)
+	require.NoError(t, loader.Reload())
+	assert.EqualValues(t, 0, closed.Load(), "an invocation is running")
+	second := invocation{id: "second", agent: loader.RootAgent()}
+	_, err = p.BeforeRunCallback()(second)
+	require.NoError(t, err)
+	p.AfterRunCallback()(first)
+	assert.EqualValues(t, 1, closed.Load())
+
+	// idle agents are released on reload
+	p.AfterRunCallback()(second)
+	require.NoError(t, loader.Reload())
+	assert.EqualValues(t, 2, closed.Load())
+
+	// failed builds release what they built
+	writeFile(t, file, "name: weather\ninstruction_file: missing.md\nmodel: {provider: openai, api_key: key}\n"+
+		"sub_agents: [{name: helper, tools: [closing], model: {provider: openai, api_key: key}}]\n")
+	assert.Error(t, loader.Reload())
+	assert.EqualValues(t, 3, closed.Load())
+}
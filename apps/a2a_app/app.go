@@ -16,14 +16,21 @@ package a2a_app
 
 import (
 	"context"
+	"fmt"
 	"net/url"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/volcengine/veadk-go/agent/registry"
 	"github.com/volcengine/veadk-go/log"
 
 	a2acore "github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2asrv"
 	"github.com/gorilla/mux"
 	"github.com/volcengine/veadk-go/apps"
+	"github.com/volcengine/veadk-go/auth/httpauth"
+	"google.golang.org/adk/agent"
 	"google.golang.org/adk/cmd/launcher/web/a2a"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/server/adka2a"
@@ -32,55 +39,245 @@ import (
 const (
 	serverName = "agentkit a2a server"
 	apiPath    = "/"
+	// AgentsPathPrefix hosts every agent of the loader under its own path, "/a2a/<agent name>/",
+	// next to the root agent at "/".
+	AgentsPathPrefix = "/a2a/"
 )
 
+// SkillsBuilder lists the skills of an agent for its extended card.
+type SkillsBuilder func(agent agent.Agent) []a2acore.AgentSkill
+
+type Option func(*agentkitA2AServerApp)
+
+// WithExtendedCard sets SupportsAuthenticatedExtendedCard on the agent cards. Authenticated
+// callers get the skills built by skills, ExtendedAgentSkills when nil, while the public
+// card only lists the agent's own skill.
+func WithExtendedCard(skills SkillsBuilder) Option {
+	return func(a *agentkitA2AServerApp) {
+		if skills == nil {
+			skills = ExtendedAgentSkills
+		}
+		a.extendedSkills = skills
+	}
+}
+
+// WithTaskStore keeps the tasks of every hosted agent in store instead of in memory,
+// e.g. a taskstore.SQLTaskStore so that tasks survive restarts.
+func WithTaskStore(store a2asrv.TaskStore) Option {
+	return func(a *agentkitA2AServerApp) {
+		a.taskStore = store
+	}
+}
+
+// WithPushNotifications lets clients register webhooks for their tasks in store, which are
+// notified by sender, and advertises Capabilities.PushNotifications on the agent cards.
+func WithPushNotifications(store a2asrv.PushConfigStore, sender a2asrv.PushSender) Option {
+	return func(a *agentkitA2AServerApp) {
+		a.pushConfigStore = store
+		a.pushSender = sender
+	}
+}
+
+// WithRegistry registers the card of every hosted agent in reg while the server runs,
+// renewed by a heartbeat so that the cards expire after ttl once the server is gone.
+func WithRegistry(reg registry.Registry, ttl time.Duration) Option {
+	return func(a *agentkitA2AServerApp) {
+		a.heartbeat = registry.NewHeartbeat(reg, ttl)
+	}
+}
+
 type agentkitA2AServerApp struct {
 	*apps.ApiConfig
-	a2aAgentUrl string
+	extendedSkills  SkillsBuilder
+	modes           ModesBuilder
+	taskStore       a2asrv.TaskStore
+	pushConfigStore a2asrv.PushConfigStore
+	pushSender      a2asrv.PushSender
+	heartbeat       *registry.Heartbeat
 }
 
 func (a *agentkitA2AServerApp) Run(ctx context.Context, config *apps.RunConfig) error {
-	return apps.Run(ctx, config, a)
+	return RunRegistered(ctx, a, func(ctx context.Context) error {
+		return apps.Run(ctx, config, a)
+	})
+}
+
+// RunRegistered calls run, keeping the cards of app registered in the registry of
+// WithRegistry until it returns. Apps embedding an A2A app call it around apps.Run.
+func RunRegistered(ctx context.Context, app apps.BasicApp, run func(ctx context.Context) error) error {
+	a2aApp, ok := app.(*agentkitA2AServerApp)
+	if !ok || a2aApp.heartbeat == nil {
+		return run(ctx)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		a2aApp.heartbeat.Run(ctx)
+	}()
+	err := run(ctx)
+	// the cards are deregistered once the server stopped
+	cancel()
+	<-stopped
+	return err
 }
 
 func (a *agentkitA2AServerApp) SetupRouters(router *mux.Router, config *apps.RunConfig) error {
-	publicURL, err := url.JoinPath(a.a2aAgentUrl, apiPath)
+	rootAgent := config.AgentLoader.RootAgent()
+	rootCard, err := a.mountAgent(router, config, rootAgent, apiPath)
 	if err != nil {
 		return err
 	}
+	a.register(rootCard)
 
-	rootAgent := config.AgentLoader.RootAgent()
+	for _, name := range config.AgentLoader.ListAgents() {
+		ag, err := config.AgentLoader.LoadAgent(name)
+		if err != nil {
+			return fmt.Errorf("load agent %s failed: %w", name, err)
+		}
+		card, err := a.mountAgent(router, config, ag, AgentsPathPrefix+url.PathEscape(name)+"/")
+		if err != nil {
+			return err
+		}
+		// the root agent is registered at the root path only
+		if name != rootAgent.Name() {
+			a.register(card)
+		}
+	}
+
+	a2aLauncher := a2a.NewLauncher()
+	a2aLauncher.UserMessage(a.GetWebUrl()+apiPath, log.Println)
+	log.Infof("       a2a:  agents are served on %s%s<agent name>/, published as %s", a.GetWebUrl(), AgentsPathPrefix, a.GetPublicUrl())
+
+	return nil
+}
+
+// register publishes card in the registry of WithRegistry.
+func (a *agentkitA2AServerApp) register(card *a2acore.AgentCard) {
+	if a.heartbeat != nil {
+		a.heartbeat.Add(card)
+	}
+}
+
+// mountAgent serves ag over JSON-RPC at path, with its agent card under path.
+func (a *agentkitA2AServerApp) mountAgent(router *mux.Router, config *apps.RunConfig, ag agent.Agent, path string) (*a2acore.AgentCard, error) {
+	publicURL, err := url.JoinPath(a.GetPublicUrl(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	skills := adka2a.BuildAgentSkills(ag)
+	if a.extendedSkills != nil {
+		skills = publicSkills(ag, skills)
+	}
+	modes := a.modes
+	if modes == nil {
+		modes = AgentModes
+	}
+	inputModes, outputModes := modes(ag)
 	agentCard := &a2acore.AgentCard{
-		Name:                              rootAgent.Name(),
-		Description:                       rootAgent.Description(),
-		DefaultInputModes:                 []string{"text/plain"},
-		DefaultOutputModes:                []string{"text/plain"},
+		Name:                              ag.Name(),
+		Description:                       ag.Description(),
+		DefaultInputModes:                 inputModes,
+		DefaultOutputModes:                outputModes,
 		URL:                               publicURL,
 		PreferredTransport:                a2acore.TransportProtocolJSONRPC,
-		Skills:                            adka2a.BuildAgentSkills(rootAgent),
-		Capabilities:                      a2acore.AgentCapabilities{Streaming: true},
-		SupportsAuthenticatedExtendedCard: false,
+		Skills:                            skills,
+		Capabilities:                      a2acore.AgentCapabilities{Streaming: true, PushNotifications: a.pushConfigStore != nil && a.pushSender != nil},
+		SupportsAuthenticatedExtendedCard: a.extendedSkills != nil,
 	}
-	router.Handle(a2asrv.WellKnownAgentCardPath, a2asrv.NewStaticAgentCardHandler(agentCard))
+	router.Handle(strings.TrimSuffix(path, "/")+a2asrv.WellKnownAgentCardPath, a2asrv.NewStaticAgentCardHandler(agentCard))
 
-	agent := config.AgentLoader.RootAgent()
 	executor := adka2a.NewExecutor(adka2a.ExecutorConfig{
 		RunnerConfig: runner.Config{
-			AppName:         agent.Name(),
-			Agent:           agent,
+			AppName:         ag.Name(),
+			Agent:           ag,
 			SessionService:  config.SessionService,
 			ArtifactService: config.ArtifactService,
 			MemoryService:   config.MemoryService,
 			PluginConfig:    config.PluginConfig,
 		},
+		AfterEventCallback: mediaParts(ag.Name(), config.ArtifactService),
 	})
-	reqHandler := a2asrv.NewHandler(executor, config.A2AOptions...)
-	router.Handle(apiPath, a2asrv.NewJSONRPCHandler(reqHandler))
+	options := append([]a2asrv.RequestHandlerOption{a2asrv.WithCallInterceptor(principalInterceptor{})}, config.A2AOptions...)
+	if a.taskStore != nil {
+		options = append(options, a2asrv.WithTaskStore(a.taskStore))
+	}
+	if a.pushConfigStore != nil && a.pushSender != nil {
+		options = append(options, a2asrv.WithPushNotifications(a.pushConfigStore, a.pushSender))
+	}
+	if a.extendedSkills != nil {
+		options = append(options, a2asrv.WithExtendedAgentCardProducer(extendedCardProducer(agentCard, a.extendedSkills(ag))))
+	}
+	reqHandler := a2asrv.NewHandler(executor, options...)
+	router.Handle(path, a2asrv.NewJSONRPCHandler(reqHandler))
 
-	a2aLauncher := a2a.NewLauncher()
-	a2aLauncher.UserMessage(a.GetWebUrl()+apiPath, log.Println)
+	return agentCard, nil
+}
 
-	return nil
+// publicSkills keeps the agent's own skill, leaving its tools and sub-agents to the extended card.
+func publicSkills(ag agent.Agent, skills []a2acore.AgentSkill) []a2acore.AgentSkill {
+	idx := slices.IndexFunc(skills, func(s a2acore.AgentSkill) bool { return s.ID == ag.Name() })
+	if idx < 0 {
+		return nil
+	}
+	return skills[idx : idx+1]
+}
+
+// extendedCardProducer serves card with skills to authenticated callers only.
+func extendedCardProducer(card *a2acore.AgentCard, skills []a2acore.AgentSkill) a2asrv.AgentCardProducer {
+	return a2asrv.AgentCardProducerFn(func(ctx context.Context) (*a2acore.AgentCard, error) {
+		if _, ok := httpauth.FromContext(ctx); !ok {
+			return nil, a2acore.ErrUnauthenticated
+		}
+		extended := *card
+		extended.Skills = skills
+		return &extended, nil
+	})
+}
+
+// ExtendedAgentSkills lists the skills of ag, its tools and its direct sub-agents as
+// adka2a.BuildAgentSkills does, plus the skills of deeper descendants tagged with their path.
+func ExtendedAgentSkills(ag agent.Agent) []a2acore.AgentSkill {
+	skills := adka2a.BuildAgentSkills(ag)
+	for _, sub := range ag.SubAgents() {
+		skills = append(skills, descendantSkills(sub, sub.Name())...)
+	}
+	return skills
+}
+
+func descendantSkills(parent agent.Agent, path string) []a2acore.AgentSkill {
+	var skills []a2acore.AgentSkill
+	for _, sub := range parent.SubAgents() {
+		subPath := path + "/" + sub.Name()
+		for _, skill := range adka2a.BuildAgentSkills(sub) {
+			// sub-agents of sub were already listed by BuildAgentSkills(sub), keep its own skills only
+			if slices.ContainsFunc(skill.Tags, func(t string) bool { return strings.HasPrefix(t, "sub_agent:") }) {
+				continue
+			}
+			skills = append(skills, a2acore.AgentSkill{
+				ID:          strings.ReplaceAll(subPath, "/", "_") + "_" + skill.ID,
+				Name:        subPath + ": " + skill.Name,
+				Description: skill.Description,
+				Tags:        slices.Concat([]string{"sub_agent:" + subPath}, skill.Tags),
+			})
+		}
+		skills = append(skills, descendantSkills(sub, subPath)...)
+	}
+	return skills
+}
+
+// principalInterceptor hands the caller authenticated by apps.Run to the A2A server,
+// which uses its name as the ADK user ID instead of one derived from the context ID.
+type principalInterceptor struct {
+	a2asrv.PassthroughCallInterceptor
+}
+
+func (principalInterceptor) Before(ctx context.Context, callCtx *a2asrv.CallContext, _ *a2asrv.Request) (context.Context, error) {
+	if p, ok := httpauth.FromContext(ctx); ok {
+		callCtx.User = &a2asrv.AuthenticatedUser{UserName: p.Subject}
+	}
+	return ctx, nil
 }
 
 func (a *agentkitA2AServerApp) GetApiConfig() *apps.ApiConfig {
@@ -91,9 +288,12 @@ func (a *agentkitA2AServerApp) GetServerName() string {
 	return serverName
 }
 
-func NewAgentkitA2AServerApp(config *apps.ApiConfig) apps.BasicApp {
-	return &agentkitA2AServerApp{
-		ApiConfig:   config,
-		a2aAgentUrl: config.GetWebUrl(),
+func NewAgentkitA2AServerApp(config *apps.ApiConfig, opts ...Option) apps.BasicApp {
+	a := &agentkitA2AServerApp{
+		ApiConfig: config,
+	}
+	for _, opt := range opts {
+		opt(a)
 	}
+	return a
 }
@@ -0,0 +1,262 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package a2a_app
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	a2acore "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2aclient"
+	"github.com/a2aproject/a2a-go/a2aclient/agentcard"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/agent/registry"
+	"github.com/volcengine/veadk-go/agent/remoteagent"
+	"github.com/volcengine/veadk-go/apps"
+	"github.com/volcengine/veadk-go/apps/a2a_app/taskstore"
+	"github.com/volcengine/veadk-go/auth/httpauth"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func replyAgent(t *testing.T, name, reply string, subAgents ...agent.Agent) agent.Agent {
+	a, err := agent.New(agent.Config{
+		Name:        name,
+		Description: name + " agent",
+		SubAgents:   subAgents,
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				event := session.NewEvent(ctx.InvocationID())
+				event.Author = name
+				event.Content = genai.NewContentFromText(reply, genai.RoleModel)
+				yield(event, nil)
+			}
+		},
+	})
+	require.NoError(t, err)
+	return a
+}
+
+func TestSetupRouters_HostsEveryAgent(t *testing.T) {
+	leaf := replyAgent(t, "leaf", "leaf")
+	helper := replyAgent(t, "helper", "hello from helper", leaf)
+	root := replyAgent(t, "root", "hello from root", helper)
+	other := replyAgent(t, "other", "hello from other")
+	loader, err := agent.NewMultiLoader(root, other)
+	require.NoError(t, err)
+
+	apiKeys, err := httpauth.NewAPIKeyAuthenticator(map[string]*httpauth.Principal{"key": {Subject: "alice"}})
+	require.NoError(t, err)
+	router := mux.NewRouter()
+	router.Use(httpauth.Middleware(httpauth.Config{
+		Authenticator: apiKeys,
+		Rules:         []httpauth.Rule{{PathSuffix: "/.well-known/agent-card.json", Public: true}},
+	}))
+	app := NewAgentkitA2AServerApp(apps.DefaultApiConfig().SetPublicURL("https://agents.example.com/"), WithExtendedCard(nil))
+	require.NoError(t, app.SetupRouters(router, &apps.RunConfig{
+		SessionService: session.InMemoryService(),
+		AgentLoader:    loader,
+	}))
+	server := httptest.NewServer(router)
+	defer server.Close()
+	ctx := context.Background()
+
+	rootCard, err := agentcard.DefaultResolver.Resolve(ctx, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "root", rootCard.Name)
+	assert.Equal(t, "https://agents.example.com/", rootCard.URL)
+
+	card, err := agentcard.DefaultResolver.Resolve(ctx, server.URL+"/a2a/other")
+	require.NoError(t, err)
+	assert.Equal(t, "other", card.Name)
+	assert.Equal(t, "https://agents.example.com/a2a/other/", card.URL)
+	assert.True(t, card.SupportsAuthenticatedExtendedCard)
+
+	// the card points at the ingress, talk to the test server instead
+	card.URL = server.URL + "/a2a/other/"
+	client, err := a2aclient.NewFromCard(ctx, card, a2aclient.WithInterceptors(&remoteagent.AuthInterceptor{Token: "key"}))
+	require.NoError(t, err)
+	result, err := client.SendMessage(ctx, &a2acore.MessageSendParams{
+		Message: a2acore.NewMessage(a2acore.MessageRoleUser, a2acore.TextPart{Text: "hi"}),
+	})
+	require.NoError(t, err)
+	task, ok := result.(*a2acore.Task)
+	require.True(t, ok, "%T", result)
+	require.NotEmpty(t, task.Artifacts)
+	assert.Equal(t, a2acore.TextPart{Text: "hello from other"}, task.Artifacts[0].Parts[0])
+
+	t.Run("extended card", func(t *testing.T) {
+		assert.Len(t, rootCard.Skills, 1, "the public card lists the agent's own skill only")
+
+		rootCard.URL = server.URL + "/"
+		client, err := a2aclient.NewFromCard(ctx, rootCard, a2aclient.WithInterceptors(&remoteagent.AuthInterceptor{Token: "key"}))
+		require.NoError(t, err)
+		extended, err := client.GetAgentCard(ctx)
+		require.NoError(t, err)
+		var names []string
+		for _, skill := range extended.Skills {
+			names = append(names, skill.Name)
+		}
+		assert.Contains(t, names, "helper: custom")
+		assert.Contains(t, names, "helper/leaf: custom", "deeper descendants are listed")
+
+		anonymous, err := a2aclient.NewFromCard(ctx, rootCard)
+		require.NoError(t, err)
+		_, err = anonymous.GetAgentCard(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("extended card requires an authenticated caller", func(t *testing.T) {
+		producer := extendedCardProducer(rootCard, ExtendedAgentSkills(root))
+		_, err := producer.Card(ctx)
+		assert.ErrorIs(t, err, a2acore.ErrUnauthenticated)
+	})
+}
+
+func TestExtendedAgentSkills_NoExtendedCard(t *testing.T) {
+	router := mux.NewRouter()
+	app := NewAgentkitA2AServerApp(apps.DefaultApiConfig().SetPublicURL(""))
+	require.NoError(t, app.SetupRouters(router, &apps.RunConfig{
+		SessionService: session.InMemoryService(),
+		AgentLoader:    agent.NewSingleLoader(replyAgent(t, "root", "hi", replyAgent(t, "helper", "hi"))),
+	}))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/a2a/root/.well-known/agent-card.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"url":"http://localhost:8000/a2a/root/"`)
+	assert.Contains(t, rec.Body.String(), `helper: `, "without an extended card the public card keeps every skill")
+}
+
+func TestSetupRouters_PushNotifications(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Skipf("sqlite is not available: %v", err)
+	}
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	defer sqlDB.Close()
+	tasks, err := taskstore.NewSQLTaskStore(db)
+	require.NoError(t, err)
+	pushConfigs, err := taskstore.NewSQLPushConfigStore(db)
+	require.NoError(t, err)
+
+	notified := make(chan *a2acore.Task, 10)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var task a2acore.Task
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&task))
+		assert.Equal(t, "secret", r.Header.Get(taskstore.NotificationTokenHeader))
+		notified <- &task
+	}))
+	defer webhook.Close()
+
+	router := mux.NewRouter()
+	app := NewAgentkitA2AServerApp(apps.DefaultApiConfig().SetPublicURL(""),
+		WithTaskStore(tasks),
+		WithPushNotifications(pushConfigs, taskstore.NewWebhookSender(taskstore.WebhookConfig{AllowPrivateNetworks: true, FailOnError: true})))
+	require.NoError(t, app.SetupRouters(router, &apps.RunConfig{
+		SessionService: session.InMemoryService(),
+		AgentLoader:    agent.NewSingleLoader(replyAgent(t, "root", "done")),
+	}))
+	server := httptest.NewServer(router)
+	defer server.Close()
+	ctx := context.Background()
+
+	card, err := agentcard.DefaultResolver.Resolve(ctx, server.URL)
+	require.NoError(t, err)
+	assert.True(t, card.Capabilities.PushNotifications)
+
+	card.URL = server.URL + "/"
+	client, err := a2aclient.NewFromCard(ctx, card)
+	require.NoError(t, err)
+	result, err := client.SendMessage(ctx, &a2acore.MessageSendParams{
+		Message: a2acore.NewMessage(a2acore.MessageRoleUser, a2acore.TextPart{Text: "hi"}),
+		Config:  &a2acore.MessageSendConfig{PushConfig: &a2acore.PushConfig{URL: webhook.URL, Token: "secret"}},
+	})
+	require.NoError(t, err)
+	task, ok := result.(*a2acore.Task)
+	require.True(t, ok, "%T", result)
+
+	stored, _, err := tasks.Get(ctx, task.ID)
+	require.NoError(t, err, "the task is persisted")
+	assert.Equal(t, a2acore.TaskStateCompleted, stored.Status.State)
+
+	select {
+	case got := <-notified:
+		assert.Equal(t, task.ID, got.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no push notification delivered")
+	}
+}
+
+func TestSetupRouters_Registry(t *testing.T) {
+	root := replyAgent(t, "root", "hello from root")
+	other := replyAgent(t, "other", "hello from other")
+	loader, err := agent.NewMultiLoader(root, other)
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	server := httptest.NewServer(router)
+	defer server.Close()
+	reg, err := registry.NewStaticRegistry()
+	require.NoError(t, err)
+	app := NewAgentkitA2AServerApp(apps.DefaultApiConfig().SetPublicURL(server.URL), WithRegistry(reg, time.Minute))
+	require.NoError(t, app.SetupRouters(router, &apps.RunConfig{
+		SessionService: session.InMemoryService(),
+		AgentLoader:    loader,
+	}))
+
+	ctx := context.Background()
+	entries, err := reg.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "other", entries[0].Name())
+	assert.Equal(t, server.URL+"/a2a/other/", entries[0].URL())
+	assert.Equal(t, "root", entries[1].Name())
+	assert.Equal(t, server.URL+"/", entries[1].URL())
+
+	// the agents are discovered and called through the registry
+	remotes, err := registry.NewLoader(ctx, registry.LoaderConfig{Registry: reg})
+	require.NoError(t, err)
+	remote, err := remotes.LoadAgent("other")
+	require.NoError(t, err)
+	r, err := runner.New(runner.Config{
+		AppName:           "client",
+		Agent:             remote,
+		SessionService:    session.InMemoryService(),
+		AutoCreateSession: true,
+	})
+	require.NoError(t, err)
+	var replies []string
+	for event, err := range r.Run(ctx, "user", "session", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		require.NoError(t, err)
+		if event.Content != nil && len(event.Content.Parts) > 0 {
+			replies = append(replies, event.Content.Parts[0].Text)
+		}
+	}
+	assert.Contains(t, replies, "hello from other")
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package a2a_app
+
+import (
+	"context"
+	"iter"
+	"net/http/httptest"
+	"testing"
+
+	a2acore "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2aclient"
+	"github.com/a2aproject/a2a-go/a2aclient/agentcard"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/apps"
+	"github.com/volcengine/veadk-go/tool/approval"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/server/adka2a"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/toolconfirmation"
+	"google.golang.org/genai"
+)
+
+// refundModel calls the refund tool, and answers once it has its result.
+type refundModel struct{}
+
+func (refundModel) Name() string { return "refund-model" }
+
+func (refundModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		if last := req.Contents[len(req.Contents)-1]; last.Parts[0].FunctionResponse != nil {
+			yield(&model.LLMResponse{Content: genai.NewContentFromText("refunded", genai.RoleModel)}, nil)
+			return
+		}
+		yield(&model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{
+			FunctionCall: &genai.FunctionCall{ID: "call-1", Name: "refund", Args: map[string]any{"order": "42"}},
+		}}}}, nil)
+	}
+}
+
+func TestSetupRouters_ApprovalInputRequired(t *testing.T) {
+	refunds := 0
+	refund, err := functiontool.New(functiontool.Config{Name: "refund", Description: "Refunds an order."},
+		func(_ tool.Context, _ map[string]any) (map[string]any, error) {
+			refunds++
+			return map[string]any{}, nil
+		})
+	require.NoError(t, err)
+	ag, err := llmagent.New(llmagent.Config{Name: "shop", Model: refundModel{}, Tools: []tool.Tool{refund}})
+	require.NoError(t, err)
+	approvals, err := approval.NewPlugin(&approval.PluginConfig{Policy: approval.ByName("refund")})
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	require.NoError(t, NewAgentkitA2AServerApp(apps.DefaultApiConfig()).SetupRouters(router, &apps.RunConfig{
+		SessionService: session.InMemoryService(),
+		AgentLoader:    agent.NewSingleLoader(ag),
+		PluginConfig:   runner.PluginConfig{Plugins: []*plugin.Plugin{approvals}},
+	}))
+	server := httptest.NewServer(router)
+	defer server.Close()
+	ctx := context.Background()
+
+	card, err := agentcard.DefaultResolver.Resolve(ctx, server.URL)
+	require.NoError(t, err)
+	card.URL = server.URL + "/"
+	client, err := a2aclient.NewFromCard(ctx, card)
+	require.NoError(t, err)
+
+	result, err := client.SendMessage(ctx, &a2acore.MessageSendParams{
+		Message: a2acore.NewMessage(a2acore.MessageRoleUser, a2acore.TextPart{Text: "Refund order 42"}),
+	})
+	require.NoError(t, err)
+	task, ok := result.(*a2acore.Task)
+	require.True(t, ok, "%T", result)
+	require.Equal(t, a2acore.TaskStateInputRequired, task.Status.State)
+	assert.Zero(t, refunds)
+
+	// the task asks for the confirmation of the refund call
+	parts, err := adka2a.ToGenAIParts(task.Status.Message.Parts)
+	require.NoError(t, err)
+	var confirmationID string
+	for _, part := range parts {
+		if part.FunctionCall != nil && part.FunctionCall.Name == toolconfirmation.FunctionCallName {
+			confirmationID = part.FunctionCall.ID
+		}
+	}
+	require.NotEmpty(t, confirmationID)
+
+	answer, err := adka2a.ToA2APart(approval.Decision{Approved: true}.Content(confirmationID).Parts[0], nil)
+	require.NoError(t, err)
+	result, err = client.SendMessage(ctx, &a2acore.MessageSendParams{
+		Message: a2acore.NewMessageForTask(a2acore.MessageRoleUser, task, answer),
+	})
+	require.NoError(t, err)
+	task, ok = result.(*a2acore.Task)
+	require.True(t, ok, "%T", result)
+	assert.Equal(t, a2acore.TaskStateCompleted, task.Status.State)
+	assert.Equal(t, 1, refunds)
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package a2a_app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"sort"
+
+	a2acore "github.com/a2aproject/a2a-go/a2a"
+	"github.com/volcengine/veadk-go/agent/llmagent"
+	"github.com/volcengine/veadk-go/log"
+	veadkmodel "github.com/volcengine/veadk-go/model"
+	"github.com/volcengine/veadk-go/tool/builtin_tools"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/server/adka2a"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+const (
+	textMode = "text/plain"
+	// maxAudioFileSize bounds the speech files attached to the task
+	maxAudioFileSize = 32 << 20
+)
+
+// ModesBuilder lists the MIME types an agent accepts and produces, for its card.
+type ModesBuilder func(agent agent.Agent) (input, output []string)
+
+// WithModes sets the DefaultInputModes and DefaultOutputModes of the agent cards, derived by
+// AgentModes when not set.
+func WithModes(modes ModesBuilder) Option {
+	return func(a *agentkitA2AServerApp) {
+		a.modes = modes
+	}
+}
+
+// mediaTools are the builtin tools producing media, with the MIME types of their results.
+var mediaTools = map[string][]string{
+	"image_generate": {"image/jpeg", "image/png"},
+	"image_edit":     {"image/jpeg", "image/png"},
+	"video_generate": {"video/mp4"},
+	"text_to_speech": {"audio/pcm"},
+}
+
+// AgentModes derives the modes of an agent from its tree: text, plus the media understood by
+// the models of the agents built with llmagent.New as input, and the media of the image,
+// video and speech tools as output.
+func AgentModes(ag agent.Agent) (input, output []string) {
+	input, output = []string{textMode}, []string{textMode}
+	var walk func(ag agent.Agent)
+	walk = func(ag agent.Agent) {
+		if llm, ok := llmagent.Model(ag); ok {
+			if provider, ok := llm.(veadkmodel.InputModesProvider); ok {
+				input = appendModes(input, provider.InputModes()...)
+			}
+		}
+		for _, skill := range adka2a.BuildAgentSkills(ag) {
+			if skill.ID == ag.Name()+"-"+skill.Name && slices.Contains(skill.Tags, "tools") {
+				output = appendModes(output, mediaTools[skill.Name]...)
+			}
+		}
+		for _, sub := range ag.SubAgents() {
+			walk(sub)
+		}
+	}
+	walk(ag)
+	return input, output
+}
+
+func appendModes(modes []string, more ...string) []string {
+	for _, mode := range more {
+		if !slices.Contains(modes, mode) {
+			modes = append(modes, mode)
+		}
+	}
+	return modes
+}
+
+// mediaParts attaches to the A2A artifacts the files behind an ADK event: the artifacts it
+// saved, loaded from artifacts, and the images, videos and speech of the media tools, which
+// the model only sees as URLs and paths.
+func mediaParts(appName string, artifacts artifact.Service) adka2a.AfterEventCallback {
+	return func(ctx adka2a.ExecutorContext, event *session.Event, processed *a2acore.TaskArtifactUpdateEvent) error {
+		if processed == nil || processed.Artifact == nil {
+			return nil
+		}
+		if artifacts != nil {
+			names := make([]string, 0, len(event.Actions.ArtifactDelta))
+			for name := range event.Actions.ArtifactDelta {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				resp, err := artifacts.Load(ctx, &artifact.LoadRequest{
+					AppName:   appName,
+					UserID:    ctx.UserID(),
+					SessionID: ctx.SessionID(),
+					FileName:  name,
+					Version:   event.Actions.ArtifactDelta[name],
+				})
+				if err != nil {
+					log.WarnContext(ctx, "load artifact for a2a failed", "artifact", name, "error", err)
+					continue
+				}
+				part, err := adka2a.ToA2APart(resp.Part, nil)
+				if err != nil {
+					log.WarnContext(ctx, "convert artifact for a2a failed", "artifact", name, "error", err)
+					continue
+				}
+				processed.Artifact.Parts = append(processed.Artifact.Parts, namedPart(part, name))
+			}
+		}
+		if event.Content != nil {
+			for _, part := range event.Content.Parts {
+				if part.FunctionResponse != nil {
+					processed.Artifact.Parts = append(processed.Artifact.Parts, toolFileParts(part.FunctionResponse)...)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// namedPart names an unnamed file part after its artifact.
+func namedPart(part a2acore.Part, name string) a2acore.Part {
+	filePart, ok := part.(a2acore.FilePart)
+	if !ok {
+		return part
+	}
+	switch file := filePart.File.(type) {
+	case a2acore.FileBytes:
+		if file.Name == "" {
+			file.Name = name
+		}
+		filePart.File = file
+	case a2acore.FileURI:
+		if file.Name == "" {
+			file.Name = name
+		}
+		filePart.File = file
+	}
+	return filePart
+}
+
+// mediaToolResult covers the results of the media tools in tool/builtin_tools.
+type mediaToolResult struct {
+	SuccessList []struct {
+		ImageName string `json:"image_name"`
+		VideoName string `json:"video_name"`
+		URL       string `json:"url"`
+		VideoURL  string `json:"video_url"`
+		B64JSON   string `json:"b64_json"`
+	} `json:"success_list"`
+	SavedAudioPath string `json:"saved_audio_path"`
+}
+
+func toolFileParts(resp *genai.FunctionResponse) []a2acore.Part {
+	modes, ok := mediaTools[resp.Name]
+	if !ok || resp.Response == nil {
+		return nil
+	}
+	data, err := json.Marshal(resp.Response)
+	if err != nil {
+		return nil
+	}
+	var result mediaToolResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil
+	}
+
+	var parts []a2acore.Part
+	for _, item := range result.SuccessList {
+		name := item.ImageName
+		if name == "" {
+			name = item.VideoName
+		}
+		switch {
+		case item.URL != "" || item.VideoURL != "":
+			uri := item.URL
+			if uri == "" {
+				uri = item.VideoURL
+			}
+			parts = append(parts, a2acore.FilePart{File: a2acore.FileURI{
+				FileMeta: a2acore.FileMeta{Name: name, MimeType: urlMimeType(uri, modes[0])},
+				URI:      uri,
+			}})
+		case item.B64JSON != "":
+			mimeType := modes[0]
+			if raw, err := base64.StdEncoding.DecodeString(item.B64JSON); err == nil {
+				mimeType = http.DetectContentType(raw)
+			}
+			parts = append(parts, a2acore.FilePart{File: a2acore.FileBytes{
+				FileMeta: a2acore.FileMeta{Name: name, MimeType: mimeType},
+				Bytes:    item.B64JSON,
+			}})
+		}
+	}
+	if result.SavedAudioPath != "" {
+		if part, ok := audioFilePart(result.SavedAudioPath, modes[0]); ok {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// urlMimeType guesses the type of a media URL from its extension.
+func urlMimeType(uri, fallback string) string {
+	if u, err := url.Parse(uri); err == nil {
+		if mimeType := mime.TypeByExtension(path.Ext(u.Path)); mimeType != "" {
+			return mimeType
+		}
+	}
+	return fallback
+}
+
+// audioFilePart inlines the speech saved by the TTS tool on the local disk. The result may
+// come from a remote agent, so only the files in the output directories of the local TTS
+// tools are read.
+func audioFilePart(file, mimeType string) (a2acore.Part, bool) {
+	resolved, ok := builtin_tools.TTSAudioFile(file)
+	if !ok {
+		log.Warnf("speech file %s was not saved by a local tts tool", file)
+		return nil, false
+	}
+	file = resolved
+	info, err := os.Stat(file)
+	if err != nil {
+		log.Warnf("stat speech file %s failed: %v", file, err)
+		return nil, false
+	}
+	if info.Size() > maxAudioFileSize {
+		log.Warnf("speech file %s of %d bytes is too large to attach to the a2a task", file, info.Size())
+		return nil, false
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		log.Warnf("read speech file %s failed: %v", file, err)
+		return nil, false
+	}
+	return a2acore.FilePart{File: a2acore.FileBytes{
+		FileMeta: a2acore.FileMeta{Name: path.Base(file), MimeType: mimeType},
+		Bytes:    base64.StdEncoding.EncodeToString(data),
+	}}, true
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package a2a_app
+
+import (
+	"context"
+	"iter"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/agent/llmagent"
+	"github.com/volcengine/veadk-go/agent/remoteagent"
+	"github.com/volcengine/veadk-go/apps"
+	veadkmodel "github.com/volcengine/veadk-go/model"
+	"google.golang.org/adk/agent"
+	adkllmagent "google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+func TestAgentModes(t *testing.T) {
+	imageTool, err := functiontool.New(functiontool.Config{Name: "image_generate", Description: "draws"},
+		func(tool.Context, struct{}) (map[string]any, error) { return nil, nil })
+	require.NoError(t, err)
+	painter, err := adkllmagent.New(adkllmagent.Config{Name: "painter", Tools: []tool.Tool{imageTool}})
+	require.NoError(t, err)
+	llm, err := veadkmodel.NewArkModel(context.Background(), "doubao-seed-1-6-250615", &veadkmodel.ArkClientConfig{APIKey: "key"})
+	require.NoError(t, err)
+	root, err := llmagent.New(&llmagent.Config{Config: adkllmagent.Config{Name: "root", Model: llm, SubAgents: []agent.Agent{painter}}})
+	require.NoError(t, err)
+
+	input, output := AgentModes(root)
+	assert.Equal(t, "text/plain", input[0])
+	assert.Contains(t, input, "image/png", "from the model of the root agent")
+	assert.Equal(t, []string{"text/plain", "image/jpeg", "image/png"}, output, "from the tools of the sub-agent")
+
+	input, output = AgentModes(replyAgent(t, "plain", "hi"))
+	assert.Equal(t, []string{"text/plain"}, input)
+	assert.Equal(t, []string{"text/plain"}, output)
+}
+
+func TestSetupRouters_MediaParts(t *testing.T) {
+	painter, err := agent.New(agent.Config{
+		Name:        "painter",
+		Description: "painter agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				saved, err := ctx.Artifacts().Save(ctx, "report.pdf", genai.NewPartFromBytes([]byte("%PDF"), "application/pdf"))
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				event := session.NewEvent(ctx.InvocationID())
+				event.Author = "painter"
+				event.Actions.ArtifactDelta["report.pdf"] = saved.Version
+				event.Content = genai.NewContentFromParts([]*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+					Name: "image_generate",
+					Response: map[string]any{"success_list": []any{
+						map[string]any{"image_name": "cat", "url": "https://cdn.example.com/cat.png"},
+					}},
+				}}}, genai.RoleUser)
+				yield(event, nil)
+			}
+		},
+	})
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	app := NewAgentkitA2AServerApp(apps.DefaultApiConfig().SetPublicURL(""))
+	require.NoError(t, app.SetupRouters(router, &apps.RunConfig{
+		SessionService:  session.InMemoryService(),
+		ArtifactService: artifact.InMemoryService(),
+		AgentLoader:     agent.NewSingleLoader(painter),
+	}))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	remote, err := remoteagent.NewVeRemoteAgent(remoteagent.NewDefaultConfig().SetName("painter").SetBaseUrl(server.URL).SetApiKey("key"))
+	require.NoError(t, err)
+	artifacts := artifact.InMemoryService()
+	r, err := runner.New(runner.Config{
+		AppName:           "client",
+		Agent:             remote,
+		SessionService:    session.InMemoryService(),
+		ArtifactService:   artifacts,
+		AutoCreateSession: true,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	var files []*genai.Part
+	for event, err := range r.Run(ctx, "user", "session", genai.NewContentFromText("draw a cat", genai.RoleUser), agent.RunConfig{}) {
+		require.NoError(t, err)
+		if event.Content == nil {
+			continue
+		}
+		for _, part := range event.Content.Parts {
+			if part.FileData != nil || part.InlineData != nil {
+				files = append(files, part)
+			}
+		}
+	}
+	require.Len(t, files, 2)
+	assert.Equal(t, "application/pdf", files[0].InlineData.MIMEType)
+	assert.Equal(t, "report.pdf", files[0].InlineData.DisplayName)
+	assert.Equal(t, "https://cdn.example.com/cat.png", files[1].FileData.FileURI)
+	assert.Equal(t, "image/png", files[1].FileData.MIMEType)
+
+	listed, err := artifacts.List(ctx, &artifact.ListRequest{AppName: "client", UserID: "user", SessionID: "session"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"report.pdf"}, listed.FileNames, "received inline files are saved as artifacts")
+}
+
+func TestToolFileParts_ForgedSpeechPath(t *testing.T) {
+	forged := filepath.Join(t.TempDir(), "tts_forged.pcm")
+	require.NoError(t, os.WriteFile(forged, []byte{1}, 0o600))
+	for _, file := range []string{"/proc/self/environ", forged} {
+		parts := toolFileParts(&genai.FunctionResponse{
+			Name:     "text_to_speech",
+			Response: map[string]any{"saved_audio_path": file},
+		})
+		assert.Empty(t, parts, file)
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DefaultRedisKeyPrefix = "veadk:a2a"
+
+	fieldTask       = "task"
+	fieldVersion    = "version"
+	fieldContextID  = "context_id"
+	fieldUser       = "user"
+	fieldState      = "state"
+	fieldUpdateTime = "update_time"
+)
+
+// RedisTaskStore is an a2asrv.TaskStore in Redis. Per task it keeps a hash with the task
+// and its version, and a sorted set per user indexes the tasks by update time.
+type RedisTaskStore struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+var _ a2asrv.TaskStore = (*RedisTaskStore)(nil)
+
+// NewRedisTaskStore stores tasks with client, typically shared with a short-term memory backend
+// through its Client method. Tasks not updated within ttl expire, zero keeps them forever.
+func NewRedisTaskStore(client *redis.Client, prefix string, ttl time.Duration) (*RedisTaskStore, error) {
+	if client == nil {
+		return nil, fmt.Errorf("task store redis client is nil")
+	}
+	if prefix == "" {
+		prefix = DefaultRedisKeyPrefix
+	}
+	return &RedisTaskStore{client: client, prefix: prefix, ttl: ttl}, nil
+}
+
+func (s *RedisTaskStore) taskKey(taskID a2a.TaskID) string {
+	return s.prefix + ":task:" + url.PathEscape(string(taskID))
+}
+
+func (s *RedisTaskStore) userIndexKey(user string) string {
+	return s.prefix + ":tasks:" + url.PathEscape(user)
+}
+
+func (s *RedisTaskStore) Save(ctx context.Context, task *a2a.Task, _ a2a.Event, _ *a2a.Task, prevVersion a2a.TaskVersion) (a2a.TaskVersion, error) {
+	if err := validateTask(task); err != nil {
+		return a2a.TaskVersionMissing, err
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return a2a.TaskVersionMissing, fmt.Errorf("failed to encode task: %w", err)
+	}
+	user, ok := userName(ctx)
+	if !ok {
+		user = anonymousUser
+	}
+
+	key := s.taskKey(task.ID)
+	var version int64
+	err = s.client.Watch(ctx, func(tx *redis.Tx) error {
+		stored, err := tx.HMGet(ctx, key, fieldVersion, fieldUser).Result()
+		if err != nil {
+			return err
+		}
+		version = 1
+		if v, ok := stored[0].(string); ok {
+			current, _ := strconv.ParseInt(v, 10, 64)
+			if prevVersion != a2a.TaskVersionMissing && current != int64(prevVersion) {
+				return a2a.ErrConcurrentTaskModification
+			}
+			version = current + 1
+			// the task stays with the user who created it
+			if owner, ok := stored[1].(string); ok {
+				user = owner
+			}
+		}
+
+		now := time.Now().UnixMicro()
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key,
+				fieldTask, data,
+				fieldVersion, version,
+				fieldContextID, task.ContextID,
+				fieldUser, user,
+				fieldState, string(task.Status.State),
+				fieldUpdateTime, now,
+			)
+			pipe.ZAdd(ctx, s.userIndexKey(user), redis.Z{Score: float64(now), Member: string(task.ID)})
+			if s.ttl > 0 {
+				pipe.Expire(ctx, key, s.ttl)
+				pipe.Expire(ctx, s.userIndexKey(user), s.ttl)
+			}
+			return nil
+		})
+		return err
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		return a2a.TaskVersionMissing, a2a.ErrConcurrentTaskModification
+	}
+	if err != nil {
+		return a2a.TaskVersionMissing, err
+	}
+	return a2a.TaskVersion(version), nil
+}
+
+func (s *RedisTaskStore) Get(ctx context.Context, taskID a2a.TaskID) (*a2a.Task, a2a.TaskVersion, error) {
+	values, err := s.client.HMGet(ctx, s.taskKey(taskID), fieldTask, fieldVersion, fieldUser).Result()
+	if err != nil {
+		return nil, a2a.TaskVersionMissing, err
+	}
+	data, ok := values[0].(string)
+	owner, _ := values[2].(string)
+	// the tasks of other users are not disclosed, not even their existence
+	if !ok || !canRead(ctx, owner) {
+		return nil, a2a.TaskVersionMissing, a2a.ErrTaskNotFound
+	}
+	task, err := decodeTask(data)
+	if err != nil {
+		return nil, a2a.TaskVersionMissing, err
+	}
+	version, _ := strconv.ParseInt(fmt.Sprint(values[1]), 10, 64)
+	return task, a2a.TaskVersion(version), nil
+}
+
+// List returns the tasks of the authenticated caller, most recently updated first.
+func (s *RedisTaskStore) List(ctx context.Context, req *a2a.ListTasksRequest) (*a2a.ListTasksResponse, error) {
+	user, ok := userName(ctx)
+	if !ok {
+		return nil, a2a.ErrUnauthenticated
+	}
+	pageSize, err := listPageSize(req)
+	if err != nil {
+		return nil, err
+	}
+	var cursorTime int64 = math.MaxInt64
+	var cursorID a2a.TaskID
+	if req.PageToken != "" {
+		if cursorTime, cursorID, err = decodePageToken(req.PageToken); err != nil {
+			return nil, err
+		}
+	}
+	minScore := "-inf"
+	if req.LastUpdatedAfter != nil {
+		minScore = strconv.FormatInt(req.LastUpdatedAfter.UnixMicro(), 10)
+	}
+
+	// the index is ordered by update time only, ties are broken by task ID below
+	members, err := s.client.ZRevRangeByScoreWithScores(ctx, s.userIndexKey(user), &redis.ZRangeBy{Min: minScore, Max: "+inf"}).Result()
+	if err != nil {
+		return nil, err
+	}
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.SliceCmd, len(members))
+	for i, m := range members {
+		cmds[i] = pipe.HMGet(ctx, s.taskKey(a2a.TaskID(m.Member.(string))), fieldTask, fieldContextID, fieldState, fieldUpdateTime)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	type listed struct {
+		data       string
+		id         a2a.TaskID
+		updateTime int64
+	}
+	var matches []listed
+	for i, m := range members {
+		values := cmds[i].Val()
+		data, ok := values[0].(string)
+		if !ok {
+			// expired task still in the index
+			continue
+		}
+		if req.ContextID != "" && values[1] != req.ContextID {
+			continue
+		}
+		if req.Status != a2a.TaskStateUnspecified && values[2] != string(req.Status) {
+			continue
+		}
+		updateTime, _ := strconv.ParseInt(fmt.Sprint(values[3]), 10, 64)
+		matches = append(matches, listed{data: data, id: a2a.TaskID(m.Member.(string)), updateTime: updateTime})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].updateTime != matches[j].updateTime {
+			return matches[i].updateTime > matches[j].updateTime
+		}
+		return matches[i].id > matches[j].id
+	})
+
+	resp := &a2a.ListTasksResponse{TotalSize: len(matches), PageSize: pageSize}
+	var last listed
+	for _, m := range matches {
+		if req.PageToken != "" && !(m.updateTime < cursorTime || (m.updateTime == cursorTime && m.id < cursorID)) {
+			continue
+		}
+		if len(resp.Tasks) == pageSize {
+			resp.NextPageToken = encodePageToken(last.updateTime, last.id)
+			break
+		}
+		task, err := decodeTask(m.data)
+		if err != nil {
+			return nil, err
+		}
+		resp.Tasks = append(resp.Tasks, listView(task, req))
+		last = m
+	}
+	return resp, nil
+}
+
+// RedisPushConfigStore is an a2asrv.PushConfigStore in Redis, with a hash of configs per task.
+type RedisPushConfigStore struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+var _ a2asrv.PushConfigStore = (*RedisPushConfigStore)(nil)
+
+// NewRedisPushConfigStore stores push configs with client. The configs of a task expire
+// after ttl without changes, zero keeps them forever.
+func NewRedisPushConfigStore(client *redis.Client, prefix string, ttl time.Duration) (*RedisPushConfigStore, error) {
+	if client == nil {
+		return nil, fmt.Errorf("push config store redis client is nil")
+	}
+	if prefix == "" {
+		prefix = DefaultRedisKeyPrefix
+	}
+	return &RedisPushConfigStore{client: client, prefix: prefix, ttl: ttl}, nil
+}
+
+func (s *RedisPushConfigStore) key(taskID a2a.TaskID) string {
+	return s.prefix + ":push:" + url.PathEscape(string(taskID))
+}
+
+func (s *RedisPushConfigStore) Save(ctx context.Context, taskID a2a.TaskID, config *a2a.PushConfig) (*a2a.PushConfig, error) {
+	saved, err := validatePushConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return nil, err
+	}
+	key := s.key(taskID)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, saved.ID, data)
+		if s.ttl > 0 {
+			pipe.Expire(ctx, key, s.ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (s *RedisPushConfigStore) Get(ctx context.Context, taskID a2a.TaskID, configID string) (*a2a.PushConfig, error) {
+	data, err := s.client.HGet(ctx, s.key(taskID), configID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrPushConfigNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodePushConfig(data)
+}
+
+func (s *RedisPushConfigStore) List(ctx context.Context, taskID a2a.TaskID) ([]*a2a.PushConfig, error) {
+	all, err := s.client.HGetAll(ctx, s.key(taskID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(all))
+	for id := range all {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	configs := make([]*a2a.PushConfig, 0, len(ids))
+	for _, id := range ids {
+		config, err := decodePushConfig(all[id])
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+func (s *RedisPushConfigStore) Delete(ctx context.Context, taskID a2a.TaskID, configID string) error {
+	return s.client.HDel(ctx, s.key(taskID), configID).Err()
+}
+
+func (s *RedisPushConfigStore) DeleteAll(ctx context.Context, taskID a2a.TaskID) error {
+	return s.client.Del(ctx, s.key(taskID)).Err()
+}
@@ -0,0 +1,311 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"gorm.io/gorm"
+)
+
+const (
+	tasksTableName       = "a2a_tasks"
+	pushConfigsTableName = "a2a_push_configs"
+)
+
+type taskRow struct {
+	ID        string `gorm:"primaryKey;size:128"`
+	ContextID string `gorm:"index;size:128"`
+	UserName  string `gorm:"index:idx_a2a_tasks_user_update;size:255"`
+	State     string `gorm:"size:32"`
+	Version   int64
+	Task      string `gorm:"type:text"`
+	// UpdateTime is in microseconds since the epoch, portable across databases
+	UpdateTime int64 `gorm:"index:idx_a2a_tasks_user_update"`
+}
+
+func (taskRow) TableName() string {
+	return tasksTableName
+}
+
+type pushConfigRow struct {
+	TaskID   string `gorm:"primaryKey;size:128"`
+	ConfigID string `gorm:"primaryKey;size:128"`
+	Config   string `gorm:"type:text"`
+}
+
+func (pushConfigRow) TableName() string {
+	return pushConfigsTableName
+}
+
+// SQLTaskStore is an a2asrv.TaskStore and a2asrv.PushConfigStore on SQLite, MySQL or PostgreSQL.
+// Task updates are optimistic: Save fails with a2a.ErrConcurrentTaskModification when the
+// task was saved by someone else since prevVersion.
+type SQLTaskStore struct {
+	db *gorm.DB
+}
+
+var _ a2asrv.TaskStore = (*SQLTaskStore)(nil)
+
+// NewSQLTaskStore creates the task table in db if needed. db is typically shared with
+// a short-term memory backend through its DB method.
+func NewSQLTaskStore(db *gorm.DB) (*SQLTaskStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("task store db is nil")
+	}
+	if err := db.AutoMigrate(&taskRow{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate a2a task table: %w", err)
+	}
+	return &SQLTaskStore{db: db}, nil
+}
+
+func (s *SQLTaskStore) Save(ctx context.Context, task *a2a.Task, _ a2a.Event, _ *a2a.Task, prevVersion a2a.TaskVersion) (a2a.TaskVersion, error) {
+	if err := validateTask(task); err != nil {
+		return a2a.TaskVersionMissing, err
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return a2a.TaskVersionMissing, fmt.Errorf("failed to encode task: %w", err)
+	}
+	user, ok := userName(ctx)
+	if !ok {
+		user = anonymousUser
+	}
+
+	var version int64
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stored taskRow
+		res := tx.Select("version").Where("id = ?", string(task.ID)).Limit(1).Find(&stored)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			version = 1
+			// in a savepoint, so that the transaction can still be queried if the insert fails
+			err = tx.Transaction(func(tx *gorm.DB) error {
+				return tx.Create(&taskRow{
+					ID:         string(task.ID),
+					ContextID:  task.ContextID,
+					UserName:   user,
+					State:      string(task.Status.State),
+					Version:    version,
+					Task:       string(data),
+					UpdateTime: time.Now().UnixMicro(),
+				}).Error
+			})
+			if err != nil && tx.Where("id = ?", string(task.ID)).Limit(1).Find(&taskRow{}).RowsAffected > 0 {
+				// another writer created the task first
+				return a2a.ErrConcurrentTaskModification
+			}
+			return err
+		}
+		if prevVersion != a2a.TaskVersionMissing && stored.Version != int64(prevVersion) {
+			return a2a.ErrConcurrentTaskModification
+		}
+
+		version = stored.Version + 1
+		res = tx.Model(&taskRow{}).
+			Where("id = ? AND version = ?", string(task.ID), stored.Version).
+			Updates(map[string]any{
+				"state":       string(task.Status.State),
+				"version":     version,
+				"task":        string(data),
+				"update_time": time.Now().UnixMicro(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return a2a.ErrConcurrentTaskModification
+		}
+		return nil
+	})
+	if err != nil {
+		return a2a.TaskVersionMissing, err
+	}
+	return a2a.TaskVersion(version), nil
+}
+
+func (s *SQLTaskStore) Get(ctx context.Context, taskID a2a.TaskID) (*a2a.Task, a2a.TaskVersion, error) {
+	var row taskRow
+	res := s.db.WithContext(ctx).Where("id = ?", string(taskID)).Limit(1).Find(&row)
+	if res.Error != nil {
+		return nil, a2a.TaskVersionMissing, res.Error
+	}
+	// the tasks of other users are not disclosed, not even their existence
+	if res.RowsAffected == 0 || !canRead(ctx, row.UserName) {
+		return nil, a2a.TaskVersionMissing, a2a.ErrTaskNotFound
+	}
+	task, err := decodeTask(row.Task)
+	if err != nil {
+		return nil, a2a.TaskVersionMissing, err
+	}
+	return task, a2a.TaskVersion(row.Version), nil
+}
+
+// List returns the tasks of the authenticated caller, most recently updated first.
+func (s *SQLTaskStore) List(ctx context.Context, req *a2a.ListTasksRequest) (*a2a.ListTasksResponse, error) {
+	user, ok := userName(ctx)
+	if !ok {
+		return nil, a2a.ErrUnauthenticated
+	}
+	pageSize, err := listPageSize(req)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.WithContext(ctx).Model(&taskRow{}).Where("user_name = ?", user)
+	if req.ContextID != "" {
+		query = query.Where("context_id = ?", req.ContextID)
+	}
+	if req.Status != a2a.TaskStateUnspecified {
+		query = query.Where("state = ?", string(req.Status))
+	}
+	if req.LastUpdatedAfter != nil {
+		query = query.Where("update_time >= ?", req.LastUpdatedAfter.UnixMicro())
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if req.PageToken != "" {
+		updateTime, taskID, err := decodePageToken(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("update_time < ? OR (update_time = ? AND id < ?)", updateTime, updateTime, string(taskID))
+	}
+	var rows []taskRow
+	err = query.Order("update_time DESC").Order("id DESC").Limit(pageSize + 1).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &a2a.ListTasksResponse{TotalSize: int(total), PageSize: pageSize}
+	if len(rows) > pageSize {
+		rows = rows[:pageSize]
+		last := rows[pageSize-1]
+		resp.NextPageToken = encodePageToken(last.UpdateTime, a2a.TaskID(last.ID))
+	}
+	for _, row := range rows {
+		task, err := decodeTask(row.Task)
+		if err != nil {
+			return nil, err
+		}
+		resp.Tasks = append(resp.Tasks, listView(task, req))
+	}
+	return resp, nil
+}
+
+// SQLPushConfigStore is an a2asrv.PushConfigStore on SQLite, MySQL or PostgreSQL.
+type SQLPushConfigStore struct {
+	db *gorm.DB
+}
+
+var _ a2asrv.PushConfigStore = (*SQLPushConfigStore)(nil)
+
+// NewSQLPushConfigStore creates the push config table in db if needed.
+func NewSQLPushConfigStore(db *gorm.DB) (*SQLPushConfigStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("push config store db is nil")
+	}
+	if err := db.AutoMigrate(&pushConfigRow{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate a2a push config table: %w", err)
+	}
+	return &SQLPushConfigStore{db: db}, nil
+}
+
+func (s *SQLPushConfigStore) Save(ctx context.Context, taskID a2a.TaskID, config *a2a.PushConfig) (*a2a.PushConfig, error) {
+	saved, err := validatePushConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&pushConfigRow{}).
+			Where("task_id = ? AND config_id = ?", string(taskID), saved.ID).
+			Update("config", string(data))
+		if res.Error != nil || res.RowsAffected > 0 {
+			return res.Error
+		}
+		return tx.Create(&pushConfigRow{TaskID: string(taskID), ConfigID: saved.ID, Config: string(data)}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (s *SQLPushConfigStore) Get(ctx context.Context, taskID a2a.TaskID, configID string) (*a2a.PushConfig, error) {
+	var row pushConfigRow
+	res := s.db.WithContext(ctx).Where("task_id = ? AND config_id = ?", string(taskID), configID).Limit(1).Find(&row)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrPushConfigNotFound
+	}
+	return decodePushConfig(row.Config)
+}
+
+func (s *SQLPushConfigStore) List(ctx context.Context, taskID a2a.TaskID) ([]*a2a.PushConfig, error) {
+	var rows []pushConfigRow
+	if err := s.db.WithContext(ctx).Where("task_id = ?", string(taskID)).Order("config_id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	configs := make([]*a2a.PushConfig, 0, len(rows))
+	for _, row := range rows {
+		config, err := decodePushConfig(row.Config)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+func (s *SQLPushConfigStore) Delete(ctx context.Context, taskID a2a.TaskID, configID string) error {
+	return s.db.WithContext(ctx).Where("task_id = ? AND config_id = ?", string(taskID), configID).Delete(&pushConfigRow{}).Error
+}
+
+func (s *SQLPushConfigStore) DeleteAll(ctx context.Context, taskID a2a.TaskID) error {
+	return s.db.WithContext(ctx).Where("task_id = ?", string(taskID)).Delete(&pushConfigRow{}).Error
+}
+
+func decodePushConfig(data string) (*a2a.PushConfig, error) {
+	var config a2a.PushConfig
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		return nil, fmt.Errorf("failed to decode push config: %w", err)
+	}
+	return &config, nil
+}
+
+func decodeTask(data string) (*a2a.Task, error) {
+	var task a2a.Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return nil, fmt.Errorf("failed to decode task: %w", err)
+	}
+	return &task, nil
+}
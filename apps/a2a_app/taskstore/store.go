@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package taskstore keeps A2A tasks and push notification configs in SQL databases or Redis,
+// so that long-running tasks survive restarts, and delivers push notifications to webhooks.
+//
+// The stores share the connections of the short-term memory backends:
+//
+//	sessions, _ := short_term_memory_backends.NewPostgreSqlSTMBackend(config)
+//	tasks, _ := taskstore.NewSQLTaskStore(sessions.DB())
+package taskstore
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultListPageSize = 50
+	MaxListPageSize     = 100
+
+	// anonymousUser owns the tasks created by unauthenticated callers
+	anonymousUser = "anonymous"
+)
+
+var (
+	ErrInvalidPageToken   = errors.New("invalid page token")
+	ErrPushConfigNotFound = errors.New("push config not found")
+)
+
+// userName returns the authenticated A2A caller of ctx.
+func userName(ctx context.Context) (string, bool) {
+	if callCtx, ok := a2asrv.CallContextFrom(ctx); ok && callCtx.User != nil && callCtx.User.Authenticated() {
+		return callCtx.User.Name(), true
+	}
+	return "", false
+}
+
+// canRead reports whether the caller of ctx may read a task owned by owner. Outside of an A2A
+// call, e.g. for the server itself, every task may be read.
+func canRead(ctx context.Context, owner string) bool {
+	if _, ok := a2asrv.CallContextFrom(ctx); !ok {
+		return true
+	}
+	user, ok := userName(ctx)
+	if !ok {
+		user = anonymousUser
+	}
+	return user == owner
+}
+
+func validateTask(task *a2a.Task) error {
+	if task == nil || task.ID == "" {
+		return fmt.Errorf("%w: task id is required", a2a.ErrInvalidParams)
+	}
+	if task.ContextID == "" {
+		return fmt.Errorf("%w: task context id is required", a2a.ErrInvalidParams)
+	}
+	return nil
+}
+
+// listPageSize validates the paging parameters of req.
+func listPageSize(req *a2a.ListTasksRequest) (int, error) {
+	if req.HistoryLength < 0 {
+		return 0, fmt.Errorf("%w: history length must be non-negative, got %d", a2a.ErrInvalidParams, req.HistoryLength)
+	}
+	switch {
+	case req.PageSize == 0:
+		return DefaultListPageSize, nil
+	case req.PageSize < 1 || req.PageSize > MaxListPageSize:
+		return 0, fmt.Errorf("%w: page size must be between 1 and %d, got %d", a2a.ErrInvalidParams, MaxListPageSize, req.PageSize)
+	}
+	return req.PageSize, nil
+}
+
+// listView trims a listed task as requested by req.
+func listView(task *a2a.Task, req *a2a.ListTasksRequest) *a2a.Task {
+	if req.HistoryLength > 0 && len(task.History) > req.HistoryLength {
+		task.History = task.History[len(task.History)-req.HistoryLength:]
+	}
+	if !req.IncludeArtifacts {
+		task.Artifacts = nil
+	}
+	return task
+}
+
+// Tasks are listed by descending update time then task ID, the page token is the
+// position of the last task of the previous page.
+func encodePageToken(updateTime int64, taskID a2a.TaskID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(updateTime, 10) + "_" + string(taskID)))
+}
+
+func decodePageToken(token string) (int64, a2a.TaskID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, "", ErrInvalidPageToken
+	}
+	updateTime, taskID, ok := strings.Cut(string(b), "_")
+	if !ok {
+		return 0, "", ErrInvalidPageToken
+	}
+	t, err := strconv.ParseInt(updateTime, 10, 64)
+	if err != nil {
+		return 0, "", ErrInvalidPageToken
+	}
+	return t, a2a.TaskID(taskID), nil
+}
+
+func validatePushConfig(config *a2a.PushConfig) (*a2a.PushConfig, error) {
+	if config == nil || config.URL == "" {
+		return nil, fmt.Errorf("%w: push config url is required", a2a.ErrInvalidParams)
+	}
+	if err := validateWebhookURL(config.URL); err != nil {
+		return nil, fmt.Errorf("%w: %w", a2a.ErrInvalidParams, err)
+	}
+	saved := *config
+	if saved.ID == "" {
+		saved.ID = uuid.Must(uuid.NewV7()).String()
+	}
+	return &saved, nil
+}
@@ -0,0 +1,338 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/auth/httpauth"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func sqliteDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Skipf("sqlite is not available: %v", err)
+	}
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// every connection of an in-memory sqlite has its own database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+func redisClient(t *testing.T) *redis.Client {
+	host := os.Getenv("DATABASE_REDIS_HOST")
+	if host == "" {
+		t.Skip("DATABASE_REDIS_HOST is not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: host, Password: os.Getenv("DATABASE_REDIS_PASSWORD")})
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func userContext(user string) context.Context {
+	ctx, callCtx := a2asrv.WithCallContext(context.Background(), nil)
+	callCtx.User = &a2asrv.AuthenticatedUser{UserName: user}
+	return ctx
+}
+
+func newTask(id, contextID string) *a2a.Task {
+	return &a2a.Task{
+		ID:        a2a.TaskID(id),
+		ContextID: contextID,
+		Status:    a2a.TaskStatus{State: a2a.TaskStateWorking},
+		History: []*a2a.Message{
+			a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "one"}),
+			a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "two"}),
+		},
+		Artifacts: []*a2a.Artifact{{ID: "artifact", Parts: a2a.ContentParts{a2a.TextPart{Text: "result"}}}},
+	}
+}
+
+func testTaskStore(t *testing.T, store a2asrv.TaskStore) {
+	alice, bob := userContext("alice-"+t.Name()), userContext("bob-"+t.Name())
+	prefix := fmt.Sprintf("%s-%d-", t.Name(), time.Now().UnixNano())
+
+	t.Run("save and get", func(t *testing.T) {
+		task := newTask(prefix+"task", "ctx")
+		v1, err := store.Save(alice, task, nil, nil, a2a.TaskVersionMissing)
+		require.NoError(t, err)
+
+		task.Status.State = a2a.TaskStateCompleted
+		v2, err := store.Save(alice, task, nil, nil, v1)
+		require.NoError(t, err)
+		assert.Greater(t, int64(v2), int64(v1))
+
+		got, version, err := store.Get(alice, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, v2, version)
+		assert.Equal(t, a2a.TaskStateCompleted, got.Status.State)
+		assert.Len(t, got.History, 2)
+
+		_, err = store.Save(alice, task, nil, nil, v1)
+		assert.ErrorIs(t, err, a2a.ErrConcurrentTaskModification)
+
+		_, _, err = store.Get(alice, a2a.TaskID(prefix+"missing"))
+		assert.ErrorIs(t, err, a2a.ErrTaskNotFound)
+
+		_, _, err = store.Get(bob, task.ID)
+		assert.ErrorIs(t, err, a2a.ErrTaskNotFound, "tasks are private to their owner")
+		anonymous, _ := a2asrv.WithCallContext(context.Background(), nil)
+		_, _, err = store.Get(anonymous, task.ID)
+		assert.ErrorIs(t, err, a2a.ErrTaskNotFound)
+		_, _, err = store.Get(context.Background(), task.ID)
+		assert.NoError(t, err, "the server itself reads every task")
+	})
+
+	t.Run("invalid task", func(t *testing.T) {
+		_, err := store.Save(alice, &a2a.Task{ID: a2a.TaskID(prefix + "no-context")}, nil, nil, a2a.TaskVersionMissing)
+		assert.ErrorIs(t, err, a2a.ErrInvalidParams)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		for i := range 5 {
+			contextID := "even"
+			if i%2 == 1 {
+				contextID = "odd"
+			}
+			_, err := store.Save(bob, newTask(fmt.Sprintf("%slist-%d", prefix, i), contextID), nil, nil, a2a.TaskVersionMissing)
+			require.NoError(t, err)
+		}
+
+		var ids []a2a.TaskID
+		req := &a2a.ListTasksRequest{PageSize: 2, HistoryLength: 1}
+		for {
+			resp, err := store.List(bob, req)
+			require.NoError(t, err)
+			assert.Equal(t, 5, resp.TotalSize)
+			for _, task := range resp.Tasks {
+				assert.Len(t, task.History, 1)
+				assert.Empty(t, task.Artifacts)
+				ids = append(ids, task.ID)
+			}
+			if resp.NextPageToken == "" {
+				break
+			}
+			req.PageToken = resp.NextPageToken
+		}
+		require.Len(t, ids, 5)
+		assert.Equal(t, a2a.TaskID(prefix+"list-4"), ids[0], "most recently updated first")
+		assert.Equal(t, a2a.TaskID(prefix+"list-0"), ids[4])
+
+		resp, err := store.List(bob, &a2a.ListTasksRequest{ContextID: "odd", IncludeArtifacts: true})
+		require.NoError(t, err)
+		assert.Len(t, resp.Tasks, 2)
+		assert.NotEmpty(t, resp.Tasks[0].Artifacts)
+
+		resp, err = store.List(alice, &a2a.ListTasksRequest{ContextID: "odd"})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Tasks, "tasks of other users are not listed")
+
+		_, err = store.List(context.Background(), &a2a.ListTasksRequest{})
+		assert.ErrorIs(t, err, a2a.ErrUnauthenticated)
+		_, err = store.List(bob, &a2a.ListTasksRequest{PageSize: MaxListPageSize + 1})
+		assert.ErrorIs(t, err, a2a.ErrInvalidParams)
+		_, err = store.List(bob, &a2a.ListTasksRequest{PageToken: "!"})
+		assert.ErrorIs(t, err, ErrInvalidPageToken)
+	})
+}
+
+func testPushConfigStore(t *testing.T, store a2asrv.PushConfigStore) {
+	ctx := context.Background()
+	taskID := a2a.TaskID(fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano()))
+
+	_, err := store.Save(ctx, taskID, &a2a.PushConfig{URL: "ftp://example.com"})
+	assert.ErrorIs(t, err, a2a.ErrInvalidParams)
+
+	first, err := store.Save(ctx, taskID, &a2a.PushConfig{URL: "https://example.com/hook", Token: "t1"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.ID, "an id is generated")
+	_, err = store.Save(ctx, taskID, &a2a.PushConfig{ID: "second", URL: "https://example.com/other"})
+	require.NoError(t, err)
+	_, err = store.Save(ctx, taskID, &a2a.PushConfig{ID: "second", URL: "https://example.com/updated"})
+	require.NoError(t, err)
+
+	got, err := store.Get(ctx, taskID, "second")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/updated", got.URL)
+	configs, err := store.List(ctx, taskID)
+	require.NoError(t, err)
+	assert.Len(t, configs, 2)
+
+	require.NoError(t, store.Delete(ctx, taskID, "second"))
+	_, err = store.Get(ctx, taskID, "second")
+	assert.ErrorIs(t, err, ErrPushConfigNotFound)
+
+	require.NoError(t, store.DeleteAll(ctx, taskID))
+	configs, err = store.List(ctx, taskID)
+	require.NoError(t, err)
+	assert.Empty(t, configs)
+}
+
+func TestSQLTaskStore(t *testing.T) {
+	store, err := NewSQLTaskStore(sqliteDB(t))
+	require.NoError(t, err)
+	testTaskStore(t, store)
+}
+
+func TestSQLPushConfigStore(t *testing.T) {
+	store, err := NewSQLPushConfigStore(sqliteDB(t))
+	require.NoError(t, err)
+	testPushConfigStore(t, store)
+}
+
+func TestRedisTaskStore(t *testing.T) {
+	store, err := NewRedisTaskStore(redisClient(t), "veadk:test:a2a", time.Minute)
+	require.NoError(t, err)
+	testTaskStore(t, store)
+}
+
+func TestRedisPushConfigStore(t *testing.T) {
+	store, err := NewRedisPushConfigStore(redisClient(t), "veadk:test:a2a", time.Minute)
+	require.NoError(t, err)
+	testPushConfigStore(t, store)
+}
+
+func TestWebhookSender(t *testing.T) {
+	task := newTask("task", "ctx")
+
+	t.Run("retries and signs", func(t *testing.T) {
+		verifier, err := httpauth.NewHMACAuthenticator(map[string]*httpauth.HMACKey{
+			"agents": {Secret: []byte("secret"), Principal: &httpauth.Principal{Subject: "agents"}},
+		}, time.Minute)
+		require.NoError(t, err)
+
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, err := verifier.Authenticate(r)
+			assert.NoError(t, err)
+			assert.Equal(t, "token", r.Header.Get(NotificationTokenHeader))
+			assert.Equal(t, "Bearer creds", r.Header.Get("Authorization"))
+			body, _ := io.ReadAll(r.Body)
+			var got a2a.Task
+			assert.NoError(t, json.Unmarshal(body, &got))
+			assert.Equal(t, task.ID, got.ID)
+		}))
+		defer server.Close()
+
+		sender := NewWebhookSender(WebhookConfig{
+			AllowPrivateNetworks: true,
+			Backoff:              time.Millisecond,
+			SigningKeyID:         "agents",
+			SigningSecret:        []byte("secret"),
+			FailOnError:          true,
+		})
+		err = sender.SendPush(context.Background(), &a2a.PushConfig{
+			URL:   server.URL,
+			Token: "token",
+			Auth:  &a2a.PushAuthInfo{Schemes: []string{"Bearer"}, Credentials: "creds"},
+		}, task)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, calls.Load())
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		sender := NewWebhookSender(WebhookConfig{AllowPrivateNetworks: true, MaxAttempts: 2, Backoff: time.Millisecond, FailOnError: true})
+		err := sender.SendPush(context.Background(), &a2a.PushConfig{URL: server.URL}, task)
+		assert.ErrorIs(t, err, ErrWebhookDelivery)
+		assert.EqualValues(t, 2, calls.Load())
+
+		lenient := NewWebhookSender(WebhookConfig{AllowPrivateNetworks: true, MaxAttempts: 1})
+		assert.NoError(t, lenient.SendPush(context.Background(), &a2a.PushConfig{URL: server.URL}, task), "errors are only logged by default")
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		sender := NewWebhookSender(WebhookConfig{AllowPrivateNetworks: true, Backoff: time.Millisecond, FailOnError: true})
+		err := sender.SendPush(context.Background(), &a2a.PushConfig{URL: server.URL}, task)
+		assert.ErrorIs(t, err, ErrWebhookDelivery)
+		assert.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("internal addresses and redirects are refused", func(t *testing.T) {
+		var calls atomic.Int32
+		internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+		}))
+		defer internal.Close()
+		redirect := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusFound))
+		defer redirect.Close()
+
+		sender := NewWebhookSender(WebhookConfig{Backoff: time.Millisecond, FailOnError: true})
+		err := sender.SendPush(context.Background(), &a2a.PushConfig{URL: internal.URL}, task)
+		assert.ErrorIs(t, err, ErrWebhookForbidden)
+
+		allowed := NewWebhookSender(WebhookConfig{AllowPrivateNetworks: true, Backoff: time.Millisecond, FailOnError: true})
+		err = allowed.SendPush(context.Background(), &a2a.PushConfig{URL: redirect.URL}, task)
+		assert.ErrorIs(t, err, ErrWebhookDelivery)
+		assert.Zero(t, calls.Load())
+
+		listed := NewWebhookSender(WebhookConfig{AllowPrivateNetworks: true, AllowedHosts: []string{"hooks.example.com"}, FailOnError: true})
+		err = listed.SendPush(context.Background(), &a2a.PushConfig{URL: internal.URL}, task)
+		assert.ErrorIs(t, err, ErrWebhookForbidden)
+		assert.Zero(t, calls.Load())
+	})
+}
+
+func TestCheckWebhookAddress(t *testing.T) {
+	for address, allowed := range map[string]bool{
+		"93.184.216.34:443":     true,
+		"[2606:4700::1111]:443": true,
+		"127.0.0.1:80":          false,
+		"10.1.2.3:80":           false,
+		"169.254.169.254:80":    false,
+		"100.100.100.200:80":    false,
+		"[::1]:80":              false,
+		"[::ffff:127.0.0.1]:80": false,
+		"[fe80::1]:80":          false,
+		"0.0.0.0:80":            false,
+	} {
+		assert.Equal(t, allowed, checkWebhookAddress(address) == nil, address)
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/volcengine/veadk-go/auth/httpauth"
+	"github.com/volcengine/veadk-go/log"
+)
+
+const (
+	// NotificationTokenHeader carries the token of the push config, so that the receiver
+	// can check that the notification belongs to a task it subscribed to.
+	NotificationTokenHeader = "X-A2A-Notification-Token"
+
+	DefaultWebhookTimeout     = 10 * time.Second
+	DefaultWebhookMaxAttempts = 3
+	DefaultWebhookBackoff     = 500 * time.Millisecond
+	maxWebhookBackoff         = 30 * time.Second
+)
+
+var (
+	ErrWebhookDelivery = errors.New("webhook delivery failed")
+	// ErrWebhookForbidden is returned for the webhooks the sender may not call: hosts out of
+	// WebhookConfig.AllowedHosts, and private addresses unless WebhookConfig.AllowPrivateNetworks.
+	ErrWebhookForbidden = errors.New("webhook address is not allowed")
+)
+
+// WebhookConfig configures the delivery of push notifications.
+type WebhookConfig struct {
+	// HTTPClient sends the notifications, a client with Timeout is used if nil. The default
+	// client refuses to connect to loopback, private and link-local addresses, and does not
+	// follow redirects, so that the URLs set by callers cannot reach internal services.
+	// A custom client is used as is.
+	HTTPClient *http.Client
+	// AllowedHosts restricts the webhooks to these host names when set.
+	AllowedHosts []string
+	// AllowPrivateNetworks lets the default client connect to private addresses, e.g. for
+	// receivers in the same network.
+	AllowPrivateNetworks bool
+	// Timeout bounds every attempt when HTTPClient is nil, DefaultWebhookTimeout if zero.
+	Timeout time.Duration
+	// MaxAttempts is the number of deliveries tried for a notification, DefaultWebhookMaxAttempts if zero.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled for each following one,
+	// DefaultWebhookBackoff if zero. A Retry-After header of the receiver takes precedence.
+	Backoff time.Duration
+	// SigningKeyID and SigningSecret sign the notifications like httpauth.SignRequest,
+	// so that receivers can verify them with an httpauth HMAC authenticator. Unsigned if empty.
+	SigningKeyID  string
+	SigningSecret []byte
+	// FailOnError returns delivery errors to the A2A handler, which stops the task execution.
+	// By default failed deliveries are only logged.
+	FailOnError bool
+}
+
+// WebhookSender is an a2asrv.PushSender which posts the task as JSON to the webhook of
+// the push config. Network errors, 429 and 5xx responses are retried with exponential backoff.
+type WebhookSender struct {
+	config WebhookConfig
+	client *http.Client
+}
+
+var _ a2asrv.PushSender = (*WebhookSender)(nil)
+
+func NewWebhookSender(config WebhookConfig) *WebhookSender {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultWebhookTimeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultWebhookMaxAttempts
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = DefaultWebhookBackoff
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = newWebhookClient(config.Timeout, config.AllowPrivateNetworks)
+	}
+	return &WebhookSender{config: config, client: client}
+}
+
+// newWebhookClient returns a client which does not follow redirects and, unless allowPrivate,
+// checks every address it connects to after name resolution.
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			return checkWebhookAddress(address)
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be dialed instead of the webhook
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkWebhookAddress refuses the loopback, private, link-local, multicast and unspecified
+// addresses.
+func checkWebhookAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWebhookForbidden, address)
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", ErrWebhookForbidden, ip)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, private as well.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+func (s *WebhookSender) SendPush(ctx context.Context, config *a2a.PushConfig, task *a2a.Task) error {
+	err := s.send(ctx, config, task)
+	if err == nil {
+		return nil
+	}
+	if s.config.FailOnError {
+		return err
+	}
+	log.Warnf("push notification of task %s to %s dropped: %v", task.ID, config.URL, err)
+	return nil
+}
+
+func (s *WebhookSender) send(ctx context.Context, config *a2a.PushConfig, task *a2a.Task) error {
+	body, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode task: %w", err)
+	}
+
+	backoff := s.config.Backoff
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := s.post(ctx, config, body)
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= s.config.MaxAttempts {
+			return fmt.Errorf("%w after %d attempt(s): %w", ErrWebhookDelivery, attempt, err)
+		}
+
+		wait := backoff
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		wait = min(wait, maxWebhookBackoff)
+		log.Debugf("push notification of task %s to %s failed, retrying in %s: %v", task.ID, config.URL, wait, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrWebhookDelivery, ctx.Err())
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// permanentError is a delivery failure which is not worth retrying.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// post delivers one notification, returning the Retry-After delay asked by the receiver if any.
+func (s *WebhookSender) post(ctx context.Context, config *a2a.PushConfig, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, &permanentError{err: err}
+	}
+	if len(s.config.AllowedHosts) > 0 && !slices.ContainsFunc(s.config.AllowedHosts, func(host string) bool {
+		return strings.EqualFold(host, req.URL.Hostname())
+	}) {
+		return 0, &permanentError{err: fmt.Errorf("%w: %s", ErrWebhookForbidden, req.URL.Hostname())}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if config.Token != "" {
+		req.Header.Set(NotificationTokenHeader, config.Token)
+	}
+	if config.Auth != nil && config.Auth.Credentials != "" {
+		for _, scheme := range config.Auth.Schemes {
+			if strings.EqualFold(scheme, "bearer") || strings.EqualFold(scheme, "basic") {
+				req.Header.Set("Authorization", http.CanonicalHeaderKey(strings.ToLower(scheme))+" "+config.Auth.Credentials)
+				break
+			}
+		}
+	}
+	if s.config.SigningKeyID != "" && len(s.config.SigningSecret) > 0 {
+		if err := httpauth.SignRequest(req, s.config.SigningKeyID, s.config.SigningSecret); err != nil {
+			return 0, &permanentError{err: err}
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, ErrWebhookForbidden) {
+			return 0, &permanentError{err: err}
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return parseRetryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("webhook returned %s", resp.Status)
+	default:
+		return 0, &permanentError{err: fmt.Errorf("webhook returned %s", resp.Status)}
+	}
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// validateWebhookURL accepts absolute http and https URLs.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid push config url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("push config url must be an absolute http or https url, got %q", raw)
+	}
+	return nil
+}
@@ -15,14 +15,20 @@
 package agentkit_server_app
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/volcengine/veadk-go/apps"
 	"github.com/volcengine/veadk-go/apps/a2a_app"
 	"github.com/volcengine/veadk-go/apps/simple_app"
+	"github.com/volcengine/veadk-go/auth/httpauth"
 	"github.com/volcengine/veadk-go/log"
 	"github.com/volcengine/veadk-go/observability"
 	"google.golang.org/adk/cmd/launcher"
@@ -34,21 +40,33 @@ const serverName = "agentkit server"
 
 type agentkitServerApp struct {
 	*apps.ApiConfig
+	a2aApp apps.BasicApp
 }
 
-func NewAgentkitServerApp(config *apps.ApiConfig) apps.BasicApp {
+// NewAgentkitServerApp serves the simple app, the A2A agents configured by a2aOptions,
+// the web UI and the ADK REST API on one port.
+func NewAgentkitServerApp(config *apps.ApiConfig, a2aOptions ...a2a_app.Option) apps.BasicApp {
 	return &agentkitServerApp{
 		ApiConfig: config,
+		a2aApp:    a2a_app.NewAgentkitA2AServerApp(config, a2aOptions...),
 	}
 }
 
 func (a *agentkitServerApp) Run(ctx context.Context, config *apps.RunConfig) error {
-	return apps.Run(ctx, config, a)
+	return a2a_app.RunRegistered(ctx, a.a2aApp, func(ctx context.Context) error {
+		return apps.Run(ctx, config, a)
+	})
 }
 
 func (a *agentkitServerApp) SetupRouters(router *mux.Router, config *apps.RunConfig) error {
 	var err error
 
+	// the trace viewer is registered first so that the webui catch-all route does not shadow it
+	if path, handler := observability.TraceViewerEndpoint(); handler != nil {
+		router.PathPrefix(path).Handler(handler).Methods(http.MethodGet)
+		log.Infof("Trace viewer is served on %s%s", a.GetWebUrl(), path)
+	}
+
 	//setup simple app routers
 	simpleApp := simple_app.NewAgentkitSimpleApp(a.ApiConfig)
 	err = simpleApp.SetupRouters(router, config)
@@ -57,10 +75,9 @@ func (a *agentkitServerApp) SetupRouters(router *mux.Router, config *apps.RunCon
 	}
 
 	//setup a2a routers
-	a2aApp := a2a_app.NewAgentkitA2AServerApp(a.ApiConfig)
-	err = a2aApp.SetupRouters(router, config)
+	err = a.a2aApp.SetupRouters(router, config)
 	if err != nil {
-		return fmt.Errorf("setup simple app routers failed: %w", err)
+		return fmt.Errorf("setup a2a app routers failed: %w", err)
 	}
 
 	launchConfig := &launcher.Config{
@@ -108,8 +125,13 @@ func (a *agentkitServerApp) SetupRouters(router *mux.Router, config *apps.RunCon
 	// Wrap it with CORS middleware
 	corsHandler := corsWithArgs(a.GetWebUrl())(apiHandler)
 
+	// the approval routes are registered before the catch-all API route
+	setupApprovalRouters(router, a.ApiPathPrefix, config, func(h http.Handler) http.Handler {
+		return observability.HTTPMiddleware(corsWithArgs(a.GetWebUrl())(h))
+	})
+
 	// Wrap with OpenTelemetry instrumentation first, then add to router
-	wrappedHandler := observability.HTTPMiddleware(http.StripPrefix(a.ApiPathPrefix, corsHandler))
+	wrappedHandler := observability.HTTPMiddleware(http.StripPrefix(a.ApiPathPrefix, principalUser(corsHandler)))
 	router.Methods("GET", "POST", "DELETE", "OPTIONS").PathPrefix(fmt.Sprintf("%s/", a.ApiPathPrefix)).Handler(wrappedHandler)
 
 	log.Infof("       api:  you can access API using %s", a.GetAPIPath())
@@ -140,3 +162,42 @@ func corsWithArgs(frontendAddress string) func(next http.Handler) http.Handler {
 		})
 	}
 }
+
+// principalUser pins the ADK user ID of REST calls to the authenticated caller, so that a
+// caller cannot read or run the sessions of another user. Unauthenticated requests pass unchanged.
+func principalUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok := httpauth.FromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// /apps/{app_name}/users/{user_id}/...
+		segments := strings.Split(r.URL.Path, "/")
+		if len(segments) > 4 && segments[1] == "apps" && segments[3] == "users" {
+			segments[4] = url.PathEscape(p.Subject)
+			r.URL.Path = strings.Join(segments, "/")
+			r.URL.RawPath = ""
+		}
+
+		if r.Method == http.MethodPost && (r.URL.Path == "/run" || r.URL.Path == "/run_sse") {
+			var body map[string]any
+			decoder := json.NewDecoder(r.Body)
+			decoder.UseNumber()
+			if err := decoder.Decode(&body); err != nil {
+				http.Error(w, fmt.Sprintf("decode request body: %v", err), http.StatusBadRequest)
+				return
+			}
+			body["userId"] = p.Subject
+			b, err := json.Marshal(body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(b))
+			r.ContentLength = int64(len(b))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
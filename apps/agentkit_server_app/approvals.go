@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentkit_server_app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/volcengine/veadk-go/apps"
+	"github.com/volcengine/veadk-go/auth/httpauth"
+	"github.com/volcengine/veadk-go/tool/approval"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+// setupApprovalRouters serves the pending tool call approvals of a session under
+// {prefix}/apps/{app_name}/users/{user_id}/sessions/{session_id}/approvals, and runs
+// the agent with a decision POSTed to .../approvals/{approval_id}.
+func setupApprovalRouters(router *mux.Router, prefix string, config *apps.RunConfig, wrap func(http.Handler) http.Handler) {
+	h := &approvalsHandler{config: config}
+	path := prefix + "/apps/{app_name}/users/{user_id}/sessions/{session_id}/approvals"
+	router.Handle(path, wrap(http.HandlerFunc(h.list))).Methods(http.MethodGet)
+	router.Handle(path+"/{approval_id}", wrap(http.HandlerFunc(h.decide))).Methods(http.MethodPost)
+}
+
+type approvalsHandler struct {
+	config *apps.RunConfig
+}
+
+func (h *approvalsHandler) list(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.session(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	pending := approval.Pending(sess)
+	if pending == nil {
+		pending = []approval.Request{}
+	}
+	writeJSON(w, pending)
+}
+
+func (h *approvalsHandler) decide(w http.ResponseWriter, r *http.Request) {
+	var decision approval.Decision
+	if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+		http.Error(w, fmt.Sprintf("decode decision: %v", err), http.StatusBadRequest)
+		return
+	}
+	sess, err := h.session(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	request, err := approval.Find(sess, mux.Vars(r)["approval_id"])
+	switch {
+	case errors.Is(err, approval.ErrNotPending):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	appName := mux.Vars(r)["app_name"]
+	ag, err := h.config.AgentLoader.LoadAgent(appName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("load agent %s: %v", appName, err), http.StatusNotFound)
+		return
+	}
+	rn, err := runner.New(runner.Config{
+		AppName:         appName,
+		Agent:           ag,
+		SessionService:  h.config.SessionService,
+		ArtifactService: h.config.ArtifactService,
+		MemoryService:   h.config.MemoryService,
+		PluginConfig:    h.config.PluginConfig,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("create runner: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	events := []*session.Event{}
+	for event, err := range rn.Run(r.Context(), sess.UserID(), sess.ID(), decision.Content(request.ConfirmationID), agent.RunConfig{}) {
+		if err != nil {
+			http.Error(w, fmt.Sprintf("run agent: %v", err), http.StatusInternalServerError)
+			return
+		}
+		events = append(events, event)
+	}
+	writeJSON(w, events)
+}
+
+// session loads the session of the request, owned by the authenticated caller if any.
+func (h *approvalsHandler) session(r *http.Request) (session.Session, error) {
+	vars := mux.Vars(r)
+	userID := vars["user_id"]
+	if p, ok := httpauth.FromContext(r.Context()); ok {
+		userID = p.Subject
+	}
+	resp, err := h.config.SessionService.Get(r.Context(), &session.GetRequest{
+		AppName:   vars["app_name"],
+		UserID:    userID,
+		SessionID: vars["session_id"],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	return resp.Session, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentkit_server_app
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/apps"
+	"github.com/volcengine/veadk-go/tool/approval"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+// deleteModel calls the delete tool, and answers once it has its result.
+type deleteModel struct{}
+
+func (deleteModel) Name() string { return "delete-model" }
+
+func (deleteModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		if last := req.Contents[len(req.Contents)-1]; last.Parts[0].FunctionResponse != nil {
+			yield(&model.LLMResponse{Content: genai.NewContentFromText("deleted", genai.RoleModel)}, nil)
+			return
+		}
+		yield(&model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{
+			FunctionCall: &genai.FunctionCall{ID: "call-1", Name: "delete_file", Args: map[string]any{"path": "/tmp/a"}},
+		}}}}, nil)
+	}
+}
+
+func TestApprovalRouters(t *testing.T) {
+	var deleted []string
+	deleteFile, err := functiontool.New(functiontool.Config{Name: "delete_file", Description: "Deletes a file."},
+		func(_ tool.Context, args struct {
+			Path string `json:"path"`
+		}) (map[string]any, error) {
+			deleted = append(deleted, args.Path)
+			return map[string]any{}, nil
+		})
+	require.NoError(t, err)
+	ag, err := llmagent.New(llmagent.Config{Name: "files", Model: deleteModel{}, Tools: []tool.Tool{deleteFile}})
+	require.NoError(t, err)
+	approvals, err := approval.NewPlugin(&approval.PluginConfig{Policy: approval.ByName("delete_file")})
+	require.NoError(t, err)
+
+	config := &apps.RunConfig{
+		SessionService: session.InMemoryService(),
+		AgentLoader:    agent.NewSingleLoader(ag),
+		PluginConfig:   runner.PluginConfig{Plugins: []*plugin.Plugin{approvals}},
+	}
+	rn, err := runner.New(runner.Config{AppName: "files", Agent: ag, SessionService: config.SessionService, AutoCreateSession: true, PluginConfig: config.PluginConfig})
+	require.NoError(t, err)
+	for _, err := range rn.Run(context.Background(), "user", "session", genai.NewContentFromText("Delete /tmp/a", genai.RoleUser), agent.RunConfig{}) {
+		require.NoError(t, err)
+	}
+
+	router := mux.NewRouter()
+	setupApprovalRouters(router, "/api", config, func(h http.Handler) http.Handler { return h })
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+	path := "/api/apps/files/users/user/sessions/session/approvals"
+
+	rec := serve(http.MethodGet, path, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var pending []approval.Request
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &pending))
+	require.Len(t, pending, 1)
+	assert.Equal(t, "delete_file", pending[0].Tool)
+
+	rec = serve(http.MethodPost, path+"/call-1", `{"approved": true, "args": {"path": "/tmp/b"}}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, []string{"/tmp/b"}, deleted)
+	assert.Contains(t, rec.Body.String(), "deleted")
+
+	assert.Equal(t, http.StatusConflict, serve(http.MethodPost, path+"/call-1", `{"approved": true}`).Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, path+"/call-2", `{"approved": true}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, path+"/call-1", `{`).Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/apps/files/users/user/sessions/other/approvals", "").Code)
+}
@@ -21,11 +21,15 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/a2aproject/a2a-go/a2asrv"
 	"github.com/gorilla/mux"
+	"github.com/volcengine/veadk-go/auth/httpauth"
+	"github.com/volcengine/veadk-go/common"
 	"github.com/volcengine/veadk-go/log"
 	"github.com/volcengine/veadk-go/observability"
 	"google.golang.org/adk/agent"
@@ -46,6 +50,12 @@ type RunConfig struct {
 	A2AOptions       []a2asrv.RequestHandlerOption
 	PluginConfig     runner.PluginConfig
 	TelemetryOptions []telemetry.Option
+	// Auth, when set, requires callers to authenticate. The principal becomes the ADK user ID,
+	// so sessions and memory are isolated per caller. The debug routes, which expose the
+	// traces of all callers, require httpauth.AdminScope unless a rule of Auth covers them.
+	Auth *httpauth.Config
+	// RateLimit, when set, limits the rate and concurrency of invocations.
+	RateLimit *RateLimitConfig
 }
 
 func (cfg *RunConfig) AppendObservability() {
@@ -75,6 +85,12 @@ type ApiConfig struct {
 	IdleTimeout     time.Duration
 	SEEWriteTimeout time.Duration
 	ApiPathPrefix   string
+	// ShutdownGracePeriod bounds how long in-flight invocations, such as SSE streams,
+	// may run once the server starts draining.
+	ShutdownGracePeriod time.Duration
+	// PublicURL is the base URL clients reach the server at, such as an ingress address.
+	// It is published in the A2A agent cards, GetWebUrl() by default.
+	PublicURL string
 }
 
 type BasicApp interface {
@@ -86,12 +102,14 @@ type BasicApp interface {
 
 func DefaultApiConfig() *ApiConfig {
 	return &ApiConfig{
-		Port:            8000,
-		WriteTimeout:    time.Second * 60,
-		ReadTimeout:     time.Second * 60,
-		IdleTimeout:     time.Second * 120,
-		SEEWriteTimeout: time.Second * 300,
-		ApiPathPrefix:   "", // set /api same as ADK-Go
+		Port:                8000,
+		WriteTimeout:        time.Second * 60,
+		ReadTimeout:         time.Second * 60,
+		IdleTimeout:         time.Second * 120,
+		SEEWriteTimeout:     time.Second * 300,
+		ApiPathPrefix:       "", // set /api same as ADK-Go
+		ShutdownGracePeriod: time.Second * 30,
+		PublicURL:           os.Getenv(common.SERVER_PUBLIC_URL),
 	}
 }
 
@@ -120,11 +138,28 @@ func (a *ApiConfig) SetSEEWriteTimeout(t int64) *ApiConfig {
 	return a
 }
 
+func (a *ApiConfig) SetShutdownGracePeriod(t int64) *ApiConfig {
+	a.ShutdownGracePeriod = time.Second * time.Duration(t)
+	return a
+}
+
 func (a *ApiConfig) SetApiPathPrefix(p string) *ApiConfig {
 	a.ApiPathPrefix = p
 	return a
 }
 
+func (a *ApiConfig) SetPublicURL(u string) *ApiConfig {
+	a.PublicURL = u
+	return a
+}
+
+func (a *ApiConfig) GetPublicUrl() string {
+	if a.PublicURL == "" {
+		return a.GetWebUrl()
+	}
+	return strings.TrimSuffix(a.PublicURL, "/")
+}
+
 func (a *ApiConfig) GetWebUrl() string {
 	return fmt.Sprintf("http://localhost:%d", a.Port)
 }
@@ -143,7 +178,8 @@ func Run(ctx context.Context, config *RunConfig, app BasicApp) error {
 	config.AppendObservability()
 
 	defer func() {
-		err := observability.Shutdown(ctx)
+		// ctx may be the one that stopped the server, flushing must not be cancelled with it
+		err := observability.Shutdown(context.WithoutCancel(ctx))
 		if err != nil {
 			log.Errorf("shutting down observability error: %s", err.Error())
 			return
@@ -151,6 +187,28 @@ func Run(ctx context.Context, config *RunConfig, app BasicApp) error {
 		log.Info("observability stopped")
 	}()
 
+	// registered before the app routers so that catch-all routes do not shadow them
+	health := newHealthStatus(defaultHealthCheckers(config))
+	router.Use(health.drainMiddleware)
+	router.HandleFunc(LivenessPath, health.livenessHandler).Methods(http.MethodGet)
+	router.HandleFunc(ReadinessPath, health.readinessHandler).Methods(http.MethodGet)
+	if config.Auth != nil {
+		router.Use(httpauth.Middleware(authConfig(config.Auth, app.GetApiConfig().ApiPathPrefix)))
+		log.Infof("Authentication is required, except for the probes and the agent card")
+	}
+	// after authentication, so that per-user limits apply to the principal
+	if config.RateLimit != nil {
+		router.Use(newLimiter(*config.RateLimit).middleware)
+	}
+	router.Use(sessionLogMiddleware)
+	log.Infof("Liveness is served on %s%s, readiness on %s%s checking %v",
+		app.GetApiConfig().GetWebUrl(), LivenessPath, app.GetApiConfig().GetWebUrl(), ReadinessPath, sortedCheckNames(health.checkers))
+
+	if path, handler := observability.PrometheusEndpoint(); handler != nil {
+		router.Handle(path, handler).Methods(http.MethodGet)
+		log.Infof("Prometheus metrics are served on %s%s", app.GetApiConfig().GetWebUrl(), path)
+	}
+
 	log.Infof("Web servers starts on %s", app.GetApiConfig().GetWebUrl())
 	err := app.SetupRouters(router, config)
 	if err != nil {
@@ -169,21 +227,80 @@ func Run(ctx context.Context, config *RunConfig, app BasicApp) error {
 		Handler:      router,
 	}
 
+	stopped := make(chan struct{})
+	shutdownDone := make(chan struct{})
 	go func() {
-		<-quit
-		log.Infof("Received shutdown signal, gracefully stopping %s...", app.GetServerName())
-		shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		defer cancel()
-		if err := srv.Shutdown(shutdownCtx); err != nil {
-			log.Errorf("Server shutdown failed: %v", err)
+		defer close(shutdownDone)
+		select {
+		case <-quit:
+			log.Infof("Received shutdown signal, gracefully stopping %s...", app.GetServerName())
+		case <-ctx.Done():
+			log.Infof("Context done, gracefully stopping %s...", app.GetServerName())
+		case <-stopped:
+			return
 		}
+		shutdown(ctx, &srv, health, app.GetApiConfig().ShutdownGracePeriod)
 	}()
 
 	err = srv.ListenAndServe()
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		close(stopped)
 		return fmt.Errorf("%s failed: %v", app.GetServerName(), err)
 	}
+	// in-flight invocations are drained before observability is flushed
+	<-shutdownDone
 
 	log.Infof("%s stopped gracefully", app.GetServerName())
 	return nil
 }
+
+// shutdown refuses new invocations, waits for the ones in flight within the grace period
+// and then stops the server.
+func shutdown(ctx context.Context, srv *http.Server, health *healthStatus, gracePeriod time.Duration) {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultApiConfig().ShutdownGracePeriod
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), gracePeriod)
+	defer cancel()
+
+	if !health.drain(shutdownCtx) {
+		_, inFlight := health.status()
+		log.Warnf("Grace period of %s elapsed with %d invocations in flight", gracePeriod, inFlight)
+	}
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Errorf("Server shutdown failed: %v", err)
+		_ = srv.Close()
+	}
+}
+
+// authConfig keeps the probes and the A2A agent card public unless the caller's rules say otherwise.
+// "/health" is the probe of the agentkit simple app.
+func authConfig(cfg *httpauth.Config, apiPathPrefix string) httpauth.Config {
+	rules := slices.Clone(cfg.Rules)
+	// the trace viewer and the ADK debug API serve the traces of every caller
+	debugPaths := []string{"/debug/", apiPathPrefix + "/debug/"}
+	if path, handler := observability.TraceViewerEndpoint(); handler != nil {
+		debugPaths = append(debugPaths, path)
+	}
+	for _, path := range debugPaths {
+		rules = append(rules, httpauth.Rule{PathPrefix: path, Scopes: []string{httpauth.AdminScope}})
+	}
+	for _, path := range []string{LivenessPath, ReadinessPath, "/health"} {
+		rules = append(rules, httpauth.Rule{PathPrefix: path, Public: true})
+	}
+	// the card of every hosted agent, not only the root one
+	rules = append(rules, httpauth.Rule{PathSuffix: a2asrv.WellKnownAgentCardPath, Public: true})
+	return httpauth.Config{Authenticator: cfg.Authenticator, Rules: rules}
+}
+
+// sessionLogMiddleware tags the request context with the session in the request path,
+// so that the logs of the handlers carry its session_id. Request bodies are only parsed
+// by the limiter when it serializes sessions.
+func sessionLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sessionID := pathSessionID(r.URL.Path); sessionID != "" {
+			r = r.WithContext(log.ContextWithSession(r.Context(), sessionID, ""))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
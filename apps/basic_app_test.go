@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/auth/httpauth"
+	"github.com/volcengine/veadk-go/log"
+)
+
+func TestAuthConfig(t *testing.T) {
+	apiKeys, err := httpauth.NewAPIKeyAuthenticator(map[string]*httpauth.Principal{
+		"key":       {Subject: "alice"},
+		"admin-key": {Subject: "ops", Scopes: []string{httpauth.AdminScope}},
+	})
+	require.NoError(t, err)
+	handler := httpauth.Middleware(authConfig(&httpauth.Config{
+		Authenticator: apiKeys,
+		Rules:         []httpauth.Rule{{PathPrefix: ReadinessPath, Scopes: []string{"ops"}}},
+	}, "/api"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for path, code := range map[string]int{
+		LivenessPath:                  http.StatusOK,
+		a2asrv.WellKnownAgentCardPath: http.StatusOK,
+		ReadinessPath:                 http.StatusUnauthorized, // the caller's rule wins over the defaults
+		"/a2a/helper/.well-known/agent-card.json": http.StatusOK,
+		"/apps/app/users/alice/session":           http.StatusUnauthorized,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, code, rec.Code, path)
+	}
+
+	// the debug routes expose every caller's traces and are reserved to admins
+	for key, code := range map[string]int{"key": http.StatusForbidden, "admin-key": http.StatusOK} {
+		for _, path := range []string{"/debug/traces", "/api/debug/trace/session/s1"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set(httpauth.APIKeyHeader, key)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, code, rec.Code, key+" "+path)
+		}
+	}
+}
+
+type unreadableBody struct{}
+
+func (unreadableBody) Read([]byte) (int, error) { return 0, errors.New("body read") }
+func (unreadableBody) Close() error             { return nil }
+
+// loggedSession returns the session_id that the log lines of a handler carry.
+func loggedSession(handler func(http.Handler) http.Handler, r *http.Request) string {
+	var buf bytes.Buffer
+	logger := slog.New(log.NewContextHandler(slog.NewTextHandler(&buf, nil)))
+	handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "handled")
+	})).ServeHTTP(httptest.NewRecorder(), r)
+	_, sessionID, _ := strings.Cut(buf.String(), log.SessionIDKey+"=")
+	sessionID, _, _ = strings.Cut(sessionID, " ")
+	return strings.TrimSpace(sessionID)
+}
+
+func TestSessionLogMiddleware(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/apps/app/users/alice/sessions/s1/events", unreadableBody{})
+	assert.Equal(t, "s1", loggedSession(sessionLogMiddleware, r))
+
+	// the body is not read, it is only parsed by the limiter when it serializes sessions
+	r = httptest.NewRequest(http.MethodPost, "/run_sse", unreadableBody{})
+	assert.Empty(t, loggedSession(sessionLogMiddleware, r))
+
+	l := newLimiter(RateLimitConfig{SerializeSessions: true})
+	r = httptest.NewRequest(http.MethodPost, "/run_sse", strings.NewReader(`{"sessionId":"s2"}`))
+	assert.Equal(t, "s2", loggedSession(l.middleware, r))
+}
@@ -0,0 +1,329 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/volcengine/veadk-go/configs"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/session"
+)
+
+const (
+	// LivenessPath reports that the process is up and serving.
+	LivenessPath = "/healthz"
+	// ReadinessPath reports whether the server can take invocations.
+	ReadinessPath = "/readyz"
+
+	// DefaultHealthCheckTimeout bounds each readiness check.
+	DefaultHealthCheckTimeout = 3 * time.Second
+
+	// healthCheckID is the app and user name used to probe the session and memory services.
+	healthCheckID = "veadk_health_check"
+)
+
+// ErrDraining is reported by the readiness probe once the server shuts down.
+var ErrDraining = errors.New("server is draining")
+
+// HealthChecker checks a dependency the server needs to take invocations.
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type healthChecker struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (c *healthChecker) Name() string {
+	return c.name
+}
+
+func (c *healthChecker) Check(ctx context.Context) error {
+	return c.check(ctx)
+}
+
+// NewHealthChecker creates a HealthChecker from a function.
+func NewHealthChecker(name string, check func(ctx context.Context) error) HealthChecker {
+	return &healthChecker{name: name, check: check}
+}
+
+// Pinger is implemented by services that can check their backend without side effects.
+// The session and memory readiness checks use it when available.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+var (
+	healthCheckersMu sync.RWMutex
+	healthCheckers   []HealthChecker
+)
+
+// RegisterHealthChecker adds a check to the readiness probe of the servers started by Run.
+func RegisterHealthChecker(c HealthChecker) {
+	healthCheckersMu.Lock()
+	defer healthCheckersMu.Unlock()
+	healthCheckers = append(healthCheckers, c)
+}
+
+func registeredHealthCheckers() []HealthChecker {
+	healthCheckersMu.RLock()
+	defer healthCheckersMu.RUnlock()
+	return append([]HealthChecker(nil), healthCheckers...)
+}
+
+// SessionServiceChecker checks that the session service answers a List request.
+func SessionServiceChecker(s session.Service) HealthChecker {
+	return NewHealthChecker("session_service", func(ctx context.Context) error {
+		if p, ok := s.(Pinger); ok {
+			return p.Ping(ctx)
+		}
+		_, err := s.List(ctx, &session.ListRequest{AppName: healthCheckID, UserID: healthCheckID})
+		return err
+	})
+}
+
+// MemoryServiceChecker checks that the memory service answers a SearchMemory request.
+// Services implementing Pinger, as the long-term memory services do, are pinged instead,
+// to avoid a search, and its embedding call, per probe.
+func MemoryServiceChecker(m memory.Service) HealthChecker {
+	return NewHealthChecker("memory_service", func(ctx context.Context) error {
+		if p, ok := m.(Pinger); ok {
+			return p.Ping(ctx)
+		}
+		_, err := m.SearchMemory(ctx, &memory.SearchRequest{Query: "ping", AppName: healthCheckID, UserID: healthCheckID})
+		return err
+	})
+}
+
+// ModelChecker checks that the model API base is reachable. Any HTTP response counts,
+// so that the probe spends no tokens and needs no credentials.
+func ModelChecker(apiBase string) HealthChecker {
+	return NewHealthChecker("model", func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiBase, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	})
+}
+
+// defaultHealthCheckers checks the services of the run config and the agent model.
+func defaultHealthCheckers(config *RunConfig) []HealthChecker {
+	var checkers []HealthChecker
+	if config.SessionService != nil {
+		checkers = append(checkers, SessionServiceChecker(config.SessionService))
+	}
+	if config.MemoryService != nil {
+		checkers = append(checkers, MemoryServiceChecker(config.MemoryService))
+	}
+	if model := configs.GetGlobalConfig().Model; model != nil && model.Agent != nil && model.Agent.ApiBase != "" {
+		checkers = append(checkers, ModelChecker(model.Agent.ApiBase))
+	}
+	return checkers
+}
+
+// healthStatus tracks the readiness of a server and the invocations it is serving.
+type healthStatus struct {
+	checkers []HealthChecker
+	timeout  time.Duration
+
+	// mu guards draining and inFlight, so that no invocation starts once drain has seen
+	// the server idle
+	mu       sync.Mutex
+	draining bool
+	inFlight int64
+	// idle is closed once the server is draining with no invocation in flight
+	idle chan struct{}
+}
+
+func newHealthStatus(checkers []HealthChecker) *healthStatus {
+	return &healthStatus{checkers: checkers, timeout: DefaultHealthCheckTimeout}
+}
+
+// HealthResponse is the body of the liveness and readiness probes.
+type HealthResponse struct {
+	Status string `json:"status"`
+	// Checks maps each readiness check to "ok" or its error.
+	Checks   map[string]string `json:"checks,omitempty"`
+	InFlight int64             `json:"in_flight"`
+}
+
+func (h *healthStatus) livenessHandler(w http.ResponseWriter, _ *http.Request) {
+	_, inFlight := h.status()
+	writeHealthResponse(w, http.StatusOK, HealthResponse{Status: "ok", InFlight: inFlight})
+}
+
+func (h *healthStatus) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	if draining, inFlight := h.status(); draining {
+		writeHealthResponse(w, http.StatusServiceUnavailable, HealthResponse{
+			Status:   "draining",
+			Checks:   map[string]string{"server": ErrDraining.Error()},
+			InFlight: inFlight,
+		})
+		return
+	}
+
+	checks, ready := h.check(r.Context())
+	_, inFlight := h.status()
+	res := HealthResponse{Status: "ready", Checks: checks, InFlight: inFlight}
+	code := http.StatusOK
+	if !ready {
+		res.Status = "not_ready"
+		code = http.StatusServiceUnavailable
+	}
+	writeHealthResponse(w, code, res)
+}
+
+// check runs the checks concurrently, each bounded by the check timeout.
+func (h *healthStatus) check(ctx context.Context) (map[string]string, bool) {
+	checkers := append(append([]HealthChecker(nil), h.checkers...), registeredHealthCheckers()...)
+	results := make([]error, len(checkers))
+
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, h.timeout)
+			defer cancel()
+			results[i] = runHealthCheck(checkCtx, c)
+		}()
+	}
+	wg.Wait()
+
+	checks := make(map[string]string, len(checkers))
+	ready := true
+	for i, c := range checkers {
+		if results[i] != nil {
+			ready = false
+			checks[c.Name()] = results[i].Error()
+			continue
+		}
+		checks[c.Name()] = "ok"
+	}
+	return checks, ready
+}
+
+func runHealthCheck(ctx context.Context, c HealthChecker) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("health check panicked: %v", r)
+		}
+	}()
+	return c.Check(ctx)
+}
+
+func writeHealthResponse(w http.ResponseWriter, code int, res HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// drainMiddleware refuses new invocations once the server is draining and tracks the
+// ones in flight. Reads such as probes and metrics are still served while draining.
+func (h *healthStatus) drainMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isInvocation(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !h.begin() {
+			w.Header().Set("Connection", "close")
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, ErrDraining.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		defer h.end()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isInvocation reports whether r may start an agent invocation; reads never do.
+func isInvocation(r *http.Request) bool {
+	return r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions
+}
+
+// begin counts a new invocation in flight, unless the server is draining.
+func (h *healthStatus) begin() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.draining {
+		return false
+	}
+	h.inFlight++
+	return true
+}
+
+func (h *healthStatus) end() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.inFlight--
+	if h.draining && h.inFlight == 0 {
+		close(h.idle)
+	}
+}
+
+// status returns whether the server is draining and the number of invocations in flight.
+func (h *healthStatus) status() (draining bool, inFlight int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.draining, h.inFlight
+}
+
+// drain marks the server as draining and waits for the invocations in flight,
+// returning false if ctx ends first.
+func (h *healthStatus) drain(ctx context.Context) bool {
+	h.mu.Lock()
+	if !h.draining {
+		h.draining = true
+		h.idle = make(chan struct{})
+		if h.inFlight == 0 {
+			close(h.idle)
+		}
+	}
+	idle := h.idle
+	h.mu.Unlock()
+
+	select {
+	case <-idle:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sortedCheckNames is used in logs to list the readiness checks in a stable order.
+func sortedCheckNames(checkers []HealthChecker) []string {
+	names := make([]string, 0, len(checkers))
+	for _, c := range checkers {
+		names = append(names, c.Name())
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/session"
+)
+
+func readiness(t *testing.T, h *healthStatus) (int, HealthResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.readinessHandler(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	var res HealthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	return rec.Code, res
+}
+
+func TestReadiness(t *testing.T) {
+	h := newHealthStatus([]HealthChecker{
+		SessionServiceChecker(session.InMemoryService()),
+		MemoryServiceChecker(memory.InMemoryService()),
+	})
+
+	code, res := readiness(t, h)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", res.Status)
+	assert.Equal(t, map[string]string{"session_service": "ok", "memory_service": "ok"}, res.Checks)
+
+	t.Run("failing registered checker", func(t *testing.T) {
+		RegisterHealthChecker(NewHealthChecker("vector_store", func(context.Context) error {
+			return errors.New("connection refused")
+		}))
+		RegisterHealthChecker(NewHealthChecker("panicking", func(context.Context) error {
+			panic("boom")
+		}))
+		t.Cleanup(func() { healthCheckers = nil })
+
+		code, res := readiness(t, h)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "not_ready", res.Status)
+		assert.Equal(t, "connection refused", res.Checks["vector_store"])
+		assert.Contains(t, res.Checks["panicking"], "boom")
+		assert.Equal(t, "ok", res.Checks["session_service"])
+	})
+
+	t.Run("slow checker times out", func(t *testing.T) {
+		slow := newHealthStatus([]HealthChecker{NewHealthChecker("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})})
+		slow.timeout = 10 * time.Millisecond
+
+		code, res := readiness(t, slow)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, context.DeadlineExceeded.Error(), res.Checks["slow"])
+	})
+}
+
+func TestModelChecker(t *testing.T) {
+	// any HTTP answer means the model endpoint is reachable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	assert.NoError(t, ModelChecker(server.URL).Check(context.Background()))
+
+	server.Close()
+	assert.Error(t, ModelChecker(server.URL).Check(context.Background()))
+}
+
+func TestDrainMiddleware(t *testing.T) {
+	h := newHealthStatus(nil)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	router := mux.NewRouter()
+	router.Use(h.drainMiddleware)
+	router.HandleFunc("/invoke", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	}).Methods(http.MethodPost)
+	router.HandleFunc(ReadinessPath, h.readinessHandler).Methods(http.MethodGet)
+
+	inFlight := httptest.NewRecorder()
+	go router.ServeHTTP(inFlight, httptest.NewRequest(http.MethodPost, "/invoke", nil))
+	<-started
+
+	drained := make(chan bool)
+	go func() { drained <- h.drain(context.Background()) }()
+	require.Eventually(t, func() bool {
+		draining, _ := h.status()
+		return draining
+	}, time.Second, time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/invoke", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	code, res := readiness(t, h)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "draining", res.Status)
+	assert.Equal(t, int64(1), res.InFlight)
+
+	close(release)
+	assert.True(t, <-drained)
+	assert.Equal(t, "done", inFlight.Body.String())
+	// nothing starts once the server has been seen idle
+	assert.False(t, h.begin())
+
+	t.Run("grace period elapses", func(t *testing.T) {
+		h := newHealthStatus(nil)
+		require.True(t, h.begin())
+		defer h.end()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.False(t, h.drain(ctx))
+	})
+}
+
+// streamApp serves a POST /stream that writes until it is released.
+type streamApp struct {
+	*ApiConfig
+	started chan struct{}
+	release chan struct{}
+}
+
+func (a *streamApp) Run(ctx context.Context, config *RunConfig) error {
+	return Run(ctx, config, a)
+}
+
+func (a *streamApp) SetupRouters(router *mux.Router, _ *RunConfig) error {
+	router.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("data: first\n\n"))
+		w.(http.Flusher).Flush()
+		close(a.started)
+		<-a.release
+		_, _ = w.Write([]byte("data: last\n\n"))
+	}).Methods(http.MethodPost)
+	return nil
+}
+
+func (a *streamApp) GetApiConfig() *ApiConfig {
+	return a.ApiConfig
+}
+
+func (a *streamApp) GetServerName() string {
+	return "stream app"
+}
+
+func TestRun_DrainsInFlightStreams(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	app := &streamApp{
+		ApiConfig: DefaultApiConfig().SetPort(port).SetShutdownGracePeriod(5),
+		started:   make(chan struct{}),
+		release:   make(chan struct{}),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() { runErr <- app.Run(ctx, &RunConfig{}) }()
+
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d", port)
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(baseURL + LivenessPath)
+		if err != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	stream := make(chan string)
+	go func() {
+		resp, err := http.Post(baseURL+"/stream", "application/json", nil)
+		if err != nil {
+			stream <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		var body strings.Builder
+		buf := make([]byte, 64)
+		for {
+			n, err := resp.Body.Read(buf)
+			body.Write(buf[:n])
+			if err != nil {
+				break
+			}
+		}
+		stream <- body.String()
+	}()
+	<-app.started
+
+	cancel()
+	select {
+	case err := <-runErr:
+		t.Fatalf("Run returned before the stream finished: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(app.release)
+	assert.Equal(t, "data: first\n\ndata: last\n\n", <-stream)
+	assert.NoError(t, <-runErr)
+}
@@ -0,0 +1,395 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volcengine/veadk-go/auth/httpauth"
+	"github.com/volcengine/veadk-go/log"
+	"github.com/volcengine/veadk-go/observability"
+)
+
+const (
+	// DefaultQueueTimeout bounds how long an invocation waits for a concurrency slot or its session.
+	DefaultQueueTimeout = 30 * time.Second
+
+	// bodies are peeked for a session ID only up to this size
+	maxSessionKeyPeek = 1 << 20
+	// idle per-user buckets are dropped after this long
+	userBucketIdleTTL = 10 * time.Minute
+)
+
+var (
+	ErrRateLimited  = errors.New("rate limit exceeded")
+	ErrServerBusy   = errors.New("too many concurrent invocations")
+	ErrSessionBusy  = errors.New("session is busy with another invocation")
+	errQueueTimeout = errors.New("queue timeout")
+)
+
+// RateLimitConfig limits the invocations admitted by apps.Run. Reads such as probes,
+// metrics and session listings are never limited. Zero values disable a limit.
+type RateLimitConfig struct {
+	// GlobalRate is the sustained number of invocations per second across all callers.
+	GlobalRate float64
+	// GlobalBurst is the bucket size of GlobalRate, ceil(GlobalRate) by default.
+	GlobalBurst int
+	// UserRate is the sustained number of invocations per second per caller. Callers are
+	// told apart by their authenticated principal, or by client IP without authentication.
+	UserRate  float64
+	UserBurst int
+
+	// MaxConcurrent caps the invocations running at once, the others queue for a slot.
+	MaxConcurrent int
+	// MaxQueued caps the invocations waiting for a slot, unbounded when 0.
+	MaxQueued int
+	// QueueTimeout bounds the wait for a slot or a busy session, DefaultQueueTimeout by default.
+	QueueTimeout time.Duration
+
+	// SerializeSessions runs the invocations of one session one after another, so that
+	// their events do not interleave.
+	SerializeSessions bool
+	// SessionKey identifies the session of a request, DefaultSessionKey by default.
+	// Requests with an empty key are not serialized.
+	SessionKey func(r *http.Request) string
+}
+
+// limiter enforces a RateLimitConfig.
+type limiter struct {
+	config RateLimitConfig
+	now    func() time.Time
+
+	mu        sync.Mutex
+	global    *tokenBucket
+	users     map[string]*tokenBucket
+	lastSweep time.Time
+	sessions  map[string]*sessionLock
+	slots     chan struct{}
+	queued    int
+}
+
+func newLimiter(config RateLimitConfig) *limiter {
+	if config.QueueTimeout <= 0 {
+		config.QueueTimeout = DefaultQueueTimeout
+	}
+	if config.SessionKey == nil {
+		config.SessionKey = DefaultSessionKey
+	}
+	l := &limiter{
+		config:   config,
+		now:      time.Now,
+		users:    make(map[string]*tokenBucket),
+		sessions: make(map[string]*sessionLock),
+	}
+	if config.GlobalRate > 0 {
+		l.global = newTokenBucket(config.GlobalRate, config.GlobalBurst)
+	}
+	if config.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+	return l
+}
+
+// middleware admits invocations through the rate limits, the session lock and the
+// concurrency slots, in that order, answering 429 with Retry-After when refused.
+func (l *limiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isInvocation(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := r.Context()
+
+		if reason, wait := l.allow(callerKey(r)); reason != "" {
+			l.reject(w, r, reason, ErrRateLimited, wait)
+			return
+		}
+
+		start := l.now()
+		if l.config.SerializeSessions {
+			if key := l.config.SessionKey(r); key != "" {
+				// the key is at hand here, sessionLogMiddleware only looks at the path
+				if sessionID, ok := strings.CutPrefix(key, "session:"); ok {
+					ctx = log.ContextWithSession(ctx, sessionID, "")
+					r = r.WithContext(ctx)
+				}
+				release, err := l.lockSession(ctx, key)
+				if err != nil {
+					l.reject(w, r, observability.ThrottleReasonSessionBusy, ErrSessionBusy, time.Second)
+					return
+				}
+				defer release()
+			}
+		}
+		if l.slots != nil {
+			release, err := l.acquireSlot(ctx)
+			if err != nil {
+				l.reject(w, r, observability.ThrottleReasonConcurrency, ErrServerBusy, time.Second)
+				return
+			}
+			defer release()
+		}
+		observability.RecordQueueWait(ctx, l.now().Sub(start).Seconds())
+
+		observability.AddActiveInvocations(ctx, 1)
+		defer observability.AddActiveInvocations(context.WithoutCancel(ctx), -1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *limiter) reject(w http.ResponseWriter, r *http.Request, reason string, err error, retryAfter time.Duration) {
+	observability.RecordThrottledRequest(r.Context(), reason)
+	log.Debugf("Throttled %s %s: %s", r.Method, r.URL.Path, reason)
+	w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(retryAfter.Seconds())))))
+	http.Error(w, err.Error(), http.StatusTooManyRequests)
+}
+
+// allow takes a token from the global and the caller's bucket. When refused, it returns
+// the throttle reason and how long until a token is available.
+func (l *limiter) allow(caller string) (string, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+
+	var user *tokenBucket
+	if l.config.UserRate > 0 {
+		l.sweepUsers(now)
+		user = l.users[caller]
+		if user == nil {
+			user = newTokenBucket(l.config.UserRate, l.config.UserBurst)
+			l.users[caller] = user
+		}
+		if wait := user.wait(now); wait > 0 {
+			return observability.ThrottleReasonUserRate, wait
+		}
+	}
+	if l.global != nil {
+		if wait := l.global.wait(now); wait > 0 {
+			return observability.ThrottleReasonGlobalRate, wait
+		}
+		l.global.take()
+	}
+	if user != nil {
+		user.take()
+	}
+	return "", 0
+}
+
+// sweepUsers drops the buckets of callers idle long enough for them to be full again.
+func (l *limiter) sweepUsers(now time.Time) {
+	if now.Sub(l.lastSweep) < userBucketIdleTTL {
+		return
+	}
+	l.lastSweep = now
+	for caller, b := range l.users {
+		if now.Sub(b.last) > userBucketIdleTTL {
+			delete(l.users, caller)
+		}
+	}
+}
+
+func (l *limiter) acquireSlot(ctx context.Context) (func(), error) {
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	default:
+	}
+
+	l.mu.Lock()
+	if l.config.MaxQueued > 0 && l.queued >= l.config.MaxQueued {
+		l.mu.Unlock()
+		return nil, ErrServerBusy
+	}
+	l.queued++
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.queued--
+		l.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(l.config.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-timer.C:
+		return nil, errQueueTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// sessionLock is a mutex usable with a timeout, shared by the requests of one session.
+type sessionLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+func (l *limiter) lockSession(ctx context.Context, key string) (func(), error) {
+	l.mu.Lock()
+	lock := l.sessions[key]
+	if lock == nil {
+		lock = &sessionLock{ch: make(chan struct{}, 1)}
+		l.sessions[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	unref := func() {
+		l.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.sessions, key)
+		}
+		l.mu.Unlock()
+	}
+
+	timer := time.NewTimer(l.config.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case lock.ch <- struct{}{}:
+		return func() {
+			<-lock.ch
+			unref()
+		}, nil
+	case <-timer.C:
+		unref()
+		return nil, errQueueTimeout
+	case <-ctx.Done():
+		unref()
+		return nil, ctx.Err()
+	}
+}
+
+// tokenBucket refills rate tokens per second up to burst.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// wait refills the bucket and returns how long until a token is available, 0 if one is.
+func (b *tokenBucket) wait(now time.Time) time.Duration {
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) take() {
+	b.tokens--
+}
+
+// callerKey identifies the caller for per-user limits.
+func callerKey(r *http.Request) string {
+	if user := httpauth.UserID(r.Context(), ""); user != "" {
+		return "user:" + user
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// DefaultSessionKey finds the session of ADK REST calls (the session path segment or the
+// sessionId of /run and /run_sse), of A2A messages (their context ID), and of the simple
+// app's /invoke, which keeps one session per caller.
+func DefaultSessionKey(r *http.Request) string {
+	if sessionID := pathSessionID(r.URL.Path); sessionID != "" {
+		return "session:" + sessionID
+	}
+	if strings.HasSuffix(r.URL.Path, "/invoke") {
+		// anonymous callers share the simple app's default session
+		return "user:" + httpauth.UserID(r.Context(), "")
+	}
+
+	body := peekBody(r)
+	if len(body) == 0 {
+		return ""
+	}
+	var payload struct {
+		SessionID  string `json:"sessionId"`
+		SessionID2 string `json:"session_id"`
+		Params     struct {
+			Message struct {
+				ContextID string `json:"contextId"`
+			} `json:"message"`
+		} `json:"params"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return ""
+	}
+	switch {
+	case payload.SessionID != "":
+		return "session:" + payload.SessionID
+	case payload.SessionID2 != "":
+		return "session:" + payload.SessionID2
+	case payload.Params.Message.ContextID != "":
+		return "session:" + payload.Params.Message.ContextID
+	}
+	return ""
+}
+
+// pathSessionID returns the session path segment of ADK REST calls.
+func pathSessionID(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment == "sessions" && i+1 < len(segments) {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+// peekBody returns the request body if it is small enough to parse, leaving r.Body intact.
+func peekBody(r *http.Request) []byte {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSessionKeyPeek+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil || len(body) > maxSessionKeyPeek {
+		return nil
+	}
+	return body
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/auth/httpauth"
+)
+
+func invoke(handler http.Handler, path, body string, user string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if user != "" {
+		r = r.WithContext(httpauth.NewContext(r.Context(), &httpauth.Principal{Subject: user}))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestLimiter_RateLimits(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := newLimiter(RateLimitConfig{GlobalRate: 10, GlobalBurst: 3, UserRate: 1, UserBurst: 2})
+	l.now = func() time.Time { return now }
+	handler := l.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	assert.Equal(t, http.StatusOK, invoke(handler, "/invoke", "", "alice").Code)
+	assert.Equal(t, http.StatusOK, invoke(handler, "/invoke", "", "alice").Code)
+	rec := invoke(handler, "/invoke", "", "alice")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "alice used up her burst")
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, invoke(handler, "/invoke", "", "bob").Code)
+	rec = invoke(handler, "/invoke", "", "carol")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "the global burst is used up")
+
+	// reads are never limited
+	read := httptest.NewRecorder()
+	handler.ServeHTTP(read, httptest.NewRequest(http.MethodGet, "/apps/app/users/alice/sessions", nil))
+	assert.Equal(t, http.StatusOK, read.Code)
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, invoke(handler, "/invoke", "", "alice").Code)
+	assert.Equal(t, http.StatusOK, invoke(handler, "/invoke", "", "carol").Code)
+}
+
+func TestLimiter_Concurrency(t *testing.T) {
+	release := make(chan struct{})
+	var running, peak atomic.Int32
+	l := newLimiter(RateLimitConfig{MaxConcurrent: 2, MaxQueued: 1, QueueTimeout: 50 * time.Millisecond})
+	handler := l.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := running.Add(1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		<-release
+		running.Add(-1)
+	}))
+
+	var wg sync.WaitGroup
+	codes := make(chan int, 3)
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- invoke(handler, "/run", "", "").Code
+		}()
+	}
+	require.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return running.Load() == 2 && l.queued == 1
+	}, time.Second, time.Millisecond)
+
+	rec := invoke(handler, "/run", "", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "the queue is full")
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// the queued invocation times out while the slots are held
+	assert.Equal(t, http.StatusTooManyRequests, <-codes)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, http.StatusOK, <-codes)
+	assert.Equal(t, http.StatusOK, <-codes)
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestLimiter_SerializeSessions(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	l := newLimiter(RateLimitConfig{SerializeSessions: true})
+	handler := l.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		order = append(order, "start "+string(body))
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		order = append(order, "end "+string(body))
+		mu.Unlock()
+	}))
+
+	var wg sync.WaitGroup
+	for _, body := range []string{`{"sessionId":"s1","n":1}`, `{"sessionId":"s1","n":2}`} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, invoke(handler, "/run_sse", body, "alice").Code)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, order, 4)
+	assert.True(t, strings.HasPrefix(order[0], "start"))
+	assert.Equal(t, strings.TrimPrefix(order[0], "start "), strings.TrimPrefix(order[1], "end "),
+		"the second invocation starts after the first ends, with the body intact")
+	assert.Empty(t, l.sessions, "session locks are released")
+}
+
+func TestDefaultSessionKey(t *testing.T) {
+	for name, tc := range map[string]struct {
+		path, body, user, key string
+	}{
+		"rest path":     {path: "/apps/app/users/alice/sessions/s1/artifacts", key: "session:s1"},
+		"run":           {path: "/run_sse", body: `{"appName":"app","userId":"alice","sessionId":"s2"}`, key: "session:s2"},
+		"a2a message":   {path: "/", body: `{"jsonrpc":"2.0","method":"message/send","params":{"message":{"contextId":"c1"}}}`, key: "session:c1"},
+		"new a2a task":  {path: "/", body: `{"jsonrpc":"2.0","method":"message/send","params":{"message":{}}}`, key: ""},
+		"simple invoke": {path: "/invoke", body: `{"prompt":"hi"}`, user: "alice", key: "user:alice"},
+		"not json":      {path: "/run", body: `prompt`, key: ""},
+	} {
+		r := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		if tc.user != "" {
+			r = r.WithContext(httpauth.NewContext(r.Context(), &httpauth.Principal{Subject: tc.user}))
+		}
+		assert.Equal(t, tc.key, DefaultSessionKey(r), name)
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, tc.body, string(body), name)
+	}
+}
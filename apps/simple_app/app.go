@@ -27,6 +27,10 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/volcengine/veadk-go/apps"
+	"github.com/volcengine/veadk-go/auth/httpauth"
+	"github.com/volcengine/veadk-go/configs"
+	"github.com/volcengine/veadk-go/cost"
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/session"
@@ -39,8 +43,11 @@ type agentkitSimpleApp struct {
 	*apps.ApiConfig
 	appName string
 	userID  string
-	session session.Session
 	runner  *runner.Runner
+	// sessionService keeps one session per caller, creations are shared by the
+	// concurrent first requests of a caller
+	sessionService session.Service
+	creations      singleflight.Group
 }
 
 func NewAgentkitSimpleApp(config *apps.ApiConfig) apps.BasicApp {
@@ -61,14 +68,10 @@ func (a *agentkitSimpleApp) SetupRouters(router *mux.Router, config *apps.RunCon
 		a.userID = "agentkit_user"
 	}
 
-	resp, err := config.SessionService.Create(context.Background(), &session.CreateRequest{
-		AppName: a.appName,
-		UserID:  a.userID,
-	})
-	if err != nil {
+	a.sessionService = config.SessionService
+	if _, err := a.sessionID(context.Background(), a.userID); err != nil {
 		return fmt.Errorf("failed to create the session service: %w", err)
 	}
-	a.session = resp.Session
 
 	r, err := runner.New(runner.Config{
 		AppName:         a.appName,
@@ -105,12 +108,17 @@ type Response struct {
 	Message   string `json:"message"`
 	SessionId string `json:"session_id"`
 	Data      string `json:"data"`
+	// Usage and Cost sum the model calls of the invocation, priced with the cost.pricing table.
+	Usage    *cost.Usage `json:"usage,omitempty"`
+	Cost     float64     `json:"cost,omitempty"`
+	Currency string      `json:"currency,omitempty"`
 }
 
 func (a *agentkitSimpleApp) newInvokeHandler() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req Request
-		ctx := context.Background()
+		// the run outlives a disconnected caller, but keeps the trace of the request
+		ctx := context.WithoutCancel(r.Context())
 
 		body, err := io.ReadAll(r.Body)
 		defer func() {
@@ -129,14 +137,33 @@ func (a *agentkitSimpleApp) newInvokeHandler() func(w http.ResponseWriter, r *ht
 			return
 		}
 
+		// authenticated callers get their own session and memory
+		userID := httpauth.UserID(r.Context(), a.userID)
+		sessionID, err := a.sessionID(ctx, userID)
+		if err != nil {
+			res := Response{Code: http.StatusInternalServerError, Message: fmt.Sprintf("create session error: %s", err.Error()), Data: ""}
+			_ = json.NewEncoder(w).Encode(res)
+			return
+		}
+		ctx = log.ContextWithSession(ctx, sessionID, "")
+
 		userInput := genai.NewContentFromText(req.Prompt, "user")
 
 		var finalResponseText []string
-		for event, err := range a.runner.Run(ctx, a.userID, a.session.ID(), userInput, agent.RunConfig{StreamingMode: agent.StreamingModeNone}) {
+		var usage cost.Usage
+		var totalCost float64
+		for event, err := range a.runner.Run(ctx, userID, sessionID, userInput, agent.RunConfig{StreamingMode: agent.StreamingModeNone}) {
 			if err != nil {
-				log.Errorf("Agent Run Error: %v", err)
+				log.ErrorContext(ctx, "Agent Run Error", "error", err)
 				continue
 			}
+			if event.UsageMetadata != nil && !event.Partial {
+				eventUsage := cost.UsageFromMetadata(event.UsageMetadata)
+				usage = usage.Add(eventUsage)
+				if c, ok := cost.DefaultPricingTable().Cost(cost.ResponseModel(&event.LLMResponse), eventUsage); ok {
+					totalCost += c
+				}
+			}
 			if event.Content != nil && !event.Partial {
 				for _, part := range event.Content.Parts {
 					if !part.Thought {
@@ -149,13 +176,47 @@ func (a *agentkitSimpleApp) newInvokeHandler() func(w http.ResponseWriter, r *ht
 		res := Response{
 			Code:      200,
 			Message:   "success",
-			SessionId: a.session.ID(),
+			SessionId: sessionID,
 			Data:      strings.Join(finalResponseText, ""),
 		}
+		if usage != (cost.Usage{}) {
+			res.Usage = &usage
+			res.Cost = totalCost
+			res.Currency = configs.GetGlobalConfig().Cost.Currency
+		}
 		_ = json.NewEncoder(w).Encode(res)
 	}
 }
 
+// sessionID returns the most recently updated session of userID in the session service,
+// creating one on first use.
+func (a *agentkitSimpleApp) sessionID(ctx context.Context, userID string) (string, error) {
+	id, err, _ := a.creations.Do(userID, func() (any, error) {
+		list, err := a.sessionService.List(ctx, &session.ListRequest{AppName: a.appName, UserID: userID})
+		if err != nil {
+			return "", err
+		}
+		var latest session.Session
+		for _, s := range list.Sessions {
+			if latest == nil || s.LastUpdateTime().After(latest.LastUpdateTime()) {
+				latest = s
+			}
+		}
+		if latest != nil {
+			return latest.ID(), nil
+		}
+		resp, err := a.sessionService.Create(ctx, &session.CreateRequest{
+			AppName: a.appName,
+			UserID:  userID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.Session.ID(), nil
+	})
+	return id.(string), err
+}
+
 func (a *agentkitSimpleApp) newHealthHandler() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		res := Response{
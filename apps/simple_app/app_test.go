@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simple_app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/apps"
+	"google.golang.org/adk/session"
+)
+
+func TestSessionID(t *testing.T) {
+	ctx := context.Background()
+	a := NewAgentkitSimpleApp(apps.DefaultApiConfig()).(*agentkitSimpleApp)
+	a.sessionService = session.InMemoryService()
+
+	alice, err := a.sessionID(ctx, "alice")
+	require.NoError(t, err)
+	again, err := a.sessionID(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, alice, again, "callers keep their session")
+
+	bob, err := a.sessionID(ctx, "bob")
+	require.NoError(t, err)
+	assert.NotEqual(t, alice, bob)
+
+	// sessions are looked up in the session service, e.g. after a restart
+	restarted := NewAgentkitSimpleApp(apps.DefaultApiConfig()).(*agentkitSimpleApp)
+	restarted.sessionService = a.sessionService
+	again, err = restarted.sessionID(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, alice, again)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpauth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+)
+
+// APIKeyHeader is checked for a static API key when no bearer token is sent.
+const APIKeyHeader = "X-API-Key"
+
+type apiKeyAuthenticator struct {
+	// keyed by the SHA-256 of the key so that lookups do not compare secrets directly
+	principals map[[sha256.Size]byte]*Principal
+}
+
+// NewAPIKeyAuthenticator accepts the static keys of keys, sent either as
+// "Authorization: Bearer <key>" (as remoteagent does) or in the X-API-Key header.
+func NewAPIKeyAuthenticator(keys map[string]*Principal) (Authenticator, error) {
+	a := &apiKeyAuthenticator{principals: make(map[[sha256.Size]byte]*Principal, len(keys))}
+	for key, p := range keys {
+		if key == "" || p == nil || p.Subject == "" {
+			return nil, fmt.Errorf("httpauth: api key needs a non-empty key and principal subject")
+		}
+		principal := *p
+		principal.Method = MethodAPIKey
+		a.principals[sha256.Sum256([]byte(key))] = &principal
+	}
+	return a, nil
+}
+
+func (a *apiKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key, ok := bearerToken(r)
+	if !ok {
+		key = r.Header.Get(APIKeyHeader)
+	}
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+	p, ok := a.principals[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown api key", ErrInvalidCredentials)
+	}
+	return p, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpauth authenticates requests to the agent HTTP servers started by apps.Run.
+//
+// An Authenticator turns request credentials (an API key, a JWT or an HMAC signature)
+// into a Principal. Middleware enforces it per route and stores the principal in the
+// request context, where the apps read it to derive the ADK user ID, so sessions and
+// memory are isolated per caller.
+package httpauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrNoCredentials is returned by an Authenticator when the request carries no credentials it understands.
+	ErrNoCredentials = errors.New("httpauth: no credentials")
+	// ErrInvalidCredentials is returned when the credentials are present but cannot be verified.
+	ErrInvalidCredentials = errors.New("httpauth: invalid credentials")
+	// ErrForbidden is returned when the principal lacks a scope required by the route.
+	ErrForbidden = errors.New("httpauth: insufficient scope")
+)
+
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+	MethodHMAC   = "hmac"
+)
+
+// AdminScope grants access to the data of all callers, e.g. the debug routes of the
+// agent servers.
+const AdminScope = "admin"
+
+// Principal is the authenticated caller.
+type Principal struct {
+	// Subject identifies the caller and is used as the ADK user ID.
+	Subject string
+	Scopes  []string
+	// Method is the authentication method that produced the principal.
+	Method string
+	// Claims holds the verified token claims for JWT principals.
+	Claims map[string]any
+}
+
+// HasScopes reports whether the principal holds every scope in scopes.
+func (p *Principal) HasScopes(scopes ...string) bool {
+	for _, s := range scopes {
+		if !slices.Contains(p.Scopes, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// Authenticator verifies the credentials of a request.
+// It returns ErrNoCredentials when the request carries none of the kind it handles.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+type chain []Authenticator
+
+// Chain tries each authenticator in order and returns the first principal.
+// When none succeeds, the first error other than ErrNoCredentials is returned.
+func Chain(authenticators ...Authenticator) Authenticator {
+	return chain(authenticators)
+}
+
+func (c chain) Authenticate(r *http.Request) (*Principal, error) {
+	var firstErr error
+	for _, a := range c {
+		p, err := a.Authenticate(r)
+		if err == nil {
+			return p, nil
+		}
+		if firstErr == nil && !errors.Is(err, ErrNoCredentials) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return nil, ErrNoCredentials
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying p.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx by Middleware.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// UserID returns the ADK user ID of the authenticated caller, or fallback for
+// unauthenticated requests.
+func UserID(ctx context.Context, fallback string) string {
+	if p, ok := FromContext(ctx); ok && p.Subject != "" {
+		return p.Subject
+	}
+	return fallback
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpauth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	HMACKeyIDHeader     = "X-Veadk-Key-Id"
+	HMACTimestampHeader = "X-Veadk-Timestamp"
+	HMACSignatureHeader = "X-Veadk-Signature"
+
+	// DefaultHMACMaxSkew bounds how old or early a signed request may be.
+	DefaultHMACMaxSkew = 5 * time.Minute
+	// DefaultHMACMaxBodySize bounds the body read to verify a signed request.
+	DefaultHMACMaxBodySize = 32 << 20
+)
+
+// HMACKey is a shared secret identified by its key ID.
+type HMACKey struct {
+	Secret    []byte
+	Principal *Principal
+}
+
+type hmacAuthenticator struct {
+	keys        map[string]*HMACKey
+	maxSkew     time.Duration
+	maxBodySize int64
+	now         func() time.Time
+}
+
+// HMACOption configures an HMAC authenticator.
+type HMACOption func(a *hmacAuthenticator)
+
+// WithHMACMaxBodySize bounds the body of the signed requests, DefaultHMACMaxBodySize by default.
+// Larger requests are rejected with http.StatusRequestEntityTooLarge before they are verified.
+func WithHMACMaxBodySize(n int64) HMACOption {
+	return func(a *hmacAuthenticator) {
+		if n > 0 {
+			a.maxBodySize = n
+		}
+	}
+}
+
+// NewHMACAuthenticator verifies requests signed with SignRequest using the secrets of keys,
+// keyed by key ID. Requests whose timestamp is more than maxSkew away from now are rejected,
+// maxSkew <= 0 means DefaultHMACMaxSkew.
+func NewHMACAuthenticator(keys map[string]*HMACKey, maxSkew time.Duration, opts ...HMACOption) (Authenticator, error) {
+	if maxSkew <= 0 {
+		maxSkew = DefaultHMACMaxSkew
+	}
+	a := &hmacAuthenticator{
+		keys:        make(map[string]*HMACKey, len(keys)),
+		maxSkew:     maxSkew,
+		maxBodySize: DefaultHMACMaxBodySize,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	for id, k := range keys {
+		if id == "" || k == nil || len(k.Secret) == 0 || k.Principal == nil || k.Principal.Subject == "" {
+			return nil, fmt.Errorf("httpauth: hmac key %q needs a secret and a principal subject", id)
+		}
+		principal := *k.Principal
+		principal.Method = MethodHMAC
+		a.keys[id] = &HMACKey{Secret: k.Secret, Principal: &principal}
+	}
+	return a, nil
+}
+
+func (a *hmacAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	keyID := r.Header.Get(HMACKeyIDHeader)
+	signature := r.Header.Get(HMACSignatureHeader)
+	if keyID == "" && signature == "" {
+		return nil, ErrNoCredentials
+	}
+	key, ok := a.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown hmac key id %q", ErrInvalidCredentials, keyID)
+	}
+
+	timestamp := r.Header.Get(HMACTimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed %s", ErrInvalidCredentials, HMACTimestampHeader)
+	}
+	if skew := a.now().Sub(time.Unix(unix, 0)).Abs(); skew > a.maxSkew {
+		return nil, fmt.Errorf("%w: request timestamp is %s off", ErrInvalidCredentials, skew.Truncate(time.Second))
+	}
+
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed %s", ErrInvalidCredentials, HMACSignatureHeader)
+	}
+	// the body is read before the signature is verified, so its size is bounded
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = http.MaxBytesReader(nil, r.Body, a.maxBodySize)
+	}
+	want, err := requestSignature(r, timestamp, key.Secret)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(got, want) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidCredentials)
+	}
+	return key.Principal, nil
+}
+
+// SignRequest signs r for an HMAC authenticator holding secret under keyID.
+// The signature covers the method, the request URI, the timestamp and the body.
+func SignRequest(r *http.Request, keyID string, secret []byte) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := requestSignature(r, timestamp, secret)
+	if err != nil {
+		return err
+	}
+	r.Header.Set(HMACKeyIDHeader, keyID)
+	r.Header.Set(HMACTimestampHeader, timestamp)
+	r.Header.Set(HMACSignatureHeader, hex.EncodeToString(signature))
+	return nil
+}
+
+// requestSignature computes HMAC-SHA256 over "METHOD\nREQUEST-URI\nTIMESTAMP\nhex(sha256(body))",
+// leaving r.Body readable again.
+func requestSignature(r *http.Request, timestamp string, secret []byte) ([]byte, error) {
+	bodyHash := sha256.New()
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("httpauth: read request body: %w", err)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		bodyHash.Write(body)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	_, _ = fmt.Fprintf(mac, "%s\n%s\n%s\n%s", r.Method, r.URL.RequestURI(), timestamp, hex.EncodeToString(bodyHash.Sum(nil)))
+	return mac.Sum(nil), nil
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	a, err := NewAPIKeyAuthenticator(map[string]*Principal{
+		"key-alice": {Subject: "alice", Scopes: []string{"invoke"}},
+	})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/invoke", nil)
+	_, err = a.Authenticate(r)
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	r.Header.Set("Authorization", "Bearer key-alice")
+	p, err := a.Authenticate(r)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", p.Subject)
+	assert.Equal(t, MethodAPIKey, p.Method)
+
+	r = httptest.NewRequest(http.MethodPost, "/invoke", nil)
+	r.Header.Set(APIKeyHeader, "key-alice")
+	_, err = a.Authenticate(r)
+	assert.NoError(t, err)
+
+	r.Header.Set(APIKeyHeader, "key-mallory")
+	_, err = a.Authenticate(r)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = NewAPIKeyAuthenticator(map[string]*Principal{"key": {}})
+	assert.Error(t, err)
+}
+
+func TestHMACAuthenticator(t *testing.T) {
+	secret := []byte("s3cret")
+	a, err := NewHMACAuthenticator(map[string]*HMACKey{
+		"svc": {Secret: secret, Principal: &Principal{Subject: "billing-service"}},
+	}, 0)
+	require.NoError(t, err)
+
+	newRequest := func() *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/invoke?stream=1", strings.NewReader(`{"prompt":"hi"}`))
+	}
+
+	r := newRequest()
+	require.NoError(t, SignRequest(r, "svc", secret))
+	p, err := a.Authenticate(r)
+	require.NoError(t, err)
+	assert.Equal(t, "billing-service", p.Subject)
+	assert.Equal(t, MethodHMAC, p.Method)
+	body, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"prompt":"hi"}`, string(body), "the body stays readable after verification")
+
+	t.Run("tampered body", func(t *testing.T) {
+		r := newRequest()
+		require.NoError(t, SignRequest(r, "svc", secret))
+		r.Body = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"prompt":"bye"}`)).Body
+		_, err := a.Authenticate(r)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("stale timestamp", func(t *testing.T) {
+		r := newRequest()
+		require.NoError(t, SignRequest(r, "svc", secret))
+		a.(*hmacAuthenticator).now = func() time.Time { return time.Now().Add(10 * time.Minute) }
+		defer func() { a.(*hmacAuthenticator).now = time.Now }()
+		_, err := a.Authenticate(r)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		small, err := NewHMACAuthenticator(map[string]*HMACKey{
+			"svc": {Secret: secret, Principal: &Principal{Subject: "billing-service"}},
+		}, 0, WithHMACMaxBodySize(4))
+		require.NoError(t, err)
+		r := newRequest()
+		require.NoError(t, SignRequest(r, "svc", secret))
+		_, err = small.Authenticate(r)
+		var tooLarge *http.MaxBytesError
+		assert.ErrorAs(t, err, &tooLarge)
+
+		rec := httptest.NewRecorder()
+		r = newRequest()
+		require.NoError(t, SignRequest(r, "svc", secret))
+		Middleware(Config{Authenticator: small})(http.NotFoundHandler()).ServeHTTP(rec, r)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		r := newRequest()
+		require.NoError(t, SignRequest(r, "other", secret))
+		_, err := a.Authenticate(r)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+}
+
+// oidcProvider serves discovery and a JWKS holding an RSA and an EC key.
+type oidcProvider struct {
+	*httptest.Server
+	rsaKey      *rsa.PrivateKey
+	ecKey       *ecdsa.PrivateKey
+	jwksFetches atomic.Int32
+}
+
+func newOIDCProvider(t *testing.T) *oidcProvider {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p := &oidcProvider{rsaKey: rsaKey, ecKey: ecKey}
+
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": p.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		p.jwksFetches.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		}})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *oidcProvider) sign(t *testing.T, alg, kid string, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+
+	var signature []byte
+	var err error
+	switch alg {
+	case "RS256":
+		signature, err = rsa.SignPKCS1v15(rand.Reader, p.rsaKey, crypto.SHA256, digest[:])
+	case "ES256":
+		r, s, signErr := ecdsa.Sign(rand.Reader, p.ecKey, digest[:])
+		signature, err = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...), signErr
+	}
+	require.NoError(t, err)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// swapPayload returns signed with the claims of other.
+func swapPayload(signed, other string) string {
+	s, o := strings.Split(signed, "."), strings.Split(other, ".")
+	return s[0] + "." + o[1] + "." + s[2]
+}
+
+func bearerRequest(token string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/run_sse", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	provider := newOIDCProvider(t)
+	a, err := NewJWTAuthenticator(JWTConfig{Issuer: provider.URL, Audience: "veadk"})
+	require.NoError(t, err)
+
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"iss":   provider.URL,
+			"aud":   []string{"veadk", "other"},
+			"sub":   "alice",
+			"scope": "agents:invoke sessions:read",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+
+	for _, alg := range []string{"RS256", "ES256"} {
+		kid := map[string]string{"RS256": "rsa-1", "ES256": "ec-1"}[alg]
+		p, err := a.Authenticate(bearerRequest(provider.sign(t, alg, kid, claims(nil))))
+		require.NoError(t, err, alg)
+		assert.Equal(t, "alice", p.Subject)
+		assert.Equal(t, MethodJWT, p.Method)
+		assert.True(t, p.HasScopes("agents:invoke", "sessions:read"))
+	}
+	assert.Equal(t, int32(1), provider.jwksFetches.Load(), "keys are cached")
+
+	for name, token := range map[string]string{
+		"expired":        provider.sign(t, "RS256", "rsa-1", claims(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()})),
+		"wrong issuer":   provider.sign(t, "RS256", "rsa-1", claims(map[string]any{"iss": "https://evil.example.com"})),
+		"wrong audience": provider.sign(t, "RS256", "rsa-1", claims(map[string]any{"aud": "other"})),
+		"no subject":     provider.sign(t, "RS256", "rsa-1", claims(map[string]any{"sub": ""})),
+		"alg mismatch":   provider.sign(t, "ES256", "rsa-1", claims(nil)),
+		"tampered":       swapPayload(provider.sign(t, "RS256", "rsa-1", claims(nil)), provider.sign(t, "RS256", "rsa-1", claims(map[string]any{"sub": "bob"}))),
+	} {
+		_, err := a.Authenticate(bearerRequest(token))
+		assert.ErrorIs(t, err, ErrInvalidCredentials, name)
+	}
+
+	t.Run("unsigned token", func(t *testing.T) {
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"rsa-1"}`))
+		payload, _ := json.Marshal(claims(nil))
+		_, err := a.Authenticate(bearerRequest(header + "." + base64.RawURLEncoding.EncodeToString(payload) + "."))
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("unknown kid is refetched at most once in a while", func(t *testing.T) {
+		before := provider.jwksFetches.Load()
+		for range 3 {
+			_, err := a.Authenticate(bearerRequest(provider.sign(t, "RS256", "rotated", claims(nil))))
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		}
+		assert.LessOrEqual(t, provider.jwksFetches.Load()-before, int32(1))
+	})
+
+	t.Run("api keys are left to other authenticators", func(t *testing.T) {
+		_, err := a.Authenticate(bearerRequest("static-key"))
+		assert.ErrorIs(t, err, ErrNoCredentials)
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	apiKeys, err := NewAPIKeyAuthenticator(map[string]*Principal{
+		"key-alice": {Subject: "alice", Scopes: []string{"invoke"}},
+		"key-bob":   {Subject: "bob"},
+	})
+	require.NoError(t, err)
+	handler := Middleware(Config{
+		Authenticator: Chain(apiKeys),
+		Rules: []Rule{
+			{PathPrefix: "/public"},
+			{PathPrefix: "/docs", Public: true},
+			{PathPrefix: "/invoke", Methods: []string{http.MethodPost}, Scopes: []string{"invoke"}},
+		},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(UserID(r.Context(), "anonymous")))
+	}))
+
+	serve := func(method, path, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			r.Header.Set("Authorization", "Bearer "+key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "/docs/index.html", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "anonymous", rec.Body.String())
+
+	rec = serve(http.MethodGet, "/public", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "rules without Public still require authentication")
+	assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+
+	rec = serve(http.MethodPost, "/invoke", "key-alice")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "alice", rec.Body.String())
+
+	rec = serve(http.MethodPost, "/invoke", "key-bob")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(http.MethodGet, "/sessions", "key-bob")
+	assert.Equal(t, "bob", rec.Body.String())
+
+	rec = serve(http.MethodGet, "/sessions", "key-mallory")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.JSONEq(t, `{"error":"httpauth: invalid credentials"}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodOptions, "/invoke", "").Code)
+}
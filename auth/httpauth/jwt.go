@@ -0,0 +1,380 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultJWKSRefreshInterval is how long fetched signing keys are trusted before refetching.
+	DefaultJWKSRefreshInterval = time.Hour
+	// DefaultJWTLeeway tolerates clock drift when checking exp and nbf.
+	DefaultJWTLeeway = time.Minute
+
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+	// an unknown kid triggers a refetch at most this often, so forged kids cannot hammer the issuer
+	minJWKSRefetchInterval = 30 * time.Second
+)
+
+// JWTConfig configures validation of JWT bearer tokens signed by an OIDC provider.
+type JWTConfig struct {
+	// Issuer is matched against the iss claim. When JWKSURL is empty the signing keys are
+	// discovered from Issuer + "/.well-known/openid-configuration".
+	Issuer string
+	// Audience, when set, must be one of the aud claim values.
+	Audience string
+	JWKSURL  string
+	// SubjectClaim names the claim used as the principal subject, "sub" by default.
+	SubjectClaim string
+	// ScopeClaim names the claim holding the scopes, either a space separated string or a list.
+	// By default "scope" is read, falling back to "scp".
+	ScopeClaim      string
+	Leeway          time.Duration
+	RefreshInterval time.Duration
+	HTTPClient      *http.Client
+}
+
+type jwtAuthenticator struct {
+	config JWTConfig
+	now    func() time.Time
+
+	mu        sync.Mutex
+	jwksURL   string
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWTAuthenticator validates RS*, PS* and ES* signed bearer tokens against the
+// provider's JWKS.
+func NewJWTAuthenticator(config JWTConfig) (Authenticator, error) {
+	if config.Issuer == "" && config.JWKSURL == "" {
+		return nil, errors.New("httpauth: jwt needs an issuer or a jwks url")
+	}
+	if config.SubjectClaim == "" {
+		config.SubjectClaim = "sub"
+	}
+	if config.Leeway <= 0 {
+		config.Leeway = DefaultJWTLeeway
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = DefaultJWKSRefreshInterval
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &jwtAuthenticator{config: config, now: time.Now, jwksURL: config.JWKSURL}, nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func (a *jwtAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		// not a JWT, possibly a static API key handled by another authenticator
+		return nil, ErrNoCredentials
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed jwt header", ErrInvalidCredentials)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed jwt signature", ErrInvalidCredentials)
+	}
+	key, err := a.key(r.Context(), header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed jwt claims", ErrInvalidCredentials)
+	}
+	if err := a.validateClaims(claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	subject, _ := claims[a.config.SubjectClaim].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing %s claim", ErrInvalidCredentials, a.config.SubjectClaim)
+	}
+	return &Principal{Subject: subject, Scopes: a.scopes(claims), Method: MethodJWT, Claims: claims}, nil
+}
+
+func (a *jwtAuthenticator) validateClaims(claims map[string]any) error {
+	now := a.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("missing exp claim")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(a.config.Leeway)) {
+		return errors.New("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(a.config.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token not valid yet")
+	}
+	if a.config.Issuer != "" && claims["iss"] != a.config.Issuer {
+		return fmt.Errorf("unexpected issuer %v", claims["iss"])
+	}
+	if a.config.Audience != "" && !slices.Contains(stringList(claims["aud"]), a.config.Audience) {
+		return fmt.Errorf("token not issued for audience %q", a.config.Audience)
+	}
+	return nil
+}
+
+func (a *jwtAuthenticator) scopes(claims map[string]any) []string {
+	if a.config.ScopeClaim != "" {
+		return stringList(claims[a.config.ScopeClaim])
+	}
+	if scopes := stringList(claims["scope"]); len(scopes) > 0 {
+		return scopes
+	}
+	return stringList(claims["scp"])
+}
+
+// stringList reads a claim that is either a space separated string or a list of strings.
+func stringList(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// key returns the signing key with kid, refetching the JWKS when it is stale or the kid is unknown.
+func (a *jwtAuthenticator) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	key, ok := a.lookup(kid)
+	stale := now.Sub(a.fetchedAt) > a.config.RefreshInterval
+	if ok && !stale {
+		return key, nil
+	}
+	if !ok && !stale && now.Sub(a.fetchedAt) < minJWKSRefetchInterval {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidCredentials, kid)
+	}
+
+	keys, err := a.fetchKeys(ctx)
+	if err != nil {
+		if ok {
+			// keep serving with the cached keys while the provider is unreachable
+			return key, nil
+		}
+		return nil, err
+	}
+	a.keys, a.fetchedAt = keys, now
+	if key, ok = a.lookup(kid); !ok {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidCredentials, kid)
+	}
+	return key, nil
+}
+
+func (a *jwtAuthenticator) lookup(kid string) (crypto.PublicKey, bool) {
+	if key, ok := a.keys[kid]; ok {
+		return key, true
+	}
+	// tokens without kid are accepted when the provider publishes a single key
+	if kid == "" && len(a.keys) == 1 {
+		for _, key := range a.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (a *jwtAuthenticator) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	if a.jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := a.getJSON(ctx, strings.TrimSuffix(a.config.Issuer, "/")+oidcDiscoveryPath, &discovery); err != nil {
+			return nil, fmt.Errorf("httpauth: oidc discovery: %w", err)
+		}
+		if discovery.JWKSURI == "" {
+			return nil, errors.New("httpauth: oidc discovery returned no jwks_uri")
+		}
+		a.jwksURL = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := a.getJSON(ctx, a.jwksURL, &set); err != nil {
+		return nil, fmt.Errorf("httpauth: fetch jwks: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (a *jwtAuthenticator) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+var jwtHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512,
+	"PS256": crypto.SHA256, "PS384": crypto.SHA384, "PS512": crypto.SHA512,
+	"ES256": crypto.SHA256, "ES384": crypto.SHA384, "ES512": crypto.SHA512,
+}
+
+// verifySignature checks signature over signingInput. Symmetric and "none" algorithms are
+// rejected: the key is always a public key from the JWKS.
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	hash, ok := jwtHashes[alg]
+	if !ok {
+		return fmt.Errorf("unsupported jwt alg %q", alg)
+	}
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return rsa.VerifyPKCS1v15(key, hash, digest, signature)
+		case "PS":
+			return rsa.VerifyPSS(key, hash, digest, signature, nil)
+		}
+	case *ecdsa.PublicKey:
+		if alg[:2] != "ES" {
+			break
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("malformed ecdsa signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errors.New("ecdsa signature mismatch")
+		}
+		return nil
+	}
+	return fmt.Errorf("jwt alg %s does not match the signing key", alg)
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
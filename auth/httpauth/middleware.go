@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpauth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/volcengine/veadk-go/log"
+)
+
+// Rule sets the access policy of the routes under PathPrefix ending with PathSuffix.
+type Rule struct {
+	PathPrefix string
+	PathSuffix string
+	// Methods restricts the rule to these HTTP methods, all methods when empty.
+	Methods []string
+	// Public routes are served without authentication.
+	Public bool
+	// Scopes must all be held by the principal.
+	Scopes []string
+}
+
+func (r *Rule) matches(req *http.Request) bool {
+	return strings.HasPrefix(req.URL.Path, r.PathPrefix) && strings.HasSuffix(req.URL.Path, r.PathSuffix) &&
+		(len(r.Methods) == 0 || slices.Contains(r.Methods, req.Method))
+}
+
+// Config configures Middleware.
+type Config struct {
+	Authenticator Authenticator
+	// Rules are matched in order, the first match applies. Routes matching no rule
+	// require an authenticated principal without specific scopes.
+	Rules []Rule
+}
+
+// Middleware authenticates every request according to config and stores the principal in
+// the request context. CORS preflight requests are let through.
+func Middleware(config Config) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var rule *Rule
+			for i := range config.Rules {
+				if config.Rules[i].matches(r) {
+					rule = &config.Rules[i]
+					break
+				}
+			}
+			if rule != nil && rule.Public {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			p, err := config.Authenticator.Authenticate(r)
+			if err != nil {
+				log.Debugf("httpauth: rejected %s %s: %v", r.Method, r.URL.Path, err)
+				if tooLarge := new(http.MaxBytesError); errors.As(err, &tooLarge) {
+					writeError(w, http.StatusRequestEntityTooLarge, err)
+					return
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer realm="veadk"`)
+				writeError(w, http.StatusUnauthorized, err)
+				return
+			}
+			if rule != nil && !p.HasScopes(rule.Scopes...) {
+				log.Debugf("httpauth: %s lacks scopes %v for %s %s", p.Subject, rule.Scopes, r.Method, r.URL.Path)
+				writeError(w, http.StatusForbidden, ErrForbidden)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+		})
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	// verification details stay in the logs
+	msg := ErrInvalidCredentials.Error()
+	switch {
+	case errors.Is(err, ErrNoCredentials):
+		msg = ErrNoCredentials.Error()
+	case errors.Is(err, ErrForbidden):
+		msg = ErrForbidden.Error()
+	case code == http.StatusRequestEntityTooLarge:
+		msg = "httpauth: request body too large"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
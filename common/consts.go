@@ -61,7 +61,8 @@ const (
 
 // Logging env key
 const (
-	LOGGING_LEVEL = "LOGGING_LEVEL"
+	LOGGING_LEVEL  = "LOGGING_LEVEL"
+	LOGGING_FORMAT = "LOGGING_FORMAT"
 )
 
 const (
@@ -122,6 +123,14 @@ const (
 	DATABASE_MEM0_REGION   = "DATABASE_MEM0_REGION"
 )
 
+// Encryption at rest
+const (
+	// ENCRYPTION_KEY is the current key as "<id>:<base64 key>".
+	ENCRYPTION_KEY = "ENCRYPTION_KEY"
+	// ENCRYPTION_PREVIOUS_KEYS are comma separated "<id>:<base64 key>" pairs still used for decryption after a rotation.
+	ENCRYPTION_PREVIOUS_KEYS = "ENCRYPTION_PREVIOUS_KEYS"
+)
+
 // Prompt pilot
 const (
 	AGENTPILOT_API_URL      = "AGENTPILOT_API_URL"
@@ -168,3 +177,21 @@ const (
 	TOOL_WEB_SCRAPER_ENDPOINT = "TOOL_WEB_SCRAPER_ENDPOINT"
 	TOOL_WEB_SCRAPER_API_KEY  = "TOOL_WEB_SCRAPER_API_KEY"
 )
+
+// Cost
+const (
+	COST_PRICING                = "COST_PRICING"
+	COST_CURRENCY               = "COST_CURRENCY"
+	COST_BUDGET_USER_DAILY      = "COST_BUDGET_USER_DAILY"
+	COST_BUDGET_USER_MONTHLY    = "COST_BUDGET_USER_MONTHLY"
+	COST_BUDGET_APP_DAILY       = "COST_BUDGET_APP_DAILY"
+	COST_BUDGET_APP_MONTHLY     = "COST_BUDGET_APP_MONTHLY"
+	COST_BUDGET_DOWNGRADE_MODEL = "COST_BUDGET_DOWNGRADE_MODEL"
+)
+
+// Agent servers
+const (
+	// SERVER_PUBLIC_URL is the base URL the servers are reachable at behind an ingress,
+	// published in the A2A agent cards.
+	SERVER_PUBLIC_URL = "SERVER_PUBLIC_URL"
+)
@@ -41,14 +41,20 @@ const (
 
 // LOGGING
 const (
-	DEFAULT_LOGGING_LEVER = "info"
+	DEFAULT_LOGGING_LEVER  = "info"
+	DEFAULT_LOGGING_FORMAT = "json"
 )
 
+// Cost
+const DEFAULT_COST_CURRENCY = "CNY"
+
 const (
 	DEFAULT_LLMAGENT_NAME        = "veAgent"
 	DEFAULT_LOOPAGENT_NAME       = "veLoopAgent"
 	DEFAULT_PARALLELAGENT_NAME   = "veParallelAgent"
 	DEFAULT_SEQUENTIALAGENT_NAME = "veSequentialAgent"
+	DEFAULT_ROUTERAGENT_NAME     = "veRouterAgent"
+	DEFAULT_GRAPHAGENT_NAME      = "veGraphAgent"
 )
 
 const DEFAULT_REGION = "cn-beijing"
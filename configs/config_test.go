@@ -156,3 +156,215 @@ func TestObservabilityConfig_Priority(t *testing.T) {
 	}
 	assert.NotNil(t, config.OpenTelemetry.CozeLoop)
 }
+
+func TestObservabilityConfig_OTLPEnvMapping(t *testing.T) {
+	t.Setenv(EnvObservabilityOpenTelemetryOTLPEndpoint, "collector:4317")
+	t.Setenv(EnvObservabilityOpenTelemetryOTLPHeaders, "authorization=Bearer%20abc, x-tenant=t1,invalid")
+	t.Setenv(EnvObservabilityOpenTelemetryOTLPInsecure, "true")
+	t.Setenv(EnvObservabilityOpenTelemetryOTLPMetricsEndpoint, "http://collector:4318/v1/metrics")
+
+	config := &ObservabilityConfig{}
+	config.MapEnvToConfig()
+
+	otlp := config.OpenTelemetry.OTLP
+	assert.NotNil(t, otlp)
+	assert.Equal(t, "collector:4317", otlp.Endpoint)
+	assert.True(t, otlp.Insecure)
+	assert.Equal(t, map[string]string{"authorization": "Bearer abc", "x-tenant": "t1"}, otlp.Headers)
+	assert.Equal(t, "http://collector:4318/v1/metrics", otlp.MetricsEndpoint)
+	assert.NotNil(t, config.OpenTelemetry.EnableMetrics)
+	assert.True(t, *config.OpenTelemetry.EnableMetrics)
+
+	clone := config.Clone()
+	clone.OpenTelemetry.OTLP.Headers["x-tenant"] = "t2"
+	assert.Equal(t, "t1", otlp.Headers["x-tenant"])
+}
+
+func TestObservabilityConfig_OTLPYamlHeadersToEnv(t *testing.T) {
+	yamlData := `
+observability:
+  opentelemetry:
+    otlp:
+      endpoint: "collector:4317"
+      client_cert_path: "/etc/otel/client.pem"
+      headers:
+        x-tenant: "t1"
+`
+	var yamlConfig map[string]interface{}
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlData), &yamlConfig))
+
+	// register the variables set from yaml for cleanup
+	for _, key := range []string{
+		EnvObservabilityOpenTelemetryOTLPEndpoint,
+		EnvObservabilityOpenTelemetryOTLPClientCertPath,
+		EnvObservabilityOpenTelemetryOTLPHeaders + "_X-TENANT",
+	} {
+		t.Setenv(key, "")
+	}
+	setYamlToEnv(yamlConfig, "")
+
+	config := &ObservabilityConfig{}
+	config.MapEnvToConfig()
+
+	otlp := config.OpenTelemetry.OTLP
+	assert.Equal(t, "collector:4317", otlp.Endpoint)
+	assert.Equal(t, "/etc/otel/client.pem", otlp.ClientCertPath)
+	assert.Equal(t, map[string]string{"x-tenant": "t1"}, otlp.Headers)
+}
+
+func TestObservabilityConfig_PrometheusEnvMapping(t *testing.T) {
+	t.Setenv(EnvObservabilityOpenTelemetryPrometheusEnable, "true")
+	t.Setenv(EnvObservabilityOpenTelemetryPrometheusPath, "/prom")
+
+	config := &ObservabilityConfig{}
+	config.MapEnvToConfig()
+
+	assert.NotNil(t, config.OpenTelemetry.Prometheus)
+	assert.True(t, config.OpenTelemetry.Prometheus.Enable)
+	assert.Equal(t, "/prom", config.OpenTelemetry.Prometheus.Path)
+	assert.NotNil(t, config.OpenTelemetry.EnableMetrics)
+	assert.True(t, *config.OpenTelemetry.EnableMetrics)
+}
+
+func TestObservabilityConfig_PrometheusYamlBucketsToEnv(t *testing.T) {
+	yamlData := `
+observability:
+  opentelemetry:
+    prometheus:
+      enable: true
+      latency_buckets: [0.5, 1, 2.5]
+`
+	var yamlConfig map[string]interface{}
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlData), &yamlConfig))
+
+	// register the variables set from yaml for cleanup
+	for _, key := range []string{
+		EnvObservabilityOpenTelemetryPrometheusEnable,
+		EnvObservabilityOpenTelemetryPrometheusLatencyBuckets,
+	} {
+		t.Setenv(key, "")
+	}
+	setYamlToEnv(yamlConfig, "")
+	t.Setenv(EnvObservabilityOpenTelemetryPrometheusTTFTBuckets, "0.1, 0.2,x")
+
+	config := &ObservabilityConfig{}
+	config.MapEnvToConfig()
+
+	assert.Equal(t, []float64{0.5, 1, 2.5}, config.OpenTelemetry.Prometheus.LatencyBuckets)
+	assert.Equal(t, []float64{0.1, 0.2}, config.OpenTelemetry.Prometheus.TimeToFirstTokenBuckets)
+}
+
+func TestObservabilityConfig_TracingEnvMapping(t *testing.T) {
+	t.Setenv(EnvObservabilityOpenTelemetryTracingSamplingRatio, "0.25")
+	t.Setenv(EnvObservabilityOpenTelemetryTracingAlwaysSampleErrors, "true")
+	t.Setenv(EnvObservabilityOpenTelemetryTracingRedactionKeys, "password, api_key,")
+	t.Setenv(EnvObservabilityOpenTelemetryTracingMaxAttributeLength, "4096")
+
+	config := &ObservabilityConfig{}
+	config.MapEnvToConfig()
+
+	tracing := config.OpenTelemetry.Tracing
+	assert.NotNil(t, tracing)
+	assert.Equal(t, 0.25, *tracing.Sampling.Ratio)
+	assert.True(t, tracing.Sampling.AlwaysSampleErrors)
+	assert.Equal(t, []string{"password", "api_key"}, tracing.Redaction.Keys)
+	assert.Equal(t, 4096, tracing.MaxAttributeLength)
+
+	clone := config.Clone().OpenTelemetry.Tracing
+	clone.Redaction.Keys[0] = "changed"
+	assert.Equal(t, "password", tracing.Redaction.Keys[0])
+}
+
+func TestObservabilityConfig_TracingYamlToEnv(t *testing.T) {
+	yamlData := `
+observability:
+  opentelemetry:
+    tracing:
+      sampling:
+        ratio: 0.1
+        parent_based: false
+      redaction:
+        keys: ["password", "api_key"]
+        patterns: ["sk-[a-z]{2,}", "\\d{3,}"]
+        omit_inline_data: true
+      attribute_length_limits:
+        gen_ai.prompt: 1024
+`
+	var yamlConfig map[string]interface{}
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlData), &yamlConfig))
+
+	// register the variables set from yaml for cleanup
+	for _, key := range []string{
+		EnvObservabilityOpenTelemetryTracingSamplingRatio,
+		EnvObservabilityOpenTelemetryTracingParentBased,
+		EnvObservabilityOpenTelemetryTracingRedactionKeys,
+		EnvObservabilityOpenTelemetryTracingRedactionPatterns,
+		EnvObservabilityOpenTelemetryTracingOmitInlineData,
+		EnvObservabilityOpenTelemetryTracingAttributeLengthLimitsPrefix + "GEN_AI.PROMPT",
+	} {
+		t.Setenv(key, "")
+	}
+	setYamlToEnv(yamlConfig, "")
+
+	config := &ObservabilityConfig{}
+	config.MapEnvToConfig()
+
+	tracing := config.OpenTelemetry.Tracing
+	assert.Equal(t, 0.1, *tracing.Sampling.Ratio)
+	assert.False(t, *tracing.Sampling.ParentBased)
+	assert.Equal(t, []string{"password", "api_key"}, tracing.Redaction.Keys)
+	assert.Equal(t, []string{"sk-[a-z]{2,}", `\d{3,}`}, tracing.Redaction.Patterns)
+	assert.True(t, tracing.Redaction.OmitInlineData)
+	assert.Equal(t, map[string]int{"gen_ai.prompt": 1024}, tracing.AttributeLengthLimits)
+}
+
+func TestCostConfig_EnvMapping(t *testing.T) {
+	t.Setenv(common.COST_PRICING, `[{"model":"doubao-seed-1-6","input":0.8,"output":8,"cached_input":0.16}]`)
+	t.Setenv(common.COST_BUDGET_USER_DAILY, "1.5")
+	t.Setenv(common.COST_BUDGET_APP_MONTHLY, "300")
+	t.Setenv(common.COST_BUDGET_DOWNGRADE_MODEL, "doubao-seed-1-6-flash")
+
+	config := &CostConfig{}
+	config.MapEnvToConfig()
+
+	assert.Equal(t, []ModelPricing{{Model: "doubao-seed-1-6", Input: 0.8, Output: 8, CachedInput: 0.16}}, config.Pricing)
+	assert.Equal(t, common.DEFAULT_COST_CURRENCY, config.Currency)
+	assert.Equal(t, &BudgetConfig{UserDaily: 1.5, AppMonthly: 300, DowngradeModel: "doubao-seed-1-6-flash"}, config.Budget)
+}
+
+func TestCostConfig_YamlPricing(t *testing.T) {
+	yamlData := `
+cost:
+  pricing:
+    - model: doubao-seed-1-6
+      input: 0.8
+      output: 8
+`
+	var yamlConfig map[string]interface{}
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlData), &yamlConfig))
+	t.Setenv(common.COST_PRICING, "")
+	setYamlToEnv(yamlConfig, "")
+
+	config := &CostConfig{}
+	config.MapEnvToConfig()
+	assert.Equal(t, []ModelPricing{{Model: "doubao-seed-1-6", Input: 0.8, Output: 8}}, config.Pricing)
+}
+
+func TestObservabilityConfig_TraceViewerEnvMapping(t *testing.T) {
+	t.Setenv(EnvObservabilityOpenTelemetryTraceViewerEnable, "true")
+	t.Setenv(EnvObservabilityOpenTelemetryTraceViewerPath, "/traces")
+	t.Setenv(EnvObservabilityOpenTelemetryTraceViewerMaxTraces, "20")
+
+	config := &ObservabilityConfig{}
+	config.MapEnvToConfig()
+
+	viewer := config.OpenTelemetry.TraceViewer
+	assert.NotNil(t, viewer)
+	assert.True(t, viewer.Enable)
+	assert.Equal(t, "/traces", viewer.Path)
+	assert.Equal(t, 20, viewer.MaxTraces)
+
+	clone := config.Clone().OpenTelemetry.TraceViewer
+	clone.MaxTraces = 5
+	assert.Equal(t, 20, viewer.MaxTraces)
+}
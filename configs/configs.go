@@ -15,6 +15,7 @@
 package configs
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -38,6 +39,7 @@ type VeADKConfig struct {
 	Database       *DatabaseConfig      `yaml:"database"`
 	LOGGING        *Logging             `yaml:"LOGGING"`
 	Observability  *ObservabilityConfig `yaml:"observability"`
+	Cost           *CostConfig          `yaml:"cost"`
 }
 
 type EnvConfigMaptoStruct interface {
@@ -100,6 +102,7 @@ func SetupVeADKConfig() error {
 				// traces are enabled automatically when at least one trace exporter is configured
 			},
 		},
+		Cost: &CostConfig{},
 	}
 	globalConfig.Model.MapEnvToConfig()
 	globalConfig.Tool.MapEnvToConfig()
@@ -109,6 +112,7 @@ func SetupVeADKConfig() error {
 	globalConfig.Database.MapEnvToConfig()
 	globalConfig.Volcengine.MapEnvToConfig()
 	globalConfig.Observability.MapEnvToConfig()
+	globalConfig.Cost.MapEnvToConfig()
 	return nil
 }
 
@@ -175,6 +179,17 @@ func setYamlToEnv(data map[string]interface{}, prefix string) {
 			if os.Getenv(fullKey) == "" {
 				_ = os.Setenv(fullKey, strconv.FormatBool(v))
 			}
+		case float64:
+			if os.Getenv(fullKey) == "" {
+				_ = os.Setenv(fullKey, strconv.FormatFloat(v, 'f', -1, 64))
+			}
+		case []interface{}:
+			// lists are kept as JSON so that items may contain commas
+			if os.Getenv(fullKey) == "" {
+				if b, err := json.Marshal(v); err == nil {
+					_ = os.Setenv(fullKey, string(b))
+				}
+			}
 		}
 	}
 }
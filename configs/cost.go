@@ -0,0 +1,82 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configs
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/volcengine/veadk-go/common"
+	"github.com/volcengine/veadk-go/utils"
+)
+
+// CostConfig configures token pricing and usage budgets.
+type CostConfig struct {
+	// Pricing lists the token prices per model. In config.yaml it is a list under cost.pricing,
+	// as an env var COST_PRICING it is the same list encoded as JSON.
+	Pricing []ModelPricing `yaml:"pricing"`
+	// Currency of the prices, only used for reporting. Defaults to CNY.
+	Currency string        `yaml:"currency"`
+	Budget   *BudgetConfig `yaml:"budget"`
+}
+
+// ModelPricing is the price of one million tokens of a model. Model matches the model name
+// exactly or, failing that, as the longest prefix, e.g. "doubao-seed-1-6" for "doubao-seed-1-6-250615".
+type ModelPricing struct {
+	Model       string  `yaml:"model" json:"model"`
+	Input       float64 `yaml:"input" json:"input"`
+	Output      float64 `yaml:"output" json:"output"`
+	CachedInput float64 `yaml:"cached_input" json:"cached_input"`
+}
+
+// BudgetConfig limits the cost per user and per app within a day or a month. Zero means unlimited.
+type BudgetConfig struct {
+	UserDaily   float64 `yaml:"user_daily"`
+	UserMonthly float64 `yaml:"user_monthly"`
+	AppDaily    float64 `yaml:"app_daily"`
+	AppMonthly  float64 `yaml:"app_monthly"`
+	// DowngradeModel is called instead of rejecting the call once a budget is exceeded.
+	DowngradeModel string `yaml:"downgrade_model"`
+}
+
+func (c *CostConfig) MapEnvToConfig() {
+	if v := utils.GetEnvWithDefault(common.COST_PRICING); v != "" {
+		var pricing []ModelPricing
+		if err := json.Unmarshal([]byte(v), &pricing); err == nil {
+			c.Pricing = pricing
+		}
+	}
+	c.Currency = utils.GetEnvWithDefault(common.COST_CURRENCY, common.DEFAULT_COST_CURRENCY)
+
+	if c.Budget == nil {
+		c.Budget = &BudgetConfig{}
+	}
+	budgets := map[string]*float64{
+		common.COST_BUDGET_USER_DAILY:   &c.Budget.UserDaily,
+		common.COST_BUDGET_USER_MONTHLY: &c.Budget.UserMonthly,
+		common.COST_BUDGET_APP_DAILY:    &c.Budget.AppDaily,
+		common.COST_BUDGET_APP_MONTHLY:  &c.Budget.AppMonthly,
+	}
+	for env, field := range budgets {
+		if v := utils.GetEnvWithDefault(env); v != "" {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				*field = f
+			}
+		}
+	}
+	if v := utils.GetEnvWithDefault(common.COST_BUDGET_DOWNGRADE_MODEL); v != "" {
+		c.Budget.DowngradeModel = v
+	}
+}
@@ -21,8 +21,11 @@ import (
 
 type Logging struct {
 	Level string
+	// Format is json or text.
+	Format string
 }
 
 func (c *Logging) MapEnvToConfig() {
 	c.Level = utils.GetEnvWithDefault(common.LOGGING_LEVEL, common.DEFAULT_LOGGING_LEVER)
+	c.Format = utils.GetEnvWithDefault(common.LOGGING_FORMAT, common.DEFAULT_LOGGING_FORMAT)
 }
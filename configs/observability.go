@@ -15,7 +15,13 @@
 package configs
 
 import (
+	"encoding/json"
+	"maps"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/volcengine/veadk-go/utils"
 )
@@ -24,6 +30,7 @@ const (
 	// Global
 	EnvOtelServiceName            = "OTEL_SERVICE_NAME"
 	EnvObservabilityEnableMetrics = "OBSERVABILITY_OPENTELEMETRY_ENABLE_METRICS"
+	EnvObservabilityEnableLogs    = "OBSERVABILITY_OPENTELEMETRY_ENABLE_LOGS"
 
 	// APMPlus
 	EnvObservabilityOpenTelemetryApmPlusProtocol    = "OBSERVABILITY_OPENTELEMETRY_APMPLUS_PROTOCOL"
@@ -44,11 +51,50 @@ const (
 	EnvObservabilityOpenTelemetryTLSAccessKey   = "OBSERVABILITY_OPENTELEMETRY_TLS_ACCESS_KEY"
 	EnvObservabilityOpenTelemetryTLSSecretKey   = "OBSERVABILITY_OPENTELEMETRY_TLS_SECRET_KEY"
 
+	// OTLP
+	EnvObservabilityOpenTelemetryOTLPEndpoint = "OBSERVABILITY_OPENTELEMETRY_OTLP_ENDPOINT"
+	EnvObservabilityOpenTelemetryOTLPProtocol = "OBSERVABILITY_OPENTELEMETRY_OTLP_PROTOCOL"
+	// EnvObservabilityOpenTelemetryOTLPHeaders is "k1=v1,k2=v2". Single headers may also be set by suffixing the header name, as written by the yaml config.
+	EnvObservabilityOpenTelemetryOTLPHeaders         = "OBSERVABILITY_OPENTELEMETRY_OTLP_HEADERS"
+	EnvObservabilityOpenTelemetryOTLPInsecure        = "OBSERVABILITY_OPENTELEMETRY_OTLP_INSECURE"
+	EnvObservabilityOpenTelemetryOTLPCompression     = "OBSERVABILITY_OPENTELEMETRY_OTLP_COMPRESSION"
+	EnvObservabilityOpenTelemetryOTLPCACertPath      = "OBSERVABILITY_OPENTELEMETRY_OTLP_CA_CERT_PATH"
+	EnvObservabilityOpenTelemetryOTLPClientCertPath  = "OBSERVABILITY_OPENTELEMETRY_OTLP_CLIENT_CERT_PATH"
+	EnvObservabilityOpenTelemetryOTLPClientKeyPath   = "OBSERVABILITY_OPENTELEMETRY_OTLP_CLIENT_KEY_PATH"
+	EnvObservabilityOpenTelemetryOTLPTracesEndpoint  = "OBSERVABILITY_OPENTELEMETRY_OTLP_TRACES_ENDPOINT"
+	EnvObservabilityOpenTelemetryOTLPMetricsEndpoint = "OBSERVABILITY_OPENTELEMETRY_OTLP_METRICS_ENDPOINT"
+	EnvObservabilityOpenTelemetryOTLPLogsEndpoint    = "OBSERVABILITY_OPENTELEMETRY_OTLP_LOGS_ENDPOINT"
+
 	// File
 	EnvObservabilityOpenTelemetryFilePath = "OBSERVABILITY_OPENTELEMETRY_FILE_PATH"
 
 	// Stdout
 	EnvObservabilityOpenTelemetryStdoutEnable = "OBSERVABILITY_OPENTELEMETRY_STDOUT_ENABLE"
+
+	// Prometheus
+	EnvObservabilityOpenTelemetryPrometheusEnable = "OBSERVABILITY_OPENTELEMETRY_PROMETHEUS_ENABLE"
+	EnvObservabilityOpenTelemetryPrometheusPath   = "OBSERVABILITY_OPENTELEMETRY_PROMETHEUS_PATH"
+	// EnvObservabilityOpenTelemetryPrometheusLatencyBuckets and EnvObservabilityOpenTelemetryPrometheusTTFTBuckets are comma separated seconds.
+	EnvObservabilityOpenTelemetryPrometheusLatencyBuckets = "OBSERVABILITY_OPENTELEMETRY_PROMETHEUS_LATENCY_BUCKETS"
+	EnvObservabilityOpenTelemetryPrometheusTTFTBuckets    = "OBSERVABILITY_OPENTELEMETRY_PROMETHEUS_TIME_TO_FIRST_TOKEN_BUCKETS"
+
+	// Trace viewer
+	EnvObservabilityOpenTelemetryTraceViewerEnable    = "OBSERVABILITY_OPENTELEMETRY_TRACE_VIEWER_ENABLE"
+	EnvObservabilityOpenTelemetryTraceViewerPath      = "OBSERVABILITY_OPENTELEMETRY_TRACE_VIEWER_PATH"
+	EnvObservabilityOpenTelemetryTraceViewerMaxTraces = "OBSERVABILITY_OPENTELEMETRY_TRACE_VIEWER_MAX_TRACES"
+
+	// Tracing
+	EnvObservabilityOpenTelemetryTracingSamplingRatio      = "OBSERVABILITY_OPENTELEMETRY_TRACING_SAMPLING_RATIO"
+	EnvObservabilityOpenTelemetryTracingParentBased        = "OBSERVABILITY_OPENTELEMETRY_TRACING_SAMPLING_PARENT_BASED"
+	EnvObservabilityOpenTelemetryTracingAlwaysSampleErrors = "OBSERVABILITY_OPENTELEMETRY_TRACING_SAMPLING_ALWAYS_SAMPLE_ERRORS"
+	EnvObservabilityOpenTelemetryTracingRedactionKeys      = "OBSERVABILITY_OPENTELEMETRY_TRACING_REDACTION_KEYS"
+	// EnvObservabilityOpenTelemetryTracingRedactionPatterns is a JSON array, as regular expressions may contain commas.
+	EnvObservabilityOpenTelemetryTracingRedactionPatterns    = "OBSERVABILITY_OPENTELEMETRY_TRACING_REDACTION_PATTERNS"
+	EnvObservabilityOpenTelemetryTracingRedactionReplacement = "OBSERVABILITY_OPENTELEMETRY_TRACING_REDACTION_REPLACEMENT"
+	EnvObservabilityOpenTelemetryTracingOmitInlineData       = "OBSERVABILITY_OPENTELEMETRY_TRACING_REDACTION_OMIT_INLINE_DATA"
+	EnvObservabilityOpenTelemetryTracingMaxAttributeLength   = "OBSERVABILITY_OPENTELEMETRY_TRACING_MAX_ATTRIBUTE_LENGTH"
+	// EnvObservabilityOpenTelemetryTracingAttributeLengthLimitsPrefix is followed by the attribute key, as written by the yaml config.
+	EnvObservabilityOpenTelemetryTracingAttributeLengthLimitsPrefix = "OBSERVABILITY_OPENTELEMETRY_TRACING_ATTRIBUTE_LENGTH_LIMITS_"
 )
 
 // ObservabilityConfig groups specific configurations for different platforms.
@@ -58,12 +104,20 @@ type ObservabilityConfig struct {
 
 type OpenTelemetryConfig struct {
 	EnableMetrics *bool `yaml:"enable_metrics"`
+	// EnableLogs exports the records of the default slog logger through an OpenTelemetry LoggerProvider.
+	EnableLogs *bool `yaml:"enable_logs"`
 
 	File     *FileConfig             `yaml:"file"`
 	Stdout   *StdoutConfig           `yaml:"stdout"`
 	ApmPlus  *ApmPlusConfig          `yaml:"apmplus"`
 	CozeLoop *CozeLoopExporterConfig `yaml:"cozeloop"`
 	TLS      *TLSExporterConfig      `yaml:"tls"`
+	OTLP     *OTLPExporterConfig     `yaml:"otlp"`
+
+	Prometheus  *PrometheusConfig  `yaml:"prometheus"`
+	TraceViewer *TraceViewerConfig `yaml:"trace_viewer"`
+
+	Tracing *TracingConfig `yaml:"tracing"`
 }
 
 type ApmPlusConfig struct {
@@ -88,6 +142,86 @@ type TLSExporterConfig struct {
 	SecretKey   string `yaml:"secret_key"`
 }
 
+// OTLPExporterConfig is a vendor-neutral OTLP target, e.g. an OpenTelemetry Collector, Jaeger or Tempo.
+type OTLPExporterConfig struct {
+	// Endpoint is the base URL shared by all signals. Over HTTP the signal path, e.g. /v1/traces, is appended.
+	Endpoint string `yaml:"endpoint"`
+	// Protocol is grpc (default) or http/protobuf.
+	Protocol string            `yaml:"protocol"`
+	Headers  map[string]string `yaml:"headers"`
+	// Insecure disables TLS. Endpoints with an http:// scheme are always insecure.
+	Insecure bool `yaml:"insecure"`
+	// CACertPath, ClientCertPath and ClientKeyPath configure TLS, e.g. for mutual TLS with a collector.
+	CACertPath     string `yaml:"ca_cert_path"`
+	ClientCertPath string `yaml:"client_cert_path"`
+	ClientKeyPath  string `yaml:"client_key_path"`
+	// Compression is gzip or none (default).
+	Compression string `yaml:"compression"`
+
+	// TracesEndpoint, MetricsEndpoint and LogsEndpoint override Endpoint per signal and are used as is.
+	TracesEndpoint  string `yaml:"traces_endpoint"`
+	MetricsEndpoint string `yaml:"metrics_endpoint"`
+	LogsEndpoint    string `yaml:"logs_endpoint"`
+}
+
+// PrometheusConfig exposes metrics for scraping on the agent server instead of pushing them.
+type PrometheusConfig struct {
+	Enable bool `yaml:"enable"`
+	// Path is the scrape path, /metrics by default.
+	Path string `yaml:"path"`
+	// LatencyBuckets and TimeToFirstTokenBuckets override the default histogram buckets in seconds.
+	LatencyBuckets          []float64 `yaml:"latency_buckets"`
+	TimeToFirstTokenBuckets []float64 `yaml:"time_to_first_token_buckets"`
+}
+
+// TraceViewerConfig keeps the spans of the last invocations in memory and serves them as a
+// timeline on the agent server, for local debugging.
+type TraceViewerConfig struct {
+	Enable bool `yaml:"enable"`
+	// Path is the route of the viewer, /debug/traces by default.
+	Path string `yaml:"path"`
+	// MaxTraces is the number of invocations kept, 50 by default.
+	MaxTraces int `yaml:"max_traces"`
+}
+
+func (c *TraceViewerConfig) Clone() *TraceViewerConfig {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	return &clone
+}
+
+// TracingConfig controls which spans are exported and what their attributes may contain.
+type TracingConfig struct {
+	Sampling  *SamplingConfig  `yaml:"sampling"`
+	Redaction *RedactionConfig `yaml:"redaction"`
+	// MaxAttributeLength truncates longer string attributes, 0 disables truncation.
+	MaxAttributeLength int `yaml:"max_attribute_length"`
+	// AttributeLengthLimits overrides MaxAttributeLength per attribute key.
+	AttributeLengthLimits map[string]int `yaml:"attribute_length_limits"`
+}
+
+type SamplingConfig struct {
+	// Ratio of traces to sample, between 0 and 1. All traces are sampled when unset.
+	Ratio *float64 `yaml:"ratio"`
+	// ParentBased follows the sampling decision of the parent span, true when unset.
+	ParentBased *bool `yaml:"parent_based"`
+	// AlwaysSampleErrors exports spans that end with an error status even if their trace is not sampled.
+	AlwaysSampleErrors bool `yaml:"always_sample_errors"`
+}
+
+type RedactionConfig struct {
+	// Keys are attribute keys, or keys of JSON objects inside attribute values, whose values are replaced.
+	Keys []string `yaml:"keys"`
+	// Patterns are regular expressions whose matches in string attributes are replaced.
+	Patterns []string `yaml:"patterns"`
+	// Replacement is [REDACTED] when empty.
+	Replacement string `yaml:"replacement"`
+	// OmitInlineData replaces base64 data URLs of inline images, audio and files with their size.
+	OmitInlineData bool `yaml:"omit_inline_data"`
+}
+
 type FileConfig struct {
 	Path string `yaml:"path"`
 }
@@ -212,6 +346,46 @@ func (c *ObservabilityConfig) MapEnvToConfig() {
 		ot.TLS.SecretKey = v
 	}
 
+	// OTLP
+	otlpEnvs := map[string]func(*OTLPExporterConfig, string){
+		EnvObservabilityOpenTelemetryOTLPEndpoint:        func(c *OTLPExporterConfig, v string) { c.Endpoint = v },
+		EnvObservabilityOpenTelemetryOTLPProtocol:        func(c *OTLPExporterConfig, v string) { c.Protocol = v },
+		EnvObservabilityOpenTelemetryOTLPHeaders:         func(c *OTLPExporterConfig, v string) { c.Headers = parseHeaders(v) },
+		EnvObservabilityOpenTelemetryOTLPInsecure:        func(c *OTLPExporterConfig, v string) { c.Insecure = v == "true" },
+		EnvObservabilityOpenTelemetryOTLPCompression:     func(c *OTLPExporterConfig, v string) { c.Compression = v },
+		EnvObservabilityOpenTelemetryOTLPCACertPath:      func(c *OTLPExporterConfig, v string) { c.CACertPath = v },
+		EnvObservabilityOpenTelemetryOTLPClientCertPath:  func(c *OTLPExporterConfig, v string) { c.ClientCertPath = v },
+		EnvObservabilityOpenTelemetryOTLPClientKeyPath:   func(c *OTLPExporterConfig, v string) { c.ClientKeyPath = v },
+		EnvObservabilityOpenTelemetryOTLPTracesEndpoint:  func(c *OTLPExporterConfig, v string) { c.TracesEndpoint = v },
+		EnvObservabilityOpenTelemetryOTLPMetricsEndpoint: func(c *OTLPExporterConfig, v string) { c.MetricsEndpoint = v },
+		EnvObservabilityOpenTelemetryOTLPLogsEndpoint:    func(c *OTLPExporterConfig, v string) { c.LogsEndpoint = v },
+	}
+	for env, set := range otlpEnvs {
+		if v := utils.GetEnvWithDefault(env); v != "" {
+			if ot.OTLP == nil {
+				ot.OTLP = &OTLPExporterConfig{}
+			}
+			set(ot.OTLP, v)
+		}
+	}
+	if headers := envWithPrefix(EnvObservabilityOpenTelemetryOTLPHeaders + "_"); len(headers) > 0 {
+		if ot.OTLP == nil {
+			ot.OTLP = &OTLPExporterConfig{}
+		}
+		if ot.OTLP.Headers == nil {
+			ot.OTLP.Headers = make(map[string]string, len(headers))
+		}
+		maps.Copy(ot.OTLP.Headers, headers)
+	}
+	if ot.OTLP != nil && ot.OTLP.MetricsEndpoint != "" && ot.EnableMetrics == nil {
+		ot.EnableMetrics = new(bool)
+		*ot.EnableMetrics = true
+	}
+	if ot.OTLP != nil && ot.OTLP.LogsEndpoint != "" && ot.EnableLogs == nil {
+		ot.EnableLogs = new(bool)
+		*ot.EnableLogs = true
+	}
+
 	// File
 	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryFilePath); v != "" {
 		if ot.File == nil {
@@ -227,6 +401,88 @@ func (c *ObservabilityConfig) MapEnvToConfig() {
 		ot.Stdout.Enable = v == "true"
 	}
 
+	// Prometheus
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryPrometheusEnable); v != "" {
+		if ot.Prometheus == nil {
+			ot.Prometheus = &PrometheusConfig{}
+		}
+		ot.Prometheus.Enable = v == "true"
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryPrometheusPath); v != "" {
+		if ot.Prometheus == nil {
+			ot.Prometheus = &PrometheusConfig{}
+		}
+		ot.Prometheus.Path = v
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryPrometheusLatencyBuckets); v != "" && ot.Prometheus != nil {
+		ot.Prometheus.LatencyBuckets = parseFloats(v)
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryPrometheusTTFTBuckets); v != "" && ot.Prometheus != nil {
+		ot.Prometheus.TimeToFirstTokenBuckets = parseFloats(v)
+	}
+	if ot.Prometheus != nil && ot.Prometheus.Enable && ot.EnableMetrics == nil {
+		ot.EnableMetrics = new(bool)
+		*ot.EnableMetrics = true
+	}
+
+	// Trace viewer
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryTraceViewerEnable); v != "" {
+		ot.traceViewer().Enable = v == "true"
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryTraceViewerPath); v != "" {
+		ot.traceViewer().Path = v
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryTraceViewerMaxTraces); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			ot.traceViewer().MaxTraces = n
+		}
+	}
+
+	// Tracing
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryTracingSamplingRatio); v != "" {
+		if ratio, err := strconv.ParseFloat(v, 64); err == nil {
+			ot.tracingSampling().Ratio = &ratio
+		}
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryTracingParentBased); v != "" {
+		parentBased := v == "true"
+		ot.tracingSampling().ParentBased = &parentBased
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryTracingAlwaysSampleErrors); v != "" {
+		ot.tracingSampling().AlwaysSampleErrors = v == "true"
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryTracingRedactionKeys); v != "" {
+		ot.tracingRedaction().Keys = splitList(v)
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryTracingRedactionPatterns); v != "" {
+		ot.tracingRedaction().Patterns = splitList(v)
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryTracingRedactionReplacement); v != "" {
+		ot.tracingRedaction().Replacement = v
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryTracingOmitInlineData); v != "" {
+		ot.tracingRedaction().OmitInlineData = v == "true"
+	}
+	if v := utils.GetEnvWithDefault(EnvObservabilityOpenTelemetryTracingMaxAttributeLength); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			if ot.Tracing == nil {
+				ot.Tracing = &TracingConfig{}
+			}
+			ot.Tracing.MaxAttributeLength = n
+		}
+	}
+	for key, v := range envWithPrefix(EnvObservabilityOpenTelemetryTracingAttributeLengthLimitsPrefix) {
+		if n, err := strconv.Atoi(v); err == nil {
+			if ot.Tracing == nil {
+				ot.Tracing = &TracingConfig{}
+			}
+			if ot.Tracing.AttributeLengthLimits == nil {
+				ot.Tracing.AttributeLengthLimits = make(map[string]int)
+			}
+			ot.Tracing.AttributeLengthLimits[key] = n
+		}
+	}
+
 	// Meter Provider
 	if v := utils.GetEnvWithDefault(EnvObservabilityEnableMetrics); v != "" {
 		if ot.EnableMetrics == nil {
@@ -234,6 +490,14 @@ func (c *ObservabilityConfig) MapEnvToConfig() {
 		}
 		*ot.EnableMetrics = v == "true"
 	}
+
+	// Logger Provider
+	if v := utils.GetEnvWithDefault(EnvObservabilityEnableLogs); v != "" {
+		if ot.EnableLogs == nil {
+			ot.EnableLogs = new(bool)
+		}
+		*ot.EnableLogs = v == "true"
+	}
 }
 
 func (c *ObservabilityConfig) Clone() *ObservabilityConfig {
@@ -252,12 +516,44 @@ func (c *OpenTelemetryConfig) Clone() *OpenTelemetryConfig {
 
 	return &OpenTelemetryConfig{
 		EnableMetrics: c.EnableMetrics,
+		EnableLogs:    c.EnableLogs,
 		ApmPlus:       c.ApmPlus.Clone(),
 		CozeLoop:      c.CozeLoop.Clone(),
 		TLS:           c.TLS.Clone(),
+		OTLP:          c.OTLP.Clone(),
 		File:          c.File.Clone(),
 		Stdout:        c.Stdout.Clone(),
+		Prometheus:    c.Prometheus.Clone(),
+		TraceViewer:   c.TraceViewer.Clone(),
+		Tracing:       c.Tracing.Clone(),
+	}
+}
+
+func (c *OpenTelemetryConfig) traceViewer() *TraceViewerConfig {
+	if c.TraceViewer == nil {
+		c.TraceViewer = &TraceViewerConfig{}
+	}
+	return c.TraceViewer
+}
+
+func (c *OpenTelemetryConfig) tracingRedaction() *RedactionConfig {
+	if c.Tracing == nil {
+		c.Tracing = &TracingConfig{}
+	}
+	if c.Tracing.Redaction == nil {
+		c.Tracing.Redaction = &RedactionConfig{}
 	}
+	return c.Tracing.Redaction
+}
+
+func (c *OpenTelemetryConfig) tracingSampling() *SamplingConfig {
+	if c.Tracing == nil {
+		c.Tracing = &TracingConfig{}
+	}
+	if c.Tracing.Sampling == nil {
+		c.Tracing.Sampling = &SamplingConfig{}
+	}
+	return c.Tracing.Sampling
 }
 
 func (c *ApmPlusConfig) Clone() *ApmPlusConfig {
@@ -297,6 +593,72 @@ func (c *TLSExporterConfig) Clone() *TLSExporterConfig {
 	}
 }
 
+func (c *OTLPExporterConfig) Clone() *OTLPExporterConfig {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.Headers = maps.Clone(c.Headers)
+	return &clone
+}
+
+// splitList splits a comma separated list, dropping empty items. A JSON array, as written
+// for yaml lists, is decoded as is.
+func splitList(v string) []string {
+	var items []string
+	if strings.HasPrefix(strings.TrimSpace(v), "[") && json.Unmarshal([]byte(v), &items) == nil {
+		return items
+	}
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseFloats parses a comma separated list or JSON array of numbers, skipping invalid items.
+func parseFloats(v string) []float64 {
+	var floats []float64
+	if strings.HasPrefix(strings.TrimSpace(v), "[") && json.Unmarshal([]byte(v), &floats) == nil {
+		return floats
+	}
+	for _, item := range strings.Split(v, ",") {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(item), 64); err == nil {
+			floats = append(floats, f)
+		}
+	}
+	return floats
+}
+
+// envWithPrefix returns the environment variables starting with prefix, keyed by the lowercased rest of their name.
+func envWithPrefix(prefix string) map[string]string {
+	values := make(map[string]string)
+	for _, kv := range os.Environ() {
+		key, value, ok := strings.Cut(kv, "=")
+		if ok && value != "" && strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+			values[strings.ToLower(key[len(prefix):])] = value
+		}
+	}
+	return values
+}
+
+// parseHeaders parses "key1=value1,key2=value2", the format of OTEL_EXPORTER_OTLP_HEADERS.
+func parseHeaders(v string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		if unescaped, err := url.PathUnescape(strings.TrimSpace(value)); err == nil {
+			value = unescaped
+		}
+		headers[strings.TrimSpace(key)] = value
+	}
+	return headers
+}
+
 func (c *FileConfig) Clone() *FileConfig {
 	if c == nil {
 		return nil
@@ -306,6 +668,39 @@ func (c *FileConfig) Clone() *FileConfig {
 	}
 }
 
+func (c *PrometheusConfig) Clone() *PrometheusConfig {
+	if c == nil {
+		return nil
+	}
+	return &PrometheusConfig{
+		Enable:                  c.Enable,
+		Path:                    c.Path,
+		LatencyBuckets:          slices.Clone(c.LatencyBuckets),
+		TimeToFirstTokenBuckets: slices.Clone(c.TimeToFirstTokenBuckets),
+	}
+}
+
+func (c *TracingConfig) Clone() *TracingConfig {
+	if c == nil {
+		return nil
+	}
+	clone := &TracingConfig{
+		MaxAttributeLength:    c.MaxAttributeLength,
+		AttributeLengthLimits: maps.Clone(c.AttributeLengthLimits),
+	}
+	if c.Sampling != nil {
+		sampling := *c.Sampling
+		clone.Sampling = &sampling
+	}
+	if c.Redaction != nil {
+		redaction := *c.Redaction
+		redaction.Keys = slices.Clone(c.Redaction.Keys)
+		redaction.Patterns = slices.Clone(c.Redaction.Patterns)
+		clone.Redaction = &redaction
+	}
+	return clone
+}
+
 func (c *StdoutConfig) Clone() *StdoutConfig {
 	if c == nil {
 		return nil
@@ -0,0 +1,132 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Record is the usage and cost of one model call.
+type Record struct {
+	Time      time.Time `json:"time"`
+	UserID    string    `json:"user_id"`
+	AppName   string    `json:"app_name"`
+	SessionID string    `json:"session_id"`
+	Model     string    `json:"model"`
+	Usage
+	Cost float64 `json:"cost"`
+}
+
+// Query selects records. Empty fields match all records, Until is exclusive.
+type Query struct {
+	UserID    string
+	AppName   string
+	SessionID string
+	Since     time.Time
+	Until     time.Time
+}
+
+func (q *Query) matches(r *Record) bool {
+	return (q.UserID == "" || q.UserID == r.UserID) &&
+		(q.AppName == "" || q.AppName == r.AppName) &&
+		(q.SessionID == "" || q.SessionID == r.SessionID) &&
+		(q.Since.IsZero() || !r.Time.Before(q.Since)) &&
+		(q.Until.IsZero() || r.Time.Before(q.Until))
+}
+
+// Total sums the records matched by a query.
+type Total struct {
+	Usage
+	Cost  float64 `json:"cost"`
+	Calls int64   `json:"calls"`
+}
+
+// Store persists the usage ledger.
+type Store interface {
+	Add(ctx context.Context, record *Record) error
+	Sum(ctx context.Context, query *Query) (*Total, error)
+}
+
+// DefaultInMemoryRetention covers the longest budget window, the current month.
+const DefaultInMemoryRetention = 32 * 24 * time.Hour
+
+// InMemoryStore keeps the ledger in process memory. It suits tests and single instance
+// deployments, use SQLStore to share budgets across instances and restarts.
+// Records older than the retention are dropped, so that the ledger does not grow forever.
+type InMemoryStore struct {
+	retention time.Duration
+	now       func() time.Time
+
+	mu        sync.RWMutex
+	records   []Record
+	lastPrune time.Time
+}
+
+type InMemoryStoreOption func(*InMemoryStore)
+
+// WithRetention keeps records for d, DefaultInMemoryRetention by default. Sums over
+// windows reaching further back only include the retained records.
+func WithRetention(d time.Duration) InMemoryStoreOption {
+	return func(s *InMemoryStore) {
+		s.retention = d
+	}
+}
+
+func NewInMemoryStore(opts ...InMemoryStoreOption) *InMemoryStore {
+	s := &InMemoryStore{retention: DefaultInMemoryRetention, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *InMemoryStore) Add(_ context.Context, record *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(s.now())
+	s.records = append(s.records, *record)
+	return nil
+}
+
+// prune drops the records older than the retention, at most once per hour.
+func (s *InMemoryStore) prune(now time.Time) {
+	if s.retention <= 0 || now.Sub(s.lastPrune) < time.Hour {
+		return
+	}
+	s.lastPrune = now
+	cutoff := now.Add(-s.retention)
+	s.records = slices.DeleteFunc(s.records, func(r Record) bool {
+		return r.Time.Before(cutoff)
+	})
+}
+
+func (s *InMemoryStore) Sum(_ context.Context, query *Query) (*Total, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	total := &Total{}
+	for i := range s.records {
+		r := &s.records[i]
+		if !query.matches(r) {
+			continue
+		}
+		total.Usage = total.Usage.Add(r.Usage)
+		total.Cost += r.Cost
+		total.Calls++
+	}
+	return total, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	records := []*Record{
+		{Time: day.Add(time.Hour), UserID: "u1", AppName: "app", SessionID: "s1", Model: "m", Usage: Usage{InputTokens: 10, OutputTokens: 5}, Cost: 1},
+		{Time: day.Add(2 * time.Hour), UserID: "u1", AppName: "app", SessionID: "s2", Model: "m", Usage: Usage{InputTokens: 20, CachedTokens: 10, OutputTokens: 5}, Cost: 2},
+		{Time: day.Add(3 * time.Hour), UserID: "u2", AppName: "app", SessionID: "s3", Model: "m", Usage: Usage{InputTokens: 30}, Cost: 4},
+		{Time: day.Add(-time.Hour), UserID: "u1", AppName: "other", SessionID: "s4", Model: "m", Usage: Usage{InputTokens: 40}, Cost: 8},
+	}
+	for _, r := range records {
+		require.NoError(t, store.Add(ctx, r))
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  Total
+	}{
+		{"all", Query{}, Total{Usage: Usage{InputTokens: 100, CachedTokens: 10, OutputTokens: 10}, Cost: 15, Calls: 4}},
+		{"user", Query{UserID: "u1"}, Total{Usage: Usage{InputTokens: 70, CachedTokens: 10, OutputTokens: 10}, Cost: 11, Calls: 3}},
+		{"user of app", Query{AppName: "app", UserID: "u1"}, Total{Usage: Usage{InputTokens: 30, CachedTokens: 10, OutputTokens: 10}, Cost: 3, Calls: 2}},
+		{"session", Query{SessionID: "s3"}, Total{Usage: Usage{InputTokens: 30}, Cost: 4, Calls: 1}},
+		{"window", Query{Since: day, Until: day.Add(3 * time.Hour)}, Total{Usage: Usage{InputTokens: 30, CachedTokens: 10, OutputTokens: 10}, Cost: 3, Calls: 2}},
+		{"none", Query{UserID: "u3"}, Total{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, err := store.Sum(ctx, &tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Usage, total.Usage)
+			assert.InDelta(t, tt.want.Cost, total.Cost, 1e-9)
+			assert.Equal(t, tt.want.Calls, total.Calls)
+		})
+	}
+}
+
+func TestInMemoryStore(t *testing.T) {
+	store := NewInMemoryStore()
+	store.now = func() time.Time { return time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC) }
+	testStore(t, store)
+}
+
+func TestInMemoryStore_Retention(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	store := NewInMemoryStore(WithRetention(24 * time.Hour))
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Add(ctx, &Record{Time: now, Cost: 1}))
+	now = now.Add(12 * time.Hour)
+	require.NoError(t, store.Add(ctx, &Record{Time: now, Cost: 2}))
+	now = now.Add(18 * time.Hour)
+	require.NoError(t, store.Add(ctx, &Record{Time: now, Cost: 4}))
+
+	total, err := store.Sum(ctx, &Query{})
+	require.NoError(t, err)
+	assert.InDelta(t, 6, total.Cost, 1e-9, "the first record is past the retention")
+	assert.Len(t, store.records, 2)
+}
+
+func TestSQLStore(t *testing.T) {
+	_, err := NewSQLStore(nil)
+	assert.ErrorIs(t, err, ErrDBNotSet)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Skipf("sqlite is not available: %v", err)
+	}
+	store, err := NewSQLStore(db)
+	require.NoError(t, err)
+	testStore(t, store)
+}
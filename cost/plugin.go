@@ -0,0 +1,226 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volcengine/veadk-go/common"
+	"github.com/volcengine/veadk-go/configs"
+	"github.com/volcengine/veadk-go/log"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+)
+
+const PluginName = "veadk-cost"
+
+var ErrBudgetExceeded = errors.New("usage budget exceeded")
+
+// PluginConfig configures the cost plugin. Zero fields fall back to the global config.
+type PluginConfig struct {
+	// Pricing prices the recorded usage. Defaults to DefaultPricingTable.
+	Pricing *PricingTable
+	// Store persists the usage ledger. Defaults to an InMemoryStore.
+	Store Store
+	// Budget limits the cost per user and app. Defaults to cost.budget of the global config.
+	Budget *configs.BudgetConfig
+	// Currency is used in budget errors and logs. Defaults to cost.currency of the global config.
+	Currency string
+	// Location sets the day and month boundaries of the budget windows. Defaults to time.Local.
+	Location *time.Location
+}
+
+// NewPlugin creates a plugin that records the usage and cost of every model call in the ledger.
+// Once the cost of the current day or month reaches a budget of the user or the app, further model
+// calls are switched to the downgrade model if one is configured, or rejected with ErrBudgetExceeded.
+// User budgets apply per user of an app, as ADK user ids are scoped to the app.
+func NewPlugin(cfg *PluginConfig) (*plugin.Plugin, error) {
+	p := newCostPlugin(cfg)
+	return plugin.New(plugin.Config{
+		Name:                PluginName,
+		BeforeModelCallback: p.BeforeModel,
+		AfterModelCallback:  p.AfterModel,
+		AfterRunCallback:    p.AfterRun,
+	})
+}
+
+func newCostPlugin(cfg *PluginConfig) *costPlugin {
+	if cfg == nil {
+		cfg = &PluginConfig{}
+	}
+	if cfg.Pricing == nil {
+		cfg.Pricing = DefaultPricingTable()
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewInMemoryStore()
+	}
+	if cfg.Budget == nil || cfg.Currency == "" {
+		global := configs.GetGlobalConfig().Cost
+		if cfg.Budget == nil && global != nil && global.Budget != nil {
+			budget := *global.Budget
+			cfg.Budget = &budget
+		}
+		if cfg.Currency == "" && global != nil {
+			cfg.Currency = global.Currency
+		}
+	}
+	if cfg.Budget == nil {
+		cfg.Budget = &configs.BudgetConfig{}
+	}
+	if cfg.Currency == "" {
+		cfg.Currency = common.DEFAULT_COST_CURRENCY
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if cfg.Pricing.Len() == 0 {
+		log.Warn("No model pricing configured, usage is recorded at zero cost")
+	}
+	return &costPlugin{config: cfg, now: time.Now}
+}
+
+type costPlugin struct {
+	config *PluginConfig
+	now    func() time.Time
+
+	// models remembers the requested model per invocation and agent, for responses not naming their model.
+	models sync.Map
+}
+
+func modelKey(invocationID, agentName string) string {
+	return invocationID + "/" + agentName
+}
+
+// BeforeModel enforces the budgets before the model is called.
+func (p *costPlugin) BeforeModel(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	exceeded, err := p.exceededBudget(ctx)
+	if err != nil {
+		// budgets are best effort, a failing ledger must not take the agent down
+		log.WarnContext(ctx, "Failed to check usage budget", "UserID", ctx.UserID(), "AppName", ctx.AppName(), "error", err)
+	}
+	if exceeded != "" {
+		downgrade := p.config.Budget.DowngradeModel
+		if downgrade == "" {
+			return nil, fmt.Errorf("%w: %s", ErrBudgetExceeded, exceeded)
+		}
+		if req.Model != downgrade {
+			log.InfoContext(ctx, "Downgrading model call", "reason", exceeded, "from", req.Model, "to", downgrade,
+				"UserID", ctx.UserID(), "AppName", ctx.AppName())
+			req.Model = downgrade
+		}
+	}
+
+	p.models.Store(modelKey(ctx.InvocationID(), ctx.AgentName()), req.Model)
+	return nil, nil
+}
+
+// exceededBudget describes the first budget reached by the user or app of ctx, or returns "" if none is.
+func (p *costPlugin) exceededBudget(ctx agent.CallbackContext) (string, error) {
+	budget := p.config.Budget
+	now := p.now().In(p.config.Location)
+	year, month, day := now.Date()
+	dayStart := time.Date(year, month, day, 0, 0, 0, 0, p.config.Location)
+	monthStart := time.Date(year, month, 1, 0, 0, 0, 0, p.config.Location)
+
+	checks := []struct {
+		name  string
+		limit float64
+		query Query
+	}{
+		{"daily user", budget.UserDaily, Query{AppName: ctx.AppName(), UserID: ctx.UserID(), Since: dayStart}},
+		{"monthly user", budget.UserMonthly, Query{AppName: ctx.AppName(), UserID: ctx.UserID(), Since: monthStart}},
+		{"daily app", budget.AppDaily, Query{AppName: ctx.AppName(), Since: dayStart}},
+		{"monthly app", budget.AppMonthly, Query{AppName: ctx.AppName(), Since: monthStart}},
+	}
+	for _, check := range checks {
+		if check.limit <= 0 {
+			continue
+		}
+		total, err := p.config.Store.Sum(ctx, &check.query)
+		if err != nil {
+			return "", err
+		}
+		if total.Cost >= check.limit {
+			return fmt.Sprintf("%s budget of %g %s reached (spent %.4f)", check.name, check.limit, p.config.Currency, total.Cost), nil
+		}
+	}
+	return "", nil
+}
+
+// AfterModel records the usage of a final model response in the ledger.
+func (p *costPlugin) AfterModel(ctx agent.CallbackContext, resp *model.LLMResponse, err error) (*model.LLMResponse, error) {
+	if err != nil || resp == nil || resp.Partial || resp.UsageMetadata == nil {
+		return nil, nil
+	}
+
+	modelName := ResponseModel(resp)
+	if modelName == "" {
+		if requested, ok := p.models.Load(modelKey(ctx.InvocationID(), ctx.AgentName())); ok {
+			modelName = requested.(string)
+		}
+	}
+
+	usage := UsageFromMetadata(resp.UsageMetadata)
+	cost, priced := p.config.Pricing.Cost(modelName, usage)
+	if !priced {
+		log.DebugContext(ctx, "No pricing for model", "model", modelName)
+	}
+
+	record := &Record{
+		Time:      p.now(),
+		UserID:    ctx.UserID(),
+		AppName:   ctx.AppName(),
+		SessionID: ctx.SessionID(),
+		Model:     modelName,
+		Usage:     usage,
+		Cost:      cost,
+	}
+	if err := p.config.Store.Add(context.WithoutCancel(ctx), record); err != nil {
+		log.WarnContext(ctx, "Failed to record usage", "model", modelName, "error", err)
+	}
+	return nil, nil
+}
+
+// AfterRun forgets the models requested during the run.
+func (p *costPlugin) AfterRun(ctx agent.InvocationContext) {
+	prefix := modelKey(ctx.InvocationID(), "")
+	p.models.Range(func(key, _ any) bool {
+		if strings.HasPrefix(key.(string), prefix) {
+			p.models.Delete(key)
+		}
+		return true
+	})
+}
+
+// ResponseModel returns the model named by a response, as reported by the veadk models.
+func ResponseModel(resp *model.LLMResponse) string {
+	if resp == nil {
+		return ""
+	}
+	if m, ok := resp.CustomMetadata["response_model"].(string); ok && m != "" {
+		return m
+	}
+	return resp.ModelVersion
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/configs"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+type fakeCallbackContext struct {
+	agent.CallbackContext
+	userID string
+}
+
+func (f *fakeCallbackContext) InvocationID() string { return "inv-1" }
+func (f *fakeCallbackContext) AgentName() string    { return "assistant" }
+func (f *fakeCallbackContext) UserID() string       { return f.userID }
+func (f *fakeCallbackContext) AppName() string      { return "app1" }
+func (f *fakeCallbackContext) SessionID() string    { return "session1" }
+func (f *fakeCallbackContext) Value(any) any        { return nil }
+
+func newTestPlugin(budget *configs.BudgetConfig, store Store) *costPlugin {
+	p := newCostPlugin(&PluginConfig{
+		Pricing:  NewPricingTable([]configs.ModelPricing{{Model: "pro", Input: 10, Output: 100}, {Model: "lite", Input: 1, Output: 10}}),
+		Store:    store,
+		Budget:   budget,
+		Currency: "CNY",
+		Location: time.UTC,
+	})
+	p.now = func() time.Time { return time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC) }
+	return p
+}
+
+func finalResponse(modelName string, input, output int32) *model.LLMResponse {
+	resp := &model.LLMResponse{
+		Content:       genai.NewContentFromText("ok", genai.RoleModel),
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: input, CandidatesTokenCount: output},
+	}
+	if modelName != "" {
+		resp.CustomMetadata = map[string]any{"response_model": modelName}
+	}
+	return resp
+}
+
+func TestNewPlugin(t *testing.T) {
+	p, err := NewPlugin(&PluginConfig{Pricing: NewPricingTable(nil), Budget: &configs.BudgetConfig{}, Currency: "USD"})
+	require.NoError(t, err)
+	assert.Equal(t, PluginName, p.Name())
+	assert.NotNil(t, p.BeforeModelCallback())
+	assert.NotNil(t, p.AfterModelCallback())
+}
+
+func TestCostPlugin_AfterModel(t *testing.T) {
+	store := NewInMemoryStore()
+	p := newTestPlugin(&configs.BudgetConfig{}, store)
+	cctx := &fakeCallbackContext{userID: "user1"}
+
+	_, err := p.BeforeModel(cctx, &model.LLMRequest{Model: "pro-250101"})
+	require.NoError(t, err)
+
+	// partial and failed responses are not recorded
+	partial := finalResponse("", 1000, 1000)
+	partial.Partial = true
+	_, _ = p.AfterModel(cctx, partial, nil)
+	_, _ = p.AfterModel(cctx, nil, assert.AnError)
+
+	// the model falls back to the requested one
+	_, err = p.AfterModel(cctx, finalResponse("", 100_000, 10_000), nil)
+	require.NoError(t, err)
+	_, err = p.AfterModel(cctx, finalResponse("lite", 100_000, 10_000), nil)
+	require.NoError(t, err)
+
+	require.Len(t, store.records, 2)
+	assert.Equal(t, "pro-250101", store.records[0].Model)
+	assert.InDelta(t, 2, store.records[0].Cost, 1e-9)
+	assert.Equal(t, "lite", store.records[1].Model)
+	assert.InDelta(t, 0.2, store.records[1].Cost, 1e-9)
+	assert.Equal(t, Record{
+		Time: p.now(), UserID: "user1", AppName: "app1", SessionID: "session1", Model: "lite",
+		Usage: Usage{InputTokens: 100_000, OutputTokens: 10_000}, Cost: store.records[1].Cost,
+	}, store.records[1])
+}
+
+func TestCostPlugin_Budget(t *testing.T) {
+	june := time.Date(2025, 6, 15, 8, 0, 0, 0, time.UTC)
+	seed := func(records ...Record) *InMemoryStore {
+		store := NewInMemoryStore()
+		for _, r := range records {
+			_ = store.Add(context.Background(), &r)
+		}
+		return store
+	}
+
+	tests := []struct {
+		name      string
+		budget    configs.BudgetConfig
+		store     *InMemoryStore
+		wantErr   bool
+		wantModel string
+	}{
+		{
+			name:      "within budget",
+			budget:    configs.BudgetConfig{UserDaily: 5, AppMonthly: 100},
+			store:     seed(Record{Time: june, UserID: "user1", AppName: "app1", Cost: 4}),
+			wantModel: "pro",
+		},
+		{
+			name:    "user daily budget reached",
+			budget:  configs.BudgetConfig{UserDaily: 5},
+			store:   seed(Record{Time: june, UserID: "user1", AppName: "app1", Cost: 5}),
+			wantErr: true,
+		},
+		{
+			name:      "spend of yesterday does not count",
+			budget:    configs.BudgetConfig{UserDaily: 5},
+			store:     seed(Record{Time: june.AddDate(0, 0, -1), UserID: "user1", AppName: "app1", Cost: 50}),
+			wantModel: "pro",
+		},
+		{
+			name:      "other users do not count",
+			budget:    configs.BudgetConfig{UserMonthly: 5},
+			store:     seed(Record{Time: june, UserID: "user2", AppName: "app1", Cost: 50}),
+			wantModel: "pro",
+		},
+		{
+			name:    "app monthly budget reached",
+			budget:  configs.BudgetConfig{AppMonthly: 10},
+			store:   seed(Record{Time: june.AddDate(0, 0, -10), UserID: "user2", AppName: "app1", Cost: 10}),
+			wantErr: true,
+		},
+		{
+			name:      "downgrade instead of reject",
+			budget:    configs.BudgetConfig{AppDaily: 1, DowngradeModel: "lite"},
+			store:     seed(Record{Time: june, UserID: "user2", AppName: "app1", Cost: 1}),
+			wantModel: "lite",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPlugin(&tt.budget, tt.store)
+			req := &model.LLMRequest{Model: "pro"}
+			resp, err := p.BeforeModel(&fakeCallbackContext{userID: "user1"}, req)
+			assert.Nil(t, resp)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrBudgetExceeded)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantModel, req.Model)
+		})
+	}
+}
+
+func TestResponseModel(t *testing.T) {
+	assert.Equal(t, "", ResponseModel(nil))
+	assert.Equal(t, "v1", ResponseModel(&model.LLMResponse{ModelVersion: "v1"}))
+	assert.Equal(t, "m1", ResponseModel(&model.LLMResponse{ModelVersion: "v1", CustomMetadata: map[string]any{"response_model": "m1"}}))
+}
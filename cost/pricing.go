@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cost prices token usage and enforces usage budgets.
+//
+// Prices are configured per one million tokens under cost in config.yaml:
+//
+//	cost:
+//	  currency: CNY
+//	  pricing:
+//	    - model: doubao-seed-1-6
+//	      input: 0.8
+//	      output: 8
+//	      cached_input: 0.16
+//	  budget:
+//	    user_daily: 5
+//	    app_monthly: 3000
+//	    downgrade_model: doubao-seed-1-6-flash-250715
+//
+// The plugin created by NewPlugin records every model call in a Store and applies the budgets.
+package cost
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/volcengine/veadk-go/configs"
+	"google.golang.org/genai"
+)
+
+const tokensPerPriceUnit = 1_000_000
+
+// Usage counts the tokens of one or more model calls.
+type Usage struct {
+	InputTokens int64 `json:"input_tokens"`
+	// CachedTokens is the part of InputTokens served from the context cache.
+	CachedTokens int64 `json:"cached_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+}
+
+// UsageFromMetadata converts the usage reported in a model response.
+func UsageFromMetadata(m *genai.GenerateContentResponseUsageMetadata) Usage {
+	if m == nil {
+		return Usage{}
+	}
+	return Usage{
+		InputTokens:  int64(m.PromptTokenCount),
+		CachedTokens: int64(m.CachedContentTokenCount),
+		OutputTokens: int64(m.CandidatesTokenCount),
+	}
+}
+
+// Add returns the sum of u and other.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		InputTokens:  u.InputTokens + other.InputTokens,
+		CachedTokens: u.CachedTokens + other.CachedTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+	}
+}
+
+// PricingTable holds the token prices per model.
+type PricingTable struct {
+	prices map[string]configs.ModelPricing
+	// models sorted by descending length, for longest prefix matching
+	models []string
+}
+
+// NewPricingTable creates a pricing table. Later entries override earlier ones of the same model.
+func NewPricingTable(pricing []configs.ModelPricing) *PricingTable {
+	t := &PricingTable{prices: make(map[string]configs.ModelPricing, len(pricing))}
+	for _, p := range pricing {
+		if p.Model == "" {
+			continue
+		}
+		if _, ok := t.prices[p.Model]; !ok {
+			t.models = append(t.models, p.Model)
+		}
+		t.prices[p.Model] = p
+	}
+	sort.SliceStable(t.models, func(i, j int) bool {
+		return len(t.models[i]) > len(t.models[j])
+	})
+	return t
+}
+
+var (
+	defaultTable     *PricingTable
+	defaultTableOnce sync.Once
+)
+
+// DefaultPricingTable returns the pricing table configured under cost.pricing of the global config.
+func DefaultPricingTable() *PricingTable {
+	defaultTableOnce.Do(func() {
+		var pricing []configs.ModelPricing
+		if cfg := configs.GetGlobalConfig().Cost; cfg != nil {
+			pricing = cfg.Pricing
+		}
+		defaultTable = NewPricingTable(pricing)
+	})
+	return defaultTable
+}
+
+// Len returns the number of priced models.
+func (t *PricingTable) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.prices)
+}
+
+// Lookup returns the pricing of model, matching its name exactly or else by the longest prefix.
+func (t *PricingTable) Lookup(model string) (configs.ModelPricing, bool) {
+	if t == nil || model == "" {
+		return configs.ModelPricing{}, false
+	}
+	if p, ok := t.prices[model]; ok {
+		return p, true
+	}
+	for _, name := range t.models {
+		if strings.HasPrefix(model, name) {
+			return t.prices[name], true
+		}
+	}
+	return configs.ModelPricing{}, false
+}
+
+// Cost prices usage of model. It reports false if the model has no pricing.
+// Cached input tokens are charged at the cached price if one is set, otherwise at the input price.
+func (t *PricingTable) Cost(model string, usage Usage) (float64, bool) {
+	p, ok := t.Lookup(model)
+	if !ok {
+		return 0, false
+	}
+	cachedPrice := p.CachedInput
+	if cachedPrice == 0 {
+		cachedPrice = p.Input
+	}
+	cached := min(usage.CachedTokens, usage.InputTokens)
+	cost := float64(usage.InputTokens-cached)*p.Input +
+		float64(cached)*cachedPrice +
+		float64(usage.OutputTokens)*p.Output
+	return cost / tokensPerPriceUnit, true
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/volcengine/veadk-go/configs"
+	"google.golang.org/genai"
+)
+
+func TestPricingTable_Lookup(t *testing.T) {
+	table := NewPricingTable([]configs.ModelPricing{
+		{Model: "doubao", Input: 1},
+		{Model: "doubao-seed-1-6", Input: 2},
+		{Model: "doubao-seed-1-6-flash", Input: 3},
+		{Model: "", Input: 4},
+	})
+	assert.Equal(t, 3, table.Len())
+
+	tests := []struct {
+		model string
+		input float64
+		found bool
+	}{
+		{"doubao-seed-1-6", 2, true},
+		{"doubao-seed-1-6-250615", 2, true},
+		{"doubao-seed-1-6-flash-250715", 3, true},
+		{"doubao-pro", 1, true},
+		{"deepseek-v3", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			p, ok := table.Lookup(tt.model)
+			assert.Equal(t, tt.found, ok)
+			assert.Equal(t, tt.input, p.Input)
+		})
+	}
+
+	var nilTable *PricingTable
+	_, ok := nilTable.Lookup("doubao")
+	assert.False(t, ok)
+}
+
+func TestPricingTable_Cost(t *testing.T) {
+	table := NewPricingTable([]configs.ModelPricing{
+		{Model: "cached", Input: 4, Output: 16, CachedInput: 0.8},
+		{Model: "plain", Input: 4, Output: 16},
+	})
+	usage := Usage{InputTokens: 1_000_000, CachedTokens: 500_000, OutputTokens: 250_000}
+
+	c, ok := table.Cost("cached", usage)
+	assert.True(t, ok)
+	assert.InDelta(t, 2+0.4+4, c, 1e-9)
+
+	c, ok = table.Cost("plain", usage)
+	assert.True(t, ok)
+	assert.InDelta(t, 4+4, c, 1e-9)
+
+	c, ok = table.Cost("unknown", usage)
+	assert.False(t, ok)
+	assert.Zero(t, c)
+}
+
+func TestUsageFromMetadata(t *testing.T) {
+	assert.Equal(t, Usage{}, UsageFromMetadata(nil))
+
+	usage := UsageFromMetadata(&genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:        100,
+		CachedContentTokenCount: 40,
+		CandidatesTokenCount:    20,
+		TotalTokenCount:         120,
+	})
+	assert.Equal(t, Usage{InputTokens: 100, CachedTokens: 40, OutputTokens: 20}, usage)
+	assert.Equal(t, Usage{InputTokens: 200, CachedTokens: 80, OutputTokens: 40}, usage.Add(usage))
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const usageTableName = "veadk_usage_records"
+
+var ErrDBNotSet = errors.New("cost: gorm db not set")
+
+type usageRow struct {
+	ID           uint      `gorm:"primaryKey"`
+	CreatedAt    time.Time `gorm:"index"`
+	UserID       string    `gorm:"size:128;index"`
+	AppName      string    `gorm:"size:128;index"`
+	SessionID    string    `gorm:"size:128;index"`
+	Model        string    `gorm:"size:128"`
+	InputTokens  int64
+	CachedTokens int64
+	OutputTokens int64
+	Cost         float64
+}
+
+func (usageRow) TableName() string {
+	return usageTableName
+}
+
+// SQLStore persists the usage ledger in a SQL database, e.g. the one of the short-term memory backend.
+type SQLStore struct {
+	db *gorm.DB
+}
+
+// NewSQLStore creates the usage table if needed and returns a store writing to it.
+func NewSQLStore(db *gorm.DB) (*SQLStore, error) {
+	if db == nil {
+		return nil, ErrDBNotSet
+	}
+	if err := db.AutoMigrate(&usageRow{}); err != nil {
+		return nil, err
+	}
+	return &SQLStore{db: db}, nil
+}
+
+func (s *SQLStore) Add(ctx context.Context, record *Record) error {
+	return s.db.WithContext(ctx).Create(&usageRow{
+		CreatedAt:    record.Time,
+		UserID:       record.UserID,
+		AppName:      record.AppName,
+		SessionID:    record.SessionID,
+		Model:        record.Model,
+		InputTokens:  record.InputTokens,
+		CachedTokens: record.CachedTokens,
+		OutputTokens: record.OutputTokens,
+		Cost:         record.Cost,
+	}).Error
+}
+
+func (s *SQLStore) Sum(ctx context.Context, query *Query) (*Total, error) {
+	tx := s.db.WithContext(ctx).Model(&usageRow{})
+	if query.UserID != "" {
+		tx = tx.Where("user_id = ?", query.UserID)
+	}
+	if query.AppName != "" {
+		tx = tx.Where("app_name = ?", query.AppName)
+	}
+	if query.SessionID != "" {
+		tx = tx.Where("session_id = ?", query.SessionID)
+	}
+	if !query.Since.IsZero() {
+		tx = tx.Where("created_at >= ?", query.Since)
+	}
+	if !query.Until.IsZero() {
+		tx = tx.Where("created_at < ?", query.Until)
+	}
+
+	var row struct {
+		InputTokens  int64
+		CachedTokens int64
+		OutputTokens int64
+		Cost         float64
+		Calls        int64
+	}
+	err := tx.Select("COALESCE(SUM(input_tokens), 0) AS input_tokens, " +
+		"COALESCE(SUM(cached_tokens), 0) AS cached_tokens, " +
+		"COALESCE(SUM(output_tokens), 0) AS output_tokens, " +
+		"COALESCE(SUM(cost), 0) AS cost, COUNT(*) AS calls").Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	return &Total{
+		Usage: Usage{
+			InputTokens:  row.InputTokens,
+			CachedTokens: row.CachedTokens,
+			OutputTokens: row.OutputTokens,
+		},
+		Cost:  row.Cost,
+		Calls: row.Calls,
+	}, nil
+}
@@ -137,6 +137,20 @@ func (c *Mem0Client) Search(ctx context.Context, req SearchMemoriesRequest) (Sea
 	return response, nil
 }
 
+// Ping checks that the server is reachable. Any HTTP response counts, so that no
+// memory is searched.
+func (c *Mem0Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("build request error: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
 func (c *Mem0Client) doRequest(ctx context.Context, method, url string, body interface{}) ([]byte, error) {
 	b, err := json.Marshal(body)
 	if err != nil {
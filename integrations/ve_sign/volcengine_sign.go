@@ -109,24 +109,59 @@ func (vr VeRequest) buildSignRequest() (*http.Request, error) {
 		return nil, fmt.Errorf("VeRequest.v2 NewRequest bad request: %w", err)
 	}
 
-	now := time.Now()
+	request.Header.Set("Content-Type", "application/json")
+	for k, v := range vr.Header {
+		request.Header.Set(k, v)
+	}
+	sign(request, bodyBytes, vr.AK, vr.SK, vr.Region, vr.Service, time.Now())
+	return request, nil
+}
+
+// SignRequest signs r with the Volcengine HMAC-SHA256 scheme, so that services can
+// authenticate the caller by its Volcengine identity. The body of r is read and restored.
+func SignRequest(r *http.Request, ak, sk, sessionToken, region, service string) error {
+	if ak == "" || sk == "" {
+		return fmt.Errorf("%w: VOLCENGINE_ACCESS_KEY or VOLCENGINE_SECRET_KEY not set", ErrVeRequestParam)
+	}
+	if region == "" || service == "" {
+		return fmt.Errorf("%w: Service or Region is empty", ErrVeRequestParam)
+	}
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return fmt.Errorf("failed to read request body: %w", err)
+		}
+		_ = r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if sessionToken != "" {
+		r.Header.Set("X-Security-Token", sessionToken)
+	}
+	sign(r, body, ak, sk, region, service, time.Now())
+	return nil
+}
+
+// sign sets the X-Date, X-Content-Sha256 and Authorization headers of request.
+func sign(request *http.Request, body []byte, ak, sk, region, service string, now time.Time) {
 	date := now.UTC().Format("20060102T150405Z")
 	authDate := date[:8]
 	request.Header.Set("X-Date", date)
 
-	payload := hex.EncodeToString(hashSHA256(bodyBytes))
+	payload := hex.EncodeToString(hashSHA256(body))
 	request.Header.Set("X-Content-Sha256", payload)
-	request.Header.Set("Content-Type", "application/json")
-	for k, v := range vr.Header {
-		request.Header.Set(k, v)
-	}
 
-	queryString := strings.ReplaceAll(queries.Encode(), "+", "%20")
+	queryString := strings.ReplaceAll(request.URL.Query().Encode(), "+", "%20")
 	signedHeaders := []string{"host", "x-date", "x-content-sha256", "content-type"}
+	// outgoing requests are sent to the URL host when Host is empty
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
 	var headerList []string
 	for _, h := range signedHeaders {
 		if h == "host" {
-			headerList = append(headerList, h+":"+request.Host)
+			headerList = append(headerList, h+":"+host)
 		} else {
 			v := request.Header.Get(h)
 			headerList = append(headerList, h+":"+strings.TrimSpace(v))
@@ -135,8 +170,8 @@ func (vr VeRequest) buildSignRequest() (*http.Request, error) {
 	headerString := strings.Join(headerList, "\n")
 
 	canonicalString := strings.Join([]string{
-		vr.Method,
-		vr.Path,
+		request.Method,
+		request.URL.Path,
 		queryString,
 		headerString + "\n",
 		strings.Join(signedHeaders, ";"),
@@ -145,7 +180,7 @@ func (vr VeRequest) buildSignRequest() (*http.Request, error) {
 
 	hashedCanonicalString := hex.EncodeToString(hashSHA256([]byte(canonicalString)))
 
-	credentialScope := authDate + "/" + vr.Region + "/" + vr.Service + "/request"
+	credentialScope := authDate + "/" + region + "/" + service + "/request"
 	signString := strings.Join([]string{
 		"HMAC-SHA256",
 		date,
@@ -153,15 +188,14 @@ func (vr VeRequest) buildSignRequest() (*http.Request, error) {
 		hashedCanonicalString,
 	}, "\n")
 
-	signedKey := getSignedKey(vr.SK, authDate, vr.Region, vr.Service)
+	signedKey := getSignedKey(sk, authDate, region, service)
 	signature := hex.EncodeToString(hmacSHA256(signedKey, signString))
 
 	authorization := "HMAC-SHA256" +
-		" Credential=" + vr.AK + "/" + credentialScope +
+		" Credential=" + ak + "/" + credentialScope +
 		", SignedHeaders=" + strings.Join(signedHeaders, ";") +
 		", Signature=" + signature
 	request.Header.Set("Authorization", authorization)
-	return request, nil
 }
 
 func (vr VeRequest) DoRequest() ([]byte, error) {
@@ -1,6 +1,12 @@
 package ve_sign
 
-import "testing"
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
 
 func TestValidateOK(t *testing.T) {
 	v := VeRequest{
@@ -57,3 +63,18 @@ func TestValidateEmptyBodyForPOST(t *testing.T) {
 		t.Fatalf("expected error for empty body on POST")
 	}
 }
+
+func TestSignFallsBackToURLHost(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	withHost := httptest.NewRequest(http.MethodPost, "https://open.volcengineapi.com/v1/test", nil)
+	sign(withHost, []byte("{}"), "ak", "sk", "cn-beijing", "open", now)
+
+	// e.g. requests built for a client, which are sent to their URL host
+	withoutHost := withHost.Clone(context.Background())
+	withoutHost.Host = ""
+	sign(withoutHost, []byte("{}"), "ak", "sk", "cn-beijing", "open", now)
+
+	if got, want := withoutHost.Header.Get("Authorization"), withHost.Header.Get("Authorization"); got != want {
+		t.Fatalf("expected the signature of the URL host %q, got %q", want, got)
+	}
+}
@@ -120,6 +120,12 @@ func NewOpenSearchKnowledgeBackend(cfg *Config) (_interface.KnowledgeBackend, er
 	}, nil
 }
 
+// Close closes the idle connections of the backend.
+func (o *OpenSearchKnowledgeBackend) Close() error {
+	o.httpClient.CloseIdleConnections()
+	return nil
+}
+
 func (o *OpenSearchKnowledgeBackend) Index() string {
 	return o.config.Index
 }
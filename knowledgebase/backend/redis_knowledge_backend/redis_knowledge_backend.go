@@ -108,6 +108,11 @@ func NewRedisKnowledgeBackend(cfg *Config) (_interface.KnowledgeBackend, error)
 	}, nil
 }
 
+// Close closes the Redis client of the backend.
+func (r *RedisKnowledgeBackend) Close() error {
+	return r.client.Close()
+}
+
 func (r *RedisKnowledgeBackend) Index() string {
 	return r.config.Index
 }
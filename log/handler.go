@@ -0,0 +1,155 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/adk/agent"
+)
+
+// Keys of the correlation attributes added to every record.
+const (
+	TraceIDKey      = "trace_id"
+	SpanIDKey       = "span_id"
+	SessionIDKey    = "session_id"
+	InvocationIDKey = "invocation_id"
+)
+
+type sessionContextKey struct{}
+
+type sessionIDs struct {
+	sessionID    string
+	invocationID string
+}
+
+// ContextWithSession attaches a session and invocation id to ctx, for code paths where
+// ctx is not an ADK agent context, e.g. HTTP handlers of the agent servers.
+func ContextWithSession(ctx context.Context, sessionID, invocationID string) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, sessionIDs{sessionID: sessionID, invocationID: invocationID})
+}
+
+func sessionFromContext(ctx context.Context) (sessionID, invocationID string) {
+	switch c := ctx.(type) {
+	case agent.ReadonlyContext:
+		return c.SessionID(), c.InvocationID()
+	case agent.InvocationContext:
+		if s := c.Session(); s != nil {
+			sessionID = s.ID()
+		}
+		return sessionID, c.InvocationID()
+	}
+	if ids, ok := ctx.Value(sessionContextKey{}).(sessionIDs); ok {
+		return ids.sessionID, ids.invocationID
+	}
+	// e.g. the executor context of the A2A server callbacks
+	if c, ok := ctx.(interface{ SessionID() string }); ok {
+		return c.SessionID(), ""
+	}
+	return "", ""
+}
+
+// ContextHandler adds trace_id, span_id, session_id and invocation_id from the
+// context of each record, linking log lines to the trace of the agent run.
+type ContextHandler struct {
+	slog.Handler
+}
+
+func NewContextHandler(h slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: h}
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			r.AddAttrs(slog.String(TraceIDKey, sc.TraceID().String()), slog.String(SpanIDKey, sc.SpanID().String()))
+		}
+		sessionID, invocationID := sessionFromContext(ctx)
+		if sessionID != "" {
+			r.AddAttrs(slog.String(SessionIDKey, sessionID))
+		}
+		if invocationID != "" {
+			r.AddAttrs(slog.String(InvocationIDKey, invocationID))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// fanoutHandler sends each record to all handlers enabled for its level.
+type fanoutHandler []slog.Handler
+
+func (h fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, handler := range h {
+		if handler.Enabled(ctx, r.Level) {
+			errs = append(errs, handler.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(fanoutHandler, len(h))
+	for i, handler := range h {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (h fanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make(fanoutHandler, len(h))
+	for i, handler := range h {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return handlers
+}
+
+// levelHandler drops records below level before they reach the wrapped handler.
+type levelHandler struct {
+	slog.Handler
+	level slog.Leveler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.Handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(newHandler(&buf, slog.LevelInfo, "json")))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	ctx = ContextWithSession(ctx, "s1", "inv1")
+	logger.With("agent", "a1").InfoContext(ctx, "running")
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, sc.TraceID().String(), line[TraceIDKey])
+	assert.Equal(t, sc.SpanID().String(), line[SpanIDKey])
+	assert.Equal(t, "s1", line[SessionIDKey])
+	assert.Equal(t, "inv1", line[InvocationIDKey])
+	assert.Equal(t, "a1", line["agent"])
+
+	buf.Reset()
+	logger.InfoContext(executorContext{Context: context.Background()}, "a2a")
+	assert.Contains(t, buf.String(), `"session_id":"s2"`)
+
+	buf.Reset()
+	logger.Info("no context")
+	assert.NotContains(t, buf.String(), TraceIDKey)
+}
+
+type executorContext struct {
+	context.Context
+}
+
+func (executorContext) SessionID() string { return "s2" }
+
+func TestNewHandler_Text(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newHandler(&buf, slog.LevelWarn, "text"))
+	logger.Info("dropped")
+	logger.Warn("kept", "k", "v")
+	assert.True(t, strings.HasPrefix(buf.String(), "time="))
+	assert.Contains(t, buf.String(), "msg=kept k=v")
+	assert.NotContains(t, buf.String(), "dropped")
+}
+
+func TestFanoutHandler(t *testing.T) {
+	var debug, info bytes.Buffer
+	var level slog.LevelVar
+	level.Set(slog.LevelInfo)
+	logger := slog.New(fanoutHandler{
+		newHandler(&info, slog.LevelInfo, "json"),
+		&levelHandler{Handler: newHandler(&debug, slog.LevelDebug, "json"), level: &level},
+	})
+
+	logger.Debug("debug")
+	assert.Empty(t, info.String())
+	assert.Empty(t, debug.String())
+
+	logger.Info("info")
+	assert.Contains(t, info.String(), `"msg":"info"`)
+	assert.Contains(t, debug.String(), `"msg":"info"`)
+}
@@ -17,6 +17,7 @@ package log
 import (
 	"context"
 	"fmt"
+	"io"
 	ilog "log"
 	"log/slog"
 	"os"
@@ -30,6 +31,11 @@ import (
 	"github.com/volcengine/veadk-go/utils"
 )
 
+var (
+	defaultLevel  = new(slog.LevelVar)
+	defaultFormat = common.DEFAULT_LOGGING_FORMAT
+)
+
 func init() {
 	levelStr := utils.GetEnvWithDefault(common.LOGGING_LEVEL, configs.GetGlobalConfig().LOGGING.Level, common.DEFAULT_LOGGING_LEVER)
 
@@ -38,13 +44,19 @@ func init() {
 		slog.Warn(fmt.Sprintf("config log level '%s' not recognized, defaulting to INFO", levelStr))
 		level = slog.LevelInfo
 	}
-	logger := NewLogger(level)
+	defaultLevel.Set(level)
+	defaultFormat = utils.GetEnvWithDefault(common.LOGGING_FORMAT, configs.GetGlobalConfig().LOGGING.Format, common.DEFAULT_LOGGING_FORMAT)
 
-	slog.SetDefault(logger)
+	slog.SetDefault(slog.New(NewContextHandler(newHandler(os.Stdout, defaultLevel, defaultFormat))))
 }
 
 func NewLogger(level slog.Level) *slog.Logger {
-	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	return slog.New(NewContextHandler(newHandler(os.Stdout, level, defaultFormat)))
+}
+
+// newHandler creates a json (default) or text handler writing to w.
+func newHandler(w io.Writer, level slog.Leveler, format string) slog.Handler {
+	opts := &slog.HandlerOptions{
 		Level: level,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			if a.Key == slog.TimeKey {
@@ -54,7 +66,21 @@ func NewLogger(level slog.Level) *slog.Logger {
 			}
 			return a
 		},
-	}))
+	}
+	if strings.EqualFold(format, "text") {
+		return slog.NewTextHandler(w, opts)
+	}
+	return slog.NewJSONHandler(w, opts)
+}
+
+// SetExportHandler additionally sends the records of the default logger to h, e.g. the
+// OpenTelemetry slog bridge, at the configured log level. A nil h stops exporting.
+func SetExportHandler(h slog.Handler) {
+	handler := newHandler(os.Stdout, defaultLevel, defaultFormat)
+	if h != nil {
+		handler = fanoutHandler{handler, &levelHandler{Handler: h, level: defaultLevel}}
+	}
+	slog.SetDefault(slog.New(NewContextHandler(handler)))
 }
 
 func Printf(format string, v ...any) {
@@ -97,6 +123,22 @@ func Errorf(format string, v ...any) {
 	slog.Error(fmt.Sprintf(format, v...))
 }
 
+func DebugContext(ctx context.Context, msg string, args ...any) {
+	slog.DebugContext(ctx, msg, args...)
+}
+
+func InfoContext(ctx context.Context, msg string, args ...any) {
+	slog.InfoContext(ctx, msg, args...)
+}
+
+func WarnContext(ctx context.Context, msg string, args ...any) {
+	slog.WarnContext(ctx, msg, args...)
+}
+
+func ErrorContext(ctx context.Context, msg string, args ...any) {
+	slog.ErrorContext(ctx, msg, args...)
+}
+
 //func Fatal(v ...any) {
 //	ilog.Fatal(v...)
 //}
@@ -0,0 +1,201 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package encryption encrypts session and memory content at rest.
+//
+// Values are envelope encrypted: every value gets a fresh data key, the value is sealed with it
+// using AES-GCM, and the data key is sealed with a key encryption key from a KeyProvider.
+// Encrypted values are strings of the form
+//
+//	veadk:enc:v1:<key id>:<sealed data key>:<sealed value>
+//
+// so the key a value was written with can be looked up after a rotation, and plaintext written
+// before encryption was enabled is still readable.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	prefix = "veadk:enc:v1:"
+
+	dataKeySize = 32
+)
+
+var (
+	ErrKeyNotFound       = errors.New("encryption key not found")
+	ErrInvalidKey        = errors.New("invalid encryption key")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Encryptor envelope encrypts values with the keys of a KeyProvider.
+type Encryptor struct {
+	keys KeyProvider
+}
+
+func NewEncryptor(keys KeyProvider) (*Encryptor, error) {
+	if keys == nil {
+		return nil, fmt.Errorf("%w: key provider is nil", ErrInvalidKey)
+	}
+	return &Encryptor{keys: keys}, nil
+}
+
+// IsEncrypted reports whether value was produced by Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt seals plaintext with a new data key wrapped by the current key.
+func (e *Encryptor) Encrypt(ctx context.Context, plaintext []byte) (string, error) {
+	key, err := e.keys.CurrentKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	header := prefix + key.ID + ":"
+	wrapped, err := seal(key.Material, dataKey, []byte(header))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dataKey, plaintext, []byte(header))
+	if err != nil {
+		return "", err
+	}
+	return header + encoding.EncodeToString(wrapped) + ":" + encoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt with whichever key it was written with.
+// Values that are not encrypted are returned as is, so existing plaintext stays readable.
+func (e *Encryptor) Decrypt(ctx context.Context, value string) ([]byte, error) {
+	if !IsEncrypted(value) {
+		return []byte(value), nil
+	}
+	keyID, wrapped, sealed, err := parse(value)
+	if err != nil {
+		return nil, err
+	}
+	key, err := e.keys.Key(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	header := []byte(prefix + keyID + ":")
+	dataKey, err := open(key.Material, wrapped, header)
+	if err != nil {
+		return nil, err
+	}
+	return open(dataKey, sealed, header)
+}
+
+// Rewrap re-encrypts value with the current key and reports whether it changed.
+// Values already under the current key and plaintext values are returned as is.
+// Run it over stored values after a rotation to retire the previous key.
+func (e *Encryptor) Rewrap(ctx context.Context, value string) (string, bool, error) {
+	if !IsEncrypted(value) {
+		return value, false, nil
+	}
+	current, err := e.keys.CurrentKey(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	if strings.HasPrefix(value, prefix+current.ID+":") {
+		return value, false, nil
+	}
+	plaintext, err := e.Decrypt(ctx, value)
+	if err != nil {
+		return "", false, err
+	}
+	rewrapped, err := e.Encrypt(ctx, plaintext)
+	if err != nil {
+		return "", false, err
+	}
+	return rewrapped, true, nil
+}
+
+// EncodeText encrypts a memory text, implementing long_term_memory_backends.TextCodec.
+func (e *Encryptor) EncodeText(ctx context.Context, text string) (string, error) {
+	return e.Encrypt(ctx, []byte(text))
+}
+
+// DecodeText decrypts a memory text, implementing long_term_memory_backends.TextCodec.
+func (e *Encryptor) DecodeText(ctx context.Context, stored string) (string, error) {
+	plaintext, err := e.Decrypt(ctx, stored)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func parse(value string) (keyID string, wrapped, sealed []byte, err error) {
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", nil, nil, ErrInvalidCiphertext
+	}
+	if wrapped, err = encoding.DecodeString(parts[1]); err != nil {
+		return "", nil, nil, fmt.Errorf("%w: %v", ErrInvalidCiphertext, err)
+	}
+	if sealed, err = encoding.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, fmt.Errorf("%w: %v", ErrInvalidCiphertext, err)
+	}
+	return parts[0], wrapped, sealed, nil
+}
+
+// seal encrypts plaintext with AES-GCM, prepending the random nonce.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCiphertext, err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	return cipher.NewGCM(block)
+}
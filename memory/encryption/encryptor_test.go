@@ -0,0 +1,182 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/common"
+)
+
+func testKey(id string, b byte) Key {
+	return Key{ID: id, Material: bytes.Repeat([]byte{b}, 32)}
+}
+
+func newTestEncryptor(t *testing.T, current string, keys ...Key) *Encryptor {
+	t.Helper()
+	ring, err := NewKeyring(current, keys...)
+	require.NoError(t, err)
+	enc, err := NewEncryptor(ring)
+	require.NoError(t, err)
+	return enc
+}
+
+func TestEncryptor(t *testing.T) {
+	ctx := context.Background()
+	enc := newTestEncryptor(t, "k1", testKey("k1", 1))
+
+	ciphertext, err := enc.Encrypt(ctx, []byte("my phone is 123"))
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(ciphertext))
+	assert.True(t, strings.HasPrefix(ciphertext, "veadk:enc:v1:k1:"))
+	assert.NotContains(t, ciphertext, "123")
+
+	again, err := enc.Encrypt(ctx, []byte("my phone is 123"))
+	require.NoError(t, err)
+	assert.NotEqual(t, ciphertext, again)
+
+	plaintext, err := enc.Decrypt(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "my phone is 123", string(plaintext))
+
+	t.Run("plaintext passes through", func(t *testing.T) {
+		plaintext, err := enc.Decrypt(ctx, "written before encryption")
+		require.NoError(t, err)
+		assert.Equal(t, "written before encryption", string(plaintext))
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := ciphertext[:len(ciphertext)-2] + "AA"
+		_, err := enc.Decrypt(ctx, tampered)
+		assert.ErrorIs(t, err, ErrInvalidCiphertext)
+
+		_, err = enc.Decrypt(ctx, "veadk:enc:v1:k1:only-two")
+		assert.ErrorIs(t, err, ErrInvalidCiphertext)
+	})
+
+	t.Run("key id is authenticated", func(t *testing.T) {
+		other := newTestEncryptor(t, "k2", testKey("k2", 1))
+		_, err := other.Decrypt(ctx, strings.Replace(ciphertext, ":k1:", ":k2:", 1))
+		assert.ErrorIs(t, err, ErrInvalidCiphertext)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		other := newTestEncryptor(t, "k2", testKey("k2", 2))
+		_, err := other.Decrypt(ctx, ciphertext)
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	})
+}
+
+func TestEncryptor_Rotation(t *testing.T) {
+	ctx := context.Background()
+	old := newTestEncryptor(t, "k1", testKey("k1", 1))
+	ciphertext, err := old.Encrypt(ctx, []byte("secret"))
+	require.NoError(t, err)
+
+	rotated := newTestEncryptor(t, "k2", testKey("k1", 1), testKey("k2", 2))
+	plaintext, err := rotated.Decrypt(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	rewrapped, changed, err := rotated.Rewrap(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, strings.HasPrefix(rewrapped, "veadk:enc:v1:k2:"))
+
+	retired := newTestEncryptor(t, "k2", testKey("k2", 2))
+	plaintext, err = retired.Decrypt(ctx, rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	same, changed, err := rotated.Rewrap(ctx, rewrapped)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, rewrapped, same)
+
+	same, changed, err = rotated.Rewrap(ctx, "plain")
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, "plain", same)
+}
+
+func TestNewKeyring(t *testing.T) {
+	_, err := NewKeyring("k1", Key{ID: "k1", Material: []byte("short")})
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = NewKeyring("a:b", testKey("a:b", 1))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = NewKeyring("k1", testKey("k1", 1), testKey("k1", 2))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = NewKeyring("k2", testKey("k1", 1))
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = NewEncryptor(nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestNewKeyFileProvider(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	k1 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	k2 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 16))
+	require.NoError(t, os.WriteFile(path, []byte("current: k2\nkeys:\n  k1: "+k1+"\n  k2: "+k2+"\n"), 0o600))
+
+	ring, err := NewKeyFileProvider(path)
+	require.NoError(t, err)
+	current, err := ring.CurrentKey(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", current.ID)
+	assert.Len(t, current.Material, 16)
+	_, err = ring.Key(ctx, "k1")
+	assert.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("current: k1\nkeys:\n  k1: not-base64!\n"), 0o600))
+	_, err = NewKeyFileProvider(path)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = NewKeyFileProvider(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestNewEnvKeyProvider(t *testing.T) {
+	ctx := context.Background()
+	k1 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	k2 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+
+	t.Setenv(common.ENCRYPTION_KEY, "")
+	_, err := NewEnvKeyProvider()
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	t.Setenv(common.ENCRYPTION_KEY, "k2:"+k2)
+	t.Setenv(common.ENCRYPTION_PREVIOUS_KEYS, "k1:"+k1+", ")
+	ring, err := NewEnvKeyProvider()
+	require.NoError(t, err)
+	current, err := ring.CurrentKey(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", current.ID)
+	_, err = ring.Key(ctx, "k1")
+	assert.NoError(t, err)
+
+	t.Setenv(common.ENCRYPTION_KEY, k2)
+	_, err = NewEnvKeyProvider()
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/volcengine/veadk-go/common"
+	"github.com/volcengine/veadk-go/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Key is a key encryption key. Material must be 16, 24 or 32 bytes, selecting AES-128, AES-192 or AES-256.
+type Key struct {
+	ID       string
+	Material []byte
+}
+
+// KeyProvider supplies key encryption keys. Implementations backed by a KMS can fetch keys lazily.
+type KeyProvider interface {
+	// CurrentKey returns the key new values are encrypted with.
+	CurrentKey(ctx context.Context) (Key, error)
+	// Key returns the key with the given id, including keys rotated out but still needed for decryption.
+	Key(ctx context.Context, id string) (Key, error)
+}
+
+// Keyring is a KeyProvider holding its keys in memory.
+type Keyring struct {
+	current string
+	keys    map[string]Key
+}
+
+// NewKeyring creates a keyring encrypting with the key currentID. The other keys are only used for decryption.
+func NewKeyring(currentID string, keys ...Key) (*Keyring, error) {
+	ring := &Keyring{current: currentID, keys: make(map[string]Key, len(keys))}
+	for _, key := range keys {
+		if key.ID == "" || strings.Contains(key.ID, ":") {
+			return nil, fmt.Errorf("%w: key id %q must be non-empty and must not contain ':'", ErrInvalidKey, key.ID)
+		}
+		if _, err := newGCM(key.Material); err != nil {
+			return nil, fmt.Errorf("key %s: %w", key.ID, err)
+		}
+		if _, ok := ring.keys[key.ID]; ok {
+			return nil, fmt.Errorf("%w: duplicate key id %q", ErrInvalidKey, key.ID)
+		}
+		ring.keys[key.ID] = key
+	}
+	if _, ok := ring.keys[currentID]; !ok {
+		return nil, fmt.Errorf("%w: current key %q", ErrKeyNotFound, currentID)
+	}
+	return ring, nil
+}
+
+func (r *Keyring) CurrentKey(_ context.Context) (Key, error) {
+	return r.keys[r.current], nil
+}
+
+func (r *Keyring) Key(_ context.Context, id string) (Key, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return Key{}, fmt.Errorf("%w: %q", ErrKeyNotFound, id)
+	}
+	return key, nil
+}
+
+// keyFile is the YAML layout read by NewKeyFileProvider.
+type keyFile struct {
+	Current string            `yaml:"current"`
+	Keys    map[string]string `yaml:"keys"`
+}
+
+// NewKeyFileProvider loads a keyring from a YAML file of base64 keys:
+//
+//	current: "2025-06"
+//	keys:
+//	  "2025-01": <base64 key>
+//	  "2025-06": <base64 key>
+//
+// Rotating adds a key and points current at it. Keep the old key until stored values were rewrapped.
+func NewKeyFileProvider(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	var file keyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse key file %s: %w", path, err)
+	}
+
+	keys := make([]Key, 0, len(file.Keys))
+	for id, encoded := range file.Keys {
+		material, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %s is not base64: %v", ErrInvalidKey, id, err)
+		}
+		keys = append(keys, Key{ID: id, Material: material})
+	}
+	return NewKeyring(file.Current, keys...)
+}
+
+// NewEnvKeyProvider creates a keyring from ENCRYPTION_KEY and, after a rotation, ENCRYPTION_PREVIOUS_KEYS.
+// Both hold "<id>:<base64 key>" pairs, the latter comma separated.
+func NewEnvKeyProvider() (*Keyring, error) {
+	currentValue := utils.GetEnvWithDefault(common.ENCRYPTION_KEY)
+	if currentValue == "" {
+		return nil, fmt.Errorf("%w: %s is not set", ErrKeyNotFound, common.ENCRYPTION_KEY)
+	}
+	current, err := parseEnvKey(currentValue)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ENCRYPTION_KEY, err)
+	}
+
+	keys := []Key{current}
+	for _, value := range strings.Split(utils.GetEnvWithDefault(common.ENCRYPTION_PREVIOUS_KEYS), ",") {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		key, err := parseEnvKey(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ENCRYPTION_PREVIOUS_KEYS, err)
+		}
+		keys = append(keys, key)
+	}
+	return NewKeyring(current.ID, keys...)
+}
+
+func parseEnvKey(value string) (Key, error) {
+	id, encoded, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return Key{}, fmt.Errorf("%w: expected <id>:<base64 key>", ErrInvalidKey)
+	}
+	material, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: key %s is not base64: %v", ErrInvalidKey, id, err)
+	}
+	return Key{ID: id, Material: material}, nil
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/volcengine/veadk-go/memory/long_term_memory_backends"
+)
+
+// ErrUnsupportedBackend is returned for backends that embed the stored text on the server side,
+// encrypting their texts would make the memories unsearchable.
+var ErrUnsupportedBackend = errors.New("long-term memory backend does not support encryption")
+
+// NewLongTermMemoryBackend wraps a backend so that memory texts are encrypted before storage
+// and decrypted after search. Embeddings are still computed from the plaintext, so vector
+// search is unaffected. Only backends implementing long_term_memory_backends.TextCodecBackend are supported.
+func NewLongTermMemoryBackend(backend long_term_memory_backends.LongTermMemoryBackend, encryptor *Encryptor) (long_term_memory_backends.LongTermMemoryBackend, error) {
+	codecBackend, ok := backend.(long_term_memory_backends.TextCodecBackend)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedBackend, backend)
+	}
+	return codecBackend.WithTextCodec(encryptor), nil
+}
+
+var _ long_term_memory_backends.TextCodec = (*Encryptor)(nil)
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/memory/long_term_memory_backends"
+)
+
+type fakeCodecBackend struct {
+	codec long_term_memory_backends.TextCodec
+	saved []string
+}
+
+func (f *fakeCodecBackend) SaveMemory(ctx context.Context, _ long_term_memory_backends.Scope, texts []string) error {
+	for _, text := range texts {
+		stored, err := f.codec.EncodeText(ctx, text)
+		if err != nil {
+			return err
+		}
+		f.saved = append(f.saved, stored)
+	}
+	return nil
+}
+
+func (f *fakeCodecBackend) SearchMemory(ctx context.Context, _ []long_term_memory_backends.Scope, _ string, _ int) ([]*long_term_memory_backends.MemItem, error) {
+	var items []*long_term_memory_backends.MemItem
+	for _, stored := range f.saved {
+		text, err := f.codec.DecodeText(ctx, stored)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &long_term_memory_backends.MemItem{Content: text})
+	}
+	return items, nil
+}
+
+func (f *fakeCodecBackend) WithTextCodec(codec long_term_memory_backends.TextCodec) long_term_memory_backends.LongTermMemoryBackend {
+	return &fakeCodecBackend{codec: codec}
+}
+
+type plainBackend struct {
+	long_term_memory_backends.LongTermMemoryBackend
+}
+
+func TestNewLongTermMemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	enc := newTestEncryptor(t, "k1", testKey("k1", 1))
+
+	_, err := NewLongTermMemoryBackend(&plainBackend{}, enc)
+	assert.ErrorIs(t, err, ErrUnsupportedBackend)
+
+	backend, err := NewLongTermMemoryBackend(&fakeCodecBackend{}, enc)
+	require.NoError(t, err)
+	scope := long_term_memory_backends.UserScope("user1")
+	require.NoError(t, backend.SaveMemory(ctx, scope, []string{"likes green tea"}))
+	assert.True(t, IsEncrypted(backend.(*fakeCodecBackend).saved[0]))
+
+	items, err := backend.SearchMemory(ctx, []long_term_memory_backends.Scope{scope}, "tea", 1)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "likes green tea", items[0].Content)
+}
@@ -0,0 +1,330 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// NewSessionService wraps a session service so that event contents and state values are encrypted
+// before they reach storage. State keys, event metadata and the session index stay in plaintext,
+// so listing and filtering by time keep working.
+//
+// Sessions returned by the wrapper must be passed back to it, not to the wrapped service.
+func NewSessionService(inner session.Service, encryptor *Encryptor) session.Service {
+	return &sessionService{inner: inner, encryptor: encryptor}
+}
+
+type sessionService struct {
+	inner     session.Service
+	encryptor *Encryptor
+}
+
+func (s *sessionService) Create(ctx context.Context, req *session.CreateRequest) (*session.CreateResponse, error) {
+	encReq := *req
+	state, err := s.encryptState(ctx, req.State)
+	if err != nil {
+		return nil, err
+	}
+	encReq.State = state
+	resp, err := s.inner.Create(ctx, &encReq)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := s.decryptSession(ctx, resp.Session)
+	if err != nil {
+		return nil, err
+	}
+	return &session.CreateResponse{Session: sess}, nil
+}
+
+func (s *sessionService) Get(ctx context.Context, req *session.GetRequest) (*session.GetResponse, error) {
+	resp, err := s.inner.Get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := s.decryptSession(ctx, resp.Session)
+	if err != nil {
+		return nil, err
+	}
+	return &session.GetResponse{Session: sess}, nil
+}
+
+func (s *sessionService) List(ctx context.Context, req *session.ListRequest) (*session.ListResponse, error) {
+	resp, err := s.inner.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]session.Session, 0, len(resp.Sessions))
+	for _, inner := range resp.Sessions {
+		sess, err := s.decryptSession(ctx, inner)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	return &session.ListResponse{Sessions: sessions}, nil
+}
+
+func (s *sessionService) Delete(ctx context.Context, req *session.DeleteRequest) error {
+	return s.inner.Delete(ctx, req)
+}
+
+func (s *sessionService) AppendEvent(ctx context.Context, curSession session.Session, event *session.Event) error {
+	if curSession == nil {
+		return fmt.Errorf("session is nil")
+	}
+	if event == nil {
+		return fmt.Errorf("event is nil")
+	}
+	if event.Partial {
+		return nil
+	}
+	sess, ok := curSession.(*encryptedSession)
+	if !ok {
+		return fmt.Errorf("unexpected session type %T for session ID %s", curSession, curSession.ID())
+	}
+
+	encEvent, err := s.encryptEvent(ctx, event)
+	if err != nil {
+		return err
+	}
+	if err := s.inner.AppendEvent(ctx, sess.inner, encEvent); err != nil {
+		return err
+	}
+	// storage may assign the id and timestamp
+	event.ID, event.Timestamp = encEvent.ID, encEvent.Timestamp
+	sess.appendEvent(event)
+	return nil
+}
+
+func (s *sessionService) encryptEvent(ctx context.Context, event *session.Event) (*session.Event, error) {
+	encEvent := *event
+	if event.Content != nil {
+		data, err := json.Marshal(event.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal event content: %w", err)
+		}
+		ciphertext, err := s.encryptor.Encrypt(ctx, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt event content: %w", err)
+		}
+		encEvent.Content = &genai.Content{Role: event.Content.Role, Parts: []*genai.Part{{Text: ciphertext}}}
+	}
+	delta, err := s.encryptState(ctx, event.Actions.StateDelta)
+	if err != nil {
+		return nil, err
+	}
+	encEvent.Actions.StateDelta = delta
+	return &encEvent, nil
+}
+
+func (s *sessionService) decryptEvent(ctx context.Context, event *session.Event) (*session.Event, error) {
+	decEvent := *event
+	if text, ok := encryptedContent(event.Content); ok {
+		data, err := s.encryptor.Decrypt(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt content of event %s: %w", event.ID, err)
+		}
+		var content genai.Content
+		if err := json.Unmarshal(data, &content); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal content of event %s: %w", event.ID, err)
+		}
+		decEvent.Content = &content
+	}
+	delta, err := s.decryptState(ctx, event.Actions.StateDelta)
+	if err != nil {
+		return nil, err
+	}
+	decEvent.Actions.StateDelta = delta
+	return &decEvent, nil
+}
+
+// encryptedContent returns the ciphertext of content written by encryptEvent.
+func encryptedContent(content *genai.Content) (string, bool) {
+	if content == nil || len(content.Parts) != 1 || content.Parts[0] == nil {
+		return "", false
+	}
+	text := content.Parts[0].Text
+	return text, IsEncrypted(text)
+}
+
+// encryptState encrypts every value of state as its JSON encoding.
+func (s *sessionService) encryptState(ctx context.Context, state map[string]any) (map[string]any, error) {
+	if state == nil {
+		return nil, nil
+	}
+	encrypted := make(map[string]any, len(state))
+	for key, value := range state {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal state %q: %w", key, err)
+		}
+		if encrypted[key], err = s.encryptor.Encrypt(ctx, data); err != nil {
+			return nil, fmt.Errorf("failed to encrypt state %q: %w", key, err)
+		}
+	}
+	return encrypted, nil
+}
+
+// decryptState reverts encryptState. Values stored before encryption was enabled are kept as is.
+func (s *sessionService) decryptState(ctx context.Context, state map[string]any) (map[string]any, error) {
+	if state == nil {
+		return nil, nil
+	}
+	decrypted := make(map[string]any, len(state))
+	for key, value := range state {
+		text, ok := value.(string)
+		if !ok || !IsEncrypted(text) {
+			decrypted[key] = value
+			continue
+		}
+		data, err := s.encryptor.Decrypt(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt state %q: %w", key, err)
+		}
+		var plain any
+		if err := json.Unmarshal(data, &plain); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal state %q: %w", key, err)
+		}
+		decrypted[key] = plain
+	}
+	return decrypted, nil
+}
+
+func (s *sessionService) decryptSession(ctx context.Context, inner session.Session) (*encryptedSession, error) {
+	state, err := s.decryptState(ctx, maps.Collect(inner.State().All()))
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		state = make(map[string]any)
+	}
+	sess := &encryptedSession{inner: inner, state: state, updatedAt: inner.LastUpdateTime()}
+	for event := range inner.Events().All() {
+		decEvent, err := s.decryptEvent(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		sess.events = append(sess.events, decEvent)
+	}
+	return sess, nil
+}
+
+// encryptedSession is the plaintext view of a session of the wrapped service.
+type encryptedSession struct {
+	inner session.Session
+
+	mu        sync.RWMutex
+	events    []*session.Event
+	state     map[string]any
+	updatedAt time.Time
+}
+
+func (s *encryptedSession) ID() string      { return s.inner.ID() }
+func (s *encryptedSession) AppName() string { return s.inner.AppName() }
+func (s *encryptedSession) UserID() string  { return s.inner.UserID() }
+
+func (s *encryptedSession) State() session.State {
+	return &plainState{session: s}
+}
+
+func (s *encryptedSession) Events() session.Events {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return plainEvents(slices.Clone(s.events))
+}
+
+func (s *encryptedSession) LastUpdateTime() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.updatedAt
+}
+
+// appendEvent applies a stored event to the plaintext view, keeping temporary state on the view only.
+func (s *encryptedSession) appendEvent(event *session.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	maps.Copy(s.state, event.Actions.StateDelta)
+	if len(event.Actions.StateDelta) > 0 {
+		delta := make(map[string]any, len(event.Actions.StateDelta))
+		for key, value := range event.Actions.StateDelta {
+			if !strings.HasPrefix(key, session.KeyPrefixTemp) {
+				delta[key] = value
+			}
+		}
+		event.Actions.StateDelta = delta
+	}
+	s.events = append(s.events, event)
+	s.updatedAt = s.inner.LastUpdateTime()
+}
+
+type plainState struct {
+	session *encryptedSession
+}
+
+func (s *plainState) Get(key string) (any, error) {
+	s.session.mu.RLock()
+	defer s.session.mu.RUnlock()
+	value, ok := s.session.state[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+	return value, nil
+}
+
+func (s *plainState) Set(key string, value any) error {
+	s.session.mu.Lock()
+	defer s.session.mu.Unlock()
+	s.session.state[key] = value
+	return nil
+}
+
+func (s *plainState) All() iter.Seq2[string, any] {
+	s.session.mu.RLock()
+	state := maps.Clone(s.session.state)
+	s.session.mu.RUnlock()
+	return maps.All(state)
+}
+
+type plainEvents []*session.Event
+
+func (e plainEvents) All() iter.Seq[*session.Event] {
+	return slices.Values(e)
+}
+
+func (e plainEvents) Len() int {
+	return len(e)
+}
+
+func (e plainEvents) At(i int) *session.Event {
+	if i >= 0 && i < len(e) {
+		return e[i]
+	}
+	return nil
+}
+
+var _ session.Service = (*sessionService)(nil)
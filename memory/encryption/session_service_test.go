@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+func TestSessionService(t *testing.T) {
+	ctx := context.Background()
+	enc := newTestEncryptor(t, "k1", testKey("k1", 1))
+	inner := session.InMemoryService()
+	svc := NewSessionService(inner, enc)
+
+	created, err := svc.Create(ctx, &session.CreateRequest{
+		AppName: "app1", UserID: "user1", SessionID: "s1",
+		State: map[string]any{"email": "alice@example.com", "app:plan": "pro"},
+	})
+	require.NoError(t, err)
+	email, err := created.Session.State().Get("email")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", email)
+
+	event := session.NewEvent("inv")
+	event.Author = "user"
+	event.Content = genai.NewContentFromText("my card is 4111", genai.RoleUser)
+	event.Actions.StateDelta = map[string]any{"count": 1, "temp:draft": "x"}
+	require.NoError(t, svc.AppendEvent(ctx, created.Session, event))
+	draft, err := created.Session.State().Get("temp:draft")
+	require.NoError(t, err)
+	assert.Equal(t, "x", draft)
+
+	t.Run("stored encrypted", func(t *testing.T) {
+		raw, err := inner.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "s1"})
+		require.NoError(t, err)
+		for key, value := range raw.Session.State().All() {
+			assert.True(t, IsEncrypted(value.(string)), key)
+		}
+		stored := raw.Session.Events().At(0)
+		assert.True(t, IsEncrypted(stored.Content.Parts[0].Text))
+		assert.Equal(t, genai.RoleUser, stored.Content.Role)
+		assert.NotContains(t, stored.Content.Parts[0].Text, "4111")
+	})
+
+	t.Run("read back decrypted", func(t *testing.T) {
+		got, err := svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "s1"})
+		require.NoError(t, err)
+		require.Equal(t, 1, got.Session.Events().Len())
+		assert.Equal(t, "my card is 4111", got.Session.Events().At(0).Content.Parts[0].Text)
+		assert.Equal(t, map[string]any{"count": float64(1)}, got.Session.Events().At(0).Actions.StateDelta)
+		plan, err := got.Session.State().Get("app:plan")
+		require.NoError(t, err)
+		assert.Equal(t, "pro", plan)
+
+		listed, err := svc.List(ctx, &session.ListRequest{AppName: "app1", UserID: "user1"})
+		require.NoError(t, err)
+		require.Len(t, listed.Sessions, 1)
+		email, err := listed.Sessions[0].State().Get("email")
+		require.NoError(t, err)
+		assert.Equal(t, "alice@example.com", email)
+	})
+
+	t.Run("rejects foreign sessions", func(t *testing.T) {
+		raw, err := inner.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "s1"})
+		require.NoError(t, err)
+		err = svc.AppendEvent(ctx, raw.Session, session.NewEvent("inv"))
+		assert.ErrorContains(t, err, "unexpected session type")
+	})
+
+	t.Run("plaintext sessions stay readable", func(t *testing.T) {
+		_, err := inner.Create(ctx, &session.CreateRequest{AppName: "app1", UserID: "user1", SessionID: "legacy", State: map[string]any{"k": "v"}})
+		require.NoError(t, err)
+		got, err := svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "legacy"})
+		require.NoError(t, err)
+		value, err := got.Session.State().Get("k")
+		require.NoError(t, err)
+		assert.Equal(t, "v", value)
+	})
+}
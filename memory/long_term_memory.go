@@ -37,6 +37,13 @@ const (
 // If config is nil, it will use the default config with backend
 // If config is not nil, it will use the config.
 func NewLongTermMemoryService(backend LongTermBackendType, config interface{}, topK ...int) (memory.Service, error) {
+	return NewScopedLongTermMemoryService(backend, config, long_term_memory_backends.ScopeConfig{}, topK...)
+}
+
+// NewScopedLongTermMemoryService creates a long term memory service that saves and searches
+// memories in the scope described by scope, e.g. per app or per agent plus shared namespaces.
+// The local backend is keyed on app and user by ADK and only supports the user and app levels.
+func NewScopedLongTermMemoryService(backend LongTermBackendType, config interface{}, scope long_term_memory_backends.ScopeConfig, topK ...int) (memory.Service, error) {
 	var memoryService memory.Service
 
 	if backend == "" {
@@ -48,6 +55,9 @@ func NewLongTermMemoryService(backend LongTermBackendType, config interface{}, t
 
 	switch backend {
 	case BackendLongTermLocal:
+		if scope.Level == long_term_memory_backends.ScopeAgent || len(scope.SharedNamespaces) > 0 {
+			return nil, fmt.Errorf("local backend only supports user and app scopes without shared namespaces, got %q", scope.Level)
+		}
 		memoryService = memory.InMemoryService()
 	case BackendLongTermViking:
 		var vikingDBMemoryConfig *long_term_memory_backends.VikingDbMemoryConfig
@@ -65,7 +75,7 @@ func NewLongTermMemoryService(backend LongTermBackendType, config interface{}, t
 		if err != nil {
 			return nil, err
 		}
-		return long_term_memory_backends.LongTermMemoryFactory(vikingBackend, topK[0]), nil
+		return long_term_memory_backends.LongTermMemoryFactory(vikingBackend, topK[0], long_term_memory_backends.WithScopeConfig(scope)), nil
 	case BackendLongTermMem0:
 		var mem0MemoryConfig *long_term_memory_backends.Mem0MemoryConfig
 		if config == nil {
@@ -81,7 +91,7 @@ func NewLongTermMemoryService(backend LongTermBackendType, config interface{}, t
 		if err != nil {
 			return nil, err
 		}
-		return long_term_memory_backends.LongTermMemoryFactory(mem0Backend, topK[0]), nil
+		return long_term_memory_backends.LongTermMemoryFactory(mem0Backend, topK[0], long_term_memory_backends.WithScopeConfig(scope)), nil
 	case BackendLongTermRedis:
 		var redisConfig *long_term_memory_backends.RedisMemoryConfig
 		if config == nil {
@@ -97,7 +107,7 @@ func NewLongTermMemoryService(backend LongTermBackendType, config interface{}, t
 		if err != nil {
 			return nil, err
 		}
-		return long_term_memory_backends.LongTermMemoryFactory(redisBackend, topK[0]), nil
+		return long_term_memory_backends.LongTermMemoryFactory(redisBackend, topK[0], long_term_memory_backends.WithScopeConfig(scope)), nil
 	case BackendLongTermOpenSearch:
 		var osConfig *long_term_memory_backends.OpenSearchMemoryConfig
 		if config == nil {
@@ -113,7 +123,7 @@ func NewLongTermMemoryService(backend LongTermBackendType, config interface{}, t
 		if err != nil {
 			return nil, err
 		}
-		return long_term_memory_backends.LongTermMemoryFactory(osBackend, topK[0]), nil
+		return long_term_memory_backends.LongTermMemoryFactory(osBackend, topK[0], long_term_memory_backends.WithScopeConfig(scope)), nil
 	default:
 		return nil, fmt.Errorf("unsupported backend type: %s", backend)
 	}
@@ -17,6 +17,8 @@ package long_term_memory_backends
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"google.golang.org/adk/memory"
@@ -29,21 +31,106 @@ type MemItem struct {
 	Timestamp time.Time
 }
 
+// LongTermMemoryBackend stores and searches memories. Search results span all the given scopes.
 type LongTermMemoryBackend interface {
-	SaveMemory(ctx context.Context, userId string, eventList []string) error
-	SearchMemory(ctx context.Context, userId, query string, topK int) ([]*MemItem, error)
+	SaveMemory(ctx context.Context, scope Scope, eventList []string) error
+	SearchMemory(ctx context.Context, scopes []Scope, query string, topK int) ([]*MemItem, error)
 }
 
-func LongTermMemoryFactory(backend LongTermMemoryBackend, tokK int) memory.Service {
-	return &basicLongTermMemory{
+// TextCodec transforms memory texts at the storage boundary, e.g. to encrypt them at rest.
+type TextCodec interface {
+	EncodeText(ctx context.Context, text string) (string, error)
+	DecodeText(ctx context.Context, stored string) (string, error)
+}
+
+// TextCodecBackend is a LongTermMemoryBackend that embeds texts itself. With a codec it computes
+// embeddings from the plaintext and only stores encoded texts, so memories stay searchable.
+type TextCodecBackend interface {
+	LongTermMemoryBackend
+	WithTextCodec(codec TextCodec) LongTermMemoryBackend
+}
+
+// encodeTexts applies codec to the texts to store. A nil codec stores them as is.
+func encodeTexts(ctx context.Context, codec TextCodec, texts []string) ([]string, error) {
+	if codec == nil {
+		return texts, nil
+	}
+	encoded := make([]string, len(texts))
+	for i, text := range texts {
+		var err error
+		if encoded[i], err = codec.EncodeText(ctx, text); err != nil {
+			return nil, fmt.Errorf("failed to encode memory text: %w", err)
+		}
+	}
+	return encoded, nil
+}
+
+// decodeItems reverts codec on searched memories in place.
+func decodeItems(ctx context.Context, codec TextCodec, items []*MemItem) ([]*MemItem, error) {
+	if codec == nil {
+		return items, nil
+	}
+	for _, item := range items {
+		var err error
+		if item.Content, err = codec.DecodeText(ctx, item.Content); err != nil {
+			return nil, fmt.Errorf("failed to decode memory text: %w", err)
+		}
+	}
+	return items, nil
+}
+
+// ScopedMemoryService is a memory.Service that can also write texts to an explicit scope,
+// e.g. seeding org-wide shared memories.
+type ScopedMemoryService interface {
+	memory.Service
+	AddMemoryToScope(ctx context.Context, scope Scope, texts []string) error
+}
+
+type LongTermMemoryOption func(*basicLongTermMemory)
+
+// WithScopeConfig sets the scope sessions are saved to and searched in.
+func WithScopeConfig(cfg ScopeConfig) LongTermMemoryOption {
+	return func(b *basicLongTermMemory) { b.scope = cfg }
+}
+
+func LongTermMemoryFactory(backend LongTermMemoryBackend, tokK int, opts ...LongTermMemoryOption) memory.Service {
+	b := &basicLongTermMemory{
 		backend: backend,
 		topK:    tokK,
 	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
 }
 
 type basicLongTermMemory struct {
 	backend LongTermMemoryBackend
 	topK    int
+	scope   ScopeConfig
+}
+
+// pinger is implemented by backends that can be checked without a search.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks the backend without searching it, for readiness probes. Backends that
+// cannot be pinged are assumed to be ready.
+func (b *basicLongTermMemory) Ping(ctx context.Context) error {
+	if p, ok := b.backend.(pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// scopeKeys joins scope keys for logging.
+func scopeKeys(scopes []Scope) string {
+	keys := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		keys = append(keys, scope.Key())
+	}
+	return strings.Join(keys, ",")
 }
 
 func (*basicLongTermMemory) filterAndConvertEvents(s session.Session) []string {
@@ -60,13 +147,27 @@ func (*basicLongTermMemory) filterAndConvertEvents(s session.Session) []string {
 }
 
 func (b *basicLongTermMemory) AddSessionToMemory(ctx context.Context, s session.Session) error {
-	userId := s.UserID()
+	scope, err := b.scope.sessionScope(ctx, s)
+	if err != nil {
+		return err
+	}
 	events := b.filterAndConvertEvents(s)
-	return b.backend.SaveMemory(ctx, userId, events)
+	return b.backend.SaveMemory(ctx, scope, events)
+}
+
+func (b *basicLongTermMemory) AddMemoryToScope(ctx context.Context, scope Scope, texts []string) error {
+	if err := scope.Validate(); err != nil {
+		return err
+	}
+	return b.backend.SaveMemory(ctx, scope, texts)
 }
 
 func (b *basicLongTermMemory) SearchMemory(ctx context.Context, req *memory.SearchRequest) (*memory.SearchResponse, error) {
-	result, err := b.backend.SearchMemory(ctx, req.UserID, req.Query, b.topK)
+	scopes, err := b.scope.searchScopes(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := b.backend.SearchMemory(ctx, scopes, req.Query, b.topK)
 	if err != nil {
 		return nil, err
 	}
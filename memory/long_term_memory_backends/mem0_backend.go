@@ -75,8 +75,14 @@ func NewMem0MemoryBackend(config *Mem0MemoryConfig) (LongTermMemoryBackend, erro
 	return backend, nil
 }
 
-func (mem *Mem0MemoryBackend) SaveMemory(ctx context.Context, userId string, eventList []string) error {
+// Ping checks that the Mem0 server is reachable.
+func (mem *Mem0MemoryBackend) Ping(ctx context.Context) error {
+	return mem.client.Ping(ctx)
+}
+
+func (mem *Mem0MemoryBackend) SaveMemory(ctx context.Context, scope Scope, eventList []string) error {
 	asyncMode := true
+	userId := scope.Key()
 	for _, event := range eventList {
 		_, err := mem.client.Add(ctx, mem0.AddMemoriesRequest{
 			Messages: []mem0.Message{
@@ -92,30 +98,54 @@ func (mem *Mem0MemoryBackend) SaveMemory(ctx context.Context, userId string, eve
 			return fmt.Errorf("failed to save memory to Mem0: %w", err)
 		}
 	}
-	log.Infof("Successfully saved user %s %d events to Mem0", userId, len(eventList))
+	log.Infof("Successfully saved scope %s %d events to Mem0", userId, len(eventList))
 	return nil
 }
 
-func (mem *Mem0MemoryBackend) SearchMemory(ctx context.Context, userId, query string, topK int) ([]*MemItem, error) {
-	log.Infof("Searching Mem0 for query: %s, user: %s, top_k: %d", query, userId, topK)
-
-	var memResp []*MemItem
+// SearchMemory searches each scope separately, since Mem0 filters on a single user id,
+// and interleaves the per-scope results.
+func (mem *Mem0MemoryBackend) SearchMemory(ctx context.Context, scopes []Scope, query string, topK int) ([]*MemItem, error) {
+	log.Infof("Searching Mem0 for query: %s, scopes: %s, top_k: %d", query, scopeKeys(scopes), topK)
+
+	var scopeResults [][]*MemItem
+	for _, scope := range scopes {
+		userId := scope.Key()
+		result, err := mem.client.Search(ctx, mem0.SearchMemoriesRequest{
+			Query:  query,
+			UserId: &userId,
+			TopK:   &topK,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search memory from Mem0: %w", err)
+		}
 
-	result, err := mem.client.Search(ctx, mem0.SearchMemoriesRequest{
-		Query:  query,
-		UserId: &userId,
-		TopK:   &topK,
-	})
-	if err != nil {
-		return memResp, fmt.Errorf("failed to search memory from Mem0: %w", err)
+		var items []*MemItem
+		for _, v := range result.Results {
+			items = append(items, &MemItem{
+				Content:   v.Memory,
+				Timestamp: v.CreatedAt,
+			})
+		}
+		scopeResults = append(scopeResults, items)
 	}
 
-	for _, v := range result.Results {
-		memResp = append(memResp, &MemItem{
-			Content:   v.Memory,
-			Timestamp: v.CreatedAt,
-		})
-	}
+	return interleaveMemItems(scopeResults, topK), nil
+}
 
-	return memResp, nil
+// interleaveMemItems merges ranked result lists round-robin, keeping at most topK items.
+func interleaveMemItems(lists [][]*MemItem, topK int) []*MemItem {
+	var merged []*MemItem
+	for i := 0; len(merged) < topK; i++ {
+		added := false
+		for _, list := range lists {
+			if i < len(list) && len(merged) < topK {
+				merged = append(merged, list[i])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return merged
 }
@@ -108,7 +108,7 @@ func TestMem0MemoryBackend_SaveMemory(t *testing.T) {
 				return mem0.AddMemoriesResponse{}, nil
 			}).Build()
 
-			err := backend.SaveMemory(ctx, "test_user", eventList)
+			err := backend.SaveMemory(ctx, UserScope("test_user"), eventList)
 			assert.Nil(t, err)
 			assert.Equal(t, 2, callCount)
 		})
@@ -117,7 +117,7 @@ func TestMem0MemoryBackend_SaveMemory(t *testing.T) {
 			eventList := []string{"event1"}
 			mockey.Mock((*mem0.Mem0Client).Add).Return(mem0.AddMemoriesResponse{}, errors.New("add error")).Build()
 
-			err := backend.SaveMemory(ctx, "test_user", eventList)
+			err := backend.SaveMemory(ctx, UserScope("test_user"), eventList)
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), "failed to save memory to Mem0")
 		})
@@ -147,7 +147,7 @@ func TestMem0MemoryBackend_SearchMemory(t *testing.T) {
 				},
 			}, nil).Build()
 
-			results, err := backend.SearchMemory(ctx, "test_user", "test query", 10)
+			results, err := backend.SearchMemory(ctx, []Scope{UserScope("test_user")}, "test query", 10)
 
 			assert.Nil(t, err)
 			assert.NotNil(t, results)
@@ -161,7 +161,7 @@ func TestMem0MemoryBackend_SearchMemory(t *testing.T) {
 				Results: []mem0.MemoryItem{},
 			}, nil).Build()
 
-			results, err := backend.SearchMemory(ctx, "test_user", "test query", 10)
+			results, err := backend.SearchMemory(ctx, []Scope{UserScope("test_user")}, "test query", 10)
 
 			assert.Nil(t, err)
 			assert.Equal(t, 0, len(results))
@@ -170,7 +170,7 @@ func TestMem0MemoryBackend_SearchMemory(t *testing.T) {
 		mockey.PatchConvey("Failure", func() {
 			mockey.Mock((*mem0.Mem0Client).Search).Return(mem0.SearchMemoriesResponse{}, errors.New("search error")).Build()
 
-			results, err := backend.SearchMemory(ctx, "test_user", "test query", 10)
+			results, err := backend.SearchMemory(ctx, []Scope{UserScope("test_user")}, "test query", 10)
 
 			assert.Nil(t, results)
 			assert.NotNil(t, err)
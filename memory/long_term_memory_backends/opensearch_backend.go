@@ -64,6 +64,7 @@ type OpenSearchMemoryBackend struct {
 	httpClient *http.Client
 	baseURL    string
 	embedder   model.Embedder
+	codec      TextCodec
 }
 
 // NewOpenSearchMemoryBackend creates a new OpenSearch-backed long-term memory backend.
@@ -140,6 +141,21 @@ func validateIndexName(name string) error {
 	return nil
 }
 
+// indexNamePart escapes a scope name into the characters allowed in index names. Everything
+// but [a-z0-9] is written as '-' and two hex digits, so '_' can separate names without collisions.
+func indexNamePart(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "-%02x", c)
+		}
+	}
+	return b.String()
+}
+
 // ensureIndex creates an OpenSearch index with the appropriate mapping if it doesn't exist.
 func (o *OpenSearchMemoryBackend) ensureIndex(ctx context.Context, indexName string) error {
 	dim := o.config.EmbeddingConfig.Dimensions
@@ -192,13 +208,29 @@ func (o *OpenSearchMemoryBackend) ensureIndex(ctx context.Context, indexName str
 	return fmt.Errorf("failed to create opensearch index %q: status=%d, body=%s", indexName, resp.StatusCode, string(respBody))
 }
 
-func (o *OpenSearchMemoryBackend) SaveMemory(ctx context.Context, userId string, eventList []string) error {
+// scopeIndexName returns the index holding the memories of a scope.
+func (o *OpenSearchMemoryBackend) scopeIndexName(scope Scope) (string, error) {
+	// user scopes keep the index of the user id, as before scopes were introduced
+	indexName := o.config.Index + "_" + scope.UserID
+	if prefix, names := scope.segments(); prefix != "" {
+		indexName = o.config.Index + "_" + prefix
+		for _, name := range names {
+			indexName += "_" + indexNamePart(name)
+		}
+	}
+	if err := validateIndexName(indexName); err != nil {
+		return "", err
+	}
+	return indexName, nil
+}
+
+func (o *OpenSearchMemoryBackend) SaveMemory(ctx context.Context, scope Scope, eventList []string) error {
 	if len(eventList) == 0 {
 		return nil
 	}
 
-	indexName := fmt.Sprintf("%s_%s", o.config.Index, userId)
-	if err := validateIndexName(indexName); err != nil {
+	indexName, err := o.scopeIndexName(scope)
+	if err != nil {
 		return err
 	}
 
@@ -210,10 +242,14 @@ func (o *OpenSearchMemoryBackend) SaveMemory(ctx context.Context, userId string,
 	if err != nil {
 		return fmt.Errorf("failed to embed texts for opensearch: %w", err)
 	}
+	stored, err := encodeTexts(ctx, o.codec, eventList)
+	if err != nil {
+		return err
+	}
 
 	// Build bulk request body
 	var buf bytes.Buffer
-	for i, event := range eventList {
+	for i, text := range stored {
 		id, err := uuid.NewUUID()
 		if err != nil {
 			return fmt.Errorf("generate uuid failed: %w", err)
@@ -226,7 +262,7 @@ func (o *OpenSearchMemoryBackend) SaveMemory(ctx context.Context, userId string,
 			},
 		}
 		doc := map[string]interface{}{
-			"text":      event,
+			"text":      text,
 			"timestamp": time.Now().UnixMilli(),
 			"vector":    resp.Embeddings[i],
 		}
@@ -250,16 +286,20 @@ func (o *OpenSearchMemoryBackend) SaveMemory(ctx context.Context, userId string,
 		return fmt.Errorf("opensearch bulk index failed: status=%d, body=%s", bulkResp.StatusCode, string(respBody))
 	}
 
-	log.Infof("Successfully saved user %s %d events to OpenSearch", userId, len(eventList))
+	log.Infof("Successfully saved scope %s %d events to OpenSearch", scope.Key(), len(eventList))
 	return nil
 }
 
-func (o *OpenSearchMemoryBackend) SearchMemory(ctx context.Context, userId, query string, topK int) ([]*MemItem, error) {
-	log.Infof("Searching OpenSearch for query: %s, user: %s, top_k: %d", query, userId, topK)
+func (o *OpenSearchMemoryBackend) SearchMemory(ctx context.Context, scopes []Scope, query string, topK int) ([]*MemItem, error) {
+	log.Infof("Searching OpenSearch for query: %s, scopes: %s, top_k: %d", query, scopeKeys(scopes), topK)
 
-	indexName := fmt.Sprintf("%s_%s", o.config.Index, userId)
-	if err := validateIndexName(indexName); err != nil {
-		return nil, err
+	indexNames := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		indexName, err := o.scopeIndexName(scope)
+		if err != nil {
+			return nil, err
+		}
+		indexNames = append(indexNames, indexName)
 	}
 
 	resp, err := o.embedder.EmbedTexts(ctx, &model.EmbeddingRequest{Texts: []string{query}})
@@ -281,7 +321,9 @@ func (o *OpenSearchMemoryBackend) SearchMemory(ctx context.Context, userId, quer
 	}
 
 	body, _ := json.Marshal(searchBody)
-	searchResp, err := o.doRequest(ctx, http.MethodPost, "/"+indexName+"/_search", body)
+	// Scopes without any memories yet have no index, skip them instead of failing the whole search
+	searchPath := "/" + strings.Join(indexNames, ",") + "/_search?ignore_unavailable=true"
+	searchResp, err := o.doRequest(ctx, http.MethodPost, searchPath, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search opensearch: %w", err)
 	}
@@ -300,7 +342,18 @@ func (o *OpenSearchMemoryBackend) SearchMemory(ctx context.Context, userId, quer
 		return nil, fmt.Errorf("opensearch search failed: status=%d, body=%s", searchResp.StatusCode, string(respBody))
 	}
 
-	return parseOpenSearchResults(respBody)
+	items, err := parseOpenSearchResults(respBody)
+	if err != nil {
+		return nil, err
+	}
+	return decodeItems(ctx, o.codec, items)
+}
+
+// WithTextCodec returns a copy of the backend storing texts encoded by codec.
+func (o *OpenSearchMemoryBackend) WithTextCodec(codec TextCodec) LongTermMemoryBackend {
+	clone := *o
+	clone.codec = codec
+	return &clone
 }
 
 func parseOpenSearchResults(respBody []byte) ([]*MemItem, error) {
@@ -331,6 +384,19 @@ func parseOpenSearchResults(respBody []byte) ([]*MemItem, error) {
 	return items, nil
 }
 
+// Ping checks that the cluster answers its health API.
+func (o *OpenSearchMemoryBackend) Ping(ctx context.Context) error {
+	resp, err := o.doRequest(ctx, http.MethodGet, "/_cluster/health", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("opensearch cluster health returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 func (o *OpenSearchMemoryBackend) doRequest(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
 	url := o.baseURL + path
 	var bodyReader io.Reader
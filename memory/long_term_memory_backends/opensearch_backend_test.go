@@ -135,12 +135,12 @@ func TestOpenSearchMemoryBackend_SaveMemory(t *testing.T) {
 		}
 
 		mockey.PatchConvey("empty event list", func() {
-			err := backend.SaveMemory(context.Background(), "user1", []string{})
+			err := backend.SaveMemory(context.Background(), UserScope("user1"), []string{})
 			assert.Nil(t, err)
 		})
 
 		mockey.PatchConvey("invalid index name", func() {
-			err := backend.SaveMemory(context.Background(), "USER_UPPER", []string{"event1"})
+			err := backend.SaveMemory(context.Background(), UserScope("USER_UPPER"), []string{"event1"})
 			assert.NotNil(t, err)
 			assert.ErrorIs(t, err, ErrInvalidIndexName)
 		})
@@ -152,7 +152,7 @@ func TestOpenSearchMemoryBackend_SaveMemory(t *testing.T) {
 				},
 			}
 			mockey.Mock((*OpenSearchMemoryBackend).ensureIndex).Return(nil).Build()
-			err := backend.SaveMemory(context.Background(), "user1", []string{"event1"})
+			err := backend.SaveMemory(context.Background(), UserScope("user1"), []string{"event1"})
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), "failed to embed texts")
 		})
@@ -164,7 +164,7 @@ func TestOpenSearchMemoryBackend_SaveMemory(t *testing.T) {
 				Body:       io.NopCloser(strings.NewReader(`{"errors":false}`)),
 			}, nil).Build()
 
-			err := backend.SaveMemory(context.Background(), "user1", []string{"event1", "event2"})
+			err := backend.SaveMemory(context.Background(), UserScope("user1"), []string{"event1", "event2"})
 			assert.Nil(t, err)
 		})
 	})
@@ -190,7 +190,7 @@ func TestOpenSearchMemoryBackend_SearchMemory(t *testing.T) {
 		}
 
 		mockey.PatchConvey("invalid index name", func() {
-			results, err := backend.SearchMemory(context.Background(), "USER_UPPER", "query", 5)
+			results, err := backend.SearchMemory(context.Background(), []Scope{UserScope("USER_UPPER")}, "query", 5)
 			assert.Nil(t, results)
 			assert.NotNil(t, err)
 			assert.ErrorIs(t, err, ErrInvalidIndexName)
@@ -202,7 +202,7 @@ func TestOpenSearchMemoryBackend_SearchMemory(t *testing.T) {
 					return nil, errors.New("embed error")
 				},
 			}
-			results, err := backend.SearchMemory(context.Background(), "user1", "query", 5)
+			results, err := backend.SearchMemory(context.Background(), []Scope{UserScope("user1")}, "query", 5)
 			assert.Nil(t, results)
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), "failed to embed query")
@@ -222,7 +222,7 @@ func TestOpenSearchMemoryBackend_SearchMemory(t *testing.T) {
 				Body:       io.NopCloser(strings.NewReader(responseBody)),
 			}, nil).Build()
 
-			results, err := backend.SearchMemory(context.Background(), "user1", "query", 5)
+			results, err := backend.SearchMemory(context.Background(), []Scope{UserScope("user1")}, "query", 5)
 			assert.Nil(t, err)
 			assert.Equal(t, 2, len(results))
 			assert.Equal(t, "memory 1", results[0].Content)
@@ -235,7 +235,7 @@ func TestOpenSearchMemoryBackend_SearchMemory(t *testing.T) {
 				Body:       io.NopCloser(strings.NewReader(`{"error":{"type":"index_not_found_exception"}}`)),
 			}, nil).Build()
 
-			results, err := backend.SearchMemory(context.Background(), "user1", "query", 5)
+			results, err := backend.SearchMemory(context.Background(), []Scope{UserScope("user1")}, "query", 5)
 			assert.Nil(t, err)
 			assert.Nil(t, results)
 		})
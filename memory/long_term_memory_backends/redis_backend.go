@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -51,6 +52,7 @@ type RedisMemoryBackend struct {
 	config   *RedisMemoryConfig
 	client   *redis.Client
 	embedder model.Embedder
+	codec    TextCodec
 }
 
 // NewRedisMemoryBackend creates a new Redis-backed long-term memory backend.
@@ -90,6 +92,11 @@ func NewRedisMemoryBackend(config *RedisMemoryConfig) (LongTermMemoryBackend, er
 	return backend, nil
 }
 
+// Ping checks the Redis connection.
+func (r *RedisMemoryBackend) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
 // ensureIndex creates the RediSearch vector index if it does not already exist.
 func (r *RedisMemoryBackend) ensureIndex(ctx context.Context) error {
 	indexName := r.config.Index
@@ -121,7 +128,7 @@ func (r *RedisMemoryBackend) ensureIndex(ctx context.Context) error {
 	return nil
 }
 
-func (r *RedisMemoryBackend) SaveMemory(ctx context.Context, userId string, eventList []string) error {
+func (r *RedisMemoryBackend) SaveMemory(ctx context.Context, scope Scope, eventList []string) error {
 	if len(eventList) == 0 {
 		return nil
 	}
@@ -130,18 +137,22 @@ func (r *RedisMemoryBackend) SaveMemory(ctx context.Context, userId string, even
 	if err != nil {
 		return fmt.Errorf("failed to embed texts for redis: %w", err)
 	}
+	stored, err := encodeTexts(ctx, r.codec, eventList)
+	if err != nil {
+		return err
+	}
 
 	pipe := r.client.Pipeline()
-	for i, event := range eventList {
+	for i, text := range stored {
 		id, err := uuid.NewUUID()
 		if err != nil {
 			return fmt.Errorf("generate uuid failed: %w", err)
 		}
-		key := fmt.Sprintf("%s:%s:%s", r.config.Index, userId, id.String())
+		key := fmt.Sprintf("%s:%s:%s", r.config.Index, scope.Key(), id.String())
 		vectorBytes := float32SliceToBytes(resp.Embeddings[i])
 
 		pipe.HSet(ctx, key, map[string]interface{}{
-			"text":      event,
+			"text":      text,
 			"timestamp": time.Now().UnixMilli(),
 			"vector":    vectorBytes,
 		})
@@ -151,12 +162,12 @@ func (r *RedisMemoryBackend) SaveMemory(ctx context.Context, userId string, even
 		return fmt.Errorf("failed to save memories to redis: %w", err)
 	}
 
-	log.Infof("Successfully saved user %s %d events to Redis", userId, len(eventList))
+	log.Infof("Successfully saved scope %s %d events to Redis", scope.Key(), len(eventList))
 	return nil
 }
 
-func (r *RedisMemoryBackend) SearchMemory(ctx context.Context, userId, query string, topK int) ([]*MemItem, error) {
-	log.Infof("Searching Redis for query: %s, user: %s, top_k: %d", query, userId, topK)
+func (r *RedisMemoryBackend) SearchMemory(ctx context.Context, scopes []Scope, query string, topK int) ([]*MemItem, error) {
+	log.Infof("Searching Redis for query: %s, scopes: %s, top_k: %d", query, scopeKeys(scopes), topK)
 
 	resp, err := r.embedder.EmbedTexts(ctx, &model.EmbeddingRequest{Texts: []string{query}})
 	if err != nil {
@@ -165,9 +176,8 @@ func (r *RedisMemoryBackend) SearchMemory(ctx context.Context, userId, query str
 
 	queryVector := float32SliceToBytes(resp.Embeddings[0])
 
-	// FT.SEARCH with KNN and prefix filter for user isolation
-	searchQuery := fmt.Sprintf("@__key:{%s\\:%s\\:*}=>[KNN %d @vector $BLOB AS score]",
-		escapeRedisTag(r.config.Index), escapeRedisTag(userId), topK)
+	// FT.SEARCH with KNN and prefix filter for scope isolation
+	searchQuery := fmt.Sprintf("%s=>[KNN %d @vector $BLOB AS score]", r.scopeFilter(scopes), topK)
 
 	cmd := r.client.Do(ctx, "FT.SEARCH", r.config.Index,
 		searchQuery,
@@ -186,7 +196,27 @@ func (r *RedisMemoryBackend) SearchMemory(ctx context.Context, userId, query str
 		return nil, fmt.Errorf("failed to parse redis search results: %w", err)
 	}
 
-	return parseRedisSearchResults(results), nil
+	return decodeItems(ctx, r.codec, parseRedisSearchResults(results))
+}
+
+// WithTextCodec returns a copy of the backend storing texts encoded by codec.
+func (r *RedisMemoryBackend) WithTextCodec(codec TextCodec) LongTermMemoryBackend {
+	clone := *r
+	clone.codec = codec
+	return &clone
+}
+
+// scopeFilter builds a key prefix filter matching any of the scopes.
+func (r *RedisMemoryBackend) scopeFilter(scopes []Scope) string {
+	filters := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		filters = append(filters, fmt.Sprintf("@__key:{%s\\:%s\\:*}",
+			escapeRedisTag(r.config.Index), escapeRedisTag(scope.Key())))
+	}
+	if len(filters) == 1 {
+		return filters[0]
+	}
+	return "(" + strings.Join(filters, " | ") + ")"
 }
 
 // parseRedisSearchResults parses FT.SEARCH results into MemItem slice.
@@ -238,7 +268,7 @@ func escapeRedisTag(s string) string {
 	var result []byte
 	for i := 0; i < len(s); i++ {
 		switch s[i] {
-		case '-', '.', ':', '/':
+		case '-', '.', ':', '/', '%', '+', '~':
 			result = append(result, '\\', s[i])
 		default:
 			result = append(result, s[i])
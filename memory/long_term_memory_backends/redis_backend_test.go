@@ -94,7 +94,7 @@ func TestRedisMemoryBackend_SaveMemory(t *testing.T) {
 		}
 
 		mockey.PatchConvey("empty event list", func() {
-			err := backend.SaveMemory(context.Background(), "user1", []string{})
+			err := backend.SaveMemory(context.Background(), UserScope("user1"), []string{})
 			assert.Nil(t, err)
 		})
 
@@ -104,7 +104,7 @@ func TestRedisMemoryBackend_SaveMemory(t *testing.T) {
 					return nil, errors.New("embed error")
 				},
 			}
-			err := backend.SaveMemory(context.Background(), "user1", []string{"event1"})
+			err := backend.SaveMemory(context.Background(), UserScope("user1"), []string{"event1"})
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), "failed to embed texts")
 		})
@@ -112,7 +112,7 @@ func TestRedisMemoryBackend_SaveMemory(t *testing.T) {
 		mockey.PatchConvey("success", func() {
 			mockey.Mock((*redis.Pipeline).Exec).Return(nil, nil).Build()
 			mockey.Mock((*redis.Pipeline).HSet).Return(redis.NewIntCmd(context.Background())).Build()
-			err := backend.SaveMemory(context.Background(), "user1", []string{"event1", "event2"})
+			err := backend.SaveMemory(context.Background(), UserScope("user1"), []string{"event1", "event2"})
 			assert.Nil(t, err)
 		})
 	})
@@ -139,7 +139,7 @@ func TestRedisMemoryBackend_SearchMemory(t *testing.T) {
 					return nil, errors.New("embed error")
 				},
 			}
-			results, err := backend.SearchMemory(context.Background(), "user1", "query", 5)
+			results, err := backend.SearchMemory(context.Background(), []Scope{UserScope("user1")}, "query", 5)
 			assert.Nil(t, results)
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), "failed to embed query")
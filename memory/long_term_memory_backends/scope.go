@@ -0,0 +1,227 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package long_term_memory_backends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/session"
+)
+
+var ErrInvalidScope = errors.New("invalid memory scope")
+
+// ScopeLevel identifies the namespace a memory is stored in.
+type ScopeLevel string
+
+const (
+	// ScopeUser keys memories on the user only. Memories are visible to every app and agent of that user.
+	ScopeUser ScopeLevel = "user"
+	// ScopeApp keys memories on app name and user.
+	ScopeApp ScopeLevel = "app"
+	// ScopeAgent keys memories on app name, agent name and user.
+	ScopeAgent ScopeLevel = "agent"
+	// ScopeShared keys memories on a namespace only, so every user can read them.
+	ScopeShared ScopeLevel = "shared"
+)
+
+// Scope is the namespace memories are saved to and searched in.
+type Scope struct {
+	Level     ScopeLevel
+	AppName   string
+	AgentName string
+	UserID    string
+	// Namespace names the shared memory pool, only used by ScopeShared.
+	Namespace string
+}
+
+func UserScope(userID string) Scope {
+	return Scope{Level: ScopeUser, UserID: userID}
+}
+
+func AppScope(appName, userID string) Scope {
+	return Scope{Level: ScopeApp, AppName: appName, UserID: userID}
+}
+
+func AgentScope(appName, agentName, userID string) Scope {
+	return Scope{Level: ScopeAgent, AppName: appName, AgentName: agentName, UserID: userID}
+}
+
+func SharedScope(namespace string) Scope {
+	return Scope{Level: ScopeShared, Namespace: namespace}
+}
+
+// Validate checks that the fields required by the scope level are set.
+func (s Scope) Validate() error {
+	var missing []string
+	switch s.Level {
+	case ScopeUser, "":
+		if s.UserID == "" {
+			missing = append(missing, "user id")
+		}
+		for _, prefix := range reservedPrefixes {
+			if strings.HasPrefix(s.UserID, prefix) {
+				return fmt.Errorf("%w: user id %q starts with the reserved prefix %q", ErrInvalidScope, s.UserID, prefix)
+			}
+		}
+	case ScopeApp:
+		if s.AppName == "" {
+			missing = append(missing, "app name")
+		}
+		if s.UserID == "" {
+			missing = append(missing, "user id")
+		}
+	case ScopeAgent:
+		if s.AppName == "" {
+			missing = append(missing, "app name")
+		}
+		if s.AgentName == "" {
+			missing = append(missing, "agent name")
+		}
+		if s.UserID == "" {
+			missing = append(missing, "user id")
+		}
+	case ScopeShared:
+		if s.Namespace == "" {
+			missing = append(missing, "namespace")
+		}
+	default:
+		return fmt.Errorf("%w: unknown level %q", ErrInvalidScope, s.Level)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s scope requires %s", ErrInvalidScope, s.Level, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// reservedPrefixes start the keys of the app, agent and shared scopes. User scopes keep the
+// bare user id as their key, as before scopes were introduced, so user ids can't start with them.
+var reservedPrefixes = []string{"_app", "_agent", "_shared"}
+
+// Key returns the storage key of the scope. The key of a user scope is the user id. The
+// other scopes are keyed by a reserved level prefix followed by the escaped names, joined
+// by ':'. Names are query-escaped so that they can't contain the separator.
+func (s Scope) Key() string {
+	prefix, names := s.segments()
+	if prefix == "" {
+		return s.UserID
+	}
+	key := prefix
+	for _, name := range names {
+		key += ":" + url.QueryEscape(name)
+	}
+	return key
+}
+
+// segments returns the reserved level prefix and the unescaped names of the scope, no
+// prefix for user scopes.
+func (s Scope) segments() (string, []string) {
+	switch s.Level {
+	case ScopeApp:
+		return "_app", []string{s.AppName, s.UserID}
+	case ScopeAgent:
+		return "_agent", []string{s.AppName, s.AgentName, s.UserID}
+	case ScopeShared:
+		return "_shared", []string{s.Namespace}
+	default:
+		return "", []string{s.UserID}
+	}
+}
+
+// ScopeConfig controls which scope a long-term memory service writes to and reads from.
+type ScopeConfig struct {
+	// Level is the scope sessions are saved to and searched in. Defaults to ScopeUser.
+	Level ScopeLevel
+	// SharedNamespaces are additionally searched on every request, so org-wide memories are visible to all users.
+	SharedNamespaces []string
+}
+
+type searchScopesKey struct{}
+
+// WithSearchScopes returns a context that overrides the scopes searched by SearchMemory.
+func WithSearchScopes(ctx context.Context, scopes ...Scope) context.Context {
+	return context.WithValue(ctx, searchScopesKey{}, scopes)
+}
+
+func searchScopesFromContext(ctx context.Context) ([]Scope, bool) {
+	scopes, ok := ctx.Value(searchScopesKey{}).([]Scope)
+	return scopes, ok && len(scopes) > 0
+}
+
+// agentNameFromContext returns the agent name when ctx is an ADK agent, callback or tool context.
+func agentNameFromContext(ctx context.Context) string {
+	if named, ok := ctx.(interface{ AgentName() string }); ok {
+		return named.AgentName()
+	}
+	return ""
+}
+
+// sessionScope derives the scope a session is saved to.
+// The agent name is taken from ctx, falling back to the last non-user author in the session.
+func (c ScopeConfig) sessionScope(ctx context.Context, s session.Session) (Scope, error) {
+	var scope Scope
+	switch c.Level {
+	case ScopeApp:
+		scope = AppScope(s.AppName(), s.UserID())
+	case ScopeAgent:
+		agentName := agentNameFromContext(ctx)
+		if agentName == "" {
+			for event := range s.Events().All() {
+				if event.Author != "" && event.Author != "user" {
+					agentName = event.Author
+				}
+			}
+		}
+		scope = AgentScope(s.AppName(), agentName, s.UserID())
+	default:
+		scope = UserScope(s.UserID())
+	}
+	return scope, scope.Validate()
+}
+
+// searchScopes derives the scopes a search request reads from.
+func (c ScopeConfig) searchScopes(ctx context.Context, req *memory.SearchRequest) ([]Scope, error) {
+	if scopes, ok := searchScopesFromContext(ctx); ok {
+		for _, scope := range scopes {
+			if err := scope.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		return scopes, nil
+	}
+
+	var scope Scope
+	switch c.Level {
+	case ScopeApp:
+		scope = AppScope(req.AppName, req.UserID)
+	case ScopeAgent:
+		scope = AgentScope(req.AppName, agentNameFromContext(ctx), req.UserID)
+	default:
+		scope = UserScope(req.UserID)
+	}
+	if err := scope.Validate(); err != nil {
+		return nil, err
+	}
+
+	scopes := []Scope{scope}
+	for _, ns := range c.SharedNamespaces {
+		scopes = append(scopes, SharedScope(ns))
+	}
+	return scopes, nil
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package long_term_memory_backends
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+type recordingBackend struct {
+	savedScope     Scope
+	savedEvents    []string
+	searchedScopes []Scope
+}
+
+func (r *recordingBackend) SaveMemory(_ context.Context, scope Scope, eventList []string) error {
+	r.savedScope = scope
+	r.savedEvents = eventList
+	return nil
+}
+
+func (r *recordingBackend) SearchMemory(_ context.Context, scopes []Scope, _ string, _ int) ([]*MemItem, error) {
+	r.searchedScopes = scopes
+	return []*MemItem{{Content: "remembered"}}, nil
+}
+
+type agentNameContext struct {
+	context.Context
+	name string
+}
+
+func (a agentNameContext) AgentName() string { return a.name }
+
+func newTestSession(t *testing.T, authors ...string) session.Session {
+	ctx := context.Background()
+	svc := session.InMemoryService()
+	resp, err := svc.Create(ctx, &session.CreateRequest{AppName: "app1", UserID: "user1"})
+	require.NoError(t, err)
+	for _, author := range authors {
+		event := session.NewEvent("inv")
+		event.Author = author
+		role := "model"
+		if author == "user" {
+			role = "user"
+		}
+		event.Content = genai.NewContentFromText("hello from "+author, genai.Role(role))
+		require.NoError(t, svc.AppendEvent(ctx, resp.Session, event))
+	}
+	return resp.Session
+}
+
+func TestScopeKey(t *testing.T) {
+	// user scopes keep the keys of the memories saved before scopes were introduced
+	assert.Equal(t, "user1", UserScope("user1").Key())
+	assert.Equal(t, "_app:app1:user1", AppScope("app1", "user1").Key())
+	assert.Equal(t, "_agent:app1:writer:user1", AgentScope("app1", "writer", "user1").Key())
+	assert.Equal(t, "_shared:org", SharedScope("org").Key())
+
+	// names can't forge the separator or another level's prefix
+	assert.NotEqual(t, AppScope("a:b", "c").Key(), AppScope("a", "b:c").Key())
+	assert.ErrorIs(t, UserScope("_shared:org").Validate(), ErrInvalidScope)
+}
+
+func TestScopeValidate(t *testing.T) {
+	assert.NoError(t, UserScope("user1").Validate())
+	assert.NoError(t, Scope{UserID: "user1"}.Validate())
+	assert.ErrorIs(t, UserScope("").Validate(), ErrInvalidScope)
+	assert.ErrorIs(t, AppScope("", "user1").Validate(), ErrInvalidScope)
+	assert.ErrorIs(t, AgentScope("app1", "", "user1").Validate(), ErrInvalidScope)
+	assert.ErrorIs(t, SharedScope("").Validate(), ErrInvalidScope)
+	assert.ErrorIs(t, Scope{Level: "team", UserID: "user1"}.Validate(), ErrInvalidScope)
+}
+
+func TestBasicLongTermMemory_AddSessionToMemory(t *testing.T) {
+	t.Run("defaults to user scope", func(t *testing.T) {
+		backend := &recordingBackend{}
+		svc := LongTermMemoryFactory(backend, 5)
+
+		require.NoError(t, svc.AddSessionToMemory(context.Background(), newTestSession(t, "user", "writer")))
+		assert.Equal(t, UserScope("user1"), backend.savedScope)
+		assert.Len(t, backend.savedEvents, 1)
+	})
+
+	t.Run("app scope from session", func(t *testing.T) {
+		backend := &recordingBackend{}
+		svc := LongTermMemoryFactory(backend, 5, WithScopeConfig(ScopeConfig{Level: ScopeApp}))
+
+		require.NoError(t, svc.AddSessionToMemory(context.Background(), newTestSession(t, "user")))
+		assert.Equal(t, AppScope("app1", "user1"), backend.savedScope)
+	})
+
+	t.Run("agent scope from last session author", func(t *testing.T) {
+		backend := &recordingBackend{}
+		svc := LongTermMemoryFactory(backend, 5, WithScopeConfig(ScopeConfig{Level: ScopeAgent}))
+
+		require.NoError(t, svc.AddSessionToMemory(context.Background(), newTestSession(t, "user", "planner", "user", "writer")))
+		assert.Equal(t, AgentScope("app1", "writer", "user1"), backend.savedScope)
+	})
+
+	t.Run("agent scope prefers context agent", func(t *testing.T) {
+		backend := &recordingBackend{}
+		svc := LongTermMemoryFactory(backend, 5, WithScopeConfig(ScopeConfig{Level: ScopeAgent}))
+		ctx := agentNameContext{Context: context.Background(), name: "reviewer"}
+
+		require.NoError(t, svc.AddSessionToMemory(ctx, newTestSession(t, "user", "writer")))
+		assert.Equal(t, AgentScope("app1", "reviewer", "user1"), backend.savedScope)
+	})
+
+	t.Run("agent scope without agent fails", func(t *testing.T) {
+		backend := &recordingBackend{}
+		svc := LongTermMemoryFactory(backend, 5, WithScopeConfig(ScopeConfig{Level: ScopeAgent}))
+
+		err := svc.AddSessionToMemory(context.Background(), newTestSession(t, "user"))
+		assert.ErrorIs(t, err, ErrInvalidScope)
+	})
+}
+
+func TestBasicLongTermMemory_SearchMemory(t *testing.T) {
+	req := &memory.SearchRequest{AppName: "app1", UserID: "user1", Query: "q"}
+
+	t.Run("scope level plus shared namespaces", func(t *testing.T) {
+		backend := &recordingBackend{}
+		svc := LongTermMemoryFactory(backend, 5, WithScopeConfig(ScopeConfig{
+			Level:            ScopeApp,
+			SharedNamespaces: []string{"org"},
+		}))
+
+		resp, err := svc.SearchMemory(context.Background(), req)
+		require.NoError(t, err)
+		assert.Len(t, resp.Memories, 1)
+		assert.Equal(t, []Scope{AppScope("app1", "user1"), SharedScope("org")}, backend.searchedScopes)
+	})
+
+	t.Run("agent scope from context", func(t *testing.T) {
+		backend := &recordingBackend{}
+		svc := LongTermMemoryFactory(backend, 5, WithScopeConfig(ScopeConfig{Level: ScopeAgent}))
+		ctx := agentNameContext{Context: context.Background(), name: "writer"}
+
+		_, err := svc.SearchMemory(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, []Scope{AgentScope("app1", "writer", "user1")}, backend.searchedScopes)
+	})
+
+	t.Run("explicit scopes override config", func(t *testing.T) {
+		backend := &recordingBackend{}
+		svc := LongTermMemoryFactory(backend, 5, WithScopeConfig(ScopeConfig{Level: ScopeApp}))
+		ctx := WithSearchScopes(context.Background(), SharedScope("org"))
+
+		_, err := svc.SearchMemory(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, []Scope{SharedScope("org")}, backend.searchedScopes)
+	})
+
+	t.Run("invalid explicit scope", func(t *testing.T) {
+		svc := LongTermMemoryFactory(&recordingBackend{}, 5)
+		ctx := WithSearchScopes(context.Background(), SharedScope(""))
+
+		_, err := svc.SearchMemory(ctx, req)
+		assert.ErrorIs(t, err, ErrInvalidScope)
+	})
+}
+
+func TestBasicLongTermMemory_AddMemoryToScope(t *testing.T) {
+	backend := &recordingBackend{}
+	svc := LongTermMemoryFactory(backend, 5).(ScopedMemoryService)
+
+	require.NoError(t, svc.AddMemoryToScope(context.Background(), SharedScope("org"), []string{"policy"}))
+	assert.Equal(t, SharedScope("org"), backend.savedScope)
+	assert.Equal(t, []string{"policy"}, backend.savedEvents)
+
+	assert.ErrorIs(t, svc.AddMemoryToScope(context.Background(), SharedScope(""), []string{"policy"}), ErrInvalidScope)
+}
+
+type pingingBackend struct {
+	recordingBackend
+	err error
+}
+
+func (p *pingingBackend) Ping(context.Context) error { return p.err }
+
+func TestBasicLongTermMemory_Ping(t *testing.T) {
+	// no scope is needed, even with agent scopes and no agent in the context
+	backend := &recordingBackend{}
+	svc := LongTermMemoryFactory(backend, 5, WithScopeConfig(ScopeConfig{Level: ScopeAgent}))
+	require.Implements(t, (*pinger)(nil), svc)
+	assert.NoError(t, svc.(pinger).Ping(context.Background()))
+	assert.Nil(t, backend.searchedScopes, "backends that cannot be pinged are not searched")
+
+	failing := errors.New("unreachable")
+	svc = LongTermMemoryFactory(&pingingBackend{err: failing}, 5)
+	assert.ErrorIs(t, svc.(pinger).Ping(context.Background()), failing)
+}
+
+func TestRedisScopeFilter(t *testing.T) {
+	backend := &RedisMemoryBackend{config: &RedisMemoryConfig{Index: "veadk-ltm"}}
+
+	assert.Equal(t, `@__key:{veadk\-ltm\:user1\:*}`, backend.scopeFilter([]Scope{UserScope("user1")}))
+	assert.Equal(t, `(@__key:{veadk\-ltm\:_app\:app1\:user1\:*} | @__key:{veadk\-ltm\:_shared\:org\:*})`,
+		backend.scopeFilter([]Scope{AppScope("app1", "user1"), SharedScope("org")}))
+}
+
+func TestOpenSearchScopeIndexName(t *testing.T) {
+	backend := &OpenSearchMemoryBackend{config: &OpenSearchMemoryConfig{Index: "veadk_ltm"}}
+
+	name, err := backend.scopeIndexName(AgentScope("app1", "writer", "user1"))
+	require.NoError(t, err)
+	assert.Equal(t, "veadk_ltm__agent_app1_writer_user1", name)
+
+	name, err = backend.scopeIndexName(UserScope("user_1"))
+	require.NoError(t, err)
+	assert.Equal(t, "veadk_ltm_user_1", name)
+	_, err = backend.scopeIndexName(UserScope("User_1"))
+	assert.ErrorIs(t, err, ErrInvalidIndexName)
+
+	a, err := backend.scopeIndexName(AppScope("a_b", "c"))
+	require.NoError(t, err)
+	b, err := backend.scopeIndexName(AppScope("a", "b_c"))
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+
+	backend.config.Index = "VEADK"
+	_, err = backend.scopeIndexName(UserScope("user1"))
+	assert.ErrorIs(t, err, ErrInvalidIndexName)
+}
+
+func TestInterleaveMemItems(t *testing.T) {
+	a1, a2, b1 := &MemItem{Content: "a1"}, &MemItem{Content: "a2"}, &MemItem{Content: "b1"}
+
+	assert.Equal(t, []*MemItem{a1, b1, a2}, interleaveMemItems([][]*MemItem{{a1, a2}, {b1}}, 5))
+	assert.Equal(t, []*MemItem{a1, b1}, interleaveMemItems([][]*MemItem{{a1, a2}, {b1}}, 2))
+	assert.Nil(t, interleaveMemItems(nil, 5))
+}
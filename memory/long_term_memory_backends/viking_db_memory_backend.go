@@ -101,7 +101,12 @@ func NewVikingDbMemoryBackend(config *VikingDbMemoryConfig) (LongTermMemoryBacke
 	return backend, nil
 }
 
-func (v *VikingDBMemoryBackend) SaveMemory(ctx context.Context, userId string, eventList []string) error {
+// Ping checks that the memory collection can be described.
+func (v *VikingDBMemoryBackend) Ping(_ context.Context) error {
+	return v.client.CollectionInfo()
+}
+
+func (v *VikingDBMemoryBackend) SaveMemory(ctx context.Context, scope Scope, eventList []string) error {
 	req := &viking_memory.AddSessionRequest{}
 	uuid1, err := uuid.NewUUID()
 	if err != nil {
@@ -117,8 +122,11 @@ func (v *VikingDBMemoryBackend) SaveMemory(ctx context.Context, userId string, e
 		})
 	}
 
-	req.Metadata.DefaultUserId = userId
+	req.Metadata.DefaultUserId = scope.Key()
 	req.Metadata.DefaultAssistantId = "assistant"
+	if scope.AgentName != "" {
+		req.Metadata.DefaultAssistantId = scope.AgentName
+	}
 	req.Metadata.Time = time.Now().UnixMilli()
 
 	resp, err := v.client.AddSession(req)
@@ -129,17 +137,22 @@ func (v *VikingDBMemoryBackend) SaveMemory(ctx context.Context, userId string, e
 		return fmt.Errorf("viking add memories failed: %v", resp)
 	}
 
-	log.Infof("Successfully saved user %s %d events to viking", userId, len(eventList))
+	log.Infof("Successfully saved scope %s %d events to viking", scope.Key(), len(eventList))
 	return nil
 }
 
-func (v *VikingDBMemoryBackend) SearchMemory(ctx context.Context, userId, query string, topK int) ([]*MemItem, error) {
-	log.Infof("Searching viking for query: %s, user: %s, top_k: %d", query, userId, topK)
+func (v *VikingDBMemoryBackend) SearchMemory(ctx context.Context, scopes []Scope, query string, topK int) ([]*MemItem, error) {
+	log.Infof("Searching viking for query: %s, scopes: %s, top_k: %d", query, scopeKeys(scopes), topK)
 	var memResp []*MemItem
 
+	userIds := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		userIds = append(userIds, scope.Key())
+	}
+
 	vikingReq := &viking_memory.CollectionSearchMemoryRequest{
 		Filter: viking_memory.Filter{
-			UserId:     []string{userId},
+			UserId:     userIds,
 			MemoryType: v.config.MemoryTypes,
 		},
 		Query: query,
@@ -80,7 +80,7 @@ func TestVikingDbMemoryBackend_SaveMemory(t *testing.T) {
 	ctx := context.Background()
 	mockey.PatchConvey("TestVikingDbMemoryBackend_SaveMemory", t, func() {
 		mockey.Mock((*viking_memory.Client).AddSession).Return(&ve_viking.CommonResponse{Code: ve_viking.VikingKnowledgeBaseSuccessCode}, nil).Build()
-		err := v.SaveMemory(ctx, "test", []string{"test1", "test2"})
+		err := v.SaveMemory(ctx, UserScope("test"), []string{"test1", "test2"})
 		assert.Nil(t, err)
 	})
 }
@@ -115,7 +115,7 @@ func TestVikingDbMemoryBackend_SearchMemory(t *testing.T) {
 				},
 			},
 		}, nil).Build()
-		resp, err := v.SearchMemory(ctx, "test", "test", 2)
+		resp, err := v.SearchMemory(ctx, []Scope{UserScope("test")}, "test", 2)
 		assert.Nil(t, err)
 		assert.Equal(t, 2, len(resp))
 		for i, v := range resp {
@@ -0,0 +1,244 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volcengine/veadk-go/log"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/genai"
+)
+
+const (
+	PluginName = "veadk-memory"
+
+	DefaultSaveConcurrency   = 4
+	DefaultSaveTimeout       = 30 * time.Second
+	DefaultRecallTokenBudget = 1024
+
+	recallHeader = "The following are memories from past conversations with the user that may be relevant. Use them when they help answer the request:"
+)
+
+var ErrMemoryServiceNotSet = errors.New("memory service not set")
+
+// PluginConfig configures the memory plugin.
+type PluginConfig struct {
+	// Service is the memory service sessions are saved to and recalled from.
+	Service memory.Service
+
+	// SaveConcurrency bounds the number of sessions saved at the same time. Defaults to DefaultSaveConcurrency.
+	SaveConcurrency int
+	// SaveTimeout bounds a single save. Defaults to DefaultSaveTimeout.
+	SaveTimeout time.Duration
+
+	// EnableRecall searches memory with the latest user message before the model is called
+	// and appends the results to the system instruction.
+	EnableRecall bool
+	// RecallTopK caps the number of injected memories. Zero keeps all results of the service.
+	RecallTopK int
+	// RecallTokenBudget caps the estimated tokens of the injected memories. Defaults to DefaultRecallTokenBudget.
+	RecallTokenBudget int
+}
+
+// NewPlugin creates a plugin that saves every session to long-term memory after each run,
+// and optionally recalls relevant memories into the system instruction before each model call.
+// Closing the plugin waits for pending saves.
+func NewPlugin(cfg *PluginConfig) (*plugin.Plugin, error) {
+	if cfg == nil || cfg.Service == nil {
+		return nil, ErrMemoryServiceNotSet
+	}
+	if cfg.SaveConcurrency <= 0 {
+		cfg.SaveConcurrency = DefaultSaveConcurrency
+	}
+	if cfg.SaveTimeout <= 0 {
+		cfg.SaveTimeout = DefaultSaveTimeout
+	}
+	if cfg.RecallTokenBudget <= 0 {
+		cfg.RecallTokenBudget = DefaultRecallTokenBudget
+	}
+
+	p := &memoryPlugin{
+		config: cfg,
+		slots:  make(chan struct{}, cfg.SaveConcurrency),
+	}
+
+	pluginCfg := plugin.Config{
+		Name:             PluginName,
+		AfterRunCallback: p.AfterRun,
+		CloseFunc:        p.Close,
+	}
+	if cfg.EnableRecall {
+		pluginCfg.BeforeModelCallback = p.BeforeModel
+	}
+	return plugin.New(pluginCfg)
+}
+
+type memoryPlugin struct {
+	config *PluginConfig
+
+	slots   chan struct{}
+	pending sync.WaitGroup
+
+	// recalled caches the recall instruction per invocation, so tool loops don't search again.
+	recalled sync.Map
+}
+
+// agentNameContext exposes the agent name of a finished run to agent-scoped memory services.
+type agentNameContext struct {
+	context.Context
+	agentName string
+}
+
+func (c agentNameContext) AgentName() string { return c.agentName }
+
+// AfterRun saves the session in the background once a run finishes.
+func (p *memoryPlugin) AfterRun(ctx agent.InvocationContext) {
+	p.recalled.Delete(ctx.InvocationID())
+
+	s := ctx.Session()
+	if s == nil {
+		return
+	}
+	var agentName string
+	if a := ctx.Agent(); a != nil {
+		agentName = a.Name()
+	}
+
+	p.pending.Add(1)
+	go func() {
+		defer p.pending.Done()
+		p.slots <- struct{}{}
+		defer func() { <-p.slots }()
+
+		// the invocation context is done once the run returns, so saving uses its own deadline;
+		// its trace and session are kept for the logs
+		saveCtx, cancel := context.WithTimeout(
+			log.ContextWithSession(context.WithoutCancel(ctx), s.ID(), ctx.InvocationID()), p.config.SaveTimeout)
+		defer cancel()
+		if err := p.config.Service.AddSessionToMemory(agentNameContext{Context: saveCtx, agentName: agentName}, s); err != nil {
+			log.WarnContext(saveCtx, "Failed to save session to memory", "UserID", s.UserID(), "error", err)
+			return
+		}
+		log.DebugContext(saveCtx, "Saved session to memory", "UserID", s.UserID())
+	}()
+}
+
+// BeforeModel appends memories relevant to the latest user message to the system instruction.
+func (p *memoryPlugin) BeforeModel(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	var instruction string
+	if cached, ok := p.recalled.Load(ctx.InvocationID()); ok {
+		instruction = cached.(string)
+	} else {
+		query := contentText(ctx.UserContent())
+		if query == "" {
+			return nil, nil
+		}
+		resp, err := p.config.Service.SearchMemory(ctx, &memory.SearchRequest{
+			Query:   query,
+			UserID:  ctx.UserID(),
+			AppName: ctx.AppName(),
+		})
+		if err != nil {
+			// recall is best effort, the model can still answer without memories
+			log.WarnContext(ctx, "Failed to recall memory", "error", err)
+			return nil, nil
+		}
+		instruction = p.buildRecallInstruction(resp.Memories)
+		p.recalled.Store(ctx.InvocationID(), instruction)
+	}
+
+	if instruction != "" {
+		appendSystemInstruction(req, instruction)
+	}
+	return nil, nil
+}
+
+// Close waits until all pending saves finished.
+func (p *memoryPlugin) Close() error {
+	p.pending.Wait()
+	return nil
+}
+
+// buildRecallInstruction renders memories as a bullet list within the token budget.
+func (p *memoryPlugin) buildRecallInstruction(memories []memory.Entry) string {
+	if p.config.RecallTopK > 0 && len(memories) > p.config.RecallTopK {
+		memories = memories[:p.config.RecallTopK]
+	}
+
+	budget := p.config.RecallTokenBudget - estimateTokens(recallHeader)
+	var lines []string
+	for _, entry := range memories {
+		text := strings.TrimSpace(contentText(entry.Content))
+		if text == "" {
+			continue
+		}
+		line := "- " + text
+		if !entry.Timestamp.IsZero() {
+			line = fmt.Sprintf("- [%s] %s", entry.Timestamp.Format(time.DateOnly), text)
+		}
+		cost := estimateTokens(line)
+		if cost > budget {
+			break
+		}
+		budget -= cost
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return recallHeader + "\n" + strings.Join(lines, "\n")
+}
+
+// estimateTokens approximates the token count of s, using the same 4 bytes per token heuristic as observability.
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+func contentText(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part != nil && part.Text != "" && !part.Thought {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func appendSystemInstruction(req *model.LLMRequest, instruction string) {
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	if req.Config.SystemInstruction == nil {
+		req.Config.SystemInstruction = genai.NewContentFromText(instruction, genai.RoleUser)
+		return
+	}
+	req.Config.SystemInstruction.Parts = append(req.Config.SystemInstruction.Parts, genai.NewPartFromText(instruction))
+}
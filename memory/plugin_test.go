@@ -0,0 +1,188 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+type fakeMemoryService struct {
+	mu         sync.Mutex
+	saved      []string
+	agentNames []string
+	searches   int
+	memories   []memory.Entry
+}
+
+func (f *fakeMemoryService) AddSessionToMemory(ctx context.Context, s session.Session) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.saved = append(f.saved, s.ID())
+	if named, ok := ctx.(interface{ AgentName() string }); ok {
+		f.agentNames = append(f.agentNames, named.AgentName())
+	}
+	return nil
+}
+
+func (f *fakeMemoryService) SearchMemory(_ context.Context, _ *memory.SearchRequest) (*memory.SearchResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.searches++
+	return &memory.SearchResponse{Memories: f.memories}, nil
+}
+
+type fakeInvocationContext struct {
+	agent.InvocationContext
+	sess  session.Session
+	agent agent.Agent
+}
+
+func (f *fakeInvocationContext) Session() session.Session { return f.sess }
+func (f *fakeInvocationContext) Agent() agent.Agent       { return f.agent }
+func (f *fakeInvocationContext) InvocationID() string     { return "inv-1" }
+
+type fakeCallbackContext struct {
+	agent.CallbackContext
+	userContent *genai.Content
+}
+
+func (f *fakeCallbackContext) UserContent() *genai.Content { return f.userContent }
+func (f *fakeCallbackContext) InvocationID() string        { return "inv-1" }
+func (f *fakeCallbackContext) UserID() string              { return "user1" }
+func (f *fakeCallbackContext) AppName() string             { return "app1" }
+func (f *fakeCallbackContext) SessionID() string           { return "session1" }
+
+func TestNewPlugin(t *testing.T) {
+	_, err := NewPlugin(nil)
+	assert.ErrorIs(t, err, ErrMemoryServiceNotSet)
+
+	cfg := &PluginConfig{Service: &fakeMemoryService{}}
+	p, err := NewPlugin(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, PluginName, p.Name())
+	assert.Nil(t, p.BeforeModelCallback())
+	assert.Equal(t, DefaultSaveConcurrency, cfg.SaveConcurrency)
+	assert.Equal(t, DefaultSaveTimeout, cfg.SaveTimeout)
+
+	p, err = NewPlugin(&PluginConfig{Service: &fakeMemoryService{}, EnableRecall: true})
+	require.NoError(t, err)
+	assert.NotNil(t, p.BeforeModelCallback())
+}
+
+func TestMemoryPlugin_AfterRun(t *testing.T) {
+	ctx := context.Background()
+	sessions := session.InMemoryService()
+	svc := &fakeMemoryService{}
+	p, err := NewPlugin(&PluginConfig{Service: svc, SaveConcurrency: 1})
+	require.NoError(t, err)
+
+	a, err := agent.New(agent.Config{Name: "assistant"})
+	require.NoError(t, err)
+
+	for _, id := range []string{"s1", "s2", "s3"} {
+		resp, err := sessions.Create(ctx, &session.CreateRequest{AppName: "app1", UserID: "user1", SessionID: id})
+		require.NoError(t, err)
+		p.AfterRunCallback()(&fakeInvocationContext{sess: resp.Session, agent: a})
+	}
+	require.NoError(t, p.Close())
+
+	assert.ElementsMatch(t, []string{"s1", "s2", "s3"}, svc.saved)
+	assert.Equal(t, []string{"assistant", "assistant", "assistant"}, svc.agentNames)
+}
+
+func TestMemoryPlugin_BeforeModel(t *testing.T) {
+	t.Run("injects memories once per invocation", func(t *testing.T) {
+		svc := &fakeMemoryService{memories: []memory.Entry{
+			{Content: genai.NewContentFromText("likes green tea", genai.RoleUser), Timestamp: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+			{Content: genai.NewContentFromText("lives in Beijing", genai.RoleUser)},
+		}}
+		p, err := NewPlugin(&PluginConfig{Service: svc, EnableRecall: true})
+		require.NoError(t, err)
+		cctx := &fakeCallbackContext{userContent: genai.NewContentFromText("what should I drink?", genai.RoleUser)}
+
+		for range 2 {
+			req := &model.LLMRequest{}
+			resp, err := p.BeforeModelCallback()(cctx, req)
+			require.NoError(t, err)
+			assert.Nil(t, resp)
+			require.NotNil(t, req.Config.SystemInstruction)
+			text := req.Config.SystemInstruction.Parts[0].Text
+			assert.Contains(t, text, "- [2025-01-02] likes green tea")
+			assert.Contains(t, text, "- lives in Beijing")
+		}
+		assert.Equal(t, 1, svc.searches)
+	})
+
+	t.Run("appends to existing system instruction", func(t *testing.T) {
+		svc := &fakeMemoryService{memories: []memory.Entry{{Content: genai.NewContentFromText("likes green tea", genai.RoleUser)}}}
+		p, err := NewPlugin(&PluginConfig{Service: svc, EnableRecall: true})
+		require.NoError(t, err)
+		req := &model.LLMRequest{Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText("You are helpful.", genai.RoleUser),
+		}}
+
+		_, err = p.BeforeModelCallback()(&fakeCallbackContext{userContent: genai.NewContentFromText("hi", genai.RoleUser)}, req)
+		require.NoError(t, err)
+		require.Len(t, req.Config.SystemInstruction.Parts, 2)
+		assert.Equal(t, "You are helpful.", req.Config.SystemInstruction.Parts[0].Text)
+	})
+
+	t.Run("no user message", func(t *testing.T) {
+		svc := &fakeMemoryService{}
+		p, err := NewPlugin(&PluginConfig{Service: svc, EnableRecall: true})
+		require.NoError(t, err)
+		req := &model.LLMRequest{}
+
+		_, err = p.BeforeModelCallback()(&fakeCallbackContext{}, req)
+		require.NoError(t, err)
+		assert.Nil(t, req.Config)
+		assert.Equal(t, 0, svc.searches)
+	})
+}
+
+func TestBuildRecallInstruction(t *testing.T) {
+	memories := []memory.Entry{
+		{Content: genai.NewContentFromText("first", genai.RoleUser)},
+		{Content: genai.NewContentFromText(strings.Repeat("x", 400), genai.RoleUser)},
+		{Content: genai.NewContentFromText("third", genai.RoleUser)},
+	}
+
+	t.Run("top k", func(t *testing.T) {
+		p := &memoryPlugin{config: &PluginConfig{RecallTopK: 1, RecallTokenBudget: DefaultRecallTokenBudget}}
+		assert.Equal(t, recallHeader+"\n- first", p.buildRecallInstruction(memories))
+	})
+
+	t.Run("token budget stops at the first memory that does not fit", func(t *testing.T) {
+		p := &memoryPlugin{config: &PluginConfig{RecallTokenBudget: estimateTokens(recallHeader) + 10}}
+		assert.Equal(t, recallHeader+"\n- first", p.buildRecallInstruction(memories))
+	})
+
+	t.Run("nothing fits", func(t *testing.T) {
+		p := &memoryPlugin{config: &PluginConfig{RecallTokenBudget: 1}}
+		assert.Empty(t, p.buildRecallInstruction(memories))
+	})
+}
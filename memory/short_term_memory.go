@@ -29,6 +29,7 @@ const (
 	BackendShortTermPostgreSQL ShortTermBackendType = "postgresql"
 	BackendShortTermSQLite     ShortTermBackendType = "sqlite"
 	BackendShortTermMySQL      ShortTermBackendType = "mysql"
+	BackendShortTermRedis      ShortTermBackendType = "redis"
 )
 
 // NewShortTermMemoryService creates a new short term memory service.
@@ -97,6 +98,24 @@ func NewShortTermMemoryService(backend ShortTermBackendType, config interface{})
 			return nil, err
 		}
 		return sessionService, nil
+	case BackendShortTermRedis:
+		var redisCfg *short_term_memory_backends.RedisBackendConfig
+		if config == nil {
+			redisCfg = &short_term_memory_backends.RedisBackendConfig{
+				RedisConfig: configs.GetGlobalConfig().Database.Redis,
+			}
+		} else {
+			var ok bool
+			redisCfg, ok = config.(*short_term_memory_backends.RedisBackendConfig)
+			if !ok {
+				return nil, fmt.Errorf("redis backend requires *RedisBackendConfig, got %T", config)
+			}
+		}
+		sessionService, err := short_term_memory_backends.NewRedisSTMBackend(redisCfg)
+		if err != nil {
+			return nil, err
+		}
+		return sessionService, nil
 	default:
 		return nil, fmt.Errorf("unsupported backend type: %s", backend)
 	}
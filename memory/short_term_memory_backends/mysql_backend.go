@@ -16,22 +16,20 @@ package short_term_memory_backends
 
 import (
 	"fmt"
-	"log/slog"
 	"net/url"
 
 	"github.com/volcengine/veadk-go/configs"
 	"github.com/volcengine/veadk-go/log"
-	"google.golang.org/adk/session"
 	"google.golang.org/adk/session/database"
 	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
 )
 
 type MysqlBackendConfig struct {
 	*configs.CommonDatabaseConfig
+	SQLSessionOptions
 }
 
-func NewMysqlSTMBackend(config *MysqlBackendConfig) (session.Service, error) {
+func NewMysqlSTMBackend(config *MysqlBackendConfig) (*SQLSessionService, error) {
 	if config == nil {
 		return nil, fmt.Errorf("mysql config is nil")
 	}
@@ -48,10 +46,13 @@ func NewMysqlSTMBackend(config *MysqlBackendConfig) (session.Service, error) {
 		)
 	}
 
-	sessionService, err := database.NewSessionService(
-		mysql.Open(config.DBUrl),
-		&gorm.Config{PrepareStmt: true, Logger: log.NewGormLogger(slog.LevelError)},
-	)
+	db, err := openSessionDB(mysql.Open(config.DBUrl), config.SQLSessionOptions)
+	if err != nil {
+		log.Error(fmt.Sprintf("open MySQL database failed: %v", err))
+		return nil, err
+	}
+	// the ADK session service shares the connection pool of db
+	sessionService, err := database.NewSessionService(mysql.New(mysql.Config{Conn: db.ConnPool}), gormConfig())
 	if err != nil {
 		log.Error(fmt.Sprintf("init MySQL DatabaseSessionService failed: %v", err))
 		return nil, err
@@ -60,5 +61,5 @@ func NewMysqlSTMBackend(config *MysqlBackendConfig) (session.Service, error) {
 		log.Error(fmt.Sprintf("AutoMigrate MySQL DatabaseSessionService failed: %v", initErr))
 	}
 
-	return sessionService, nil
+	return newSQLSessionService(sessionService, db, config.SQLSessionOptions), nil
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/volcengine/veadk-go/configs"
 	"google.golang.org/adk/session/database"
+	"gorm.io/gorm"
 )
 
 func TestNewMysqlSTMBackend(t *testing.T) {
@@ -62,6 +63,7 @@ func TestNewMysqlSTMBackend(t *testing.T) {
 
 	for _, tt := range tests {
 		mockey.PatchConvey(tt.name, t, func() {
+			mockey.Mock(openSessionDB).Return(&gorm.DB{Config: &gorm.Config{}}, nil).Build()
 			mockey.Mock(database.NewSessionService).Return(&mockSessionServiceImpl{}, nil).Build()
 			mockey.Mock(database.AutoMigrate).Return(nil).Build()
 			t.Run(tt.name, func(t *testing.T) {
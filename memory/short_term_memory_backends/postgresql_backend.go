@@ -16,23 +16,21 @@ package short_term_memory_backends
 
 import (
 	"fmt"
-	"log/slog"
 	"net/url"
 	"strings"
 
 	"github.com/volcengine/veadk-go/configs"
 	"github.com/volcengine/veadk-go/log"
-	"google.golang.org/adk/session"
 	"google.golang.org/adk/session/database"
 	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
 type PostgresqlBackendConfig struct {
 	*configs.CommonDatabaseConfig
+	SQLSessionOptions
 }
 
-func NewPostgreSqlSTMBackend(config *PostgresqlBackendConfig) (session.Service, error) {
+func NewPostgreSqlSTMBackend(config *PostgresqlBackendConfig) (*SQLSessionService, error) {
 	if config == nil {
 		return nil, fmt.Errorf("postgresql config is nil")
 	}
@@ -56,10 +54,13 @@ func NewPostgreSqlSTMBackend(config *PostgresqlBackendConfig) (session.Service,
 		)
 	}
 
-	sessionService, err := database.NewSessionService(
-		postgres.Open(config.DBUrl),
-		&gorm.Config{PrepareStmt: true, Logger: log.NewGormLogger(slog.LevelError)},
-	)
+	db, err := openSessionDB(postgres.Open(config.DBUrl), config.SQLSessionOptions)
+	if err != nil {
+		log.Error(fmt.Sprintf("open database failed: %v", err))
+		return nil, err
+	}
+	// the ADK session service shares the connection pool of db
+	sessionService, err := database.NewSessionService(postgres.New(postgres.Config{Conn: db.ConnPool}), gormConfig())
 	if err != nil {
 		log.Error(fmt.Sprintf("init DatabaseSessionService failed: %v", err))
 		return nil, err
@@ -68,5 +69,5 @@ func NewPostgreSqlSTMBackend(config *PostgresqlBackendConfig) (session.Service,
 		log.Error(fmt.Sprintf("AutoMigrate DatabaseSessionService failed: %v", initErr))
 	}
 
-	return sessionService, nil
+	return newSQLSessionService(sessionService, db, config.SQLSessionOptions), nil
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/volcengine/veadk-go/configs"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/session/database"
+	"gorm.io/gorm"
 )
 
 type mockSessionServiceImpl struct {
@@ -62,6 +63,7 @@ func TestNewPostgreSqlSTMBackend(t *testing.T) {
 
 	for _, tt := range tests {
 		mockey.PatchConvey(tt.name, t, func() {
+			mockey.Mock(openSessionDB).Return(&gorm.DB{Config: &gorm.Config{}}, nil).Build()
 			mockey.Mock(database.NewSessionService).Return(&mockSessionServiceImpl{}, nil).Build()
 			mockey.Mock(database.AutoMigrate).Return(nil).Build()
 			t.Run(tt.name, func(t *testing.T) {
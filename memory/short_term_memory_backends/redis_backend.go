@@ -0,0 +1,668 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package short_term_memory_backends
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/volcengine/veadk-go/configs"
+	"github.com/volcengine/veadk-go/log"
+	"google.golang.org/adk/session"
+)
+
+const (
+	DefaultRedisPort      = 6379
+	DefaultRedisKeyPrefix = "veadk:stm"
+
+	redisConnectTimeout = 5 * time.Second
+	// maxAppendRetries bounds the retries of an append whose transaction lost a race on the session key
+	// without the session having changed, e.g. because its TTL was refreshed.
+	maxAppendRetries = 3
+
+	fieldUpdateTime = "update_time"
+	fieldVersion    = "version"
+	fieldEvent      = "event"
+)
+
+// ErrStaleSession is returned by AppendEvent when the session was modified after it was loaded.
+var ErrStaleSession = errors.New("stale session")
+
+type RedisBackendConfig struct {
+	*configs.RedisConfig
+	// KeyPrefix namespaces all keys of the service. Defaults to DefaultRedisKeyPrefix.
+	KeyPrefix string
+	// SessionTTL expires a session, its state and its events after this long without updates.
+	// Zero keeps sessions forever. App and user state are shared between sessions and never expire.
+	SessionTTL time.Duration
+}
+
+// RedisSessionService is a session.Service storing sessions in Redis, so that horizontally scaled
+// agents share conversations. Per session it keeps a hash with the update time and version,
+// a hash with the session state and a stream with the events. App and user state live in their own
+// hashes, and a sorted set per user indexes the sessions by update time.
+//
+// AppendEvent is optimistic: it fails with ErrStaleSession if another writer appended
+// to the session after it was loaded.
+type RedisSessionService struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+func NewRedisSTMBackend(config *RedisBackendConfig) (*RedisSessionService, error) {
+	if config == nil || config.RedisConfig == nil {
+		return nil, fmt.Errorf("redis config is nil")
+	}
+	if config.Port == 0 {
+		config.Port = DefaultRedisPort
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = DefaultRedisKeyPrefix
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", config.Host, config.Port),
+		Username: config.Username,
+		Password: config.Password,
+		DB:       config.DB,
+	})
+	if err := pingRedis(client); err != nil {
+		_ = client.Close()
+		log.Error(fmt.Sprintf("connect to Redis failed: %v", err))
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return newRedisSessionService(client, config.KeyPrefix, config.SessionTTL), nil
+}
+
+func pingRedis(client *redis.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisConnectTimeout)
+	defer cancel()
+	return client.Ping(ctx).Err()
+}
+
+func newRedisSessionService(client *redis.Client, prefix string, ttl time.Duration) *RedisSessionService {
+	return &RedisSessionService{client: client, prefix: prefix, ttl: ttl}
+}
+
+// Client returns the Redis client of the service, so that other stores can share it.
+func (s *RedisSessionService) Client() *redis.Client {
+	return s.client
+}
+
+// Close closes the Redis client.
+func (s *RedisSessionService) Close() error {
+	return s.client.Close()
+}
+
+func (s *RedisSessionService) Create(ctx context.Context, req *session.CreateRequest) (*session.CreateResponse, error) {
+	if req.AppName == "" || req.UserID == "" {
+		return nil, fmt.Errorf("app_name and user_id are required, got app_name: %q, user_id: %q", req.AppName, req.UserID)
+	}
+	sessionID := req.SessionID
+	if sessionID == "" {
+		sessionID = uuid.NewString()
+	}
+
+	appDelta, userDelta, sessionState := splitStateDelta(req.State)
+	keys := s.keys(req.AppName, req.UserID, sessionID)
+	now := time.Now()
+
+	err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, keys.meta).Result()
+		if err != nil {
+			return err
+		}
+		if exists > 0 {
+			return fmt.Errorf("session %s already exists", sessionID)
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, keys.meta, fieldUpdateTime, now.UnixMicro(), fieldVersion, 0)
+			if err := s.writeState(ctx, pipe, keys, appDelta, userDelta, sessionState); err != nil {
+				return err
+			}
+			pipe.ZAdd(ctx, keys.index, redis.Z{Score: float64(now.UnixMicro()), Member: sessionID})
+			pipe.SAdd(ctx, keys.users, req.UserID)
+			s.expire(ctx, pipe, keys)
+			return nil
+		})
+		return err
+	}, keys.meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	appState, userState, err := s.sharedState(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	return &session.CreateResponse{Session: &redisSession{
+		appName:   req.AppName,
+		userID:    req.UserID,
+		id:        sessionID,
+		state:     mergeState(appState, userState, sessionState),
+		updatedAt: time.UnixMicro(now.UnixMicro()),
+	}}, nil
+}
+
+func (s *RedisSessionService) Get(ctx context.Context, req *session.GetRequest) (*session.GetResponse, error) {
+	if req.AppName == "" || req.UserID == "" || req.SessionID == "" {
+		return nil, fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", req.AppName, req.UserID, req.SessionID)
+	}
+	keys := s.keys(req.AppName, req.UserID, req.SessionID)
+
+	var eventsCmd *redis.XMessageSliceCmd
+	cmds, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HGetAll(ctx, keys.meta)
+		pipe.HGetAll(ctx, keys.state)
+		pipe.HGetAll(ctx, keys.app)
+		pipe.HGetAll(ctx, keys.user)
+		if req.NumRecentEvents > 0 {
+			eventsCmd = pipe.XRevRangeN(ctx, keys.events, "+", "-", int64(req.NumRecentEvents))
+		} else {
+			eventsCmd = pipe.XRange(ctx, keys.events, "-", "+")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	meta := cmds[0].(*redis.MapStringStringCmd).Val()
+	if len(meta) == 0 {
+		return nil, fmt.Errorf("session %s not found", req.SessionID)
+	}
+	sess, err := newRedisSession(req.AppName, req.UserID, req.SessionID, meta,
+		cmds[1].(*redis.MapStringStringCmd).Val(),
+		cmds[2].(*redis.MapStringStringCmd).Val(),
+		cmds[3].(*redis.MapStringStringCmd).Val())
+	if err != nil {
+		return nil, err
+	}
+
+	messages := eventsCmd.Val()
+	if req.NumRecentEvents > 0 {
+		slices.Reverse(messages)
+	}
+	for _, msg := range messages {
+		event, err := decodeEvent(msg)
+		if err != nil {
+			return nil, err
+		}
+		if !req.After.IsZero() && event.Timestamp.Before(req.After) {
+			continue
+		}
+		sess.events = append(sess.events, event)
+	}
+	return &session.GetResponse{Session: sess}, nil
+}
+
+// List returns the sessions of a user, or of every user of the app if UserID is empty.
+// Sessions are returned without events.
+func (s *RedisSessionService) List(ctx context.Context, req *session.ListRequest) (*session.ListResponse, error) {
+	if req.AppName == "" {
+		return nil, fmt.Errorf("app_name is required, got app_name: %q", req.AppName)
+	}
+
+	userIDs := []string{req.UserID}
+	if req.UserID == "" {
+		var err error
+		userIDs, err = s.client.SMembers(ctx, s.keys(req.AppName, "", "").users).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list users: %w", err)
+		}
+		slices.Sort(userIDs)
+	}
+
+	sessions := make([]session.Session, 0)
+	for _, userID := range userIDs {
+		userSessions, err := s.listUserSessions(ctx, req.AppName, userID)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, userSessions...)
+	}
+	return &session.ListResponse{Sessions: sessions}, nil
+}
+
+func (s *RedisSessionService) listUserSessions(ctx context.Context, appName, userID string) ([]session.Session, error) {
+	userKeys := s.keys(appName, userID, "")
+	ids, err := s.client.ZRange(ctx, userKeys.index, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	cmds, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HGetAll(ctx, userKeys.app)
+		pipe.HGetAll(ctx, userKeys.user)
+		for _, id := range ids {
+			keys := s.keys(appName, userID, id)
+			pipe.HGetAll(ctx, keys.meta)
+			pipe.HGetAll(ctx, keys.state)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	appState := cmds[0].(*redis.MapStringStringCmd).Val()
+	userState := cmds[1].(*redis.MapStringStringCmd).Val()
+	var sessions []session.Session
+	var expired []any
+	for i, id := range ids {
+		meta := cmds[2+2*i].(*redis.MapStringStringCmd).Val()
+		if len(meta) == 0 {
+			expired = append(expired, id)
+			continue
+		}
+		sess, err := newRedisSession(appName, userID, id, meta, cmds[3+2*i].(*redis.MapStringStringCmd).Val(), appState, userState)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	if len(expired) > 0 {
+		// the index has no TTL per member, expired sessions are dropped from it lazily
+		if err := s.client.ZRem(ctx, userKeys.index, expired...).Err(); err != nil {
+			log.Warn("Failed to remove expired sessions from index", "AppName", appName, "UserID", userID, "error", err)
+		}
+	}
+	return sessions, nil
+}
+
+func (s *RedisSessionService) Delete(ctx context.Context, req *session.DeleteRequest) error {
+	if req.AppName == "" || req.UserID == "" || req.SessionID == "" {
+		return fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", req.AppName, req.UserID, req.SessionID)
+	}
+	keys := s.keys(req.AppName, req.UserID, req.SessionID)
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keys.meta, keys.state, keys.events)
+		pipe.ZRem(ctx, keys.index, req.SessionID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// AppendEvent appends the event to the session stream and applies its state delta.
+// Temporary state keys are kept on the given session only.
+func (s *RedisSessionService) AppendEvent(ctx context.Context, curSession session.Session, event *session.Event) error {
+	if curSession == nil {
+		return fmt.Errorf("session is nil")
+	}
+	if event == nil {
+		return fmt.Errorf("event is nil")
+	}
+	if event.Partial {
+		return nil
+	}
+	sess, ok := curSession.(*redisSession)
+	if !ok {
+		return fmt.Errorf("unexpected session type %T for session ID %s", curSession, curSession.ID())
+	}
+
+	stored := *event
+	stored.Actions.StateDelta = trimTempState(event.Actions.StateDelta)
+	payload, err := json.Marshal(&stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	appDelta, userDelta, sessionDelta := splitStateDelta(event.Actions.StateDelta)
+	keys := s.keys(sess.appName, sess.userID, sess.id)
+	updatedAt := event.Timestamp
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+	version := sess.currentVersion()
+
+	for range maxAppendRetries {
+		err = s.client.Watch(ctx, func(tx *redis.Tx) error {
+			storedVersion, err := tx.HGet(ctx, keys.meta, fieldVersion).Int64()
+			if errors.Is(err, redis.Nil) {
+				return fmt.Errorf("session not found, cannot apply event")
+			}
+			if err != nil {
+				return err
+			}
+			if storedVersion != version {
+				return fmt.Errorf("%w: session %s is at version %d, the appended session at version %d", ErrStaleSession, sess.id, storedVersion, version)
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.XAdd(ctx, &redis.XAddArgs{Stream: keys.events, Values: []any{fieldEvent, payload}})
+				pipe.HSet(ctx, keys.meta, fieldUpdateTime, updatedAt.UnixMicro(), fieldVersion, version+1)
+				if err := s.writeState(ctx, pipe, keys, appDelta, userDelta, sessionDelta); err != nil {
+					return err
+				}
+				pipe.ZAdd(ctx, keys.index, redis.Z{Score: float64(updatedAt.UnixMicro()), Member: sess.id})
+				s.expire(ctx, pipe, keys)
+				return nil
+			})
+			return err
+		}, keys.meta)
+		if !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to append event: %w", err)
+	}
+
+	sess.appendEvent(event, version+1, time.UnixMicro(updatedAt.UnixMicro()))
+	return nil
+}
+
+func (s *RedisSessionService) writeState(ctx context.Context, pipe redis.Pipeliner, keys redisSessionKeys, appDelta, userDelta, sessionDelta map[string]any) error {
+	for key, delta := range map[string]map[string]any{keys.app: appDelta, keys.user: userDelta, keys.state: sessionDelta} {
+		if len(delta) == 0 {
+			continue
+		}
+		values, err := encodeState(delta)
+		if err != nil {
+			return err
+		}
+		pipe.HSet(ctx, key, values)
+	}
+	return nil
+}
+
+func (s *RedisSessionService) expire(ctx context.Context, pipe redis.Pipeliner, keys redisSessionKeys) {
+	if s.ttl <= 0 {
+		return
+	}
+	for _, key := range []string{keys.meta, keys.state, keys.events, keys.index} {
+		pipe.Expire(ctx, key, s.ttl)
+	}
+}
+
+func (s *RedisSessionService) sharedState(ctx context.Context, keys redisSessionKeys) (map[string]any, map[string]any, error) {
+	cmds, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HGetAll(ctx, keys.app)
+		pipe.HGetAll(ctx, keys.user)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get app and user state: %w", err)
+	}
+	appState, err := decodeState(cmds[0].(*redis.MapStringStringCmd).Val())
+	if err != nil {
+		return nil, nil, err
+	}
+	userState, err := decodeState(cmds[1].(*redis.MapStringStringCmd).Val())
+	if err != nil {
+		return nil, nil, err
+	}
+	return appState, userState, nil
+}
+
+// redisSessionKeys are the keys holding one session and the state shared with it.
+type redisSessionKeys struct {
+	meta   string
+	state  string
+	events string
+	index  string
+	users  string
+	app    string
+	user   string
+}
+
+func (s *RedisSessionService) keys(appName, userID, sessionID string) redisSessionKeys {
+	app, user, id := keyPart(appName), keyPart(userID), keyPart(sessionID)
+	meta := fmt.Sprintf("%s:session:%s:%s:%s", s.prefix, app, user, id)
+	return redisSessionKeys{
+		meta:   meta,
+		state:  meta + ":state",
+		events: meta + ":events",
+		index:  fmt.Sprintf("%s:sessions:%s:%s", s.prefix, app, user),
+		users:  fmt.Sprintf("%s:users:%s", s.prefix, app),
+		app:    fmt.Sprintf("%s:app:%s", s.prefix, app),
+		user:   fmt.Sprintf("%s:user:%s:%s", s.prefix, app, user),
+	}
+}
+
+// keyPart escapes ":" so that ids containing it can't collide with other keys.
+func keyPart(s string) string {
+	return url.QueryEscape(s)
+}
+
+// splitStateDelta splits a state delta into app, user and session deltas, dropping temporary keys.
+// App and user keys are returned without their prefixes.
+func splitStateDelta(delta map[string]any) (appDelta, userDelta, sessionDelta map[string]any) {
+	appDelta, userDelta, sessionDelta = map[string]any{}, map[string]any{}, map[string]any{}
+	for key, value := range delta {
+		if k, ok := strings.CutPrefix(key, session.KeyPrefixApp); ok {
+			appDelta[k] = value
+		} else if k, ok := strings.CutPrefix(key, session.KeyPrefixUser); ok {
+			userDelta[k] = value
+		} else if !strings.HasPrefix(key, session.KeyPrefixTemp) {
+			sessionDelta[key] = value
+		}
+	}
+	return appDelta, userDelta, sessionDelta
+}
+
+// mergeState combines the scopes into the state seen by a session, with app and user keys prefixed.
+func mergeState(appState, userState, sessionState map[string]any) map[string]any {
+	merged := make(map[string]any, len(appState)+len(userState)+len(sessionState))
+	maps.Copy(merged, sessionState)
+	for key, value := range appState {
+		merged[session.KeyPrefixApp+key] = value
+	}
+	for key, value := range userState {
+		merged[session.KeyPrefixUser+key] = value
+	}
+	return merged
+}
+
+func trimTempState(delta map[string]any) map[string]any {
+	if len(delta) == 0 {
+		return delta
+	}
+	trimmed := make(map[string]any, len(delta))
+	for key, value := range delta {
+		if !strings.HasPrefix(key, session.KeyPrefixTemp) {
+			trimmed[key] = value
+		}
+	}
+	return trimmed
+}
+
+func encodeState(state map[string]any) (map[string]any, error) {
+	values := make(map[string]any, len(state))
+	for key, value := range state {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal state %q: %w", key, err)
+		}
+		values[key] = string(data)
+	}
+	return values, nil
+}
+
+func decodeState(values map[string]string) (map[string]any, error) {
+	state := make(map[string]any, len(values))
+	for key, data := range values {
+		var value any
+		if err := json.Unmarshal([]byte(data), &value); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal state %q: %w", key, err)
+		}
+		state[key] = value
+	}
+	return state, nil
+}
+
+func decodeEvent(msg redis.XMessage) (*session.Event, error) {
+	data, ok := msg.Values[fieldEvent].(string)
+	if !ok {
+		return nil, fmt.Errorf("event %s has no payload", msg.ID)
+	}
+	var event session.Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event %s: %w", msg.ID, err)
+	}
+	return &event, nil
+}
+
+// redisSession is a snapshot of a session loaded from Redis.
+type redisSession struct {
+	appName string
+	userID  string
+	id      string
+
+	mu        sync.RWMutex
+	events    []*session.Event
+	state     map[string]any
+	updatedAt time.Time
+	version   int64
+}
+
+func newRedisSession(appName, userID, id string, meta, sessionValues, appValues, userValues map[string]string) (*redisSession, error) {
+	version, err := strconv.ParseInt(meta[fieldVersion], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version of session %s: %w", id, err)
+	}
+	updatedAt, err := strconv.ParseInt(meta[fieldUpdateTime], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid update time of session %s: %w", id, err)
+	}
+	sessionState, err := decodeState(sessionValues)
+	if err != nil {
+		return nil, err
+	}
+	appState, err := decodeState(appValues)
+	if err != nil {
+		return nil, err
+	}
+	userState, err := decodeState(userValues)
+	if err != nil {
+		return nil, err
+	}
+	return &redisSession{
+		appName:   appName,
+		userID:    userID,
+		id:        id,
+		state:     mergeState(appState, userState, sessionState),
+		updatedAt: time.UnixMicro(updatedAt),
+		version:   version,
+	}, nil
+}
+
+func (s *redisSession) ID() string      { return s.id }
+func (s *redisSession) AppName() string { return s.appName }
+func (s *redisSession) UserID() string  { return s.userID }
+
+func (s *redisSession) State() session.State {
+	return &redisState{session: s}
+}
+
+func (s *redisSession) Events() session.Events {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return redisEvents(slices.Clone(s.events))
+}
+
+func (s *redisSession) LastUpdateTime() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.updatedAt
+}
+
+func (s *redisSession) currentVersion() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+// appendEvent applies a stored event to the snapshot, including its temporary state.
+func (s *redisSession) appendEvent(event *session.Event, version int64, updatedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		s.state = make(map[string]any)
+	}
+	maps.Copy(s.state, event.Actions.StateDelta)
+	event.Actions.StateDelta = trimTempState(event.Actions.StateDelta)
+	s.events = append(s.events, event)
+	s.updatedAt = updatedAt
+	s.version = version
+}
+
+type redisState struct {
+	session *redisSession
+}
+
+func (s *redisState) Get(key string) (any, error) {
+	s.session.mu.RLock()
+	defer s.session.mu.RUnlock()
+	value, ok := s.session.state[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+	return value, nil
+}
+
+func (s *redisState) Set(key string, value any) error {
+	s.session.mu.Lock()
+	defer s.session.mu.Unlock()
+	if s.session.state == nil {
+		s.session.state = make(map[string]any)
+	}
+	s.session.state[key] = value
+	return nil
+}
+
+func (s *redisState) All() iter.Seq2[string, any] {
+	s.session.mu.RLock()
+	state := maps.Clone(s.session.state)
+	s.session.mu.RUnlock()
+	return maps.All(state)
+}
+
+type redisEvents []*session.Event
+
+func (e redisEvents) All() iter.Seq[*session.Event] {
+	return slices.Values(e)
+}
+
+func (e redisEvents) Len() int {
+	return len(e)
+}
+
+func (e redisEvents) At(i int) *session.Event {
+	if i >= 0 && i < len(e) {
+		return e[i]
+	}
+	return nil
+}
+
+var _ session.Service = (*RedisSessionService)(nil)
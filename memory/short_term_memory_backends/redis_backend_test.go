@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package short_term_memory_backends
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bytedance/mockey"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/common"
+	"github.com/volcengine/veadk-go/configs"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+func TestNewRedisSTMBackend(t *testing.T) {
+	mockey.PatchConvey("TestNewRedisSTMBackend", t, func() {
+		mockey.PatchConvey("nil config", func() {
+			_, err := NewRedisSTMBackend(nil)
+			assert.Error(t, err)
+			_, err = NewRedisSTMBackend(&RedisBackendConfig{})
+			assert.Error(t, err)
+		})
+
+		mockey.PatchConvey("connect failed", func() {
+			mockey.Mock(pingRedis).Return(errors.New("connection refused")).Build()
+			config := &RedisBackendConfig{RedisConfig: &configs.RedisConfig{Host: "localhost"}}
+			svc, err := NewRedisSTMBackend(config)
+			assert.Nil(t, svc)
+			assert.ErrorContains(t, err, "connection refused")
+			assert.Equal(t, DefaultRedisPort, config.Port)
+			assert.Equal(t, DefaultRedisKeyPrefix, config.KeyPrefix)
+		})
+
+		mockey.PatchConvey("success", func() {
+			mockey.Mock(pingRedis).Return(nil).Build()
+			svc, err := NewRedisSTMBackend(&RedisBackendConfig{
+				RedisConfig: &configs.RedisConfig{Host: "localhost"},
+				SessionTTL:  time.Hour,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, time.Hour, svc.ttl)
+			_ = svc.Close()
+		})
+	})
+}
+
+func TestRedisSessionKeys(t *testing.T) {
+	svc := newRedisSessionService(nil, "p", 0)
+	keys := svc.keys("app", "u:1", "s1")
+	assert.Equal(t, "p:session:app:u%3A1:s1", keys.meta)
+	assert.Equal(t, "p:session:app:u%3A1:s1:state", keys.state)
+	assert.Equal(t, "p:session:app:u%3A1:s1:events", keys.events)
+	assert.Equal(t, "p:sessions:app:u%3A1", keys.index)
+	assert.Equal(t, "p:users:app", keys.users)
+	assert.Equal(t, "p:app:app", keys.app)
+	assert.Equal(t, "p:user:app:u%3A1", keys.user)
+
+	assert.NotEqual(t, svc.keys("a:b", "c", "d").meta, svc.keys("a", "b:c", "d").meta)
+}
+
+func TestSplitAndMergeState(t *testing.T) {
+	appDelta, userDelta, sessionDelta := splitStateDelta(map[string]any{
+		"app:theme":  "dark",
+		"user:name":  "alice",
+		"temp:draft": "x",
+		"turn":       1,
+	})
+	assert.Equal(t, map[string]any{"theme": "dark"}, appDelta)
+	assert.Equal(t, map[string]any{"name": "alice"}, userDelta)
+	assert.Equal(t, map[string]any{"turn": 1}, sessionDelta)
+
+	assert.Equal(t, map[string]any{"app:theme": "dark", "user:name": "alice", "turn": 1},
+		mergeState(appDelta, userDelta, sessionDelta))
+	assert.Equal(t, map[string]any{"turn": 1}, trimTempState(map[string]any{"temp:draft": "x", "turn": 1}))
+}
+
+func TestStateEncoding(t *testing.T) {
+	values, err := encodeState(map[string]any{"n": 1, "s": "x", "l": []string{"a"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"n": "1", "s": `"x"`, "l": `["a"]`}, values)
+
+	state, err := decodeState(map[string]string{"n": "1", "s": `"x"`})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"n": float64(1), "s": "x"}, state)
+
+	_, err = decodeState(map[string]string{"bad": "{"})
+	assert.Error(t, err)
+}
+
+// newTestRedisService connects to the Redis configured by the DATABASE_REDIS_* environment variables.
+func newTestRedisService(t *testing.T, ttl time.Duration) *RedisSessionService {
+	t.Helper()
+	host := os.Getenv(common.DATABASE_REDIS_HOST)
+	if host == "" {
+		t.Skip("missing DATABASE_REDIS_HOST")
+	}
+	config := &configs.RedisConfig{Host: host, Password: os.Getenv(common.DATABASE_REDIS_PASSWORD)}
+	config.Port, _ = strconv.Atoi(os.Getenv(common.DATABASE_REDIS_PORT))
+	svc, err := NewRedisSTMBackend(&RedisBackendConfig{
+		RedisConfig: config,
+		KeyPrefix:   "veadk-test:" + uuid.NewString(),
+		SessionTTL:  ttl,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ctx := context.Background()
+		keys, _ := svc.client.Keys(ctx, svc.prefix+":*").Result()
+		if len(keys) > 0 {
+			svc.client.Del(ctx, keys...)
+		}
+		_ = svc.Close()
+	})
+	return svc
+}
+
+func TestRedisSessionService(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestRedisService(t, time.Hour)
+
+	created, err := svc.Create(ctx, &session.CreateRequest{
+		AppName: "app1", UserID: "user1", SessionID: "s1",
+		State: map[string]any{"app:theme": "dark", "turn": 0},
+	})
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, &session.CreateRequest{AppName: "app1", UserID: "user1", SessionID: "s1"})
+	assert.ErrorContains(t, err, "already exists")
+
+	sess := created.Session
+	for i, text := range []string{"one", "two", "three"} {
+		event := session.NewEvent("inv")
+		event.Author = "user"
+		event.Content = genai.NewContentFromText(text, genai.RoleUser)
+		event.Actions.StateDelta = map[string]any{"turn": i + 1, "user:last": text, "temp:scratch": text}
+		require.NoError(t, svc.AppendEvent(ctx, sess, event))
+	}
+	scratch, err := sess.State().Get("temp:scratch")
+	require.NoError(t, err)
+	assert.Equal(t, "three", scratch)
+
+	t.Run("get", func(t *testing.T) {
+		got, err := svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "s1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "two", "three"}, eventTexts(got.Session))
+		assert.Equal(t, map[string]any{"app:theme": "dark", "user:last": "three", "turn": float64(3)},
+			collectState(got.Session))
+		assert.NotContains(t, got.Session.Events().At(2).Actions.StateDelta, "temp:scratch")
+
+		recent, err := svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "s1", NumRecentEvents: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"two", "three"}, eventTexts(recent.Session))
+
+		_, err = svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "missing"})
+		assert.Error(t, err)
+	})
+
+	t.Run("stale append", func(t *testing.T) {
+		stale, err := svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "s1"})
+		require.NoError(t, err)
+		require.NoError(t, svc.AppendEvent(ctx, sess, session.NewEvent("inv")))
+
+		err = svc.AppendEvent(ctx, stale.Session, session.NewEvent("inv"))
+		assert.ErrorIs(t, err, ErrStaleSession)
+	})
+
+	t.Run("list and delete", func(t *testing.T) {
+		_, err := svc.Create(ctx, &session.CreateRequest{AppName: "app1", UserID: "user2", SessionID: "s2"})
+		require.NoError(t, err)
+
+		all, err := svc.List(ctx, &session.ListRequest{AppName: "app1"})
+		require.NoError(t, err)
+		assert.Len(t, all.Sessions, 2)
+
+		mine, err := svc.List(ctx, &session.ListRequest{AppName: "app1", UserID: "user2"})
+		require.NoError(t, err)
+		require.Len(t, mine.Sessions, 1)
+		theme, err := mine.Sessions[0].State().Get("app:theme")
+		require.NoError(t, err)
+		assert.Equal(t, "dark", theme)
+
+		require.NoError(t, svc.Delete(ctx, &session.DeleteRequest{AppName: "app1", UserID: "user2", SessionID: "s2"}))
+		mine, err = svc.List(ctx, &session.ListRequest{AppName: "app1", UserID: "user2"})
+		require.NoError(t, err)
+		assert.Empty(t, mine.Sessions)
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		ttl, err := svc.client.TTL(ctx, svc.keys("app1", "user1", "s1").events).Result()
+		require.NoError(t, err)
+		assert.Greater(t, ttl, time.Duration(0))
+	})
+}
+
+func collectState(s session.Session) map[string]any {
+	state := map[string]any{}
+	for k, v := range s.State().All() {
+		state[k] = v
+	}
+	return state
+}
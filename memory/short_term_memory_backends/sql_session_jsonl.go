@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package short_term_memory_backends
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	"google.golang.org/adk/session"
+)
+
+// maxJSONLLineSize bounds a single exported session, sessions with large inline data can be several MB.
+const maxJSONLLineSize = 64 << 20
+
+// SessionRecord is one line of a JSONL session export.
+type SessionRecord struct {
+	SessionInfo
+	// State holds session state plus the app and user state visible to the session, with their key prefixes.
+	State  map[string]any   `json:"state,omitempty"`
+	Events []*session.Event `json:"events,omitempty"`
+}
+
+// ExportJSONL writes every session matching req as one JSON line, walking all pages.
+// The page size and token of req are used as the starting point. It returns the number of exported sessions.
+func (s *SQLSessionService) ExportJSONL(ctx context.Context, w io.Writer, req *ListSessionsRequest) (int, error) {
+	if req == nil {
+		req = &ListSessionsRequest{}
+	}
+	page := *req
+	enc := json.NewEncoder(w)
+
+	exported := 0
+	for {
+		resp, err := s.ListSessions(ctx, &page)
+		if err != nil {
+			return exported, err
+		}
+		for _, info := range resp.Sessions {
+			got, err := s.Get(ctx, &session.GetRequest{AppName: info.AppName, UserID: info.UserID, SessionID: info.ID})
+			if err != nil {
+				return exported, fmt.Errorf("failed to get session %s: %w", info.ID, err)
+			}
+			record := SessionRecord{
+				SessionInfo: info,
+				State:       maps.Collect(got.Session.State().All()),
+			}
+			for event := range got.Session.Events().All() {
+				record.Events = append(record.Events, event)
+			}
+			if err := enc.Encode(&record); err != nil {
+				return exported, fmt.Errorf("failed to write session %s: %w", info.ID, err)
+			}
+			exported++
+		}
+		if resp.NextPageToken == "" {
+			return exported, nil
+		}
+		page.PageToken = resp.NextPageToken
+	}
+}
+
+// ImportJSONL creates the sessions read from an ExportJSONL stream, keeping their ids and events.
+// Importing a session that already exists fails. It returns the number of imported sessions.
+func (s *SQLSessionService) ImportJSONL(ctx context.Context, r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+
+	imported := 0
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var record SessionRecord
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return imported, fmt.Errorf("failed to parse session on line %d: %w", line, err)
+		}
+		_, err := s.copySession(ctx, &session.CreateRequest{
+			AppName:   record.AppName,
+			UserID:    record.UserID,
+			SessionID: record.ID,
+			State:     record.State,
+		}, record.Events)
+		if err != nil {
+			return imported, fmt.Errorf("failed to import session on line %d: %w", line, err)
+		}
+		imported++
+	}
+	if err := scanner.Err(); err != nil {
+		return imported, fmt.Errorf("failed to read sessions: %w", err)
+	}
+	return imported, nil
+}
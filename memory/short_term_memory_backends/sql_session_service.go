@@ -0,0 +1,427 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package short_term_memory_backends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/volcengine/veadk-go/log"
+	"google.golang.org/adk/session"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultReapInterval  = 10 * time.Minute
+	DefaultListPageSize  = 50
+	MaxListPageSize      = 1000
+	defaultReapBatchSize = 100
+	sessionsTableName    = "sessions"
+	eventsTableName      = "events"
+)
+
+var (
+	ErrInvalidPageToken = errors.New("invalid page token")
+	ErrEventNotFound    = errors.New("event not found in session")
+)
+
+// SQLSessionOptions holds connection pool and session lifecycle settings shared by the SQL backends.
+type SQLSessionOptions struct {
+	// MaxOpenConns limits open connections. Zero means unlimited.
+	MaxOpenConns int
+	// MaxIdleConns limits idle connections. Zero keeps the database/sql default.
+	MaxIdleConns int
+	// ConnMaxLifetime closes connections older than the duration. Zero means no limit.
+	ConnMaxLifetime time.Duration
+	// ConnMaxIdleTime closes connections idle longer than the duration. Zero means no limit.
+	ConnMaxIdleTime time.Duration
+
+	// SessionTTL expires sessions that have not been updated within the TTL. Zero disables expiry.
+	SessionTTL time.Duration
+	// ReapInterval is how often expired sessions are deleted. Defaults to DefaultReapInterval.
+	ReapInterval time.Duration
+}
+
+// SQLSessionService is a session.Service backed by a SQL database, extending the ADK database
+// session service with paginated listing, TTL expiry, forking and JSONL export/import.
+type SQLSessionService struct {
+	session.Service
+
+	db      *gorm.DB
+	options SQLSessionOptions
+
+	stopReaper context.CancelFunc
+	reaperDone sync.WaitGroup
+}
+
+// sessionRow mirrors the key and timestamp columns of the ADK sessions table.
+type sessionRow struct {
+	AppName    string
+	UserID     string
+	ID         string
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+func (sessionRow) TableName() string {
+	return sessionsTableName
+}
+
+func gormConfig() *gorm.Config {
+	return &gorm.Config{PrepareStmt: true, Logger: log.NewGormLogger(slog.LevelError)}
+}
+
+// openSessionDB opens the database and applies the pool settings.
+// Statements are not prepared on this handle, so its ConnPool is the plain *sql.DB that the ADK service can share.
+func openSessionDB(dialector gorm.Dialector, options SQLSessionOptions) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: log.NewGormLogger(slog.LevelError)})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if options.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(options.MaxOpenConns)
+	}
+	if options.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(options.MaxIdleConns)
+	}
+	if options.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(options.ConnMaxLifetime)
+	}
+	if options.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(options.ConnMaxIdleTime)
+	}
+	return db, nil
+}
+
+// newSQLSessionService wraps the ADK session service sharing db, and starts the reaper when a TTL is set.
+func newSQLSessionService(base session.Service, db *gorm.DB, options SQLSessionOptions) *SQLSessionService {
+	if options.ReapInterval <= 0 {
+		options.ReapInterval = DefaultReapInterval
+	}
+	s := &SQLSessionService{
+		Service: base,
+		db:      db,
+		options: options,
+	}
+	if options.SessionTTL > 0 {
+		s.startReaper()
+	}
+	return s
+}
+
+func (s *SQLSessionService) startReaper() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopReaper = cancel
+	s.reaperDone.Add(1)
+	go func() {
+		defer s.reaperDone.Done()
+		ticker := time.NewTicker(s.options.ReapInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				n, err := s.DeleteExpiredSessions(ctx)
+				if err != nil {
+					log.Warn("Failed to delete expired sessions", "error", err)
+					continue
+				}
+				if n > 0 {
+					log.Info("Deleted expired sessions", "count", n)
+				}
+			}
+		}
+	}()
+}
+
+// DB returns the connection pool of the service, so that other stores can share it.
+func (s *SQLSessionService) DB() *gorm.DB {
+	return s.db
+}
+
+// Close stops the reaper and closes the database connections.
+func (s *SQLSessionService) Close() error {
+	if s.stopReaper != nil {
+		s.stopReaper()
+		s.reaperDone.Wait()
+	}
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// DeleteExpiredSessions deletes sessions, and their events, not updated within the session TTL.
+// It is a no-op when no TTL is configured.
+func (s *SQLSessionService) DeleteExpiredSessions(ctx context.Context) (int, error) {
+	if s.options.SessionTTL <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().Add(-s.options.SessionTTL)
+
+	deleted := 0
+	for {
+		var expired []sessionRow
+		err := s.db.WithContext(ctx).
+			Where("update_time < ?", cutoff).
+			Limit(defaultReapBatchSize).
+			Find(&expired).Error
+		if err != nil {
+			return deleted, fmt.Errorf("failed to query expired sessions: %w", err)
+		}
+		if len(expired) == 0 {
+			return deleted, nil
+		}
+
+		for _, row := range expired {
+			ok, err := s.deleteIfExpired(ctx, row, cutoff)
+			if err != nil {
+				return deleted, err
+			}
+			if ok {
+				deleted++
+			}
+		}
+		if len(expired) < defaultReapBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// deleteIfExpired deletes a session and its events, unless the session was updated after the cutoff meanwhile.
+func (s *SQLSessionService) deleteIfExpired(ctx context.Context, row sessionRow, cutoff time.Time) (bool, error) {
+	deleted := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("app_name = ? AND user_id = ? AND id = ? AND update_time < ?", row.AppName, row.UserID, row.ID, cutoff).
+			Delete(&sessionRow{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		deleted = true
+		// events cascade with foreign keys enabled, but SQLite does not enforce them by default
+		return tx.Exec("DELETE FROM "+eventsTableName+" WHERE app_name = ? AND user_id = ? AND session_id = ?",
+			row.AppName, row.UserID, row.ID).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete expired session %s: %w", row.ID, err)
+	}
+	return deleted, nil
+}
+
+// ListSessionsRequest filters and paginates sessions. All filters are optional.
+type ListSessionsRequest struct {
+	AppName string
+	UserID  string
+	// UpdatedAfter keeps sessions updated at or after the time.
+	UpdatedAfter time.Time
+	// UpdatedBefore keeps sessions updated before the time.
+	UpdatedBefore time.Time
+	// PageSize defaults to DefaultListPageSize and is capped at MaxListPageSize.
+	PageSize int
+	// PageToken is the NextPageToken of the previous page.
+	PageToken string
+}
+
+// SessionInfo describes a session without loading its events and state.
+type SessionInfo struct {
+	AppName    string    `json:"app_name"`
+	UserID     string    `json:"user_id"`
+	ID         string    `json:"id"`
+	CreateTime time.Time `json:"create_time"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// ListSessionsResponse is a page of sessions, most recently updated first.
+type ListSessionsResponse struct {
+	Sessions []SessionInfo
+	// NextPageToken is empty on the last page.
+	NextPageToken string
+}
+
+// ListSessions lists sessions across apps and users, most recently updated first.
+func (s *SQLSessionService) ListSessions(ctx context.Context, req *ListSessionsRequest) (*ListSessionsResponse, error) {
+	if req == nil {
+		req = &ListSessionsRequest{}
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultListPageSize
+	}
+	pageSize = min(pageSize, MaxListPageSize)
+
+	offset := 0
+	if req.PageToken != "" {
+		var err error
+		offset, err = strconv.Atoi(req.PageToken)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPageToken, req.PageToken)
+		}
+	}
+
+	query := s.db.WithContext(ctx).Model(&sessionRow{})
+	if req.AppName != "" {
+		query = query.Where("app_name = ?", req.AppName)
+	}
+	if req.UserID != "" {
+		query = query.Where("user_id = ?", req.UserID)
+	}
+	if !req.UpdatedAfter.IsZero() {
+		query = query.Where("update_time >= ?", req.UpdatedAfter)
+	}
+	if !req.UpdatedBefore.IsZero() {
+		query = query.Where("update_time < ?", req.UpdatedBefore)
+	}
+
+	// fetch one more row than requested to know whether there is a next page
+	var rows []sessionRow
+	err := query.Order("update_time DESC, app_name, user_id, id").
+		Offset(offset).
+		Limit(pageSize + 1).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	resp := &ListSessionsResponse{Sessions: make([]SessionInfo, 0, min(len(rows), pageSize))}
+	if len(rows) > pageSize {
+		rows = rows[:pageSize]
+		resp.NextPageToken = strconv.Itoa(offset + pageSize)
+	}
+	for _, row := range rows {
+		resp.Sessions = append(resp.Sessions, SessionInfo(row))
+	}
+	return resp, nil
+}
+
+// ForkSessionRequest describes a session to branch from.
+type ForkSessionRequest struct {
+	AppName   string
+	UserID    string
+	SessionID string
+	// NewSessionID is the id of the fork. A random id is used if empty.
+	NewSessionID string
+	// UpToEventID, if set, copies events up to and including the event.
+	UpToEventID string
+}
+
+// ForkSession copies a session, its state and events into a new session of the same user,
+// so a conversation can branch without touching the original.
+// When forking at UpToEventID, the session state is rebuilt from the state deltas of the copied events.
+func (s *SQLSessionService) ForkSession(ctx context.Context, req *ForkSessionRequest) (session.Session, error) {
+	resp, err := s.Get(ctx, &session.GetRequest{AppName: req.AppName, UserID: req.UserID, SessionID: req.SessionID})
+	if err != nil {
+		return nil, err
+	}
+	src := resp.Session
+
+	var events []*session.Event
+	for event := range src.Events().All() {
+		events = append(events, event)
+	}
+
+	state := sessionScopedState(src.State())
+	if req.UpToEventID != "" {
+		idx := -1
+		for i, event := range events {
+			if event.ID == req.UpToEventID {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrEventNotFound, req.UpToEventID)
+		}
+		events = events[:idx+1]
+		state = map[string]any{}
+	}
+
+	newSessionID := req.NewSessionID
+	if newSessionID == "" {
+		newSessionID = uuid.NewString()
+	}
+	return s.copySession(ctx, &session.CreateRequest{
+		AppName:   req.AppName,
+		UserID:    req.UserID,
+		SessionID: newSessionID,
+		State:     state,
+	}, events)
+}
+
+// isSharedStateKey reports whether key is app or user state, which is shared across sessions.
+func isSharedStateKey(key string) bool {
+	return strings.HasPrefix(key, session.KeyPrefixApp) || strings.HasPrefix(key, session.KeyPrefixUser)
+}
+
+func sessionScopedState(state session.State) map[string]any {
+	scoped := map[string]any{}
+	for k, v := range state.All() {
+		if !isSharedStateKey(k) && !strings.HasPrefix(k, session.KeyPrefixTemp) {
+			scoped[k] = v
+		}
+	}
+	return scoped
+}
+
+// copySession creates a session and appends copies of events to it.
+// App and user state deltas are dropped from the copies, so replaying old events never overwrites shared state.
+// Appending sets the update time to the last copied event, so it is reset to now afterwards;
+// otherwise a copy of an old session would be expired by the TTL reaper right away.
+func (s *SQLSessionService) copySession(ctx context.Context, req *session.CreateRequest, events []*session.Event) (session.Session, error) {
+	created, err := s.Create(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session %s: %w", req.SessionID, err)
+	}
+	for _, event := range events {
+		copied := *event
+		copied.Actions.StateDelta = maps.Clone(event.Actions.StateDelta)
+		maps.DeleteFunc(copied.Actions.StateDelta, func(k string, _ any) bool { return isSharedStateKey(k) })
+		if err := s.AppendEvent(ctx, created.Session, &copied); err != nil {
+			return nil, fmt.Errorf("failed to copy event %s to session %s: %w", event.ID, req.SessionID, err)
+		}
+	}
+	if len(events) == 0 {
+		return created.Session, nil
+	}
+
+	err = s.db.WithContext(ctx).Model(&sessionRow{}).
+		Where("app_name = ? AND user_id = ? AND id = ?", req.AppName, req.UserID, created.Session.ID()).
+		Update("update_time", time.Now()).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to update session %s: %w", req.SessionID, err)
+	}
+	// reload, as the update time of the created session is now stale for further appends
+	resp, err := s.Get(ctx, &session.GetRequest{AppName: req.AppName, UserID: req.UserID, SessionID: created.Session.ID()})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Session, nil
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package short_term_memory_backends
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/configs"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+func newTestSQLiteService(t *testing.T, options SQLSessionOptions) *SQLSessionService {
+	t.Helper()
+	svc, err := NewSqliteSTMBackend(&SqliteBackendConfig{
+		CommonDatabaseConfig: &configs.CommonDatabaseConfig{DBUrl: filepath.Join(t.TempDir(), "sessions.db")},
+		SQLSessionOptions:    options,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = svc.Close() })
+	return svc
+}
+
+func createTestSession(t *testing.T, svc session.Service, appName, userID, sessionID string, texts ...string) session.Session {
+	t.Helper()
+	ctx := context.Background()
+	resp, err := svc.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+	require.NoError(t, err)
+	for i, text := range texts {
+		event := session.NewEvent("inv")
+		event.Author = "user"
+		event.Content = genai.NewContentFromText(text, genai.RoleUser)
+		event.Actions.StateDelta = map[string]any{"turn": float64(i + 1), "user:last": text}
+		require.NoError(t, svc.AppendEvent(ctx, resp.Session, event))
+	}
+	return resp.Session
+}
+
+func eventTexts(s session.Session) []string {
+	var texts []string
+	for event := range s.Events().All() {
+		texts = append(texts, event.Content.Parts[0].Text)
+	}
+	return texts
+}
+
+func TestSQLSessionService_Pool(t *testing.T) {
+	svc := newTestSQLiteService(t, SQLSessionOptions{MaxOpenConns: 3})
+	sqlDB, err := svc.db.DB()
+	require.NoError(t, err)
+	assert.Equal(t, 3, sqlDB.Stats().MaxOpenConnections)
+}
+
+func TestSQLSessionService_ListSessions(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestSQLiteService(t, SQLSessionOptions{})
+	for _, id := range []string{"s1", "s2", "s3"} {
+		createTestSession(t, svc, "app1", "user1", id, "hello "+id)
+	}
+	createTestSession(t, svc, "app2", "user2", "other", "hi")
+
+	t.Run("paginates most recent first", func(t *testing.T) {
+		page1, err := svc.ListSessions(ctx, &ListSessionsRequest{AppName: "app1", PageSize: 2})
+		require.NoError(t, err)
+		require.Len(t, page1.Sessions, 2)
+		assert.Equal(t, "s3", page1.Sessions[0].ID)
+		assert.Equal(t, "s2", page1.Sessions[1].ID)
+		require.NotEmpty(t, page1.NextPageToken)
+
+		page2, err := svc.ListSessions(ctx, &ListSessionsRequest{AppName: "app1", PageSize: 2, PageToken: page1.NextPageToken})
+		require.NoError(t, err)
+		require.Len(t, page2.Sessions, 1)
+		assert.Equal(t, "s1", page2.Sessions[0].ID)
+		assert.Empty(t, page2.NextPageToken)
+	})
+
+	t.Run("filters by update time across apps", func(t *testing.T) {
+		all, err := svc.ListSessions(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, all.Sessions, 4)
+
+		after, err := svc.ListSessions(ctx, &ListSessionsRequest{UpdatedAfter: all.Sessions[1].UpdateTime})
+		require.NoError(t, err)
+		assert.Len(t, after.Sessions, 2)
+
+		before, err := svc.ListSessions(ctx, &ListSessionsRequest{UpdatedBefore: all.Sessions[1].UpdateTime})
+		require.NoError(t, err)
+		assert.Len(t, before.Sessions, 2)
+	})
+
+	t.Run("invalid page token", func(t *testing.T) {
+		_, err := svc.ListSessions(ctx, &ListSessionsRequest{PageToken: "abc"})
+		assert.ErrorIs(t, err, ErrInvalidPageToken)
+	})
+}
+
+func TestSQLSessionService_DeleteExpiredSessions(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestSQLiteService(t, SQLSessionOptions{SessionTTL: time.Hour, ReapInterval: time.Hour})
+	createTestSession(t, svc, "app1", "user1", "stale", "old")
+	createTestSession(t, svc, "app1", "user1", "fresh", "new")
+	require.NoError(t, svc.db.Model(&sessionRow{}).Where("id = ?", "stale").
+		Update("update_time", time.Now().Add(-2*time.Hour)).Error)
+
+	n, err := svc.DeleteExpiredSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "stale"})
+	assert.Error(t, err)
+	var staleEvents int64
+	require.NoError(t, svc.db.Table(eventsTableName).Where("session_id = ?", "stale").Count(&staleEvents).Error)
+	assert.Zero(t, staleEvents)
+
+	_, err = svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "fresh"})
+	assert.NoError(t, err)
+
+	noTTL := newTestSQLiteService(t, SQLSessionOptions{})
+	n, err = noTTL.DeleteExpiredSessions(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestSQLSessionService_ForkSession(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestSQLiteService(t, SQLSessionOptions{})
+	src := createTestSession(t, svc, "app1", "user1", "src", "one", "two", "three")
+
+	t.Run("full fork", func(t *testing.T) {
+		fork, err := svc.ForkSession(ctx, &ForkSessionRequest{AppName: "app1", UserID: "user1", SessionID: "src", NewSessionID: "full"})
+		require.NoError(t, err)
+		assert.Equal(t, "full", fork.ID())
+
+		got, err := svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "full"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "two", "three"}, eventTexts(got.Session))
+		turn, err := got.Session.State().Get("turn")
+		require.NoError(t, err)
+		assert.Equal(t, float64(3), turn)
+	})
+
+	t.Run("fork at event", func(t *testing.T) {
+		second := src.Events().At(1)
+		fork, err := svc.ForkSession(ctx, &ForkSessionRequest{AppName: "app1", UserID: "user1", SessionID: "src", UpToEventID: second.ID})
+		require.NoError(t, err)
+		assert.NotEmpty(t, fork.ID())
+
+		got, err := svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: fork.ID()})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "two"}, eventTexts(got.Session))
+		turn, err := got.Session.State().Get("turn")
+		require.NoError(t, err)
+		assert.Equal(t, float64(2), turn)
+
+		// user state is shared and must not be rewound by the fork
+		last, err := got.Session.State().Get("user:last")
+		require.NoError(t, err)
+		assert.Equal(t, "three", last)
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, err := svc.ForkSession(ctx, &ForkSessionRequest{AppName: "app1", UserID: "user1", SessionID: "src", UpToEventID: "missing"})
+		assert.ErrorIs(t, err, ErrEventNotFound)
+	})
+
+	t.Run("original untouched", func(t *testing.T) {
+		got, err := svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "src"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "two", "three"}, eventTexts(got.Session))
+	})
+}
+
+func TestSQLSessionService_ExportImportJSONL(t *testing.T) {
+	ctx := context.Background()
+	src := newTestSQLiteService(t, SQLSessionOptions{})
+	createTestSession(t, src, "app1", "user1", "s1", "one", "two")
+	createTestSession(t, src, "app1", "user2", "s2", "hello")
+
+	var buf bytes.Buffer
+	n, err := src.ExportJSONL(ctx, &buf, &ListSessionsRequest{PageSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 2, strings.Count(buf.String(), "\n"))
+
+	dst := newTestSQLiteService(t, SQLSessionOptions{})
+	n, err = dst.ImportJSONL(ctx, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	got, err := dst.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, eventTexts(got.Session))
+	last, err := got.Session.State().Get("user:last")
+	require.NoError(t, err)
+	assert.Equal(t, "two", last)
+
+	_, err = dst.ImportJSONL(ctx, strings.NewReader("{not json}\n"))
+	assert.Error(t, err)
+}
+
+func TestSQLSessionService_CopiesOutliveTTL(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestSQLiteService(t, SQLSessionOptions{SessionTTL: time.Hour, ReapInterval: time.Hour})
+
+	resp, err := svc.Create(ctx, &session.CreateRequest{AppName: "app1", UserID: "user1", SessionID: "old"})
+	require.NoError(t, err)
+	event := session.NewEvent("inv")
+	event.Author = "user"
+	event.Content = genai.NewContentFromText("old", genai.RoleUser)
+	event.Timestamp = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, svc.AppendEvent(ctx, resp.Session, event))
+
+	fork, err := svc.ForkSession(ctx, &ForkSessionRequest{AppName: "app1", UserID: "user1", SessionID: "old", NewSessionID: "fork"})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), fork.LastUpdateTime(), time.Minute)
+
+	var buf bytes.Buffer
+	_, err = svc.ExportJSONL(ctx, &buf, &ListSessionsRequest{AppName: "app1"})
+	require.NoError(t, err)
+	dst := newTestSQLiteService(t, SQLSessionOptions{SessionTTL: time.Hour, ReapInterval: time.Hour})
+	_, err = dst.ImportJSONL(ctx, &buf)
+	require.NoError(t, err)
+
+	n, err := svc.DeleteExpiredSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	_, err = svc.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "fork"})
+	assert.NoError(t, err)
+
+	n, err = dst.DeleteExpiredSessions(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	imported, err := dst.Get(ctx, &session.GetRequest{AppName: "app1", UserID: "user1", SessionID: "old"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old"}, eventTexts(imported.Session))
+
+	// the returned copy stays usable for further appends
+	next := session.NewEvent("inv")
+	next.Author = "user"
+	next.Content = genai.NewContentFromText("next", genai.RoleUser)
+	assert.NoError(t, svc.AppendEvent(ctx, fork, next))
+}
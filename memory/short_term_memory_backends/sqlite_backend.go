@@ -16,21 +16,19 @@ package short_term_memory_backends
 
 import (
 	"fmt"
-	"log/slog"
 
 	"github.com/volcengine/veadk-go/configs"
 	"github.com/volcengine/veadk-go/log"
-	"google.golang.org/adk/session"
 	"google.golang.org/adk/session/database"
 	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
 )
 
 type SqliteBackendConfig struct {
 	*configs.CommonDatabaseConfig
+	SQLSessionOptions
 }
 
-func NewSqliteSTMBackend(config *SqliteBackendConfig) (session.Service, error) {
+func NewSqliteSTMBackend(config *SqliteBackendConfig) (*SQLSessionService, error) {
 	if config == nil {
 		return nil, fmt.Errorf("sqlite config is nil")
 	}
@@ -39,10 +37,13 @@ func NewSqliteSTMBackend(config *SqliteBackendConfig) (session.Service, error) {
 		log.Info("SQLite DBUrl is empty, using in-memory database")
 	}
 
-	sessionService, err := database.NewSessionService(
-		sqlite.Open(config.DBUrl),
-		&gorm.Config{PrepareStmt: true, Logger: log.NewGormLogger(slog.LevelError)},
-	)
+	db, err := openSessionDB(sqlite.Open(config.DBUrl), config.SQLSessionOptions)
+	if err != nil {
+		log.Error(fmt.Sprintf("open SQLite database failed: %v", err))
+		return nil, err
+	}
+	// the ADK session service shares the connection pool of db
+	sessionService, err := database.NewSessionService(sqlite.New(sqlite.Config{Conn: db.ConnPool}), gormConfig())
 	if err != nil {
 		log.Error(fmt.Sprintf("init SQLite DatabaseSessionService failed: %v", err))
 		return nil, err
@@ -51,5 +52,5 @@ func NewSqliteSTMBackend(config *SqliteBackendConfig) (session.Service, error) {
 		log.Error(fmt.Sprintf("AutoMigrate SQLite DatabaseSessionService failed: %v", initErr))
 	}
 
-	return sessionService, nil
+	return newSQLSessionService(sessionService, db, config.SQLSessionOptions), nil
 }
@@ -53,7 +53,7 @@ func TestNewShortTermMemory(t *testing.T) {
 	for _, tt := range tests {
 		mockey.PatchConvey(tt.name, t, func() {
 			t.Run(tt.name, func(t *testing.T) {
-				mockey.Mock(short_term_memory_backends.NewPostgreSqlSTMBackend).Return(&mockSessionServiceImpl{}, nil).Build()
+				mockey.Mock(short_term_memory_backends.NewPostgreSqlSTMBackend).Return(&short_term_memory_backends.SQLSessionService{Service: &mockSessionServiceImpl{}}, nil).Build()
 				sessionService, err := NewShortTermMemoryService(tt.backend, nil)
 				assert.True(t, tt.wantErr == (err != nil))
 				if err == nil {
@@ -104,7 +104,7 @@ func (m *arkModel) GenerateContent(ctx context.Context, req *model.LLMRequest, s
 // convertArkRequest converts a genai LLMRequest to an ARK SDK CreateChatCompletionRequest.
 func (m *arkModel) convertArkRequest(req *model.LLMRequest) (*arkmodel.CreateChatCompletionRequest, error) {
 	arkReq := &arkmodel.CreateChatCompletionRequest{
-		Model:    m.name,
+		Model:    requestModel(req, m.name),
 		Messages: make([]*arkmodel.ChatCompletionMessage, 0),
 		StreamOptions: &arkmodel.StreamOptions{
 			IncludeUsage: true,
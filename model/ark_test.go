@@ -346,6 +346,19 @@ func TestArkModel_ConvertRequest(t *testing.T) {
 		assert.Equal(t, "You are helpful.", *arkReq.Messages[0].Content.StringValue)
 	})
 
+	t.Run("request_model", func(t *testing.T) {
+		am := &arkModel{name: "test-model", config: &ArkClientConfig{}}
+
+		arkReq, err := am.convertArkRequest(&model.LLMRequest{Contents: genai.Text("Hello")})
+		assert.NoError(t, err)
+		assert.Equal(t, "test-model", arkReq.Model)
+
+		// plugins may switch the model before the call
+		arkReq, err = am.convertArkRequest(&model.LLMRequest{Model: "cheaper-model", Contents: genai.Text("Hello")})
+		assert.NoError(t, err)
+		assert.Equal(t, "cheaper-model", arkReq.Model)
+	})
+
 	t.Run("generation_config", func(t *testing.T) {
 		am := &arkModel{name: "test-model", config: &ArkClientConfig{}}
 		req := &model.LLMRequest{
@@ -22,6 +22,15 @@ import (
 	"google.golang.org/genai"
 )
 
+// requestModel returns the model named by req, which plugins may rewrite before the call,
+// e.g. to downgrade to a cheaper model, falling back to the configured model.
+func requestModel(req *model.LLMRequest, name string) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return name
+}
+
 // mapFinishReason converts an OpenAI/ARK finish reason string to a genai.FinishReason.
 func mapFinishReason(reason string) genai.FinishReason {
 	switch reason {
@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"slices"
+	"strings"
+)
+
+// InputModesProvider is implemented by models which know the media they understand,
+// as MIME types beside text/plain.
+type InputModesProvider interface {
+	InputModes() []string
+}
+
+var (
+	imageInputModes = []string{"image/png", "image/jpeg", "image/webp"}
+	videoInputModes = []string{"video/mp4"}
+
+	// inputModeRules match model names, first match wins.
+	inputModeRules = []struct {
+		substrings []string
+		modes      []string
+	}{
+		{[]string{"doubao-seed", "vision"}, slices.Concat(imageInputModes, videoInputModes)},
+		{[]string{"gpt-4o", "gpt-4.1", "gpt-5", "gemini", "claude", "-vl"}, imageInputModes},
+	}
+)
+
+// InputModes returns the media accepted by the model named modelName beside text,
+// nil for text-only or unknown models.
+func InputModes(modelName string) []string {
+	name := strings.ToLower(modelName)
+	for _, rule := range inputModeRules {
+		for _, s := range rule.substrings {
+			if strings.Contains(name, s) {
+				return slices.Clone(rule.modes)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *arkModel) InputModes() []string {
+	return InputModes(m.name)
+}
+
+func (m *openAIModel) InputModes() []string {
+	return InputModes(m.name)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputModes(t *testing.T) {
+	assert.Contains(t, InputModes("doubao-seed-1-6-250615"), "video/mp4")
+	assert.Contains(t, InputModes("doubao-1-5-vision-pro-32k"), "image/jpeg")
+	assert.Equal(t, imageInputModes, InputModes("GPT-4o-mini"))
+	assert.Nil(t, InputModes("deepseek-v3"))
+
+	llm, err := NewArkModel(context.Background(), "doubao-seed-1-6-250615", &ArkClientConfig{APIKey: "key"})
+	require.NoError(t, err)
+	provider, ok := llm.(InputModesProvider)
+	require.True(t, ok)
+	assert.Contains(t, provider.InputModes(), "image/png")
+}
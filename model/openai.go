@@ -228,7 +228,7 @@ type promptTokensDetails struct {
 
 func (m *openAIModel) convertOpenAIRequest(req *model.LLMRequest) (*openAIRequest, error) {
 	openaiReq := &openAIRequest{
-		Model:    m.name,
+		Model:    requestModel(req, m.name),
 		Messages: make([]message, 0),
 	}
 
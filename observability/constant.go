@@ -57,6 +57,16 @@ const (
 	SpanPrefixInvokeAgent     = SpanInvokeAgent + " "
 	SpanPrefixGenerateContent = OperationNameGenerateContent + " "
 	SpanPrefixExecuteTool     = SpanExecuteTool + " "
+
+	// Workflow agent spans, suffixed with the workflow agent name
+	SpanLoopIteration        = "loop_iteration"
+	SpanParallelBranch       = "parallel_branch"
+	SpanSequentialStep       = "sequential_step"
+	SpanGraphStep            = "graph_step"
+	SpanPrefixLoopIteration  = SpanLoopIteration + " "
+	SpanPrefixParallelBranch = SpanParallelBranch + " "
+	SpanPrefixSequentialStep = SpanSequentialStep + " "
+	SpanPrefixGraphStep      = SpanGraphStep + " "
 )
 
 // Metric names
@@ -75,6 +85,15 @@ const (
 
 	// AgentKit specific metrics
 	MetricNameAgentKitDuration = "agentkit_runtime_operation_latency"
+
+	// Workflow agent metrics
+	MetricNameWorkflowStepDuration = "gen_ai.workflow.step.duration"
+	MetricNameWorkflowSteps        = "gen_ai.workflow.steps"
+
+	// Agent server admission metrics
+	MetricNameServerThrottledRequests = "veadk.server.requests.throttled"
+	MetricNameServerQueueWait         = "veadk.server.queue.wait"
+	MetricNameServerActiveInvocations = "veadk.server.invocations.active"
 )
 
 // General attributes
@@ -173,6 +192,7 @@ const (
 	AttrGenAIUsageTotalTokens              = "gen_ai.usage.total_tokens"
 	AttrGenAIUsageCacheCreationInputTokens = "gen_ai.usage.cache_creation_input_tokens"
 	AttrGenAIUsageCacheReadInputTokens     = "gen_ai.usage.cache_read_input_tokens"
+	AttrGenAIUsageCost                     = "gen_ai.usage.cost"
 	AttrGenAIMessages                      = "gen_ai.messages"
 	AttrGenAIChoice                        = "gen_ai.choice"
 	AttrGenAIResponsePromptTokenCount      = "gen_ai.response.prompt_token_count"
@@ -189,6 +209,52 @@ const (
 	EventGenAIContentCompletion = "gen_ai.content.completion"
 	EventGenAIUserMessage       = "gen_ai.user.message"
 	EventGenAIChoice            = "gen_ai.choice"
+
+	// EventWorkflowMaxIterations is added to the loop agent span when MaxIterations is exhausted.
+	EventWorkflowMaxIterations = "gen_ai.workflow.max_iterations_reached"
+)
+
+// Workflow agent attributes
+const (
+	AttrGenAIWorkflowType          = "gen_ai.workflow.type"
+	AttrGenAIWorkflowName          = "gen_ai.workflow.name"
+	AttrGenAIWorkflowStepIndex     = "gen_ai.workflow.step.index"
+	AttrGenAIWorkflowSubAgents     = "gen_ai.workflow.sub_agents"
+	AttrGenAIWorkflowBranch        = "gen_ai.workflow.branch"
+	AttrGenAIWorkflowMaxIterations = "gen_ai.workflow.max_iterations"
+	AttrGenAIWorkflowExitReason    = "gen_ai.workflow.exit_reason"
+	AttrGenAIWorkflowEscalatedBy   = "gen_ai.workflow.escalated_by"
+	AttrGenAIWorkflowNode          = "gen_ai.workflow.node"
+
+	WorkflowTypeLoop       = "loop"
+	WorkflowTypeParallel   = "parallel"
+	WorkflowTypeSequential = "sequential"
+	WorkflowTypeGraph      = "graph"
+
+	// Exit reasons of a workflow step
+	WorkflowExitCompleted     = "completed"
+	WorkflowExitEscalated     = "escalated"
+	WorkflowExitMaxIterations = "max_iterations"
+	WorkflowExitError         = "error"
+	WorkflowExitCancelled     = "cancelled"
+)
+
+// Agent server admission attributes
+const (
+	AttrServerThrottleReason = "veadk.server.throttle.reason"
+
+	// Reasons a request is rejected with 429
+	ThrottleReasonGlobalRate  = "global_rate"
+	ThrottleReasonUserRate    = "user_rate"
+	ThrottleReasonConcurrency = "concurrency"
+	ThrottleReasonSessionBusy = "session_busy"
+)
+
+// Router agent attributes
+const (
+	AttrRouterAgent      = "veadk.router.agent"
+	AttrRouterConfidence = "veadk.router.confidence"
+	AttrRouterReason     = "veadk.router.reason"
 )
 
 // Tool attributes
@@ -199,6 +265,8 @@ const (
 	ADKAttrLLMResponseName  = ADKAttributePrefix + "llm_response"
 	ADKAttrInvocationID     = ADKAttributePrefix + "invocation_id"
 	ADKAttrSessionID        = ADKAttributePrefix + "session_id"
+	// ADKAttrUsageCacheReadInputTokens is the cached input tokens of the ADK generate_content span.
+	ADKAttrUsageCacheReadInputTokens = "gen_ai.usage.cache_read.input_tokens"
 
 	AttrGenAIOperationName   = "gen_ai.operation.name"
 	AttrGenAIOperationType   = "gen_ai.operation.type"
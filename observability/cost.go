@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"slices"
+
+	"github.com/volcengine/veadk-go/cost"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// withLLMCost adds the cost of the token usage recorded on an LLM span, priced with the
+// cost.pricing table. The attributes are returned unchanged if the model has no pricing.
+func withLLMCost(pricing *cost.PricingTable, attrs []attribute.KeyValue) []attribute.KeyValue {
+	if pricing.Len() == 0 {
+		return attrs
+	}
+
+	modelName := getStringAttribute(attrs, AttrGenAIResponseModel, getStringAttribute(attrs, AttrGenAIRequestModel, ""))
+	var usage cost.Usage
+	for _, kv := range attrs {
+		switch string(kv.Key) {
+		case AttrGenAIUsageInputTokens:
+			usage.InputTokens = kv.Value.AsInt64()
+		case AttrGenAIUsageOutputTokens:
+			usage.OutputTokens = kv.Value.AsInt64()
+		case AttrGenAIUsageCacheReadInputTokens, ADKAttrUsageCacheReadInputTokens:
+			usage.CachedTokens = max(usage.CachedTokens, kv.Value.AsInt64())
+		case AttrGenAIUsageCost:
+			return attrs
+		}
+	}
+	if usage.InputTokens == 0 && usage.OutputTokens == 0 {
+		return attrs
+	}
+
+	c, ok := pricing.Cost(modelName, usage)
+	if !ok {
+		return attrs
+	}
+	return append(slices.Clip(attrs), attribute.Float64(AttrGenAIUsageCost, c))
+}
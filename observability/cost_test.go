@@ -0,0 +1,46 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/volcengine/veadk-go/configs"
+	"github.com/volcengine/veadk-go/cost"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestWithLLMCost(t *testing.T) {
+	pricing := cost.NewPricingTable([]configs.ModelPricing{{Model: "doubao-seed", Input: 1, Output: 10, CachedInput: 0.5}})
+	usage := []attribute.KeyValue{
+		attribute.Int(AttrGenAIUsageInputTokens, 1_000_000),
+		attribute.Int(AttrGenAIUsageOutputTokens, 100_000),
+		attribute.Int(ADKAttrUsageCacheReadInputTokens, 400_000),
+	}
+
+	attrs := append([]attribute.KeyValue{attribute.String(AttrGenAIRequestModel, "doubao-seed-1-6")}, usage...)
+	got := withLLMCost(pricing, attrs)
+	assert.Len(t, got, len(attrs)+1)
+	assert.Equal(t, attribute.Float64(AttrGenAIUsageCost, 0.6+0.2+1), got[len(attrs)])
+	assert.Len(t, attrs, 4, "input attributes are not modified")
+
+	// unknown model, no usage or no pricing leave the attributes unchanged
+	unknown := append([]attribute.KeyValue{attribute.String(AttrGenAIRequestModel, "other")}, usage...)
+	assert.Equal(t, unknown, withLLMCost(pricing, unknown))
+	noUsage := []attribute.KeyValue{attribute.String(AttrGenAIRequestModel, "doubao-seed")}
+	assert.Equal(t, noUsage, withLLMCost(pricing, noUsage))
+	assert.Equal(t, attrs, withLLMCost(cost.NewPricingTable(nil), attrs))
+}
@@ -232,6 +232,27 @@ func NewMultiExporter(ctx context.Context, cfg *configs.OpenTelemetryConfig) (tr
 		}
 	}
 
+	if cfg.OTLP != nil {
+		if firstNonEmpty(cfg.OTLP.Endpoint, cfg.OTLP.TracesEndpoint, cfg.OTLP.MetricsEndpoint, cfg.OTLP.LogsEndpoint) == "" {
+			return nil, fmt.Errorf("OTLP endpoint or a signal endpoint is required")
+		}
+		if cfg.OTLP.Endpoint == "" && cfg.OTLP.TracesEndpoint == "" {
+			// the OTLP target may only receive metrics or logs
+			log.Debug("OTLP traces endpoint is not set, skipping OTLP span exporter")
+		} else if exp, err := NewOTLPExporter(ctx, cfg.OTLP); err == nil {
+			exporters = append(exporters, exp)
+			log.Info("Exporting spans to OTLP", "endpoint", firstNonEmpty(cfg.OTLP.TracesEndpoint, cfg.OTLP.Endpoint))
+		} else {
+			return nil, err
+		}
+	}
+
+	if cfg.TraceViewer != nil && cfg.TraceViewer.Enable {
+		exporters = append(exporters, NewTraceViewer(cfg.TraceViewer))
+		path, _ := TraceViewerEndpoint()
+		log.Info("Keeping recent traces for the trace viewer", "path", path, "max_traces", cfg.TraceViewer.MaxTraces)
+	}
+
 	log.Debug("trace data will be exported", "exporter count", len(exporters))
 
 	if len(exporters) == 0 {
@@ -295,6 +316,25 @@ func NewMetricReader(ctx context.Context, cfg *configs.OpenTelemetryConfig) ([]s
 		}
 	}
 
+	if cfg.OTLP != nil && (cfg.OTLP.Endpoint != "" || cfg.OTLP.MetricsEndpoint != "") {
+		if exp, err := NewOTLPMetricExporter(ctx, cfg.OTLP); err == nil {
+			readers = append(readers, sdkmetric.NewPeriodicReader(exp))
+			log.Info("Exporting metrics to OTLP", "endpoint", firstNonEmpty(cfg.OTLP.MetricsEndpoint, cfg.OTLP.Endpoint))
+		} else {
+			log.Warn("Failed to create OTLP metric exporter", "err", err)
+		}
+	}
+
+	if cfg.Prometheus != nil && cfg.Prometheus.Enable {
+		if reader, err := NewPrometheusReader(cfg.Prometheus); err == nil {
+			readers = append(readers, reader)
+			path, _ := PrometheusEndpoint()
+			log.Info("Exposing metrics to Prometheus", "path", path)
+		} else {
+			log.Warn("Failed to create Prometheus metric reader", "err", err)
+		}
+	}
+
 	log.Debug("metric data will be exported", "exporter count", len(readers))
 
 	return readers, nil
@@ -23,6 +23,7 @@ import (
 	"github.com/volcengine/veadk-go/log"
 
 	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -61,7 +62,7 @@ func Init(ctx context.Context, cfg *configs.ObservabilityConfig) error {
 
 // Shutdown shuts down the observability system, flushing all spans and metrics.
 func Shutdown(ctx context.Context) error {
-	log.Info("Shut down TracerProvider and MeterProvider")
+	log.Info("Shut down TracerProvider, MeterProvider and LoggerProvider")
 	var errs []error
 
 	// 0. End all active root invocation spans to ensure they are recorded and flushed.
@@ -86,8 +87,19 @@ func Shutdown(ctx context.Context) error {
 	}
 
 	// 2. Shutdown local MeterProvider if exists
-	if meterProvider != nil {
-		if err := meterProvider.Shutdown(ctx); err != nil {
+	for _, mp := range []*sdkmetric.MeterProvider{meterProvider, prometheusMeterProvider} {
+		if mp == nil {
+			continue
+		}
+		if err := mp.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// 3. Stop exporting logs and shutdown local LoggerProvider if exists
+	if loggerProvider != nil {
+		log.SetExportHandler(nil)
+		if err := loggerProvider.Shutdown(ctx); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -109,7 +121,12 @@ func initWithConfig(ctx context.Context, cfg *configs.OpenTelemetryConfig) error
 		errs = append(errs, err)
 	}
 
-	if !traceInitialized && !metricsInitialized {
+	logsInitialized, err := initializeLoggerProvider(ctx, cfg)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if !traceInitialized && !metricsInitialized && !logsInitialized {
 		log.Info("No observability exporters are configured, observability data will not be exported")
 		return ErrNoExporters
 	}
@@ -125,28 +142,41 @@ func newVeadkExporter(exp sdktrace.SpanExporter) sdktrace.SpanExporter {
 }
 
 // setGlobalTracerProvider configures the global OpenTelemetry TracerProvider.
-func setGlobalTracerProvider(exp sdktrace.SpanExporter, spanProcessors ...sdktrace.SpanProcessor) {
+func setGlobalTracerProvider(exp sdktrace.SpanExporter, cfg *configs.TracingConfig, spanProcessors ...sdktrace.SpanProcessor) error {
 	// Always wrap with VeADKTranslatedExporter to ensure ADK-internal spans are correctly mapped
 	translatedExp := newVeadkExporter(exp)
 	if translatedExp == nil {
-		return
+		return nil
 	}
 
-	// Default processors
-	allProcessors := append([]sdktrace.SpanProcessor{NewVeADKSpanProcessor()}, spanProcessors...)
+	filter, err := newSpanFilter(cfg)
+	if err != nil {
+		return err
+	}
 
 	// Use BatchSpanProcessor for all exporters to ensure performance and batching.
-	finalProcessor := sdktrace.NewBatchSpanProcessor(translatedExp)
+	// The VeADK processor feeds it, so that sampling, redaction and truncation apply before export.
+	finalProcessor := newVeADKExportSpanProcessor(sdktrace.NewBatchSpanProcessor(translatedExp), filter)
+
+	// Default processors
+	allProcessors := append([]sdktrace.SpanProcessor{finalProcessor}, spanProcessors...)
+
+	var sampler sdktrace.Sampler
+	if cfg != nil {
+		sampler = newSampler(cfg.Sampling)
+	}
 
 	// 1. Try to register with existing TracerProvider if it's an SDK TracerProvider
 	globalTP := otel.GetTracerProvider()
 	if sdkTP, ok := globalTP.(*sdktrace.TracerProvider); ok {
 		log.Info("Registering ADK Processors to existing global TracerProvider")
+		if sampler != nil {
+			log.Warn("Sampling config is ignored, the existing global TracerProvider keeps its sampler")
+		}
 		for _, sp := range allProcessors {
 			sdkTP.RegisterSpanProcessor(sp)
 		}
-		sdkTP.RegisterSpanProcessor(finalProcessor)
-		return
+		return nil
 	}
 
 	// 2. Fallback: Create a new global TracerProvider
@@ -155,12 +185,14 @@ func setGlobalTracerProvider(exp sdktrace.SpanExporter, spanProcessors ...sdktra
 	for _, sp := range allProcessors {
 		opts = append(opts, sdktrace.WithSpanProcessor(sp))
 	}
+	if sampler != nil {
+		opts = append(opts, sdktrace.WithSampler(sampler))
+	}
 
-	tp := sdktrace.NewTracerProvider(
-		append(opts, sdktrace.WithSpanProcessor(finalProcessor))...,
-	)
+	tp := sdktrace.NewTracerProvider(opts...)
 
 	otel.SetTracerProvider(tp)
+	return nil
 }
 
 func initializeTraceProvider(ctx context.Context, cfg *configs.OpenTelemetryConfig) (bool, error) {
@@ -177,7 +209,9 @@ func initializeTraceProvider(ctx context.Context, cfg *configs.OpenTelemetryConf
 		return false, nil
 	}
 
-	setGlobalTracerProvider(exp)
+	if err := setGlobalTracerProvider(exp, cfg.Tracing); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
@@ -196,6 +230,10 @@ func initializeMeterProvider(ctx context.Context, cfg *configs.OpenTelemetryConf
 		return false, nil
 	}
 
-	registerMetrics(readers)
+	var views []sdkmetric.View
+	if cfg.Prometheus != nil && cfg.Prometheus.Enable {
+		views = prometheusViews(cfg.Prometheus)
+	}
+	registerMetrics(readers, views...)
 	return true, nil
 }
@@ -32,7 +32,7 @@ func TestSetGlobalTracerProvider(t *testing.T) {
 
 	exporter := tracetest.NewInMemoryExporter()
 	// Just verifies no panic and provider is updated
-	setGlobalTracerProvider(exporter)
+	assert.NoError(t, setGlobalTracerProvider(exporter, nil))
 
 	// Ensure we can start a span
 	ctx := context.Background()
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"context"
+
+	"github.com/volcengine/veadk-go/configs"
+	"github.com/volcengine/veadk-go/log"
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel/log/global"
+	olog "go.opentelemetry.io/otel/sdk/log"
+)
+
+var loggerProvider *olog.LoggerProvider
+
+// NewLogProcessors creates one or more log processors based on the provided configuration.
+func NewLogProcessors(ctx context.Context, cfg *configs.OpenTelemetryConfig) ([]olog.Processor, error) {
+	var processors []olog.Processor
+
+	if cfg.ApmPlus != nil && cfg.ApmPlus.Endpoint != "" && cfg.ApmPlus.APIKey != "" {
+		if exp, err := NewAPMPlusLogExporter(ctx, cfg.ApmPlus); err == nil {
+			processors = append(processors, olog.NewBatchProcessor(exp))
+			log.Info("Exporting logs to APMPlus", "endpoint", cfg.ApmPlus.Endpoint, "service_name", cfg.ApmPlus.ServiceName)
+		} else {
+			log.Warn("Failed to create APMPlus log exporter", "err", err)
+		}
+	}
+
+	if cfg.OTLP != nil && (cfg.OTLP.Endpoint != "" || cfg.OTLP.LogsEndpoint != "") {
+		if exp, err := NewOTLPLogExporter(ctx, cfg.OTLP); err == nil {
+			processors = append(processors, olog.NewBatchProcessor(exp))
+			log.Info("Exporting logs to OTLP", "endpoint", firstNonEmpty(cfg.OTLP.LogsEndpoint, cfg.OTLP.Endpoint))
+		} else {
+			log.Warn("Failed to create OTLP log exporter", "err", err)
+		}
+	}
+
+	log.Debug("log data will be exported", "exporter count", len(processors))
+
+	return processors, nil
+}
+
+// NewAPMPlusLogExporter creates an OTLP log exporter for APMPlus.
+func NewAPMPlusLogExporter(ctx context.Context, cfg *configs.ApmPlusConfig) (olog.Exporter, error) {
+	return createLogClient(ctx, cfg.Endpoint, cfg.Protocol, map[string]string{
+		"X-ByteAPM-AppKey": cfg.APIKey,
+	})
+}
+
+// initializeLoggerProvider sets the global LoggerProvider and bridges the default slog logger to it,
+// so that every log line carries the trace and span of its context.
+func initializeLoggerProvider(ctx context.Context, cfg *configs.OpenTelemetryConfig) (bool, error) {
+	if cfg == nil || cfg.EnableLogs == nil || !*cfg.EnableLogs {
+		log.Debug("Logger provider is not enabled")
+		return false, nil
+	}
+
+	processors, err := NewLogProcessors(ctx, cfg)
+	if err != nil {
+		return false, err
+	}
+
+	if len(processors) == 0 {
+		return false, nil
+	}
+
+	registerLogs(processors)
+	return true, nil
+}
+
+func registerLogs(processors []olog.Processor) {
+	var opts []olog.LoggerProviderOption
+	for _, p := range processors {
+		opts = append(opts, olog.WithProcessor(p))
+	}
+
+	lp := olog.NewLoggerProvider(opts...)
+	loggerProvider = lp
+	global.SetLoggerProvider(lp)
+	log.SetExportHandler(otelslog.NewHandler(InstrumentationName, otelslog.WithLoggerProvider(lp)))
+}
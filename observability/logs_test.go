@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/configs"
+	"github.com/volcengine/veadk-go/log"
+	otellog "go.opentelemetry.io/otel/log"
+	olog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type memoryLogExporter struct {
+	mu      sync.Mutex
+	records []olog.Record
+}
+
+func (e *memoryLogExporter) Export(_ context.Context, records []olog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryLogExporter) Shutdown(context.Context) error { return nil }
+
+func (e *memoryLogExporter) ForceFlush(context.Context) error { return nil }
+
+func TestRegisterLogs(t *testing.T) {
+	exporter := &memoryLogExporter{}
+	registerLogs([]olog.Processor{olog.NewSimpleProcessor(exporter)})
+	t.Cleanup(func() {
+		log.SetExportHandler(nil)
+		loggerProvider = nil
+	})
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}, TraceFlags: trace.FlagsSampled})
+	ctx := log.ContextWithSession(trace.ContextWithSpanContext(context.Background(), sc), "s1", "inv1")
+	log.InfoContext(ctx, "tool called", "tool", "search")
+	log.Debug("below level")
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	require.Len(t, exporter.records, 1)
+	record := exporter.records[0]
+	assert.Equal(t, "tool called", record.Body().AsString())
+	assert.Equal(t, sc.TraceID(), record.TraceID())
+	assert.Equal(t, sc.SpanID(), record.SpanID())
+
+	attrs := map[string]string{}
+	record.WalkAttributes(func(kv otellog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value.AsString()
+		return true
+	})
+	assert.Equal(t, "search", attrs["tool"])
+	assert.Equal(t, "s1", attrs[log.SessionIDKey])
+	assert.Equal(t, "inv1", attrs[log.InvocationIDKey])
+}
+
+func TestInitializeLoggerProvider(t *testing.T) {
+	ok, err := initializeLoggerProvider(context.Background(), &configs.OpenTelemetryConfig{})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	enabled := true
+	ok, err = initializeLoggerProvider(context.Background(), &configs.OpenTelemetryConfig{EnableLogs: &enabled})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
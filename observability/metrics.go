@@ -57,6 +57,8 @@ var (
 	meterOnce     sync.Once
 	instrumentsMu sync.RWMutex
 	meterProvider *sdkmetric.MeterProvider
+	// prometheusMeterProvider feeds the Prometheus reader, see registerMetrics.
+	prometheusMeterProvider *sdkmetric.MeterProvider
 
 	// Standard Gen AI Metrics
 	tokenUsageHistograms        []metric.Float64Histogram
@@ -74,23 +76,53 @@ var (
 
 	// special metrics for AgentKit
 	agentkitDurationHistograms []metric.Float64Histogram
+
+	// workflow agent metrics
+	workflowStepDurationHistograms []metric.Float64Histogram
+	workflowStepsCounters          []metric.Int64Counter
+
+	// agent server admission metrics
+	serverThrottledCounters       []metric.Int64Counter
+	serverQueueWaitHistograms     []metric.Float64Histogram
+	serverActiveInvocationsGauges []metric.Int64UpDownCounter
 )
 
 // registerMetrics configures a single global OpenTelemetry MeterProvider.
-func registerMetrics(readers []sdkmetric.Reader) {
+// A Prometheus reader gets a MeterProvider of its own, as views apply to a whole provider and
+// the Prometheus bucket views must not change the histograms pushed by the other readers.
+func registerMetrics(readers []sdkmetric.Reader, views ...sdkmetric.View) {
 	meterOnce.Do(func() {
-		options := []sdkmetric.Option{}
-		for _, r := range readers {
-			options = append(options, sdkmetric.WithReader(r))
+		meterProvider, prometheusMeterProvider = newMeterProviders(readers, views)
+		if meterProvider != nil {
+			otel.SetMeterProvider(meterProvider)
+			initializeInstruments(meterProvider.Meter(InstrumentationName))
+		}
+		if prometheusMeterProvider != nil {
+			if meterProvider == nil {
+				otel.SetMeterProvider(prometheusMeterProvider)
+			}
+			initializeInstruments(prometheusMeterProvider.Meter(InstrumentationName))
 		}
-
-		mp := sdkmetric.NewMeterProvider(options...)
-		meterProvider = mp
-		otel.SetMeterProvider(mp)
-		initializeInstruments(mp.Meter(InstrumentationName))
 	})
 }
 
+// newMeterProviders returns the provider of the push readers and the provider of the Prometheus reader
+// with the views, either of which is nil without such readers.
+func newMeterProviders(readers []sdkmetric.Reader, views []sdkmetric.View) (push, pull *sdkmetric.MeterProvider) {
+	options := []sdkmetric.Option{}
+	for _, r := range readers {
+		if isPrometheusReader(r) {
+			pull = sdkmetric.NewMeterProvider(sdkmetric.WithReader(r), sdkmetric.WithView(views...))
+			continue
+		}
+		options = append(options, sdkmetric.WithReader(r))
+	}
+	if len(options) > 0 {
+		push = sdkmetric.NewMeterProvider(options...)
+	}
+	return push, pull
+}
+
 // initializeInstruments initializes the metrics instruments for the provided meter.
 // This function is internal and should not be called directly
 func initializeInstruments(m metric.Meter) {
@@ -198,6 +230,53 @@ func initializeInstruments(m metric.Meter) {
 	); err == nil {
 		agentkitDurationHistograms = append(agentkitDurationHistograms, h)
 	}
+
+	// Workflow step duration, per loop iteration, parallel branch or sequential step
+	if h, err := m.Float64Histogram(
+		MetricNameWorkflowStepDuration,
+		metric.WithDescription("Duration of workflow agent steps in seconds"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(agentkitDurationSecondBuckets...),
+	); err == nil {
+		workflowStepDurationHistograms = append(workflowStepDurationHistograms, h)
+	}
+
+	// Workflow step counter
+	if c, err := m.Int64Counter(
+		MetricNameWorkflowSteps,
+		metric.WithDescription("Number of workflow agent steps by exit reason"),
+		metric.WithUnit("1"),
+	); err == nil {
+		workflowStepsCounters = append(workflowStepsCounters, c)
+	}
+
+	// Requests rejected by the server rate limits
+	if c, err := m.Int64Counter(
+		MetricNameServerThrottledRequests,
+		metric.WithDescription("Number of invocations rejected with 429 by reason"),
+		metric.WithUnit("1"),
+	); err == nil {
+		serverThrottledCounters = append(serverThrottledCounters, c)
+	}
+
+	// Time invocations wait for a concurrency slot or their session
+	if h, err := m.Float64Histogram(
+		MetricNameServerQueueWait,
+		metric.WithDescription("Time invocations wait before being admitted in seconds"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(genAIClientOperationDurationBuckets...),
+	); err == nil {
+		serverQueueWaitHistograms = append(serverQueueWaitHistograms, h)
+	}
+
+	// Invocations currently running
+	if g, err := m.Int64UpDownCounter(
+		MetricNameServerActiveInvocations,
+		metric.WithDescription("Number of invocations currently running"),
+		metric.WithUnit("1"),
+	); err == nil {
+		serverActiveInvocationsGauges = append(serverActiveInvocationsGauges, g)
+	}
 }
 
 // RecordTokenUsage records the number of tokens used.
@@ -275,3 +354,35 @@ func RecordAgentKitDuration(ctx context.Context, durationSeconds float64, attrs
 		histogram.Record(ctx, durationSeconds, metric.WithAttributes(attrs...))
 	}
 }
+
+// RecordWorkflowStep records the duration of a workflow agent step and counts it.
+func RecordWorkflowStep(ctx context.Context, durationSeconds float64, attrs ...attribute.KeyValue) {
+	for _, histogram := range workflowStepDurationHistograms {
+		histogram.Record(ctx, durationSeconds, metric.WithAttributes(attrs...))
+	}
+	for _, counter := range workflowStepsCounters {
+		counter.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+}
+
+// RecordThrottledRequest counts an invocation rejected by the server admission limits.
+func RecordThrottledRequest(ctx context.Context, reason string, attrs ...attribute.KeyValue) {
+	attrs = append(attrs, attribute.String(AttrServerThrottleReason, reason))
+	for _, counter := range serverThrottledCounters {
+		counter.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+}
+
+// RecordQueueWait records how long an invocation waited before being admitted.
+func RecordQueueWait(ctx context.Context, waitSeconds float64, attrs ...attribute.KeyValue) {
+	for _, histogram := range serverQueueWaitHistograms {
+		histogram.Record(ctx, waitSeconds, metric.WithAttributes(attrs...))
+	}
+}
+
+// AddActiveInvocations adjusts the number of running invocations by delta.
+func AddActiveInvocations(ctx context.Context, delta int64, attrs ...attribute.KeyValue) {
+	for _, gauge := range serverActiveInvocationsGauges {
+		gauge.Add(ctx, delta, metric.WithAttributes(attrs...))
+	}
+}
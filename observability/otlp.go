@@ -0,0 +1,253 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/volcengine/veadk-go/configs"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	olog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	otlpCompressionGzip = "gzip"
+	otlpCompressionNone = "none"
+
+	otlpProtocolGRPC         = "grpc"
+	otlpProtocolHTTPProtobuf = "http/protobuf"
+)
+
+var ErrOTLPEndpointNotSet = errors.New("OTLP endpoint is not set")
+
+// otlpSignal is the resolved target of one signal of an OTLP exporter config.
+type otlpSignal struct {
+	endpoint    string
+	http        bool
+	headers     map[string]string
+	insecure    bool
+	tlsConfig   *tls.Config
+	compression bool
+}
+
+// resolveOTLPSignal returns the target of a signal, preferring its dedicated endpoint over the shared one.
+// Over HTTP the signal path is appended to the shared endpoint, as the OTLP exporter spec requires.
+func resolveOTLPSignal(cfg *configs.OTLPExporterConfig, signalEndpoint, httpPath string) (*otlpSignal, error) {
+	protocol := cfg.Protocol
+	if protocol == "" {
+		protocol = os.Getenv(OTELExporterOTLPProtocolEnvKey)
+	}
+	signal := &otlpSignal{
+		headers:  cfg.Headers,
+		insecure: cfg.Insecure,
+	}
+
+	// the OTLP HTTP exporters only send protobuf, so http/json is rejected instead of silently sending protobuf
+	switch strings.ToLower(protocol) {
+	case otlpProtocolHTTPProtobuf:
+		signal.http = true
+	case "", otlpProtocolGRPC:
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q, expected grpc or http/protobuf", protocol)
+	}
+
+	switch strings.ToLower(cfg.Compression) {
+	case otlpCompressionGzip:
+		signal.compression = true
+	case "", otlpCompressionNone:
+	default:
+		return nil, fmt.Errorf("unsupported OTLP compression %q, expected gzip or none", cfg.Compression)
+	}
+
+	endpoint := signalEndpoint
+	if endpoint == "" {
+		if cfg.Endpoint == "" {
+			return nil, ErrOTLPEndpointNotSet
+		}
+		endpoint = cfg.Endpoint
+		if signal.http {
+			endpoint = strings.TrimSuffix(endpoint, "/") + httpPath
+		}
+	}
+	if !strings.Contains(endpoint, "://") {
+		scheme := "https://"
+		if cfg.Insecure {
+			scheme = "http://"
+		}
+		endpoint = scheme + endpoint
+	}
+	if _, err := url.Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: %w", endpoint, err)
+	}
+	signal.endpoint = endpoint
+
+	if !cfg.Insecure && (cfg.CACertPath != "" || cfg.ClientCertPath != "") {
+		tlsConfig, err := otlpTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		signal.tlsConfig = tlsConfig
+	}
+	return signal, nil
+}
+
+func otlpTLSConfig(cfg *configs.OTLPExporterConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CACertPath != "" {
+		pem, err := os.ReadFile(cfg.CACertPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OTLP CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in OTLP CA certificate %s", cfg.CACertPath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.ClientCertPath != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertPath, cfg.ClientKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load OTLP client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// NewOTLPExporter creates a span exporter for a generic OTLP target.
+func NewOTLPExporter(ctx context.Context, cfg *configs.OTLPExporterConfig) (trace.SpanExporter, error) {
+	signal, err := resolveOTLPSignal(cfg, cfg.TracesEndpoint, "/v1/traces")
+	if err != nil {
+		return nil, err
+	}
+
+	if signal.http {
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(signal.endpoint), otlptracehttp.WithHeaders(signal.headers)}
+		if signal.insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if signal.tlsConfig != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(signal.tlsConfig))
+		}
+		if signal.compression {
+			opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpointURL(signal.endpoint), otlptracegrpc.WithHeaders(signal.headers)}
+	if signal.insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if signal.tlsConfig != nil {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(signal.tlsConfig)))
+	}
+	if signal.compression {
+		opts = append(opts, otlptracegrpc.WithCompressor(otlpCompressionGzip))
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// NewOTLPMetricExporter creates a metric exporter for a generic OTLP target.
+func NewOTLPMetricExporter(ctx context.Context, cfg *configs.OTLPExporterConfig) (sdkmetric.Exporter, error) {
+	signal, err := resolveOTLPSignal(cfg, cfg.MetricsEndpoint, "/v1/metrics")
+	if err != nil {
+		return nil, err
+	}
+
+	if signal.http {
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpointURL(signal.endpoint), otlpmetrichttp.WithHeaders(signal.headers)}
+		if signal.insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if signal.tlsConfig != nil {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(signal.tlsConfig))
+		}
+		if signal.compression {
+			opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpointURL(signal.endpoint), otlpmetricgrpc.WithHeaders(signal.headers)}
+	if signal.insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if signal.tlsConfig != nil {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(signal.tlsConfig)))
+	}
+	if signal.compression {
+		opts = append(opts, otlpmetricgrpc.WithCompressor(otlpCompressionGzip))
+	}
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// NewOTLPLogExporter creates a log exporter for a generic OTLP target.
+func NewOTLPLogExporter(ctx context.Context, cfg *configs.OTLPExporterConfig) (olog.Exporter, error) {
+	signal, err := resolveOTLPSignal(cfg, cfg.LogsEndpoint, "/v1/logs")
+	if err != nil {
+		return nil, err
+	}
+
+	if signal.http {
+		opts := []otlploghttp.Option{otlploghttp.WithEndpointURL(signal.endpoint), otlploghttp.WithHeaders(signal.headers)}
+		if signal.insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if signal.tlsConfig != nil {
+			opts = append(opts, otlploghttp.WithTLSClientConfig(signal.tlsConfig))
+		}
+		if signal.compression {
+			opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+		}
+		return otlploghttp.New(ctx, opts...)
+	}
+
+	opts := []otlploggrpc.Option{otlploggrpc.WithEndpointURL(signal.endpoint), otlploggrpc.WithHeaders(signal.headers)}
+	if signal.insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+	if signal.tlsConfig != nil {
+		opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(signal.tlsConfig)))
+	}
+	if signal.compression {
+		opts = append(opts, otlploggrpc.WithCompressor(otlpCompressionGzip))
+	}
+	return otlploggrpc.New(ctx, opts...)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/configs"
+)
+
+func TestResolveOTLPSignal(t *testing.T) {
+	t.Setenv(OTELExporterOTLPProtocolEnvKey, "")
+
+	t.Run("http appends signal path", func(t *testing.T) {
+		signal, err := resolveOTLPSignal(&configs.OTLPExporterConfig{Endpoint: "http://collector:4318/", Protocol: "http/protobuf"}, "", "/v1/traces")
+		require.NoError(t, err)
+		assert.True(t, signal.http)
+		assert.Equal(t, "http://collector:4318/v1/traces", signal.endpoint)
+	})
+
+	t.Run("grpc keeps endpoint", func(t *testing.T) {
+		signal, err := resolveOTLPSignal(&configs.OTLPExporterConfig{Endpoint: "collector:4317", Insecure: true}, "", "/v1/traces")
+		require.NoError(t, err)
+		assert.False(t, signal.http)
+		assert.Equal(t, "http://collector:4317", signal.endpoint)
+	})
+
+	t.Run("signal endpoint wins", func(t *testing.T) {
+		cfg := &configs.OTLPExporterConfig{Endpoint: "collector:4318", Protocol: "http/protobuf", MetricsEndpoint: "https://metrics:443/custom"}
+		signal, err := resolveOTLPSignal(cfg, cfg.MetricsEndpoint, "/v1/metrics")
+		require.NoError(t, err)
+		assert.Equal(t, "https://metrics:443/custom", signal.endpoint)
+	})
+
+	t.Run("protocol from env", func(t *testing.T) {
+		t.Setenv(OTELExporterOTLPProtocolEnvKey, "http/protobuf")
+		signal, err := resolveOTLPSignal(&configs.OTLPExporterConfig{Endpoint: "collector:4318"}, "", "/v1/logs")
+		require.NoError(t, err)
+		assert.Equal(t, "https://collector:4318/v1/logs", signal.endpoint)
+	})
+
+	t.Run("http/json is not supported", func(t *testing.T) {
+		_, err := resolveOTLPSignal(&configs.OTLPExporterConfig{Endpoint: "collector:4318", Protocol: "http/json"}, "", "/v1/traces")
+		assert.ErrorContains(t, err, "unsupported OTLP protocol")
+	})
+
+	t.Run("compression", func(t *testing.T) {
+		signal, err := resolveOTLPSignal(&configs.OTLPExporterConfig{Endpoint: "collector:4317", Compression: "GZIP"}, "", "/v1/traces")
+		require.NoError(t, err)
+		assert.True(t, signal.compression)
+
+		_, err = resolveOTLPSignal(&configs.OTLPExporterConfig{Endpoint: "collector:4317", Compression: "zstd"}, "", "/v1/traces")
+		assert.ErrorContains(t, err, "unsupported OTLP compression")
+	})
+
+	t.Run("missing endpoint", func(t *testing.T) {
+		_, err := resolveOTLPSignal(&configs.OTLPExporterConfig{TracesEndpoint: "collector:4317"}, "", "/v1/metrics")
+		assert.ErrorIs(t, err, ErrOTLPEndpointNotSet)
+	})
+
+	t.Run("missing ca certificate", func(t *testing.T) {
+		_, err := resolveOTLPSignal(&configs.OTLPExporterConfig{Endpoint: "collector:4317", CACertPath: filepath.Join(t.TempDir(), "ca.pem")}, "", "/v1/traces")
+		assert.ErrorContains(t, err, "failed to read OTLP CA certificate")
+	})
+}
+
+func TestNewOTLPExporters(t *testing.T) {
+	ctx := context.Background()
+	for _, protocol := range []string{"grpc", "http/protobuf"} {
+		t.Run(protocol, func(t *testing.T) {
+			cfg := &configs.OTLPExporterConfig{
+				Endpoint:    "localhost:4317",
+				Protocol:    protocol,
+				Headers:     map[string]string{"authorization": "Bearer test"},
+				Insecure:    true,
+				Compression: "gzip",
+			}
+
+			spanExporter, err := NewOTLPExporter(ctx, cfg)
+			require.NoError(t, err)
+			assert.NotNil(t, spanExporter)
+
+			metricExporter, err := NewOTLPMetricExporter(ctx, cfg)
+			require.NoError(t, err)
+			assert.NotNil(t, metricExporter)
+
+			logExporter, err := NewOTLPLogExporter(ctx, cfg)
+			require.NoError(t, err)
+			assert.NotNil(t, logExporter)
+		})
+	}
+}
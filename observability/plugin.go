@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/volcengine/veadk-go/configs"
+	"github.com/volcengine/veadk-go/cost"
 	"github.com/volcengine/veadk-go/log"
 
 	"go.opentelemetry.io/otel"
@@ -105,7 +106,7 @@ func (p *adkObservabilityPlugin) isMetricsEnabled() bool {
 
 // BeforeRun is called before an agent run starts.
 func (p *adkObservabilityPlugin) BeforeRun(ctx agent.InvocationContext) (*genai.Content, error) {
-	log.Debug("Before Run", "InvocationID", ctx.InvocationID(), "SessionID", ctx.Session().ID(), "UserID", ctx.Session().UserID())
+	log.DebugContext(ctx, "Before Run", "InvocationID", ctx.InvocationID(), "SessionID", ctx.Session().ID(), "UserID", ctx.Session().UserID())
 	// 1. Start the 'invocation' span - ADK doesn't create this yet
 	// (e.g. spans from HTTP middleware will be the parent)
 	_, span := p.tracer.Start(context.Context(ctx), SpanInvocation, trace.WithSpanKind(trace.SpanKindServer))
@@ -147,7 +148,7 @@ func (p *adkObservabilityPlugin) BeforeRun(ctx agent.InvocationContext) (*genai.
 
 // AfterRun is called after an agent run ends.
 func (p *adkObservabilityPlugin) AfterRun(ctx agent.InvocationContext) {
-	log.Debug("After Run", "InvocationID", ctx.InvocationID(), "SessionID", ctx.Session().ID(), "UserID", ctx.Session().UserID())
+	log.DebugContext(ctx, "After Run", "InvocationID", ctx.InvocationID(), "SessionID", ctx.Session().ID(), "UserID", ctx.Session().UserID())
 	// 1. End the span
 	s, _ := ctx.Session().State().Get(stateKeyInvocationSpan)
 	if s == nil {
@@ -155,7 +156,7 @@ func (p *adkObservabilityPlugin) AfterRun(ctx agent.InvocationContext) {
 	}
 
 	span := s.(trace.Span)
-	log.Debug("AfterRun get a span from state", "span", span, "isRecording", span.IsRecording())
+	log.DebugContext(ctx, "AfterRun get a span from state", "span", span, "isRecording", span.IsRecording())
 
 	if span.IsRecording() {
 		// Capture final output if available
@@ -187,6 +188,9 @@ func (p *adkObservabilityPlugin) AfterRun(ctx agent.InvocationContext) {
 		if meta.TotalTokens > 0 {
 			span.SetAttributes(attribute.Int64(AttrGenAIUsageTotalTokens, meta.TotalTokens))
 		}
+		if meta.Cost > 0 {
+			span.SetAttributes(attribute.Float64(AttrGenAIUsageCost, meta.Cost))
+		}
 
 		// Record final metrics for invocation
 		if !meta.StartTime.IsZero() {
@@ -237,7 +241,7 @@ func (p *adkObservabilityPlugin) AfterRun(ctx agent.InvocationContext) {
 // This is the primary trace-bridging point for adk trace -> veadk invocation trace.
 // BeforeModel keeps an idempotent bridge as a secondary safety net.
 func (p *adkObservabilityPlugin) BeforeAgent(ctx agent.CallbackContext) (*genai.Content, error) {
-	log.Debug("BeforeAgent",
+	log.DebugContext(ctx, "BeforeAgent",
 		"InvocationID", ctx.InvocationID(), "SessionID", ctx.SessionID(), "UserID", ctx.UserID(), "AgentName", ctx.AgentName(), "AppName", ctx.AppName())
 	p.tryBridgeTraceMappingFromCallback(ctx, "BeforeAgent")
 	return nil, nil
@@ -247,12 +251,12 @@ func (p *adkObservabilityPlugin) tryBridgeTraceMappingFromCallback(ctx agent.Cal
 	adkSC := trace.SpanFromContext(context.Context(ctx)).SpanContext()
 	veadkInvocationSC, ok := getInvocationSpanContextFromState(ctx.State())
 	if !ok {
-		log.Debug("Skip trace mapping bridge: invocation span missing in state", "stage", stage)
+		log.DebugContext(ctx, "Skip trace mapping bridge: invocation span missing in state", "stage", stage)
 		return
 	}
 
 	if registerTraceMappingIfPossible(GetRegistry(), adkSC, veadkInvocationSC) {
-		log.Debug("Bridged adk trace to veadk invocation trace",
+		log.DebugContext(ctx, "Bridged adk trace to veadk invocation trace",
 			"stage", stage,
 			"adk_trace_id", adkSC.TraceID().String(),
 			"veadk_trace_id", veadkInvocationSC.TraceID().String(),
@@ -262,14 +266,14 @@ func (p *adkObservabilityPlugin) tryBridgeTraceMappingFromCallback(ctx agent.Cal
 
 // AfterAgent is called after an agent execution.
 func (p *adkObservabilityPlugin) AfterAgent(ctx agent.CallbackContext) (*genai.Content, error) {
-	log.Debug("AfterAgent",
+	log.DebugContext(ctx, "AfterAgent",
 		"InvocationID", ctx.InvocationID(), "SessionID", ctx.SessionID(), "UserID", ctx.UserID(), "AgentName", ctx.AgentName(), "AppName", ctx.AppName())
 	return nil, nil
 }
 
 // BeforeModel is called before the LLM is called.
 func (p *adkObservabilityPlugin) BeforeModel(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error) {
-	log.Debug("BeforeModel",
+	log.DebugContext(ctx, "BeforeModel",
 		"InvocationID", ctx.InvocationID(), "SessionID", ctx.SessionID(), "UserID", ctx.UserID(), "AgentName", ctx.AgentName(), "AppName", ctx.AppName())
 	p.tryBridgeTraceMappingFromCallback(ctx, "BeforeModel")
 	// ADK now emits model spans natively. Plugin only keeps metadata for metrics and invocation aggregation.
@@ -278,6 +282,7 @@ func (p *adkObservabilityPlugin) BeforeModel(ctx agent.CallbackContext, req *mod
 	meta.PrevPromptTokens = meta.PromptTokens
 	meta.PrevCandidateTokens = meta.CandidateTokens
 	meta.PrevTotalTokens = meta.TotalTokens
+	meta.PrevCost = meta.Cost
 	meta.ModelName = req.Model
 	p.storeSpanMetadata(ctx.State(), meta)
 	return nil, nil
@@ -285,7 +290,7 @@ func (p *adkObservabilityPlugin) BeforeModel(ctx agent.CallbackContext, req *mod
 
 // AfterModel is called after the LLM returns.
 func (p *adkObservabilityPlugin) AfterModel(ctx agent.CallbackContext, resp *model.LLMResponse, err error) (*model.LLMResponse, error) {
-	log.Debug("AfterModel",
+	log.DebugContext(ctx, "AfterModel",
 		"InvocationID", ctx.InvocationID(), "SessionID", ctx.SessionID(), "UserID", ctx.UserID(), "AgentName", ctx.AgentName(), "AppName", ctx.AppName())
 	meta := p.getSpanMetadata(ctx.State())
 
@@ -380,6 +385,9 @@ func (p *adkObservabilityPlugin) accumulateLLMUsageAndRecordMetrics(ctx agent.Ca
 		currentCandidate,
 		currentTotal,
 	)
+	if c, ok := cost.DefaultPricingTable().Cost(modelName, cost.UsageFromMetadata(resp.UsageMetadata)); ok {
+		meta.Cost = meta.PrevCost + c
+	}
 	p.storeSpanMetadata(ctx.State(), meta)
 
 	if p.isMetricsEnabled() {
@@ -409,7 +417,7 @@ func mergeUsageTotals(prevPrompt, prevCandidate, prevTotal, currentPrompt, curre
 // BeforeTool is a lightweight debug-only callback.
 // Tool span metrics and token estimation are handled in span processor / translator paths.
 func (p *adkObservabilityPlugin) BeforeTool(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
-	log.Debug("BeforeTool",
+	log.DebugContext(ctx, "BeforeTool",
 		"InvocationID", ctx.InvocationID(), "SessionID", ctx.SessionID(), "UserID", ctx.UserID(), "AgentName", ctx.AgentName(), "AppName", ctx.AppName(),
 		"ToolName", t.Name(), "ToolArgs", args)
 	return nil, nil
@@ -420,7 +428,7 @@ func (p *adkObservabilityPlugin) BeforeTool(ctx tool.Context, t tool.Tool, args
 // AfterTool is a lightweight debug-only callback.
 // Tool span metrics and token estimation are handled in span processor / translator paths.
 func (p *adkObservabilityPlugin) AfterTool(ctx tool.Context, t tool.Tool, args map[string]any, result map[string]any, err error) (map[string]any, error) {
-	log.Debug("AfterTool",
+	log.DebugContext(ctx, "AfterTool",
 		"InvocationID", ctx.InvocationID(), "SessionID", ctx.SessionID(), "UserID", ctx.UserID(), "AgentName", ctx.AgentName(), "AppName", ctx.AppName(),
 		"ToolName", t.Name(), "ToolArgs", args, "ToolResult", result, "ToolError", err)
 
@@ -476,5 +484,8 @@ type spanMetadata struct {
 	PrevPromptTokens    int64
 	PrevCandidateTokens int64
 	PrevTotalTokens     int64
-	ModelName           string
+	// Cost is the accumulated cost of the model calls, priced with cost.DefaultPricingTable.
+	Cost      float64
+	PrevCost  float64
+	ModelName string
 }
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/volcengine/veadk-go/configs"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// DefaultPrometheusPath is the scrape path used when PrometheusConfig.Path is empty.
+const DefaultPrometheusPath = "/metrics"
+
+// Bucket boundaries used when metrics are scraped by Prometheus. LLM calls take from a few
+// hundred milliseconds to minutes, so the buckets are denser between 1s and 60s than the
+// power-of-two buckets pushed to APMPlus.
+var (
+	prometheusLatencyBuckets = []float64{
+		0.1, 0.25, 0.5, 1, 2, 3, 5, 7.5, 10, 15, 20, 30, 45, 60, 90, 120, 180, 300,
+	}
+
+	prometheusTimeToFirstTokenBuckets = []float64{
+		0.05, 0.1, 0.2, 0.3, 0.5, 0.75, 1, 1.5, 2, 3, 5, 7.5, 10, 15, 30,
+	}
+)
+
+var (
+	prometheusMu      sync.RWMutex
+	prometheusPath    string
+	prometheusHandler http.Handler
+)
+
+// NewPrometheusReader creates a pull based metric reader backed by its own Prometheus registry.
+// The registry is served by the handler returned from PrometheusEndpoint.
+func NewPrometheusReader(cfg *configs.PrometheusConfig) (sdkmetric.Reader, error) {
+	registry := prometheus.NewRegistry()
+	reader, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+	}
+
+	path := cfg.Path
+	if path == "" {
+		path = DefaultPrometheusPath
+	}
+
+	prometheusMu.Lock()
+	defer prometheusMu.Unlock()
+	prometheusPath = path
+	prometheusHandler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	return reader, nil
+}
+
+// PrometheusEndpoint returns the scrape path and handler of the Prometheus reader.
+// The handler is nil when Prometheus is not enabled.
+func PrometheusEndpoint() (string, http.Handler) {
+	prometheusMu.RLock()
+	defer prometheusMu.RUnlock()
+	return prometheusPath, prometheusHandler
+}
+
+func isPrometheusReader(r sdkmetric.Reader) bool {
+	_, ok := r.(*otelprometheus.Exporter)
+	return ok
+}
+
+// prometheusViews overrides the histogram buckets of the latency instruments.
+// They are only installed on the MeterProvider of the Prometheus reader, see registerMetrics.
+func prometheusViews(cfg *configs.PrometheusConfig) []sdkmetric.View {
+	latency := prometheusLatencyBuckets
+	if len(cfg.LatencyBuckets) > 0 {
+		latency = cfg.LatencyBuckets
+	}
+	timeToFirstToken := prometheusTimeToFirstTokenBuckets
+	if len(cfg.TimeToFirstTokenBuckets) > 0 {
+		timeToFirstToken = cfg.TimeToFirstTokenBuckets
+	}
+
+	bucketsByName := map[string][]float64{
+		MetricNameOperationDuration:       latency,
+		MetricNameStreamingTimeToGenerate: latency,
+		MetricNameAgentKitDuration:        latency,
+		MetricNameWorkflowStepDuration:    latency,
+		MetricNameServerQueueWait:         latency,
+		MetricNameFirstTokenLatency:       timeToFirstToken,
+	}
+
+	views := make([]sdkmetric.View, 0, len(bucketsByName))
+	for name, buckets := range bucketsByName {
+		views = append(views, sdkmetric.NewView(
+			sdkmetric.Instrument{Name: name},
+			sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{Boundaries: buckets}},
+		))
+	}
+	return views
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/configs"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestNewPrometheusReader(t *testing.T) {
+	cfg := &configs.PrometheusConfig{Enable: true, TimeToFirstTokenBuckets: []float64{0.5, 1}}
+	reader, err := NewPrometheusReader(cfg)
+	require.NoError(t, err)
+
+	path, handler := PrometheusEndpoint()
+	assert.Equal(t, DefaultPrometheusPath, path)
+	require.NotNil(t, handler)
+
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithView(prometheusViews(cfg)...))
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+	meter := mp.Meter(InstrumentationName)
+
+	duration, err := meter.Float64Histogram(MetricNameOperationDuration, metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(genAIClientOperationDurationBuckets...))
+	require.NoError(t, err)
+	duration.Record(context.Background(), 6)
+	firstToken, err := meter.Float64Histogram(MetricNameFirstTokenLatency, metric.WithUnit("s"))
+	require.NoError(t, err)
+	firstToken.Record(context.Background(), 0.7)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `gen_ai_client_operation_duration_seconds_bucket{`)
+	assert.Contains(t, string(body), `le="7.5"`)
+	assert.NotContains(t, string(body), `le="5.12"`)
+	assert.Contains(t, string(body), `gen_ai_chat_completions_streaming_time_to_first_token_seconds_bucket{`)
+	assert.Contains(t, string(body), `le="0.5"`)
+}
+
+func TestNewMetricReader_Prometheus(t *testing.T) {
+	readers, err := NewMetricReader(context.Background(), &configs.OpenTelemetryConfig{
+		Prometheus: &configs.PrometheusConfig{Enable: true, Path: "/custom-metrics"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, readers, 1)
+
+	path, handler := PrometheusEndpoint()
+	assert.Equal(t, "/custom-metrics", path)
+	assert.NotNil(t, handler)
+}
+
+func TestPrometheusViewsOnlyApplyToPrometheusReader(t *testing.T) {
+	cfg := &configs.PrometheusConfig{Enable: true}
+	promReader, err := NewPrometheusReader(cfg)
+	require.NoError(t, err)
+	pushReader := sdkmetric.NewManualReader()
+
+	push, pull := newMeterProviders([]sdkmetric.Reader{pushReader, promReader}, prometheusViews(cfg))
+	require.NotNil(t, push)
+	require.NotNil(t, pull)
+	t.Cleanup(func() {
+		_ = push.Shutdown(context.Background())
+		_ = pull.Shutdown(context.Background())
+	})
+
+	for _, mp := range []*sdkmetric.MeterProvider{push, pull} {
+		duration, err := mp.Meter(InstrumentationName).Float64Histogram(MetricNameOperationDuration, metric.WithUnit("s"),
+			metric.WithExplicitBucketBoundaries(genAIClientOperationDurationBuckets...))
+		require.NoError(t, err)
+		duration.Record(context.Background(), 6)
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, pushReader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	hist := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64])
+	assert.Equal(t, genAIClientOperationDurationBuckets, hist.DataPoints[0].Bounds)
+
+	path, handler := PrometheusEndpoint()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Contains(t, rec.Body.String(), `le="7.5"`)
+}
@@ -0,0 +1,327 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/volcengine/veadk-go/configs"
+	"github.com/volcengine/veadk-go/cost"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	DefaultRedactionReplacement = "[REDACTED]"
+	truncatedSuffix             = "...[truncated]"
+)
+
+// AttributeRedactor rewrites span attributes before they are exported, e.g. to mask
+// PII found by a custom detector. It runs after the configured redaction rules.
+type AttributeRedactor interface {
+	RedactAttribute(spanName string, kv attribute.KeyValue) attribute.KeyValue
+}
+
+// AttributeRedactorFunc adapts a function to AttributeRedactor.
+type AttributeRedactorFunc func(spanName string, kv attribute.KeyValue) attribute.KeyValue
+
+func (f AttributeRedactorFunc) RedactAttribute(spanName string, kv attribute.KeyValue) attribute.KeyValue {
+	return f(spanName, kv)
+}
+
+var (
+	redactorsMu sync.RWMutex
+	redactors   []AttributeRedactor
+)
+
+// RegisterAttributeRedactor adds a redactor applied to every exported span.
+func RegisterAttributeRedactor(r AttributeRedactor) {
+	redactorsMu.Lock()
+	defer redactorsMu.Unlock()
+	redactors = append(redactors, r)
+}
+
+func registeredRedactors() []AttributeRedactor {
+	redactorsMu.RLock()
+	defer redactorsMu.RUnlock()
+	return redactors
+}
+
+// spanFilter decides which ended spans are exported and rewrites their attributes.
+type spanFilter struct {
+	alwaysSampleErrors bool
+
+	keys           map[string]bool
+	patterns       []*regexp.Regexp
+	replacement    string
+	omitInlineData bool
+
+	maxLength    int
+	lengthLimits map[string]int
+
+	pricing *cost.PricingTable
+}
+
+func newSpanFilter(cfg *configs.TracingConfig) (*spanFilter, error) {
+	f := &spanFilter{replacement: DefaultRedactionReplacement, pricing: cost.DefaultPricingTable()}
+	if cfg == nil {
+		return f, nil
+	}
+
+	f.maxLength = cfg.MaxAttributeLength
+	f.lengthLimits = cfg.AttributeLengthLimits
+	if cfg.Sampling != nil {
+		f.alwaysSampleErrors = cfg.Sampling.AlwaysSampleErrors
+	}
+
+	if r := cfg.Redaction; r != nil {
+		if r.Replacement != "" {
+			f.replacement = r.Replacement
+		}
+		f.omitInlineData = r.OmitInlineData
+		if len(r.Keys) > 0 {
+			f.keys = make(map[string]bool, len(r.Keys))
+			for _, key := range r.Keys {
+				f.keys[strings.ToLower(key)] = true
+			}
+		}
+		for _, pattern := range r.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+			}
+			f.patterns = append(f.patterns, re)
+		}
+	}
+	return f, nil
+}
+
+// shouldExport reports whether an ended span is exported. Spans of unsampled traces are
+// only recorded, unless they end with an error and AlwaysSampleErrors is set.
+func (f *spanFilter) shouldExport(span sdktrace.ReadOnlySpan) bool {
+	if span.SpanContext().IsSampled() {
+		return true
+	}
+	return f.alwaysSampleErrors && span.Status().Code == codes.Error
+}
+
+func (f *spanFilter) apply(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	filtered := &filteredSpan{
+		ReadOnlySpan: span,
+		spanContext:  span.SpanContext(),
+		attributes:   f.filterAttributes(span.Name(), span.Attributes()),
+	}
+	if classifySemanticSpanKind(span.Name()) == semanticSpanLLM {
+		filtered.attributes = withLLMCost(f.pricing, filtered.attributes)
+	}
+	if !filtered.spanContext.IsSampled() {
+		filtered.spanContext = filtered.spanContext.WithTraceFlags(filtered.spanContext.TraceFlags().WithSampled(true))
+	}
+
+	if events := span.Events(); len(events) > 0 {
+		filtered.events = make([]sdktrace.Event, len(events))
+		for i, event := range events {
+			event.Attributes = f.filterAttributes(span.Name(), event.Attributes)
+			filtered.events[i] = event
+		}
+	}
+	return filtered
+}
+
+func (f *spanFilter) filterAttributes(spanName string, attrs []attribute.KeyValue) []attribute.KeyValue {
+	hooks := registeredRedactors()
+	if len(attrs) == 0 || (!f.enabled() && len(hooks) == 0) {
+		return attrs
+	}
+
+	filtered := make([]attribute.KeyValue, len(attrs))
+	for i, kv := range attrs {
+		kv = f.redact(kv)
+		for _, hook := range hooks {
+			kv = hook.RedactAttribute(spanName, kv)
+		}
+		filtered[i] = f.truncate(kv)
+	}
+	return filtered
+}
+
+func (f *spanFilter) enabled() bool {
+	return len(f.keys) > 0 || len(f.patterns) > 0 || f.omitInlineData || f.maxLength > 0 || len(f.lengthLimits) > 0
+}
+
+func (f *spanFilter) redact(kv attribute.KeyValue) attribute.KeyValue {
+	if f.keys[strings.ToLower(string(kv.Key))] {
+		return attribute.String(string(kv.Key), f.replacement)
+	}
+
+	switch kv.Value.Type() {
+	case attribute.STRING:
+		return attribute.String(string(kv.Key), f.redactString(kv.Value.AsString()))
+	case attribute.STRINGSLICE:
+		values := kv.Value.AsStringSlice()
+		for i, v := range values {
+			values[i] = f.redactString(v)
+		}
+		return attribute.StringSlice(string(kv.Key), values)
+	default:
+		return kv
+	}
+}
+
+func (f *spanFilter) redactString(v string) string {
+	if (len(f.keys) > 0 || f.omitInlineData) && looksLikeJSON(v) {
+		var payload any
+		if err := json.Unmarshal([]byte(v), &payload); err == nil {
+			if redacted, changed := f.redactJSON(payload); changed {
+				if b, err := json.Marshal(redacted); err == nil {
+					v = string(b)
+				}
+			}
+		}
+	}
+	for _, re := range f.patterns {
+		v = re.ReplaceAllString(v, f.replacement)
+	}
+	return v
+}
+
+func (f *spanFilter) redactJSON(value any) (any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		changed := false
+		for key, item := range v {
+			if f.keys[strings.ToLower(key)] {
+				v[key] = f.replacement
+				changed = true
+				continue
+			}
+			if redacted, ok := f.redactJSON(item); ok {
+				v[key] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	case []any:
+		changed := false
+		for i, item := range v {
+			if redacted, ok := f.redactJSON(item); ok {
+				v[i] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	case string:
+		if f.omitInlineData {
+			if omitted, ok := omitDataURL(v); ok {
+				return omitted, true
+			}
+		}
+		// Tool args and responses are often JSON encoded strings nested in the payload.
+		if len(f.keys) > 0 && looksLikeJSON(v) {
+			var nested any
+			if err := json.Unmarshal([]byte(v), &nested); err == nil {
+				if redacted, ok := f.redactJSON(nested); ok {
+					if b, err := json.Marshal(redacted); err == nil {
+						return string(b), true
+					}
+				}
+			}
+		}
+		return v, false
+	default:
+		return v, false
+	}
+}
+
+// omitDataURL replaces the payload of a base64 data URL, as written by serializeContentForTelemetry for inline data.
+func omitDataURL(v string) (string, bool) {
+	if !strings.HasPrefix(v, "data:") {
+		return "", false
+	}
+	header, data, ok := strings.Cut(v, ";base64,")
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%s;base64,[omitted %d bytes]", header, len(data)*3/4), true
+}
+
+func looksLikeJSON(v string) bool {
+	v = strings.TrimSpace(v)
+	return strings.HasPrefix(v, "{") || strings.HasPrefix(v, "[")
+}
+
+func (f *spanFilter) truncate(kv attribute.KeyValue) attribute.KeyValue {
+	limit, ok := f.lengthLimits[string(kv.Key)]
+	if !ok {
+		limit = f.maxLength
+	}
+	if limit <= 0 {
+		return kv
+	}
+
+	switch kv.Value.Type() {
+	case attribute.STRING:
+		return attribute.String(string(kv.Key), truncateString(kv.Value.AsString(), limit))
+	case attribute.STRINGSLICE:
+		values := kv.Value.AsStringSlice()
+		for i, v := range values {
+			values[i] = truncateString(v, limit)
+		}
+		return attribute.StringSlice(string(kv.Key), values)
+	default:
+		return kv
+	}
+}
+
+// truncateString cuts v to at most limit bytes on a rune boundary and marks it as truncated.
+func truncateString(v string, limit int) string {
+	if len(v) <= limit {
+		return v
+	}
+	for limit > 0 && !utf8.RuneStart(v[limit]) {
+		limit--
+	}
+	return v[:limit] + truncatedSuffix
+}
+
+// filteredSpan is an ended span with redacted attributes, handed to the export processor.
+type filteredSpan struct {
+	sdktrace.ReadOnlySpan
+	spanContext trace.SpanContext
+	attributes  []attribute.KeyValue
+	events      []sdktrace.Event
+}
+
+func (s *filteredSpan) SpanContext() trace.SpanContext {
+	return s.spanContext
+}
+
+func (s *filteredSpan) Attributes() []attribute.KeyValue {
+	return s.attributes
+}
+
+func (s *filteredSpan) Events() []sdktrace.Event {
+	if s.events == nil {
+		return s.ReadOnlySpan.Events()
+	}
+	return s.events
+}
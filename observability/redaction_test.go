@@ -0,0 +1,182 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/configs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestTracerProvider(t *testing.T, cfg *configs.TracingConfig) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+	filter, err := newSpanFilter(cfg)
+	require.NoError(t, err)
+
+	exporter := tracetest.NewInMemoryExporter()
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSpanProcessor(newVeADKExportSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter), filter)),
+	}
+	if sampler := newSampler(cfg.Sampling); sampler != nil {
+		opts = append(opts, sdktrace.WithSampler(sampler))
+	}
+	tp := sdktrace.NewTracerProvider(opts...)
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return tp, exporter
+}
+
+func exportedAttribute(t *testing.T, span tracetest.SpanStub, key string) attribute.Value {
+	t.Helper()
+	for _, kv := range span.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	t.Fatalf("attribute %s not exported", key)
+	return attribute.Value{}
+}
+
+func TestSpanFilter_Redaction(t *testing.T) {
+	tp, exporter := newTestTracerProvider(t, &configs.TracingConfig{
+		Redaction: &configs.RedactionConfig{
+			Keys:           []string{"password", "user.email"},
+			Patterns:       []string{`\d{3}-\d{4}-\d{4}`},
+			OmitInlineData: true,
+		},
+	})
+
+	_, span := tp.Tracer("test").Start(context.Background(), "execute_tool login")
+	span.SetAttributes(
+		attribute.String("user.email", "alice@example.com"),
+		attribute.String(ADKAttrToolCallArgsName, `{"user":"alice","password":"hunter2","nested":{"Password":"x"}}`),
+		attribute.String("gen_ai.prompt", "call me at 138-0000-1234"),
+		attribute.String("gen_ai.input", `{"parts":[{"type":"image_url","image_url":{"url":"data:image/png;base64,aGVsbG8gd29ybGQh"}}]}`),
+	)
+	span.AddEvent("gen_ai.user.message", trace.WithAttributes(attribute.String("content", "138-0000-1234")))
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	got := spans[0]
+	assert.Equal(t, DefaultRedactionReplacement, exportedAttribute(t, got, "user.email").AsString())
+	assert.JSONEq(t, `{"user":"alice","password":"[REDACTED]","nested":{"Password":"[REDACTED]"}}`,
+		exportedAttribute(t, got, ADKAttrToolCallArgsName).AsString())
+	assert.Equal(t, "call me at [REDACTED]", exportedAttribute(t, got, "gen_ai.prompt").AsString())
+	assert.Contains(t, exportedAttribute(t, got, "gen_ai.input").AsString(), "data:image/png;base64,[omitted 12 bytes]")
+	require.Len(t, got.Events, 1)
+	assert.Equal(t, "[REDACTED]", got.Events[0].Attributes[0].Value.AsString())
+}
+
+func TestSpanFilter_Hook(t *testing.T) {
+	tp, exporter := newTestTracerProvider(t, &configs.TracingConfig{})
+	RegisterAttributeRedactor(AttributeRedactorFunc(func(spanName string, kv attribute.KeyValue) attribute.KeyValue {
+		if kv.Key == "secret" {
+			return attribute.String("secret", "masked by "+spanName)
+		}
+		return kv
+	}))
+	t.Cleanup(func() {
+		redactorsMu.Lock()
+		redactors = nil
+		redactorsMu.Unlock()
+	})
+
+	_, span := tp.Tracer("test").Start(context.Background(), "hooked")
+	span.SetAttributes(attribute.String("secret", "s3cr3t"))
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "masked by hooked", exportedAttribute(t, spans[0], "secret").AsString())
+}
+
+func TestSpanFilter_Truncation(t *testing.T) {
+	tp, exporter := newTestTracerProvider(t, &configs.TracingConfig{
+		MaxAttributeLength:    8,
+		AttributeLengthLimits: map[string]int{"short": 2, "unlimited": 0},
+	})
+
+	_, span := tp.Tracer("test").Start(context.Background(), "truncated")
+	span.SetAttributes(
+		attribute.String("long", strings.Repeat("a", 20)),
+		attribute.String("short", "你好"),
+		attribute.String("unlimited", strings.Repeat("b", 20)),
+		attribute.StringSlice("slice", []string{"0123456789", "ok"}),
+		attribute.Int("number", 1234567890),
+	)
+	span.End()
+
+	got := exporter.GetSpans()[0]
+	assert.Equal(t, "aaaaaaaa"+truncatedSuffix, exportedAttribute(t, got, "long").AsString())
+	assert.Equal(t, truncatedSuffix, exportedAttribute(t, got, "short").AsString())
+	assert.Equal(t, strings.Repeat("b", 20), exportedAttribute(t, got, "unlimited").AsString())
+	assert.Equal(t, []string{"01234567" + truncatedSuffix, "ok"}, exportedAttribute(t, got, "slice").AsStringSlice())
+	assert.Equal(t, int64(1234567890), exportedAttribute(t, got, "number").AsInt64())
+}
+
+func TestSpanFilter_Sampling(t *testing.T) {
+	zero := 0.0
+
+	t.Run("unsampled spans are dropped", func(t *testing.T) {
+		tp, exporter := newTestTracerProvider(t, &configs.TracingConfig{Sampling: &configs.SamplingConfig{Ratio: &zero}})
+		_, span := tp.Tracer("test").Start(context.Background(), "ok")
+		assert.False(t, span.IsRecording())
+		span.SetStatus(codes.Error, "failed")
+		span.End()
+		assert.Empty(t, exporter.GetSpans())
+	})
+
+	t.Run("errors are always sampled", func(t *testing.T) {
+		tp, exporter := newTestTracerProvider(t, &configs.TracingConfig{Sampling: &configs.SamplingConfig{Ratio: &zero, AlwaysSampleErrors: true}})
+		_, okSpan := tp.Tracer("test").Start(context.Background(), "ok")
+		assert.True(t, okSpan.IsRecording())
+		okSpan.End()
+		_, errSpan := tp.Tracer("test").Start(context.Background(), "failed")
+		errSpan.RecordError(errors.New("boom"))
+		errSpan.SetStatus(codes.Error, "boom")
+		errSpan.End()
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "failed", spans[0].Name)
+		assert.True(t, spans[0].SpanContext.IsSampled())
+	})
+
+	t.Run("parent based", func(t *testing.T) {
+		one := 1.0
+		tp, exporter := newTestTracerProvider(t, &configs.TracingConfig{Sampling: &configs.SamplingConfig{Ratio: &one}})
+		parent := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, Remote: true,
+		})
+		_, span := tp.Tracer("test").Start(trace.ContextWithRemoteSpanContext(context.Background(), parent), "child")
+		span.End()
+		assert.Empty(t, exporter.GetSpans())
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := newSpanFilter(&configs.TracingConfig{Redaction: &configs.RedactionConfig{Patterns: []string{"("}}})
+		assert.ErrorContains(t, err, "invalid redaction pattern")
+	})
+}
@@ -64,6 +64,8 @@ type traceInfos struct {
 	veadkTraceID trace.TraceID
 	invocationSC trace.SpanContext
 	toolCallIDs  []string
+	// workflowSpanIDs are the workflow step spans that parent invoke_agent spans of sub-agents
+	workflowSpanIDs map[trace.SpanID]bool
 }
 
 var (
@@ -274,6 +276,31 @@ func (r *TraceRegistry) RegisterInvocationSpanContext(adkTraceID trace.TraceID,
 	res.invocationSC = invocationSC
 }
 
+// RegisterWorkflowSpan records a workflow step span, so that the invoke_agent spans of the
+// sub-agents run in the step keep it as their parent instead of the invocation span.
+func (r *TraceRegistry) RegisterWorkflowSpan(sc trace.SpanContext) {
+	if !sc.IsValid() {
+		return
+	}
+	res := r.getOrCreateTraceInfos(sc.TraceID())
+	r.resourcesMu.Lock()
+	defer r.resourcesMu.Unlock()
+	if res.workflowSpanIDs == nil {
+		res.workflowSpanIDs = make(map[trace.SpanID]bool)
+	}
+	res.workflowSpanIDs[sc.SpanID()] = true
+}
+
+// IsWorkflowSpan reports whether the span context belongs to a registered workflow step span.
+func (r *TraceRegistry) IsWorkflowSpan(sc trace.SpanContext) bool {
+	r.resourcesMu.RLock()
+	defer r.resourcesMu.RUnlock()
+	if res, ok := r.adkTraceToVeadkTraceMap[sc.TraceID()]; ok {
+		return res.workflowSpanIDs[sc.SpanID()]
+	}
+	return false
+}
+
 // GetInvocationSpanContext gets the VeADK invocation span context for an adk TraceID.
 func (r *TraceRegistry) GetInvocationSpanContext(adkTraceID trace.TraceID) (trace.SpanContext, bool) {
 	r.resourcesMu.RLock()
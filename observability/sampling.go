@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"fmt"
+
+	"github.com/volcengine/veadk-go/configs"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// recordingSampler records the spans dropped by its delegate instead of discarding them,
+// so that veadkSpanProcessor can still export the ones ending with an error.
+// It is only used with AlwaysSampleErrors, as recording every span costs what sampling saves.
+type recordingSampler struct {
+	sdktrace.Sampler
+}
+
+func (s recordingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.Sampler.ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+func (s recordingSampler) Description() string {
+	return fmt.Sprintf("Recording{%s}", s.Sampler.Description())
+}
+
+// newSampler builds the head sampler of the tracer provider, nil keeps the SDK default.
+func newSampler(cfg *configs.SamplingConfig) sdktrace.Sampler {
+	if cfg == nil || cfg.Ratio == nil {
+		return nil
+	}
+
+	root := sdktrace.TraceIDRatioBased(*cfg.Ratio)
+	if cfg.ParentBased == nil || *cfg.ParentBased {
+		root = sdktrace.ParentBased(root)
+	}
+	if !cfg.AlwaysSampleErrors {
+		return root
+	}
+	return recordingSampler{Sampler: root}
+}
@@ -9,7 +9,11 @@ import (
 	"google.golang.org/adk/agent"
 )
 
-type veadkSpanProcessor struct{}
+type veadkSpanProcessor struct {
+	// next exports the ended spans accepted by filter, it is nil when the processor only enriches spans.
+	next   sdktrace.SpanProcessor
+	filter *spanFilter
+}
 
 type semanticSpanKind int
 
@@ -25,12 +29,28 @@ func NewVeADKSpanProcessor() sdktrace.SpanProcessor {
 	return &veadkSpanProcessor{}
 }
 
+// newVeADKExportSpanProcessor returns a processor that also hands ended spans to next,
+// after dropping unsampled ones and applying the redaction and truncation rules of filter.
+func newVeADKExportSpanProcessor(next sdktrace.SpanProcessor, filter *spanFilter) sdktrace.SpanProcessor {
+	return &veadkSpanProcessor{next: next, filter: filter}
+}
+
 func (p *veadkSpanProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
 	p.setCommonAttributes(ctx, span)
 	p.setSemanticAttributes(ctx, span)
+	if p.next != nil {
+		p.next.OnStart(ctx, span)
+	}
 }
 
 func (p *veadkSpanProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	p.recordToolMetrics(span)
+	if p.next != nil && p.filter.shouldExport(span) {
+		p.next.OnEnd(p.filter.apply(span))
+	}
+}
+
+func (p *veadkSpanProcessor) recordToolMetrics(span sdktrace.ReadOnlySpan) {
 	if classifySemanticSpanKind(span.Name()) != semanticSpanTool {
 		return
 	}
@@ -57,9 +77,19 @@ func (p *veadkSpanProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
 	p.recordToolTokenUsageFromSpanAttributes(span, metricAttrs)
 }
 
-func (p *veadkSpanProcessor) Shutdown(context.Context) error { return nil }
+func (p *veadkSpanProcessor) Shutdown(ctx context.Context) error {
+	if p.next != nil {
+		return p.next.Shutdown(ctx)
+	}
+	return nil
+}
 
-func (p *veadkSpanProcessor) ForceFlush(context.Context) error { return nil }
+func (p *veadkSpanProcessor) ForceFlush(ctx context.Context) error {
+	if p.next != nil {
+		return p.next.ForceFlush(ctx)
+	}
+	return nil
+}
 
 func (p *veadkSpanProcessor) setCommonAttributes(ctx context.Context, span sdktrace.ReadWriteSpan) {
 	sessionID := FallbackSessionID
@@ -0,0 +1,154 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ReadTraceFile reads the spans written by the file exporter, so that a trace file can be
+// inspected in the trace viewer after the fact. Metrics written to the same file are skipped.
+func ReadTraceFile(r io.Reader) ([]*SpanData, error) {
+	var spans []*SpanData
+	dec := json.NewDecoder(r)
+	for {
+		var stub fileSpan
+		if err := dec.Decode(&stub); err != nil {
+			if errors.Is(err, io.EOF) {
+				return spans, nil
+			}
+			return spans, fmt.Errorf("failed to decode trace file: %w", err)
+		}
+		if stub.SpanContext.SpanID == "" {
+			continue
+		}
+		spans = append(spans, stub.spanData())
+	}
+}
+
+// LoadTraceFile loads the spans of a trace file into a new TraceStore keeping at most maxTraces traces.
+func LoadTraceFile(path string, maxTraces int) (*TraceStore, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	spans, err := ReadTraceFile(f)
+	if err != nil {
+		return nil, err
+	}
+	store := NewTraceStore(maxTraces)
+	store.Add(spans...)
+	return store, nil
+}
+
+// fileSpan is the JSON layout of tracetest.SpanStub as written by stdouttrace.
+type fileSpan struct {
+	Name        string
+	SpanContext fileSpanContext
+	Parent      fileSpanContext
+	StartTime   time.Time
+	EndTime     time.Time
+	Attributes  []fileAttribute
+	Events      []struct {
+		Name       string
+		Attributes []fileAttribute
+		Time       time.Time
+	}
+	Status struct {
+		Code        json.RawMessage
+		Description string
+	}
+}
+
+type fileSpanContext struct {
+	TraceID string
+	SpanID  string
+}
+
+type fileAttribute struct {
+	Key   string
+	Value struct {
+		Type  string
+		Value json.RawMessage
+	}
+}
+
+func (s *fileSpan) spanData() *SpanData {
+	data := &SpanData{
+		TraceID:       s.SpanContext.TraceID,
+		SpanID:        s.SpanContext.SpanID,
+		Name:          s.Name,
+		Kind:          spanDataKind(s.Name),
+		StartTime:     s.StartTime,
+		EndTime:       s.EndTime,
+		Error:         isErrorStatusCode(s.Status.Code),
+		StatusMessage: s.Status.Description,
+		Attributes:    fileAttributeMap(s.Attributes),
+	}
+	if s.Parent.SpanID != "" && strings.Trim(s.Parent.SpanID, "0") != "" {
+		data.ParentSpanID = s.Parent.SpanID
+	}
+	for _, event := range s.Events {
+		data.Events = append(data.Events, SpanEventData{
+			Name:       event.Name,
+			Time:       event.Time,
+			Attributes: fileAttributeMap(event.Attributes),
+		})
+	}
+	return data
+}
+
+// isErrorStatusCode accepts the status code as its name or as its numeric value.
+func isErrorStatusCode(raw json.RawMessage) bool {
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return name == "Error"
+	}
+	var code int
+	if err := json.Unmarshal(raw, &code); err == nil {
+		// codes.Error
+		return code == 1
+	}
+	return false
+}
+
+func fileAttributeMap(attrs []fileAttribute) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		var v any
+		switch attr.Value.Type {
+		case "INT64":
+			var n int64
+			if err := json.Unmarshal(attr.Value.Value, &n); err == nil {
+				v = n
+			}
+		default:
+			_ = json.Unmarshal(attr.Value.Value, &v)
+		}
+		m[attr.Key] = v
+	}
+	return m
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// DefaultTraceViewerMaxTraces is the number of invocations kept when TraceViewerConfig.MaxTraces is not set.
+	DefaultTraceViewerMaxTraces = 50
+	// maxSpansPerTrace bounds the memory of a single runaway invocation.
+	maxSpansPerTrace = 2000
+)
+
+// Span kinds of SpanData.
+const (
+	SpanDataKindInvocation = "invocation"
+	SpanDataKindAgent      = "agent"
+	SpanDataKindLLM        = "llm"
+	SpanDataKindTool       = "tool"
+	SpanDataKindWorkflow   = "workflow"
+	SpanDataKindOther      = "other"
+)
+
+// SpanData is an ended span as kept by the TraceStore and shown by the trace viewer.
+type SpanData struct {
+	TraceID       string          `json:"trace_id"`
+	SpanID        string          `json:"span_id"`
+	ParentSpanID  string          `json:"parent_span_id,omitempty"`
+	Name          string          `json:"name"`
+	Kind          string          `json:"kind"`
+	StartTime     time.Time       `json:"start_time"`
+	EndTime       time.Time       `json:"end_time"`
+	Error         bool            `json:"error,omitempty"`
+	StatusMessage string          `json:"status_message,omitempty"`
+	Attributes    map[string]any  `json:"attributes,omitempty"`
+	Events        []SpanEventData `json:"events,omitempty"`
+}
+
+// SpanEventData is an event of a SpanData.
+type SpanEventData struct {
+	Name       string         `json:"name"`
+	Time       time.Time      `json:"time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// Duration returns the latency of the span.
+func (s *SpanData) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+func (s *SpanData) stringAttr(key string) string {
+	v, _ := s.Attributes[key].(string)
+	return v
+}
+
+func (s *SpanData) intAttr(key string) int64 {
+	switch v := s.Attributes[key].(type) {
+	case int64:
+		return v
+	case float64:
+		// attributes loaded from JSON
+		return int64(v)
+	}
+	return 0
+}
+
+func (s *SpanData) floatAttr(key string) float64 {
+	switch v := s.Attributes[key].(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+func spanDataKind(name string) string {
+	switch classifyTranslatedSpanKind(name) {
+	case translatedSpanInvocation:
+		return SpanDataKindInvocation
+	case translatedSpanAgent:
+		return SpanDataKindAgent
+	case translatedSpanLLM:
+		return SpanDataKindLLM
+	case translatedSpanTool:
+		return SpanDataKindTool
+	}
+	if isWorkflowSpanName(name) {
+		return SpanDataKindWorkflow
+	}
+	return SpanDataKindOther
+}
+
+func newSpanData(span sdktrace.ReadOnlySpan) *SpanData {
+	data := &SpanData{
+		TraceID:       span.SpanContext().TraceID().String(),
+		SpanID:        span.SpanContext().SpanID().String(),
+		Name:          span.Name(),
+		Kind:          spanDataKind(span.Name()),
+		StartTime:     span.StartTime(),
+		EndTime:       span.EndTime(),
+		Error:         span.Status().Code == codes.Error,
+		StatusMessage: span.Status().Description,
+		Attributes:    attributeMap(span.Attributes()),
+	}
+	if parent := span.Parent(); parent.SpanID().IsValid() {
+		data.ParentSpanID = parent.SpanID().String()
+	}
+	for _, event := range span.Events() {
+		data.Events = append(data.Events, SpanEventData{
+			Name:       event.Name,
+			Time:       event.Time,
+			Attributes: attributeMap(event.Attributes),
+		})
+	}
+	return data
+}
+
+func attributeMap(attrs []attribute.KeyValue) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, kv := range attrs {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return m
+}
+
+// TraceData holds the spans of one invocation, ordered by start time.
+type TraceData struct {
+	TraceID string      `json:"trace_id"`
+	Spans   []*SpanData `json:"spans"`
+}
+
+// TraceSummary is the overview of a trace listed by the trace viewer.
+type TraceSummary struct {
+	TraceID      string        `json:"trace_id"`
+	Name         string        `json:"name"`
+	SessionID    string        `json:"session_id,omitempty"`
+	UserID       string        `json:"user_id,omitempty"`
+	InvocationID string        `json:"invocation_id,omitempty"`
+	StartTime    time.Time     `json:"start_time"`
+	Duration     time.Duration `json:"duration"`
+	SpanCount    int           `json:"span_count"`
+	LLMCalls     int           `json:"llm_calls"`
+	ToolCalls    int           `json:"tool_calls"`
+	InputTokens  int64         `json:"input_tokens"`
+	OutputTokens int64         `json:"output_tokens"`
+	Cost         float64       `json:"cost,omitempty"`
+	Error        bool          `json:"error,omitempty"`
+}
+
+// Summary aggregates the spans of the trace.
+func (t *TraceData) Summary() TraceSummary {
+	summary := TraceSummary{TraceID: t.TraceID, SpanCount: len(t.Spans)}
+	if len(t.Spans) == 0 {
+		return summary
+	}
+
+	spanIDs := make(map[string]bool, len(t.Spans))
+	for _, span := range t.Spans {
+		spanIDs[span.SpanID] = true
+	}
+
+	var end time.Time
+	summary.StartTime = t.Spans[0].StartTime
+	for _, span := range t.Spans {
+		if span.EndTime.After(end) {
+			end = span.EndTime
+		}
+		if summary.Name == "" && (span.ParentSpanID == "" || !spanIDs[span.ParentSpanID]) {
+			summary.Name = span.Name
+		}
+		if summary.SessionID == "" {
+			summary.SessionID = span.stringAttr(AttrGenAISessionID)
+		}
+		if summary.UserID == "" {
+			summary.UserID = span.stringAttr(AttrGenAIUserID)
+		}
+		if summary.InvocationID == "" {
+			summary.InvocationID = span.stringAttr(AttrGenAIInvocationID)
+		}
+		summary.Error = summary.Error || span.Error
+
+		switch span.Kind {
+		case SpanDataKindLLM:
+			summary.LLMCalls++
+			summary.InputTokens += span.intAttr(AttrGenAIUsageInputTokens)
+			summary.OutputTokens += span.intAttr(AttrGenAIUsageOutputTokens)
+			summary.Cost += span.floatAttr(AttrGenAIUsageCost)
+		case SpanDataKindTool:
+			summary.ToolCalls++
+		}
+	}
+	summary.Duration = end.Sub(summary.StartTime)
+	return summary
+}
+
+// TraceStore keeps the spans of the last invocations in memory for the trace viewer.
+// It is a SpanExporter, so it receives spans after translation, sampling and redaction
+// like any other exporter.
+type TraceStore struct {
+	mu        sync.RWMutex
+	maxTraces int
+	traces    map[string]*TraceData
+	// order lists the trace ids from the oldest to the newest
+	order []string
+}
+
+// NewTraceStore creates a store keeping at most maxTraces traces, DefaultTraceViewerMaxTraces if not positive.
+func NewTraceStore(maxTraces int) *TraceStore {
+	if maxTraces <= 0 {
+		maxTraces = DefaultTraceViewerMaxTraces
+	}
+	return &TraceStore{
+		maxTraces: maxTraces,
+		traces:    make(map[string]*TraceData),
+	}
+}
+
+// ExportSpans adds the spans to their traces.
+func (s *TraceStore) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	data := make([]*SpanData, 0, len(spans))
+	for _, span := range spans {
+		data = append(data, newSpanData(span))
+	}
+	s.Add(data...)
+	return nil
+}
+
+// Shutdown keeps the traces, so they can still be viewed while the server shuts down.
+func (s *TraceStore) Shutdown(context.Context) error {
+	return nil
+}
+
+// Add adds spans to their traces, evicting the oldest traces beyond the limit of the store.
+func (s *TraceStore) Add(spans ...*SpanData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, span := range spans {
+		t, ok := s.traces[span.TraceID]
+		if !ok {
+			t = &TraceData{TraceID: span.TraceID}
+			s.traces[span.TraceID] = t
+			s.order = append(s.order, span.TraceID)
+		}
+		if len(t.Spans) >= maxSpansPerTrace {
+			continue
+		}
+		// spans mostly end in order, so inserting from the back is cheap
+		i := len(t.Spans)
+		for i > 0 && t.Spans[i-1].StartTime.After(span.StartTime) {
+			i--
+		}
+		t.Spans = append(t.Spans, nil)
+		copy(t.Spans[i+1:], t.Spans[i:])
+		t.Spans[i] = span
+	}
+
+	for len(s.order) > s.maxTraces {
+		delete(s.traces, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// Traces returns the summaries of the kept traces, the most recent first.
+func (s *TraceStore) Traces() []TraceSummary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summaries := make([]TraceSummary, 0, len(s.traces))
+	for _, t := range s.traces {
+		summaries = append(summaries, t.Summary())
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].StartTime.After(summaries[j].StartTime)
+	})
+	return summaries
+}
+
+// Trace returns a copy of the trace with the given id.
+func (s *TraceStore) Trace(traceID string) (*TraceData, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.traces[traceID]
+	if !ok {
+		return nil, false
+	}
+	return &TraceData{TraceID: t.TraceID, Spans: append([]*SpanData(nil), t.Spans...)}, true
+}
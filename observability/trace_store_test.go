@@ -0,0 +1,208 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/configs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordTestInvocation records an invocation with an agent, an LLM call and a failed tool call.
+func recordTestInvocation(t *testing.T, exporters ...sdktrace.SpanExporter) string {
+	t.Helper()
+	var opts []sdktrace.TracerProviderOption
+	for _, exp := range exporters {
+		opts = append(opts, sdktrace.WithSyncer(exp))
+	}
+	tp := sdktrace.NewTracerProvider(opts...)
+	tracer := tp.Tracer("test")
+
+	ctx, invocation := tracer.Start(context.Background(), SpanInvocation)
+	invocation.SetAttributes(
+		attribute.String(AttrGenAISessionID, "session-1"),
+		attribute.String(AttrGenAIUserID, "user-1"),
+	)
+	agentCtx, agent := tracer.Start(ctx, SpanPrefixInvokeAgent+"weather_agent")
+	_, llm := tracer.Start(agentCtx, SpanCallLLM)
+	llm.SetAttributes(
+		attribute.String(AttrGenAIRequestModel, "doubao-seed-1-6"),
+		attribute.Int64(AttrGenAIUsageInputTokens, 120),
+		attribute.Int64(AttrGenAIUsageOutputTokens, 30),
+		attribute.Float64(AttrGenAIUsageCost, 0.25),
+		attribute.String(AttrInputValue, "what is the weather in <Beijing>?"),
+		attribute.String(AttrOutputValue, "call get_weather"),
+	)
+	llm.AddEvent("gen_ai.choice", trace.WithAttributes(attribute.Int64("index", 0)))
+	llm.End()
+	_, tool := tracer.Start(agentCtx, SpanPrefixExecuteTool+"get_weather")
+	tool.SetAttributes(attribute.String(AttrGenAIToolInput, `{"city":"Beijing"}`))
+	tool.SetStatus(codes.Error, "weather service unavailable")
+	tool.End()
+	agent.End()
+	invocation.End()
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+	return invocation.SpanContext().TraceID().String()
+}
+
+func TestTraceStore_Summary(t *testing.T) {
+	store := NewTraceStore(10)
+	traceID := recordTestInvocation(t, store)
+
+	traces := store.Traces()
+	require.Len(t, traces, 1)
+	summary := traces[0]
+	assert.Equal(t, traceID, summary.TraceID)
+	assert.Equal(t, SpanInvocation, summary.Name)
+	assert.Equal(t, "session-1", summary.SessionID)
+	assert.Equal(t, "user-1", summary.UserID)
+	assert.Equal(t, 4, summary.SpanCount)
+	assert.Equal(t, 1, summary.LLMCalls)
+	assert.Equal(t, 1, summary.ToolCalls)
+	assert.Equal(t, int64(120), summary.InputTokens)
+	assert.Equal(t, int64(30), summary.OutputTokens)
+	assert.InDelta(t, 0.25, summary.Cost, 1e-9)
+	assert.True(t, summary.Error)
+
+	data, ok := store.Trace(traceID)
+	require.True(t, ok)
+	kinds := make([]string, 0, len(data.Spans))
+	for _, span := range data.Spans {
+		kinds = append(kinds, span.Kind)
+	}
+	assert.Equal(t, []string{SpanDataKindInvocation, SpanDataKindAgent, SpanDataKindLLM, SpanDataKindTool}, kinds)
+}
+
+func TestTraceStore_EvictsOldestTraces(t *testing.T) {
+	store := NewTraceStore(2)
+	first := recordTestInvocation(t, store)
+	second := recordTestInvocation(t, store)
+	third := recordTestInvocation(t, store)
+
+	_, ok := store.Trace(first)
+	assert.False(t, ok)
+	traces := store.Traces()
+	require.Len(t, traces, 2)
+	assert.Equal(t, third, traces[0].TraceID)
+	assert.Equal(t, second, traces[1].TraceID)
+}
+
+func TestLoadTraceFile(t *testing.T) {
+	var buf bytes.Buffer
+	fileExporter, err := stdouttrace.New(stdouttrace.WithWriter(&buf), stdouttrace.WithPrettyPrint())
+	require.NoError(t, err)
+	live := NewTraceStore(10)
+	traceID := recordTestInvocation(t, fileExporter, live)
+
+	// metrics are written to the same file and must be skipped
+	buf.WriteString(`{"Resource":[],"ScopeMetrics":[]}`)
+	path := filepath.Join(t.TempDir(), "trace.json")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+	store, err := LoadTraceFile(path, 10)
+	require.NoError(t, err)
+
+	loaded, ok := store.Trace(traceID)
+	require.True(t, ok)
+	expectedTrace, _ := live.Trace(traceID)
+	expected, actual := expectedTrace.Summary(), loaded.Summary()
+	assert.True(t, expected.StartTime.Equal(actual.StartTime))
+	actual.StartTime = expected.StartTime
+	assert.Equal(t, expected, actual)
+	require.Len(t, loaded.Spans, 4)
+	assert.Equal(t, "", loaded.Spans[0].ParentSpanID)
+	assert.Equal(t, loaded.Spans[1].SpanID, loaded.Spans[2].ParentSpanID)
+	assert.Equal(t, "weather service unavailable", loaded.Spans[3].StatusMessage)
+	assert.Equal(t, "what is the weather in <Beijing>?", loaded.Spans[2].Attributes[AttrInputValue])
+	require.Len(t, loaded.Spans[2].Events, 1)
+	assert.Equal(t, int64(0), loaded.Spans[2].Events[0].Attributes["index"])
+
+	_, err = LoadTraceFile(filepath.Join(t.TempDir(), "missing.json"), 10)
+	assert.Error(t, err)
+}
+
+func TestTraceViewerHandler(t *testing.T) {
+	store := NewTraceStore(10)
+	traceID := recordTestInvocation(t, store)
+	handler := NewTraceViewerHandler(store, DefaultTraceViewerPath)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get(DefaultTraceViewerPath + "/")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), fmt.Sprintf(`href="%s/%s"`, DefaultTraceViewerPath, traceID))
+
+	rec = get(DefaultTraceViewerPath + "/" + traceID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, SpanPrefixExecuteTool+"get_weather")
+	assert.Contains(t, body, "model: doubao-seed-1-6")
+	assert.Contains(t, body, "tokens: 120 in / 30 out")
+	assert.Contains(t, body, "what is the weather in &lt;Beijing&gt;?")
+	assert.Contains(t, body, "weather service unavailable")
+
+	rec = get(DefaultTraceViewerPath + "/api/traces")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var summaries []TraceSummary
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summaries))
+	require.Len(t, summaries, 1)
+	assert.Equal(t, traceID, summaries[0].TraceID)
+
+	rec = get(DefaultTraceViewerPath + "/api/traces/" + traceID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var data TraceData
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &data))
+	assert.Len(t, data.Spans, 4)
+
+	assert.Equal(t, http.StatusNotFound, get(DefaultTraceViewerPath+"/unknown").Code)
+	assert.Equal(t, http.StatusNotFound, get(DefaultTraceViewerPath+"/api/traces/unknown").Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, DefaultTraceViewerPath+"/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestNewMultiExporter_TraceViewer(t *testing.T) {
+	exp, err := NewMultiExporter(context.Background(), &configs.OpenTelemetryConfig{
+		TraceViewer: &configs.TraceViewerConfig{Enable: true, Path: "/traces/", MaxTraces: 5},
+	})
+	require.NoError(t, err)
+	_, ok := exp.(*TraceStore)
+	assert.True(t, ok)
+
+	path, handler := TraceViewerEndpoint()
+	assert.Equal(t, "/traces", path)
+	assert.NotNil(t, handler)
+}
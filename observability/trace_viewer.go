@@ -0,0 +1,284 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volcengine/veadk-go/configs"
+)
+
+// DefaultTraceViewerPath is the route of the trace viewer when TraceViewerConfig.Path is empty.
+const DefaultTraceViewerPath = "/debug/traces"
+
+var (
+	traceViewerMu      sync.RWMutex
+	traceViewerPath    string
+	traceViewerHandler http.Handler
+)
+
+// NewTraceViewer creates the TraceStore exporter of the trace viewer.
+// The store is served by the handler returned from TraceViewerEndpoint.
+func NewTraceViewer(cfg *configs.TraceViewerConfig) *TraceStore {
+	path := strings.TrimSuffix(cfg.Path, "/")
+	if path == "" {
+		path = DefaultTraceViewerPath
+	}
+	store := NewTraceStore(cfg.MaxTraces)
+
+	traceViewerMu.Lock()
+	defer traceViewerMu.Unlock()
+	traceViewerPath = path
+	traceViewerHandler = NewTraceViewerHandler(store, path)
+	return store
+}
+
+// TraceViewerEndpoint returns the route prefix and handler of the trace viewer.
+// The handler is nil when the trace viewer is not enabled.
+func TraceViewerEndpoint() (string, http.Handler) {
+	traceViewerMu.RLock()
+	defer traceViewerMu.RUnlock()
+	return traceViewerPath, traceViewerHandler
+}
+
+// NewTraceViewerHandler serves the traces of the store under prefix:
+//
+//	GET {prefix}/                   lists the traces
+//	GET {prefix}/{trace_id}         renders the timeline of a trace
+//	GET {prefix}/api/traces         lists the traces as JSON
+//	GET {prefix}/api/traces/{id}    returns the spans of a trace as JSON
+func NewTraceViewerHandler(store *TraceStore, prefix string) http.Handler {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+		switch {
+		case path == "":
+			renderTraceViewer(w, traceListTemplate, traceListPage{Prefix: prefix, Traces: store.Traces()})
+		case path == "api/traces":
+			writeTraceViewerJSON(w, store.Traces())
+		case strings.HasPrefix(path, "api/traces/"):
+			t, ok := store.Trace(strings.TrimPrefix(path, "api/traces/"))
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			writeTraceViewerJSON(w, t)
+		default:
+			t, ok := store.Trace(path)
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			renderTraceViewer(w, traceTimelineTemplate, newTimelinePage(prefix, t))
+		}
+	})
+}
+
+func writeTraceViewerJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func renderTraceViewer(w http.ResponseWriter, tmpl *template.Template, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+type traceListPage struct {
+	Prefix string
+	Traces []TraceSummary
+}
+
+type timelinePage struct {
+	Prefix  string
+	Summary TraceSummary
+	Rows    []timelineRow
+}
+
+// timelineRow is a span placed on the timeline, Offset and Width are percents of the trace duration.
+type timelineRow struct {
+	Span   *SpanData
+	Depth  int
+	Offset float64
+	Width  float64
+
+	Model        string
+	InputTokens  int64
+	OutputTokens int64
+	Cost         float64
+	Input        string
+	Output       string
+	Attributes   []timelineAttribute
+}
+
+type timelineAttribute struct {
+	Key   string
+	Value string
+}
+
+func newTimelinePage(prefix string, t *TraceData) timelinePage {
+	summary := t.Summary()
+	page := timelinePage{Prefix: prefix, Summary: summary}
+
+	children := make(map[string][]*SpanData)
+	spanIDs := make(map[string]bool, len(t.Spans))
+	for _, span := range t.Spans {
+		spanIDs[span.SpanID] = true
+	}
+	var roots []*SpanData
+	for _, span := range t.Spans {
+		if span.ParentSpanID == "" || !spanIDs[span.ParentSpanID] {
+			roots = append(roots, span)
+			continue
+		}
+		children[span.ParentSpanID] = append(children[span.ParentSpanID], span)
+	}
+
+	total := summary.Duration
+	var walk func(span *SpanData, depth int)
+	walk = func(span *SpanData, depth int) {
+		page.Rows = append(page.Rows, newTimelineRow(span, depth, summary.StartTime, total))
+		for _, child := range children[span.SpanID] {
+			walk(child, depth+1)
+		}
+	}
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	return page
+}
+
+func newTimelineRow(span *SpanData, depth int, start time.Time, total time.Duration) timelineRow {
+	row := timelineRow{Span: span, Depth: depth, Width: 100}
+	if total > 0 {
+		row.Offset = float64(span.StartTime.Sub(start)) / float64(total) * 100
+		row.Width = float64(span.Duration()) / float64(total) * 100
+	}
+
+	switch span.Kind {
+	case SpanDataKindLLM:
+		row.Model = span.stringAttr(AttrGenAIResponseModel)
+		if row.Model == "" {
+			row.Model = span.stringAttr(AttrGenAIRequestModel)
+		}
+		row.InputTokens = span.intAttr(AttrGenAIUsageInputTokens)
+		row.OutputTokens = span.intAttr(AttrGenAIUsageOutputTokens)
+		row.Cost = span.floatAttr(AttrGenAIUsageCost)
+		row.Input = span.stringAttr(AttrInputValue)
+		row.Output = span.stringAttr(AttrOutputValue)
+	case SpanDataKindTool:
+		row.Input = span.stringAttr(AttrGenAIToolInput)
+		row.Output = span.stringAttr(AttrGenAIToolOutput)
+	default:
+		row.Input = span.stringAttr(AttrInputValue)
+		row.Output = span.stringAttr(AttrOutputValue)
+	}
+
+	for key, value := range span.Attributes {
+		row.Attributes = append(row.Attributes, timelineAttribute{Key: key, Value: fmt.Sprint(value)})
+	}
+	sort.Slice(row.Attributes, func(i, j int) bool { return row.Attributes[i].Key < row.Attributes[j].Key })
+	return row
+}
+
+var traceViewerFuncs = template.FuncMap{
+	"duration": func(d time.Duration) string {
+		return d.Round(time.Millisecond).String()
+	},
+	"time": func(t time.Time) string {
+		return t.Local().Format("2006-01-02 15:04:05.000")
+	},
+	"indent": func(depth int) int {
+		return depth * 16
+	},
+}
+
+const traceViewerStyle = `<style>
+body { font-family: -apple-system, sans-serif; font-size: 13px; margin: 16px; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #eee; }
+.error { color: #c62828; }
+.row { display: flex; align-items: flex-start; border-bottom: 1px solid #f0f0f0; padding: 2px 0; }
+.name { width: 40%; }
+.bar-cell { width: 60%; position: relative; height: 18px; }
+.bar { position: absolute; top: 3px; height: 12px; min-width: 2px; border-radius: 2px; }
+.kind-invocation { background: #7e57c2; } .kind-agent { background: #42a5f5; }
+.kind-workflow { background: #26a69a; } .kind-llm { background: #66bb6a; } .kind-tool { background: #ffa726; } .kind-other { background: #bdbdbd; }
+.bar.error { background: #e53935; }
+pre { white-space: pre-wrap; word-break: break-all; background: #fafafa; padding: 6px; margin: 4px 0; max-height: 320px; overflow: auto; }
+summary { cursor: pointer; }
+</style>`
+
+var traceListTemplate = template.Must(template.New("traces").Funcs(traceViewerFuncs).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Traces</title>` + traceViewerStyle + `</head><body>
+<h2>Recent invocations</h2>
+<table>
+<tr><th>Start</th><th>Name</th><th>Session</th><th>User</th><th>Duration</th><th>Spans</th><th>LLM calls</th><th>Tool calls</th><th>Tokens in/out</th><th>Cost</th></tr>
+{{- range .Traces}}
+<tr{{if .Error}} class="error"{{end}}>
+<td><a href="{{$.Prefix}}/{{.TraceID}}">{{time .StartTime}}</a></td>
+<td>{{.Name}}</td><td>{{.SessionID}}</td><td>{{.UserID}}</td><td>{{duration .Duration}}</td>
+<td>{{.SpanCount}}</td><td>{{.LLMCalls}}</td><td>{{.ToolCalls}}</td><td>{{.InputTokens}}/{{.OutputTokens}}</td>
+<td>{{if .Cost}}{{printf "%.6f" .Cost}}{{end}}</td>
+</tr>
+{{- else}}
+<tr><td colspan="10">No traces recorded yet.</td></tr>
+{{- end}}
+</table>
+</body></html>`))
+
+var traceTimelineTemplate = template.Must(template.New("trace").Funcs(traceViewerFuncs).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Trace {{.Summary.TraceID}}</title>` + traceViewerStyle + `</head><body>
+<p><a href="{{.Prefix}}/">&larr; all traces</a></p>
+{{- with .Summary}}
+<h2>{{.Name}}</h2>
+<p>trace <code>{{.TraceID}}</code> &middot; session {{.SessionID}} &middot; user {{.UserID}} &middot; {{time .StartTime}} &middot; {{duration .Duration}}
+&middot; {{.LLMCalls}} LLM calls &middot; {{.ToolCalls}} tool calls &middot; tokens {{.InputTokens}}/{{.OutputTokens}}{{if .Cost}} &middot; cost {{printf "%.6f" .Cost}}{{end}}</p>
+{{- end}}
+{{- range .Rows}}
+<div class="row">
+<div class="name" style="padding-left: {{indent .Depth}}px">
+<details>
+<summary{{if .Span.Error}} class="error"{{end}}>{{.Span.Name}} <small>{{duration .Span.Duration}}</small></summary>
+{{- if .Model}}<div>model: {{.Model}}</div>{{end}}
+{{- if or .InputTokens .OutputTokens}}<div>tokens: {{.InputTokens}} in / {{.OutputTokens}} out{{if .Cost}} &middot; cost {{printf "%.6f" .Cost}}{{end}}</div>{{end}}
+{{- if .Span.StatusMessage}}<div class="error">{{.Span.StatusMessage}}</div>{{end}}
+{{- if .Input}}<div>input</div><pre>{{.Input}}</pre>{{end}}
+{{- if .Output}}<div>output</div><pre>{{.Output}}</pre>{{end}}
+<details><summary>attributes</summary><table>
+{{- range .Attributes}}<tr><td>{{.Key}}</td><td>{{.Value}}</td></tr>{{end}}
+</table></details>
+</details>
+</div>
+<div class="bar-cell"><div class="bar kind-{{.Span.Kind}}{{if .Span.Error}} error{{end}}" style="left: {{printf "%.2f" .Offset}}%; width: {{printf "%.2f" .Width}}%"></div></div>
+</div>
+{{- end}}
+</body></html>`))
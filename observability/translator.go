@@ -460,8 +460,9 @@ func (p *translatedSpan) Parent() oteltrace.SpanContext {
 	parent := p.ReadOnlySpan.Parent()
 	registry := GetRegistry()
 
-	// 1. Check if this is an invoke_agent span - link to our invocation span if available
-	if classifyTranslatedSpanKind(p.ReadOnlySpan.Name()) == translatedSpanAgent {
+	// 1. Check if this is an invoke_agent span - link to our invocation span if available,
+	// unless the agent runs as a step of a workflow agent
+	if classifyTranslatedSpanKind(p.ReadOnlySpan.Name()) == translatedSpanAgent && !registry.IsWorkflowSpan(parent) {
 		adkTraceID := p.ReadOnlySpan.SpanContext().TraceID()
 		if invocationSC, ok := registry.GetInvocationSpanContext(adkTraceID); ok {
 			return invocationSC
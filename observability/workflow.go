@@ -0,0 +1,142 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/adk/agent"
+)
+
+// WorkflowSpan is the span of one step of a workflow agent: a loop iteration, a parallel
+// branch, a sequential step or a graph node. The invoke_agent spans of the sub-agents run
+// in the step are its children, and the step is recorded in the workflow metrics when it ends.
+type WorkflowSpan struct {
+	span         trace.Span
+	start        time.Time
+	workflowType string
+	metricAttrs  []attribute.KeyValue
+	exitReason   string
+	escalatedBy  string
+	err          error
+}
+
+// StartWorkflowSpan starts the span of a workflow step as a child of the workflow agent span in ctx,
+// and returns the invocation context the sub-agents of the step must run with.
+func StartWorkflowSpan(ctx agent.InvocationContext, workflowType string, index int, attrs ...attribute.KeyValue) (agent.InvocationContext, *WorkflowSpan) {
+	workflowName := FallbackAgentName
+	if ctx.Agent() != nil {
+		workflowName = ctx.Agent().Name()
+	}
+	return StartWorkflowStepSpan(ctx, workflowName, workflowType, index, attrs...)
+}
+
+// StartWorkflowStepSpan is StartWorkflowSpan for a step run with the context of the sub-agent,
+// e.g. a parallel branch, which does not carry the workflow agent.
+func StartWorkflowStepSpan(ctx agent.InvocationContext, workflowName, workflowType string, index int, attrs ...attribute.KeyValue) (agent.InvocationContext, *WorkflowSpan) {
+	spanCtx, span := otel.Tracer(InstrumentationName).Start(context.Context(ctx), workflowSpanName(workflowType, workflowName),
+		trace.WithAttributes(
+			attribute.String(AttrGenAIWorkflowType, workflowType),
+			attribute.String(AttrGenAIWorkflowName, workflowName),
+			attribute.Int(AttrGenAIWorkflowStepIndex, index),
+		),
+		trace.WithAttributes(attrs...),
+	)
+	setWorkflowAttributes(span)
+	GetRegistry().RegisterWorkflowSpan(span.SpanContext())
+
+	return ctx.WithContext(spanCtx), &WorkflowSpan{
+		span:         span,
+		start:        time.Now(),
+		workflowType: workflowType,
+		metricAttrs: []attribute.KeyValue{
+			attribute.String(AttrGenAIWorkflowType, workflowType),
+			attribute.String(AttrGenAIWorkflowName, workflowName),
+		},
+		exitReason: WorkflowExitCompleted,
+	}
+}
+
+func workflowSpanName(workflowType, workflowName string) string {
+	switch workflowType {
+	case WorkflowTypeLoop:
+		return SpanPrefixLoopIteration + workflowName
+	case WorkflowTypeParallel:
+		return SpanPrefixParallelBranch + workflowName
+	case WorkflowTypeGraph:
+		return SpanPrefixGraphStep + workflowName
+	default:
+		return SpanPrefixSequentialStep + workflowName
+	}
+}
+
+func isWorkflowSpanName(name string) bool {
+	return strings.HasPrefix(name, SpanPrefixLoopIteration) ||
+		strings.HasPrefix(name, SpanPrefixParallelBranch) ||
+		strings.HasPrefix(name, SpanPrefixSequentialStep) ||
+		strings.HasPrefix(name, SpanPrefixGraphStep)
+}
+
+// SetError records err as the error of the step, if no error was recorded yet.
+func (s *WorkflowSpan) SetError(err error) {
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+// Escalate records that the sub-agent author escalated, which ends the loop.
+func (s *WorkflowSpan) Escalate(author string) {
+	s.exitReason = WorkflowExitEscalated
+	s.escalatedBy = author
+}
+
+// SetExitReason overrides the exit reason of the step, WorkflowExitCompleted by default.
+func (s *WorkflowSpan) SetExitReason(reason string) {
+	s.exitReason = reason
+}
+
+// End ends the span and records the step metrics.
+func (s *WorkflowSpan) End() {
+	exitReason := s.exitReason
+	if s.err != nil && exitReason == WorkflowExitCompleted {
+		exitReason = WorkflowExitError
+	}
+
+	s.span.SetAttributes(attribute.String(AttrGenAIWorkflowExitReason, exitReason))
+	if s.escalatedBy != "" {
+		s.span.SetAttributes(attribute.String(AttrGenAIWorkflowEscalatedBy, s.escalatedBy))
+	}
+	if s.err != nil {
+		s.span.RecordError(s.err)
+		s.span.SetStatus(codes.Error, s.err.Error())
+	}
+	s.span.End()
+
+	RecordWorkflowStep(context.Background(), time.Since(s.start).Seconds(),
+		append(s.metricAttrs, attribute.String(AttrGenAIWorkflowExitReason, exitReason))...)
+}
+
+// RecordMaxIterationsReached adds EventWorkflowMaxIterations to the loop agent span in ctx.
+func RecordMaxIterationsReached(ctx context.Context, maxIterations uint) {
+	trace.SpanFromContext(ctx).AddEvent(EventWorkflowMaxIterations, trace.WithAttributes(
+		attribute.Int64(AttrGenAIWorkflowMaxIterations, int64(maxIterations)),
+	))
+}
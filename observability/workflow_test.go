@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTranslatedSpan_KeepsWorkflowParentOfSubAgents(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := tp.Tracer("test")
+
+	ctx, loop := tracer.Start(context.Background(), SpanPrefixInvokeAgent+"refine")
+	ctx, iteration := tracer.Start(ctx, SpanPrefixLoopIteration+"refine")
+	_, worker := tracer.Start(ctx, SpanPrefixInvokeAgent+"worker")
+	worker.End()
+	iteration.End()
+	loop.End()
+
+	registry := GetRegistry()
+	adkTraceID := loop.SpanContext().TraceID()
+	veadkTraceID, _ := trace.TraceIDFromHex("44444444444444444444444444444444")
+	invocationSpanID, _ := trace.SpanIDFromHex("4444444444444444")
+	invocationSC := trace.NewSpanContext(trace.SpanContextConfig{TraceID: veadkTraceID, SpanID: invocationSpanID})
+	registry.RegisterInvocationSpanContext(adkTraceID, invocationSC)
+	registry.RegisterTraceMapping(adkTraceID, veadkTraceID)
+	registry.RegisterWorkflowSpan(iteration.SpanContext())
+	t.Cleanup(func() { registry.cleanupByTraceID(adkTraceID, invocationSpanID) })
+
+	assert.True(t, registry.IsWorkflowSpan(iteration.SpanContext()))
+	assert.False(t, registry.IsWorkflowSpan(loop.SpanContext()))
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range exporter.GetSpans().Snapshots() {
+		spans[span.Name()] = span
+	}
+	require.Len(t, spans, 3)
+
+	// the workflow agent hangs under the invocation
+	translatedLoop := &translatedSpan{ReadOnlySpan: spans[SpanPrefixInvokeAgent+"refine"]}
+	assert.Equal(t, invocationSC, translatedLoop.Parent())
+
+	// the sub-agent keeps the iteration as parent, moved to the veadk trace
+	translatedWorker := &translatedSpan{ReadOnlySpan: spans[SpanPrefixInvokeAgent+"worker"]}
+	assert.Equal(t, iteration.SpanContext().SpanID(), translatedWorker.Parent().SpanID())
+	assert.Equal(t, veadkTraceID, translatedWorker.Parent().TraceID())
+	assert.Equal(t, veadkTraceID, translatedWorker.SpanContext().TraceID())
+
+	translatedIteration := &translatedSpan{ReadOnlySpan: spans[SpanPrefixLoopIteration+"refine"]}
+	assert.Equal(t, loop.SpanContext().SpanID(), translatedIteration.Parent().SpanID())
+	assert.Equal(t, veadkTraceID, translatedIteration.Parent().TraceID())
+}
+
+func TestSpanDataKind_Workflow(t *testing.T) {
+	assert.Equal(t, SpanDataKindWorkflow, spanDataKind(SpanPrefixParallelBranch+"fanout"))
+	assert.Equal(t, SpanDataKindWorkflow, spanDataKind(SpanPrefixSequentialStep+"pipeline"))
+	assert.Equal(t, SpanDataKindAgent, spanDataKind(SpanPrefixInvokeAgent+"pipeline"))
+}
@@ -30,6 +30,8 @@ import (
 var (
 	ErrNilCozeLoopWorkspaceID = errors.New("coze loop workspace id is nil, Please configure it via environment COZELOOP_WORKSPACE_ID")
 	ErrNilCozeLoopApiToken    = errors.New("coze loop api token is nil, Please configure it via environment COZELOOP_API_TOKEN")
+	ErrNilCozeLoopClient      = errors.New("CozeLoop client is not initialized")
+	ErrPromptNotFound         = errors.New("prompt not found")
 )
 
 type BasePromptManager interface {
@@ -74,6 +76,21 @@ func NewCozeLoopPromptManager(promptKey, version, label string) (*CozeLoopPrompt
 // 参数说明 ：https://loop.coze.cn/open/docs/cozeloop/prompt-version-tag-for-go-sdk
 // promptKey, version, label string 按顺序写入，可以覆盖配置参数
 func (m *CozeLoopPromptManager) GetPrompt(args ...string) string {
+	prompt, err := m.fetchPrompt(context.Background(), args...)
+	if err != nil {
+		log.Info(err.Error())
+		return DEFAULT_INSTRUCTION
+	}
+	return prompt
+}
+
+// FetchPrompt retrieves the configured prompt from CozeLoop, reporting failures instead of
+// falling back to the default instruction.
+func (m *CozeLoopPromptManager) FetchPrompt(ctx context.Context) (string, error) {
+	return m.fetchPrompt(ctx)
+}
+
+func (m *CozeLoopPromptManager) fetchPrompt(ctx context.Context, args ...string) (string, error) {
 	promptKey := m.promptKey
 	if len(args) >= 1 {
 		promptKey = args[0]
@@ -88,11 +105,10 @@ func (m *CozeLoopPromptManager) GetPrompt(args ...string) string {
 	}
 
 	if m.client == nil {
-		log.Info("CozeLoop client is not initialized")
-		return DEFAULT_INSTRUCTION
+		return "", ErrNilCozeLoopClient
 	}
 
-	pmt, err := m.client.GetPrompt(context.Background(), cozeloop.GetPromptParam{
+	pmt, err := m.client.GetPrompt(ctx, cozeloop.GetPromptParam{
 		PromptKey: promptKey,
 		Version:   version,
 		Label:     label,
@@ -104,11 +120,9 @@ func (m *CozeLoopPromptManager) GetPrompt(args ...string) string {
 		pmt.PromptTemplate != nil &&
 		len(pmt.PromptTemplate.Messages) > 0 &&
 		pmt.PromptTemplate.Messages[0].Content != nil {
-		return *pmt.PromptTemplate.Messages[0].Content
+		return *pmt.PromptTemplate.Messages[0].Content, nil
 	}
 
-	log.Infof("Prompt %s version %s label %s not found, get prompt result is %v, return default instruction",
-		promptKey, version, label, pmt)
-
-	return DEFAULT_INSTRUCTION
+	return "", fmt.Errorf("%w: prompt %s version %s label %s not found, get prompt result is %v, error is %v",
+		ErrPromptNotFound, promptKey, version, label, pmt, err)
 }
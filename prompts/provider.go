@@ -0,0 +1,138 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompts
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volcengine/veadk-go/log"
+	"github.com/volcengine/veadk-go/utils"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/util/instructionutil"
+)
+
+// DefaultPromptTTL is how long InstructionProvider caches a prompt.
+const DefaultPromptTTL = time.Minute
+
+// PromptFetcher is implemented by prompt managers which report failures, so that the last
+// prompt fetched is kept when the prompt service is unavailable.
+type PromptFetcher interface {
+	FetchPrompt(ctx context.Context) (string, error)
+}
+
+// InstructionProvider serves the prompt of a BasePromptManager as the instruction of an
+// agent, fetched again once the TTL elapsed. Each invocation keeps the prompt it started with.
+type InstructionProvider struct {
+	manager BasePromptManager
+	ttl     time.Duration
+
+	// fetches lets one caller at a time fetch the prompt, without holding mu
+	fetches singleflight.Group
+
+	mu      sync.Mutex
+	prompt  string
+	fetched time.Time
+	// loaded is set once a prompt was fetched
+	loaded bool
+	// epoch counts the invalidations, so that fetches started before one don't hide it
+	epoch int
+
+	snapshots utils.InvocationSnapshots[string]
+}
+
+// NewInstructionProvider caches the prompts of manager for ttl, DefaultPromptTTL when zero.
+func NewInstructionProvider(manager BasePromptManager, ttl time.Duration) *InstructionProvider {
+	if ttl <= 0 {
+		ttl = DefaultPromptTTL
+	}
+	return &InstructionProvider{manager: manager, ttl: ttl}
+}
+
+// Instruction implements llmagent.InstructionProvider. Session state placeholders of the
+// prompt are filled in as for static instructions.
+func (p *InstructionProvider) Instruction(ctx agent.ReadonlyContext) (string, error) {
+	prompt := p.snapshots.Get(ctx.InvocationID(), func() string { return p.Prompt(ctx) })
+	if !strings.Contains(prompt, "{") {
+		return prompt, nil
+	}
+	return instructionutil.InjectSessionState(ctx, prompt)
+}
+
+// Prompt returns the cached prompt, fetching it when it expired. Concurrent callers wait
+// for the same fetch.
+func (p *InstructionProvider) Prompt(ctx context.Context) string {
+	p.mu.Lock()
+	if !p.fetched.IsZero() && time.Since(p.fetched) < p.ttl {
+		defer p.mu.Unlock()
+		return p.prompt
+	}
+	p.mu.Unlock()
+
+	prompt, _, _ := p.fetches.Do("", func() (any, error) {
+		return p.fetch(ctx), nil
+	})
+	return prompt.(string)
+}
+
+func (p *InstructionProvider) fetch(ctx context.Context) string {
+	p.mu.Lock()
+	epoch := p.epoch
+	p.mu.Unlock()
+
+	fetcher, ok := p.manager.(PromptFetcher)
+	if !ok {
+		prompt := p.manager.GetPrompt()
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.prompt, p.loaded = prompt, true
+		p.markFetched(epoch)
+		return p.prompt
+	}
+	prompt, err := fetcher.FetchPrompt(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case err == nil:
+		p.prompt, p.loaded = prompt, true
+	case !p.loaded:
+		log.WarnContext(ctx, "fetch prompt failed, using the default instruction", "error", err)
+		p.prompt = DEFAULT_INSTRUCTION
+	default:
+		log.WarnContext(ctx, "fetch prompt failed, keeping the previous prompt", "error", err)
+	}
+	// failures are retried once the TTL elapsed too
+	p.markFetched(epoch)
+	return p.prompt
+}
+
+// markFetched caches the prompt fetched since epoch, unless it was invalidated meanwhile.
+func (p *InstructionProvider) markFetched(epoch int) {
+	if p.epoch == epoch {
+		p.fetched = time.Now()
+	}
+}
+
+// Invalidate fetches the prompt again on its next use.
+func (p *InstructionProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fetched = time.Time{}
+	p.epoch++
+}
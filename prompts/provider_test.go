@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompts
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/agent"
+)
+
+type fakeManager struct {
+	prompt  string
+	err     error
+	fetches int
+}
+
+func (m *fakeManager) GetPrompt(...string) string {
+	return m.prompt
+}
+
+func (m *fakeManager) FetchPrompt(context.Context) (string, error) {
+	m.fetches++
+	return m.prompt, m.err
+}
+
+type invocation struct {
+	agent.ReadonlyContext
+	id string
+}
+
+func (i invocation) InvocationID() string {
+	return i.id
+}
+
+func TestInstructionProvider(t *testing.T) {
+	manager := &fakeManager{prompt: "v1"}
+	provider := NewInstructionProvider(manager, 20*time.Millisecond)
+
+	instruction, err := provider.Instruction(invocation{id: "first"})
+	require.NoError(t, err)
+	assert.Equal(t, "v1", instruction)
+
+	// cached until the TTL elapsed
+	manager.prompt = "v2"
+	assert.Equal(t, "v1", provider.Prompt(context.Background()))
+	assert.Equal(t, 1, manager.fetches)
+	time.Sleep(30 * time.Millisecond)
+	instruction, err = provider.Instruction(invocation{id: "second"})
+	require.NoError(t, err)
+	assert.Equal(t, "v2", instruction)
+
+	// the first invocation keeps its prompt
+	instruction, err = provider.Instruction(invocation{id: "first"})
+	require.NoError(t, err)
+	assert.Equal(t, "v1", instruction)
+
+	// failures keep the last prompt
+	manager.prompt, manager.err = "", errors.New("unavailable")
+	provider.Invalidate()
+	assert.Equal(t, "v2", provider.Prompt(context.Background()))
+
+	// without any prompt, the default instruction is used
+	provider = NewInstructionProvider(&fakeManager{err: errors.New("unavailable")}, 0)
+	assert.Equal(t, DEFAULT_INSTRUCTION, provider.Prompt(context.Background()))
+}
+
+// slowManager blocks its fetches until release is closed.
+type slowManager struct {
+	fetches atomic.Int32
+	release chan struct{}
+}
+
+func (m *slowManager) GetPrompt(...string) string {
+	return ""
+}
+
+func (m *slowManager) FetchPrompt(context.Context) (string, error) {
+	m.fetches.Add(1)
+	<-m.release
+	return "slow", nil
+}
+
+func TestInstructionProvider_SingleFetch(t *testing.T) {
+	manager := &slowManager{release: make(chan struct{})}
+	provider := NewInstructionProvider(manager, time.Hour)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "slow", provider.Prompt(context.Background()))
+		}()
+	}
+	assert.Eventually(t, func() bool { return manager.fetches.Load() == 1 }, time.Second, time.Millisecond)
+	close(manager.release)
+	wg.Wait()
+	assert.EqualValues(t, 1, manager.fetches.Load(), "concurrent callers share the fetch")
+
+	// an invalidation during a fetch isn't lost, and doesn't wait for it
+	manager = &slowManager{release: make(chan struct{})}
+	provider = NewInstructionProvider(manager, time.Hour)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		provider.Prompt(context.Background())
+	}()
+	assert.Eventually(t, func() bool { return manager.fetches.Load() == 1 }, time.Second, time.Millisecond)
+	provider.Invalidate()
+	close(manager.release)
+	<-done
+	assert.Equal(t, "slow", provider.Prompt(context.Background()))
+	assert.EqualValues(t, 2, manager.fetches.Load())
+}
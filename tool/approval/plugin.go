@@ -0,0 +1,267 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/volcengine/veadk-go/log"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
+	"google.golang.org/genai"
+)
+
+const PluginName = "veadk-approval"
+
+// StateKeyPrefix prefixes the session state keys of the approval requests, followed
+// by the function call ID of the tool call.
+const StateKeyPrefix = "approval:"
+
+// Status of an approval request.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+var (
+	ErrNoPolicy   = errors.New("approval plugin requires a policy")
+	ErrNotFound   = errors.New("approval request not found")
+	ErrNotPending = errors.New("approval request is not pending")
+)
+
+// Request is a tool call waiting for, or given, approval.
+type Request struct {
+	// ID is the function call ID of the tool call.
+	ID string `json:"id"`
+	// ConfirmationID is the function call ID of the confirmation request a Decision
+	// answers. It is set by Pending and Find.
+	ConfirmationID string         `json:"confirmation_id,omitempty"`
+	Tool           string         `json:"tool"`
+	Args           map[string]any `json:"args"`
+	Hint           string         `json:"hint"`
+	Agent          string         `json:"agent"`
+	InvocationID   string         `json:"invocation_id"`
+	Status         string         `json:"status"`
+	RequestedAt    time.Time      `json:"requested_at"`
+	DecidedAt      *time.Time     `json:"decided_at,omitempty"`
+}
+
+// Decision approves or rejects a Request.
+type Decision struct {
+	Approved bool `json:"approved"`
+	// Args replace the arguments of an approved tool call when set.
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// Content returns the user message that answers the confirmation request
+// confirmationID with d, to run the agent with.
+func (d Decision) Content(confirmationID string) *genai.Content {
+	payload := map[string]any{}
+	if d.Args != nil {
+		payload["args"] = d.Args
+	}
+	return &genai.Content{
+		Role: genai.RoleUser,
+		Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+			ID:       confirmationID,
+			Name:     toolconfirmation.FunctionCallName,
+			Response: map[string]any{"confirmed": d.Approved, "payload": payload},
+		}}},
+	}
+}
+
+// PluginConfig configures the approval plugin.
+type PluginConfig struct {
+	// Policy decides which tool calls need approval.
+	Policy Policy
+	// Hint describes a tool call to the approver. Defaults to the tool name and arguments.
+	Hint func(t tool.Tool, args map[string]any) string
+}
+
+// NewPlugin creates a plugin that pauses the invocation on the tool calls Policy
+// requires approval for, with an ADK confirmation request. The request is stored in
+// the session state under StateKeyPrefix until it is answered with a Decision, which
+// runs the tool, with the arguments of the decision if any, or rejects the call.
+func NewPlugin(cfg *PluginConfig) (*plugin.Plugin, error) {
+	if cfg == nil || cfg.Policy == nil {
+		return nil, ErrNoPolicy
+	}
+	p := &approvalPlugin{config: cfg, now: time.Now}
+	if p.config.Hint == nil {
+		p.config.Hint = defaultHint
+	}
+	return plugin.New(plugin.Config{
+		Name:               PluginName,
+		BeforeToolCallback: p.BeforeTool,
+	})
+}
+
+type approvalPlugin struct {
+	config *PluginConfig
+	now    func() time.Time
+}
+
+func defaultHint(t tool.Tool, args map[string]any) string {
+	b, _ := json.Marshal(args)
+	return fmt.Sprintf("Approve the call of tool %s with arguments %s?", t.Name(), b)
+}
+
+// BeforeTool requests approval for a new tool call, or applies the decision on it.
+func (p *approvalPlugin) BeforeTool(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+	key := StateKeyPrefix + ctx.FunctionCallID()
+	if confirmation := ctx.ToolConfirmation(); confirmation != nil {
+		value, err := ctx.State().Get(key)
+		if err != nil {
+			// not requested by this plugin, the tool handles its confirmation
+			return nil, nil
+		}
+		request, ok := requestFromState(value)
+		if !ok || request.Status != StatusPending {
+			return nil, nil
+		}
+		return nil, p.decide(ctx, key, request, confirmation, args)
+	}
+
+	if !p.config.Policy.RequiresApproval(ctx, t, args) {
+		return nil, nil
+	}
+	request := Request{
+		ID:           ctx.FunctionCallID(),
+		Tool:         t.Name(),
+		Args:         maps.Clone(args),
+		Hint:         p.config.Hint(t, args),
+		Agent:        ctx.AgentName(),
+		InvocationID: ctx.InvocationID(),
+		Status:       StatusPending,
+		RequestedAt:  p.now(),
+	}
+	if err := ctx.RequestConfirmation(request.Hint, map[string]any{"args": request.Args}); err != nil {
+		return nil, err
+	}
+	ctx.Actions().StateDelta[key] = request.state()
+	// the invocation ends here until the decision
+	ctx.Actions().SkipSummarization = true
+	log.InfoContext(ctx, "tool call is waiting for approval", "function_call_id", request.ID, "tool", request.Tool)
+	return nil, fmt.Errorf("tool %q %w", t.Name(), tool.ErrConfirmationRequired)
+}
+
+// decide records the decision on request and applies modified arguments to args.
+func (p *approvalPlugin) decide(ctx tool.Context, key string, request Request, confirmation *toolconfirmation.ToolConfirmation, args map[string]any) error {
+	now := p.now()
+	request.DecidedAt = &now
+	if !confirmation.Confirmed {
+		request.Status = StatusRejected
+		ctx.Actions().StateDelta[key] = request.state()
+		return fmt.Errorf("tool %q %w", request.Tool, tool.ErrConfirmationRejected)
+	}
+
+	if payload, ok := confirmation.Payload.(map[string]any); ok {
+		if modified, ok := payload["args"].(map[string]any); ok {
+			clear(args)
+			maps.Copy(args, modified)
+		}
+	}
+	request.Status = StatusApproved
+	request.Args = maps.Clone(args)
+	ctx.Actions().StateDelta[key] = request.state()
+	return nil
+}
+
+// state converts r to JSON values, as persistent session services store them.
+func (r Request) state() map[string]any {
+	var state map[string]any
+	b, _ := json.Marshal(r)
+	_ = json.Unmarshal(b, &state)
+	return state
+}
+
+func requestFromState(value any) (Request, bool) {
+	var request Request
+	b, err := json.Marshal(value)
+	if err != nil || json.Unmarshal(b, &request) != nil || request.ID == "" {
+		return Request{}, false
+	}
+	return request, true
+}
+
+// Pending lists the pending approval requests of sess, oldest first.
+func Pending(sess session.Session) []Request {
+	confirmations := confirmationIDs(sess)
+	var pending []Request
+	for key, value := range sess.State().All() {
+		if !strings.HasPrefix(key, StateKeyPrefix) {
+			continue
+		}
+		request, ok := requestFromState(value)
+		if !ok || request.Status != StatusPending {
+			continue
+		}
+		request.ConfirmationID = confirmations[request.ID]
+		pending = append(pending, request)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].RequestedAt.Before(pending[j].RequestedAt) })
+	return pending
+}
+
+// Find returns the pending approval request id of sess.
+func Find(sess session.Session, id string) (Request, error) {
+	value, err := sess.State().Get(StateKeyPrefix + id)
+	if err != nil {
+		return Request{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	request, ok := requestFromState(value)
+	if !ok {
+		return Request{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if request.Status != StatusPending {
+		return Request{}, fmt.Errorf("%w: %s is %s", ErrNotPending, id, request.Status)
+	}
+	request.ConfirmationID = confirmationIDs(sess)[id]
+	if request.ConfirmationID == "" {
+		return Request{}, fmt.Errorf("%w: no confirmation request for %s", ErrNotFound, id)
+	}
+	return request, nil
+}
+
+// confirmationIDs maps the tool calls of the confirmation requests of sess to the
+// IDs of the requests.
+func confirmationIDs(sess session.Session) map[string]string {
+	ids := map[string]string{}
+	for event := range sess.Events().All() {
+		if event.Content == nil {
+			continue
+		}
+		for _, part := range event.Content.Parts {
+			if part == nil || part.FunctionCall == nil || part.FunctionCall.Name != toolconfirmation.FunctionCallName {
+				continue
+			}
+			original, err := toolconfirmation.OriginalCallFrom(part.FunctionCall)
+			if err != nil {
+				continue
+			}
+			ids[original.ID] = part.FunctionCall.ID
+		}
+	}
+	return ids
+}
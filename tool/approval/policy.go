@@ -0,0 +1,207 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/volcengine/veadk-go/log"
+	"github.com/volcengine/veadk-go/skills"
+	"google.golang.org/adk/tool"
+)
+
+// Policy decides which tool calls need approval.
+type Policy interface {
+	RequiresApproval(ctx tool.Context, t tool.Tool, args map[string]any) bool
+}
+
+// PolicyFunc adapts a function to a Policy.
+type PolicyFunc func(ctx tool.Context, t tool.Tool, args map[string]any) bool
+
+func (f PolicyFunc) RequiresApproval(ctx tool.Context, t tool.Tool, args map[string]any) bool {
+	return f(ctx, t, args)
+}
+
+// ByName requires approval for the calls of the tools names.
+func ByName(names ...string) Policy {
+	return PolicyFunc(func(_ tool.Context, t tool.Tool, _ map[string]any) bool {
+		return slices.Contains(names, t.Name())
+	})
+}
+
+// ByArgs requires approval for the calls of the tool name whose arguments match predicate.
+func ByArgs(name string, predicate func(args map[string]any) bool) Policy {
+	return PolicyFunc(func(_ tool.Context, t tool.Tool, args map[string]any) bool {
+		return t.Name() == name && predicate(args)
+	})
+}
+
+// AnyOf requires approval when any of policies does.
+func AnyOf(policies ...Policy) Policy {
+	return PolicyFunc(func(ctx tool.Context, t tool.Tool, args map[string]any) bool {
+		for _, policy := range policies {
+			if policy.RequiresApproval(ctx, t, args) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// NotAllowedBySkills requires approval for the calls no allowed-tools pattern of
+// skillList matches. The patterns are separated by spaces or commas outside parentheses.
+// A pattern is a tool name, where * matches anything, optionally followed by an argument
+// pattern in parentheses that the main argument of the tool must match, such as
+// run_skill_script(pdf-*) or Bash(git status:*), see patternArgs. The prefix:* form of
+// the skills specification reads as prefix*. Argument patterns with a * do not match
+// arguments chaining shell commands, see shellControlOperators. Invalid patterns are
+// logged and ignored.
+func NotAllowedBySkills(skillList ...*skills.Skill) Policy {
+	var patterns []toolPattern
+	for _, sk := range skillList {
+		if sk == nil || sk.Frontmatter == nil {
+			continue
+		}
+		fields, err := splitToolPatterns(sk.Frontmatter.AllowedTools)
+		if err != nil {
+			log.Warn("ignoring the allowed tools of skill", "skill", sk.Frontmatter.Name, "error", err)
+			continue
+		}
+		for _, field := range fields {
+			pattern, err := parseToolPattern(field)
+			if err != nil {
+				log.Warn("ignoring allowed tools pattern of skill", "skill", sk.Frontmatter.Name, "pattern", field, "error", err)
+				continue
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+	return PolicyFunc(func(_ tool.Context, t tool.Tool, args map[string]any) bool {
+		for _, pattern := range patterns {
+			if pattern.matches(t.Name(), args) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+type toolPattern struct {
+	name *regexp.Regexp
+	arg  *regexp.Regexp
+	// wildcardArg is set when arg matches any suffix or infix, which could hide a command.
+	wildcardArg bool
+}
+
+var toolPatternSyntax = regexp.MustCompile(`^([^()]+)(?:\(([^()]*)\))?$`)
+
+// patternArgs names the argument the argument patterns of a tool match. For the other
+// tools, it is the first of defaultPatternArgs the call has.
+var patternArgs = map[string]string{
+	"load_skill":          "name",
+	"load_skill_resource": "skill_name",
+	"run_skill_script":    "skill_name",
+}
+
+var defaultPatternArgs = []string{"command", "skill_name", "name"}
+
+// shellControlOperators chain or substitute commands, e.g. "git status; rm -rf ~" would
+// otherwise match Bash(git status:*).
+var shellControlOperators = []string{";", "&", "|", "`", "$(", "\n", "\r"}
+
+// splitToolPatterns splits allowed-tools at the spaces and commas outside parentheses.
+func splitToolPatterns(allowed string) ([]string, error) {
+	var fields []string
+	depth, start := 0, 0
+	for i, r := range allowed + " " {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth == 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in allowed tools %q", allowed)
+			}
+			depth--
+		case depth == 0 && (r == ',' || unicode.IsSpace(r)):
+			if field := allowed[start:i]; field != "" {
+				fields = append(fields, field)
+			}
+			start = i + 1
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in allowed tools %q", allowed)
+	}
+	return fields, nil
+}
+
+func parseToolPattern(s string) (toolPattern, error) {
+	match := toolPatternSyntax.FindStringSubmatch(s)
+	if match == nil {
+		return toolPattern{}, fmt.Errorf("invalid tool pattern %q", s)
+	}
+	pattern := toolPattern{name: globRegexp(match[1])}
+	if match[2] != "" {
+		glob := strings.ReplaceAll(match[2], ":*", "*")
+		pattern.arg = globRegexp(glob)
+		pattern.wildcardArg = strings.Contains(glob, "*")
+	}
+	return pattern, nil
+}
+
+func globRegexp(glob string) *regexp.Regexp {
+	return regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(glob), `\*`, ".*") + "$")
+}
+
+func (p toolPattern) matches(name string, args map[string]any) bool {
+	if !p.name.MatchString(name) {
+		return false
+	}
+	if p.arg == nil {
+		return true
+	}
+	value, ok := patternArg(name, args)
+	if !ok || p.wildcardArg && containsAny(value, shellControlOperators) {
+		return false
+	}
+	return p.arg.MatchString(value)
+}
+
+func containsAny(s string, substrs []string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// patternArg returns the argument of a call of the tool name matched by argument patterns.
+func patternArg(name string, args map[string]any) (string, bool) {
+	if arg, ok := patternArgs[name]; ok {
+		value, ok := args[arg].(string)
+		return value, ok
+	}
+	for _, arg := range defaultPatternArgs {
+		if value, ok := args[arg].(string); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
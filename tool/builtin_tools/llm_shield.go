@@ -302,11 +302,11 @@ func (p *LLMShieldClient) beforeModelCallBack(ctx agent.CallbackContext, req *mo
 		return nil, nil
 	}
 
-	log.Debugf("agent %s beforeModelCallBack lastUserMessage is %s", ctx.AgentName(), lastUserMessage)
+	log.DebugContext(ctx, "LLM Shield beforeModelCallBack", "agent", ctx.AgentName(), "last_user_message", lastUserMessage)
 
 	blockMsg, err := p.requestLLMShield(lastUserMessage, "user")
 	if err != nil {
-		log.Errorf("LLM Shield beforeModelCallBack error: %v", err)
+		log.ErrorContext(ctx, "LLM Shield beforeModelCallBack failed", "error", err)
 		return nil, nil
 	}
 
@@ -337,15 +337,15 @@ func (p *LLMShieldClient) afterModelCallBack(ctx agent.CallbackContext, resp *mo
 		return nil, nil
 	}
 
-	log.Debugf("agent %s afterModelCallBack lastUserMessage is %s", ctx.AgentName(), lastModelMessage)
+	log.DebugContext(ctx, "LLM Shield afterModelCallBack", "agent", ctx.AgentName(), "last_model_message", lastModelMessage)
 
 	blockMsg, err := p.requestLLMShield(lastModelMessage, "assistant")
 	if err != nil {
-		log.Errorf("LLM Shield afterModelCallBack error: %v", err)
+		log.ErrorContext(ctx, "LLM Shield afterModelCallBack failed", "error", err)
 		return nil, nil
 	}
 
-	log.Debugf("agent %s afterModelCallBack blockMsg is %s", ctx.AgentName(), blockMsg)
+	log.DebugContext(ctx, "LLM Shield afterModelCallBack", "agent", ctx.AgentName(), "block_message", blockMsg)
 
 	if blockMsg != "" {
 		return &model.LLMResponse{
@@ -373,7 +373,7 @@ func (p *LLMShieldClient) beforeToolCallback(ctx tool.Context, tool tool.Tool, a
 
 	blockMsg, err := p.requestLLMShield(message, "user")
 	if err != nil {
-		log.Errorf("LLM Shield beforeToolCallback error: %v", err)
+		log.ErrorContext(ctx, "LLM Shield beforeToolCallback failed", "error", err)
 		return nil, nil
 	}
 
@@ -396,7 +396,7 @@ func (p *LLMShieldClient) afterToolCallback(ctx tool.Context, tool tool.Tool, ar
 
 	blockMsg, err := p.requestLLMShield(message, "assistant")
 	if err != nil {
-		log.Errorf("LLM Shield beforeToolCallback error: %v", err)
+		log.ErrorContext(ctx, "LLM Shield afterToolCallback failed", "error", err)
 		return nil, nil
 	}
 
@@ -15,7 +15,6 @@
 package builtin_tools
 
 import (
-	"context"
 	"fmt"
 
 	"google.golang.org/adk/tool"
@@ -42,8 +41,8 @@ type Result struct {
 }
 
 func memorySearchToolFunc(tctx tool.Context, args Args) (Result, error) {
-
-	searchResults, err := tctx.SearchMemory(context.Background(), args.Query)
+	// tctx carries the agent name, which agent-scoped memory services search in
+	searchResults, err := tctx.SearchMemory(tctx, args.Query)
 	if err != nil {
 		return Result{}, fmt.Errorf("failed memory search")
 	}
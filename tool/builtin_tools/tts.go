@@ -25,7 +25,9 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/volcengine/veadk-go/auth/veauth"
@@ -43,6 +45,12 @@ const (
 
 var ErrTTSConfig = errors.New("tts config error")
 
+// ttsAudioPattern names the speech files saved by the TTS tool.
+const ttsAudioPattern = "tts_*.pcm"
+
+// ttsOutputDirs holds the resolved directories the TTS tools saved speech files in.
+var ttsOutputDirs sync.Map
+
 //go:noinline
 func doTTSRequest(client *http.Client, req *http.Request) (*http.Response, error) {
 	return client.Do(req)
@@ -315,7 +323,11 @@ func (c *TTSConfig) saveAudioData(audioData []byte) (string, error) {
 		return "", fmt.Errorf("create tts output path failed: %w", err)
 	}
 
-	audioFile, err := os.CreateTemp(c.OutputPath, "tts_*.pcm")
+	if dir, err := resolvePath(c.OutputPath); err == nil {
+		ttsOutputDirs.Store(dir, struct{}{})
+	}
+
+	audioFile, err := os.CreateTemp(c.OutputPath, ttsAudioPattern)
 	if err != nil {
 		return "", fmt.Errorf("create tts audio file failed: %w", err)
 	}
@@ -332,6 +344,31 @@ func (c *TTSConfig) saveAudioData(audioData []byte) (string, error) {
 	return audioPath, nil
 }
 
+// TTSAudioFile resolves file and reports whether it is a speech file saved by the TTS
+// tool in its output directory, so that paths of other files passed off as TTS results
+// are not read.
+func TTSAudioFile(file string) (string, bool) {
+	resolved, err := resolvePath(file)
+	if err != nil {
+		return "", false
+	}
+	if ok, _ := filepath.Match(ttsAudioPattern, filepath.Base(resolved)); !ok {
+		return "", false
+	}
+	if _, ok := ttsOutputDirs.Load(filepath.Dir(resolved)); !ok {
+		return "", false
+	}
+	return resolved, true
+}
+
+func resolvePath(file string) (string, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
 func userIDFromContext(ctx context.Context) string {
 	if ctx == nil {
 		return ""
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -159,3 +160,30 @@ func TestTTSHandlerEmptyText(t *testing.T) {
 	assert.Contains(t, err.Error(), "text is empty")
 	assert.Empty(t, result.SavedAudioPath)
 }
+
+func TestTTSAudioFile(t *testing.T) {
+	cfg := &TTSConfig{OutputPath: t.TempDir()}
+	saved, err := cfg.saveAudioData([]byte{1, 2})
+	require.NoError(t, err)
+
+	resolved, ok := TTSAudioFile(saved)
+	assert.True(t, ok)
+	want, err := filepath.EvalSymlinks(saved)
+	require.NoError(t, err)
+	assert.Equal(t, want, resolved)
+
+	other := filepath.Join(cfg.OutputPath, "secret.txt")
+	require.NoError(t, os.WriteFile(other, []byte("secret"), 0o600))
+	_, ok = TTSAudioFile(other)
+	assert.False(t, ok, "not a speech file")
+
+	link := filepath.Join(cfg.OutputPath, "tts_link.pcm")
+	require.NoError(t, os.Symlink(other, link))
+	_, ok = TTSAudioFile(link)
+	assert.False(t, ok, "links are resolved first")
+
+	forged := filepath.Join(t.TempDir(), "tts_forged.pcm")
+	require.NoError(t, os.WriteFile(forged, []byte{1}, 0o600))
+	_, ok = TTSAudioFile(forged)
+	assert.False(t, ok, "not in an output directory")
+}
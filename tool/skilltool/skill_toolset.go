@@ -20,10 +20,12 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync/atomic"
 
 	"github.com/volcengine/veadk-go/code_executors"
 	"github.com/volcengine/veadk-go/log"
 	"github.com/volcengine/veadk-go/skills"
+	"github.com/volcengine/veadk-go/utils"
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
@@ -47,23 +49,22 @@ const DEFAULT_SKILL_SYSTEM_INSTRUCTION = "" +
 
 // SkillToolset A toolset for managing and interacting with agent skills.
 type SkillToolset struct {
-	skills       map[string]*skills.Skill
+	// skills holds the current skills by name, swapped as a whole on reload
+	skills       atomic.Pointer[map[string]*skills.Skill]
+	snapshots    utils.InvocationSnapshots[map[string]*skills.Skill]
 	tools        []tool.Tool
 	codeExecutor code_executors.CodeExecutor
+	// dirs the skills were loaded from, by NewSkillToolsetFromDirs
+	dirs []string
 }
 
 func NewSkillToolset(skillList []*skills.Skill, codeExecutor code_executors.CodeExecutor) (*SkillToolset, error) {
-	m := make(map[string]*skills.Skill, len(skillList))
-	for _, s := range skillList {
-		if _, dup := m[s.Name()]; dup {
-			return nil, fmt.Errorf("duplicate skill name '%s'", s.Name())
-		}
-		m[s.Name()] = s
-	}
 	st := &SkillToolset{
-		skills:       m,
 		codeExecutor: codeExecutor,
 	}
+	if err := st.SetSkills(skillList); err != nil {
+		return nil, err
+	}
 	st.tools = []tool.Tool{
 		st.listSkillsTool(),
 		st.loadSkillTool(),
@@ -86,7 +87,7 @@ func (s *SkillToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
 //}
 
 func (s *SkillToolset) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
-	skillList := s.listSkills()
+	skillList := s.listSkills(ctx)
 	skillXML := skills.FormatSkillsAsXML(skillList)
 	instruction := []string{DEFAULT_SKILL_SYSTEM_INSTRUCTION, skillXML}
 	if req.Config.SystemInstruction == nil {
@@ -111,14 +112,38 @@ func (s *SkillToolset) ProcessRequest(ctx tool.Context, req *model.LLMRequest) e
 	return nil
 }
 
-func (s *SkillToolset) getSkill(name string) (*skills.Skill, bool) {
-	sk, ok := s.skills[name]
+// SetSkills replaces the skills of the toolset. Invocations which already used the toolset
+// keep the skills they started with.
+func (s *SkillToolset) SetSkills(skillList []*skills.Skill) error {
+	m := make(map[string]*skills.Skill, len(skillList))
+	for _, sk := range skillList {
+		if _, dup := m[sk.Name()]; dup {
+			return fmt.Errorf("duplicate skill name '%s'", sk.Name())
+		}
+		m[sk.Name()] = sk
+	}
+	s.skills.Store(&m)
+	return nil
+}
+
+// skillsOf returns the skills pinned to the invocation of ctx.
+func (s *SkillToolset) skillsOf(ctx tool.Context) map[string]*skills.Skill {
+	current := func() map[string]*skills.Skill { return *s.skills.Load() }
+	if ctx == nil {
+		return current()
+	}
+	return s.snapshots.Get(ctx.InvocationID(), current)
+}
+
+func (s *SkillToolset) getSkill(ctx tool.Context, name string) (*skills.Skill, bool) {
+	sk, ok := s.skillsOf(ctx)[name]
 	return sk, ok
 }
 
-func (s *SkillToolset) listSkills() []*skills.Skill {
-	var out = make([]*skills.Skill, 0, len(s.skills))
-	for _, v := range s.skills {
+func (s *SkillToolset) listSkills(ctx tool.Context) []*skills.Skill {
+	current := s.skillsOf(ctx)
+	var out = make([]*skills.Skill, 0, len(current))
+	for _, v := range current {
 		out = append(out, v)
 	}
 	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
@@ -128,7 +153,7 @@ func (s *SkillToolset) listSkills() []*skills.Skill {
 type listSkillsArgs struct{}
 
 func (s *SkillToolset) listSkillsToolHandler(ctx tool.Context, args listSkillsArgs) (map[string]any, error) {
-	xml := skills.FormatSkillsAsXML(s.listSkills())
+	xml := skills.FormatSkillsAsXML(s.listSkills(ctx))
 	return map[string]any{"result": xml}, nil
 }
 
@@ -152,7 +177,7 @@ func (s *SkillToolset) loadSkillToolHandler(ctx tool.Context, args loadSkillArgs
 			"error_code": "MISSING_SKILL_NAME",
 		}, nil
 	}
-	sk, ok := s.getSkill(args.Name)
+	sk, ok := s.getSkill(ctx, args.Name)
 	if !ok {
 		return map[string]any{
 			"error":      fmt.Sprintf("Skill '%s' not found.", args.Name),
@@ -188,7 +213,7 @@ func (s *SkillToolset) loadSkillResourceToolHandler(ctx tool.Context, args loadS
 	if strings.TrimSpace(args.Path) == "" {
 		return map[string]any{"error": "Resource path is required.", "error_code": "MISSING_RESOURCE_PATH"}, nil
 	}
-	sk, ok := s.getSkill(args.SkillName)
+	sk, ok := s.getSkill(ctx, args.SkillName)
 	if !ok {
 		return map[string]any{"error": fmt.Sprintf("Skill '%s' not found.", args.SkillName), "error_code": "SKILL_NOT_FOUND"}, nil
 	}
@@ -247,7 +272,7 @@ func (s *SkillToolset) runSkillScriptToolHandler(ctx tool.Context, args runSkill
 	if strings.TrimSpace(args.ScriptPath) == "" {
 		return map[string]any{"error": "Script path is required.", "error_code": "MISSING_SCRIPT_PATH"}, nil
 	}
-	sk, ok := s.getSkill(args.SkillName)
+	sk, ok := s.getSkill(ctx, args.SkillName)
 	if !ok {
 		return map[string]any{"error": fmt.Sprintf("Skill '%s' not found.", args.SkillName), "error_code": "SKILL_NOT_FOUND"}, nil
 	}
@@ -266,7 +291,7 @@ func (s *SkillToolset) runSkillScriptToolHandler(ctx tool.Context, args runSkill
 		}, nil
 	}
 	argsStr, _ := json.Marshal(args)
-	log.Debugf("runSkillScriptToolHandler args is %s", string(argsStr))
+	log.DebugContext(ctx, "run skill script", "args", string(argsStr))
 	codeExecutorResult, err := s.codeExecutor.ExecuteCode(nil, code_executors.CodeExecutionInput{
 		Args:        args.Args,
 		ScriptPath:  filepath.Join(sk.GetSkillPath(), "scripts", name),
@@ -274,7 +299,7 @@ func (s *SkillToolset) runSkillScriptToolHandler(ctx tool.Context, args runSkill
 		ExecutionID: ctx.InvocationID(),
 	})
 	resultStr, _ := json.Marshal(codeExecutorResult)
-	log.Debugf("codeExecutor result is %s", string(resultStr))
+	log.DebugContext(ctx, "skill script executed", "result", string(resultStr))
 	if err != nil {
 		return map[string]any{
 			"error":      fmt.Sprintf("Failed to execute script '%s':\n%s", args.ScriptPath, err.Error()),
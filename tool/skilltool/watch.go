@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skilltool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/volcengine/veadk-go/code_executors"
+	"github.com/volcengine/veadk-go/log"
+	"github.com/volcengine/veadk-go/skills"
+	"github.com/volcengine/veadk-go/utils"
+)
+
+var ErrNoSkillDirs = errors.New("skill toolset was not loaded from directories")
+
+// NewSkillToolsetFromDirs loads a skill from each of dirs. Reload and Watch load them again.
+func NewSkillToolsetFromDirs(dirs []string, codeExecutor code_executors.CodeExecutor) (*SkillToolset, error) {
+	skillList, err := loadSkillDirs(dirs)
+	if err != nil {
+		return nil, err
+	}
+	st, err := NewSkillToolset(skillList, codeExecutor)
+	if err != nil {
+		return nil, err
+	}
+	st.dirs = dirs
+	return st, nil
+}
+
+// Reload loads the skills from their directories again. The current skills are kept when
+// one of them fails to load.
+func (s *SkillToolset) Reload() error {
+	if len(s.dirs) == 0 {
+		return ErrNoSkillDirs
+	}
+	skillList, err := loadSkillDirs(s.dirs)
+	if err != nil {
+		return err
+	}
+	return s.SetSkills(skillList)
+}
+
+// Watch reloads the skills whenever their directories change, polling them every interval,
+// until ctx is done.
+func (s *SkillToolset) Watch(ctx context.Context, interval time.Duration) error {
+	if len(s.dirs) == 0 {
+		return ErrNoSkillDirs
+	}
+	utils.WatchPaths(ctx, s.dirs, interval, func() {
+		if err := s.Reload(); err != nil {
+			log.Warnf("reload skills from %v failed, keeping the current skills: %v", s.dirs, err)
+			return
+		}
+		log.Infof("reloaded skills from %v", s.dirs)
+	})
+	return nil
+}
+
+func loadSkillDirs(dirs []string) ([]*skills.Skill, error) {
+	skillList := make([]*skills.Skill, 0, len(dirs))
+	for _, dir := range dirs {
+		skill, err := skills.LoadSkillFromDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("load skill %s: %w", dir, err)
+		}
+		skillList = append(skillList, skill)
+	}
+	return skillList, nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skilltool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type invocationContext struct {
+	mockToolContext
+	id string
+}
+
+func (c *invocationContext) InvocationID() string {
+	return c.id
+}
+
+func writeSkill(t *testing.T, dir, description string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	content := "---\nname: " + filepath.Base(dir) + "\ndescription: " + description + "\n---\n\n# Skill\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0o600))
+}
+
+func TestSkillToolset_Reload(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "greeter")
+	writeSkill(t, dir, "Says hello")
+	toolset, err := NewSkillToolsetFromDirs([]string{dir}, nil)
+	require.NoError(t, err)
+
+	first := &invocationContext{id: "first"}
+	result, err := toolset.loadSkillToolHandler(first, loadSkillArgs{Name: "greeter"})
+	require.NoError(t, err)
+	assert.Contains(t, result["frontmatter"], "Says hello")
+
+	writeSkill(t, dir, "Says goodbye")
+	require.NoError(t, toolset.Reload())
+
+	// the running invocation keeps its skills, the next one gets the new ones
+	result, err = toolset.loadSkillToolHandler(first, loadSkillArgs{Name: "greeter"})
+	require.NoError(t, err)
+	assert.Contains(t, result["frontmatter"], "Says hello")
+	result, err = toolset.loadSkillToolHandler(&invocationContext{id: "second"}, loadSkillArgs{Name: "greeter"})
+	require.NoError(t, err)
+	assert.Contains(t, result["frontmatter"], "Says goodbye")
+
+	// broken skills keep the current ones
+	require.NoError(t, os.Remove(filepath.Join(dir, "SKILL.md")))
+	assert.Error(t, toolset.Reload())
+	assert.Len(t, toolset.listSkills(nil), 1)
+
+	static, err := NewSkillToolset(nil, nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, static.Reload(), ErrNoSkillDirs)
+}
+
+func TestSkillToolset_Watch(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "greeter")
+	writeSkill(t, dir, "Says hello")
+	toolset, err := NewSkillToolsetFromDirs([]string{dir}, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = toolset.Watch(ctx, 10*time.Millisecond) }()
+	time.Sleep(30 * time.Millisecond)
+
+	writeSkill(t, dir, "Says goodbye, and more")
+	assert.Eventually(t, func() bool {
+		return toolset.listSkills(nil)[0].Description() == "Says goodbye, and more"
+	}, 2*time.Second, 10*time.Millisecond)
+}
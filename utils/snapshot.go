@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultSnapshotRetention is how long an invocation keeps its snapshot.
+const DefaultSnapshotRetention = time.Hour
+
+// InvocationSnapshots pins a value to each invocation the first time it is read, so that
+// an invocation keeps working with the same value while a newer one is swapped in.
+type InvocationSnapshots[T any] struct {
+	// Retention is how long snapshots are kept, DefaultSnapshotRetention when zero.
+	Retention time.Duration
+
+	mu        sync.Mutex
+	snapshots map[string]snapshot[T]
+	pruned    time.Time
+}
+
+type snapshot[T any] struct {
+	value  T
+	pinned time.Time
+}
+
+// Get returns the value pinned to invocationID, pinning current() when there is none.
+// Reads outside of an invocation, with an empty invocationID, always get current().
+// current is called without holding the lock, concurrent first reads of an invocation
+// all get the value pinned by the first of them to finish.
+func (s *InvocationSnapshots[T]) Get(invocationID string, current func() T) T {
+	if invocationID == "" {
+		return current()
+	}
+	if value, ok := s.pinned(invocationID); ok {
+		return value
+	}
+	value := current()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if snap, ok := s.snapshots[invocationID]; ok {
+		return snap.value
+	}
+	if s.snapshots == nil {
+		s.snapshots = make(map[string]snapshot[T])
+	}
+	now := time.Now()
+	retention := s.Retention
+	if retention <= 0 {
+		retention = DefaultSnapshotRetention
+	}
+	if now.Sub(s.pruned) > retention/10 {
+		for id, snap := range s.snapshots {
+			if now.Sub(snap.pinned) > retention {
+				delete(s.snapshots, id)
+			}
+		}
+		s.pruned = now
+	}
+	s.snapshots[invocationID] = snapshot[T]{value: value, pinned: now}
+	return value
+}
+
+func (s *InvocationSnapshots[T]) pinned(invocationID string) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, ok := s.snapshots[invocationID]
+	return snap.value, ok
+}
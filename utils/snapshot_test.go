@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvocationSnapshots(t *testing.T) {
+	var snapshots InvocationSnapshots[int]
+	value := 1
+	current := func() int { return value }
+
+	assert.Equal(t, 1, snapshots.Get("first", current))
+	value = 2
+	// the first invocation keeps its value, new ones get the current one
+	assert.Equal(t, 1, snapshots.Get("first", current))
+	assert.Equal(t, 2, snapshots.Get("second", current))
+	assert.Equal(t, 2, snapshots.Get("", current))
+
+	// expired snapshots are dropped
+	snapshots = InvocationSnapshots[int]{Retention: 10 * time.Millisecond}
+	value = 3
+	snapshots.Get("old", current)
+	time.Sleep(20 * time.Millisecond)
+	snapshots.Get("new", current)
+	assert.Len(t, snapshots.snapshots, 1)
+}
+
+func TestInvocationSnapshots_CurrentOutsideLock(t *testing.T) {
+	var snapshots InvocationSnapshots[int]
+	// current may be slow or read other snapshots, it does not block the other invocations
+	nested := snapshots.Get("outer", func() int {
+		return snapshots.Get("inner", func() int { return 1 }) + 1
+	})
+	assert.Equal(t, 2, nested)
+
+	// concurrent first reads of an invocation agree on the pinned value
+	var calls atomic.Int32
+	values := make(chan int, 2)
+	for range 2 {
+		go func() {
+			values <- snapshots.Get("shared", func() int {
+				time.Sleep(10 * time.Millisecond)
+				return int(calls.Add(1))
+			})
+		}()
+	}
+	first := <-values
+	assert.Equal(t, first, <-values)
+	assert.Equal(t, first, snapshots.Get("shared", func() int { return 0 }))
+}
+
+func TestWatchPaths(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "skill", "SKILL.md")
+	before, err := Fingerprint(dir, filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte("v1"), 0o600))
+	after, err := Fingerprint(dir, filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after)
+
+	var changes atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		WatchPaths(ctx, []string{dir}, 10*time.Millisecond, func() { changes.Add(1) })
+	}()
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, os.WriteFile(file, []byte("version 2"), 0o600))
+	assert.Eventually(t, func() bool { return changes.Load() == 1 }, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Beijing Volcano Engine Technology Co., Ltd. and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// DefaultWatchInterval is how often WatchPaths polls the files.
+const DefaultWatchInterval = 2 * time.Second
+
+// Fingerprint summarizes the names, sizes and modification times of the files at paths,
+// walking directories, so that it changes whenever one of them does. Missing paths are
+// part of the fingerprint as such.
+func Fingerprint(paths ...string) (string, error) {
+	hash := sha256.New()
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) {
+				_, _ = fmt.Fprintf(hash, "%s missing\n", path)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(hash, "%s %d %d %d\n", path, info.Size(), info.ModTime().UnixNano(), info.Mode())
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// WatchPaths polls the files at paths every interval, DefaultWatchInterval when zero, and
+// calls onChange when they changed, until ctx is done. Changes are reported once the
+// files stayed unchanged for an interval, so that files being written aren't read halfway.
+func WatchPaths(ctx context.Context, paths []string, interval time.Duration, onChange func()) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	// unreadable files are retried on the next poll
+	last, _ := Fingerprint(paths...)
+	pending := ""
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		current, err := Fingerprint(paths...)
+		if err != nil {
+			continue
+		}
+		switch {
+		case current == last:
+			pending = ""
+		case current != pending:
+			// changed since the last poll, wait for the writes to settle
+			pending = current
+		default:
+			last, pending = current, ""
+			onChange()
+		}
+	}
+}